@@ -2,14 +2,18 @@ package v0alpha1
 
 // Defines values for PlaylistItemType.
 const (
-	PlaylistItemTypeDashboardById  PlaylistItemType = "dashboard_by_id"
-	PlaylistItemTypeDashboardByTag PlaylistItemType = "dashboard_by_tag"
-	PlaylistItemTypeDashboardByUid PlaylistItemType = "dashboard_by_uid"
+	PlaylistItemTypeDashboardById     PlaylistItemType = "dashboard_by_id"
+	PlaylistItemTypeDashboardBySearch PlaylistItemType = "dashboard_by_search"
+	PlaylistItemTypeDashboardByTag    PlaylistItemType = "dashboard_by_tag"
+	PlaylistItemTypeDashboardByUid    PlaylistItemType = "dashboard_by_uid"
 )
 
 // PlaylistItem defines model for PlaylistItem.
 // +k8s:openapi-gen=true
 type PlaylistItem struct {
+	// Options override how the dashboards of the item are displayed.
+	Options *PlaylistItemOptions `json:"options,omitempty"`
+
 	// type of the item.
 	Type PlaylistItemType `json:"type"`
 
@@ -20,9 +24,24 @@ type PlaylistItem struct {
 	//  - dashboard_by_tag: The value is a tag which is set on any number of dashboards. All
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
+	//  - dashboard_by_search: The value is a JSON encoded search. All
+	//  dashboards matching the search will be added to the playlist.
 	Value string `json:"value"`
 }
 
+// PlaylistItemOptions defines model for PlaylistItemOptions.
+// +k8s:openapi-gen=true
+type PlaylistItemOptions struct {
+	// Interval overrides the time between switching the dashboards of the item.
+	Interval *string `json:"interval,omitempty"`
+
+	// TimeRange overrides the time range of the dashboards.
+	TimeRange *PlaylistTimeRange `json:"timeRange,omitempty"`
+
+	// Variables override the values of the dashboard variables.
+	Variables map[string][]string `json:"variables,omitempty"`
+}
+
 // PlaylistItemType type of the item.
 // +k8s:openapi-gen=true
 type PlaylistItemType string
@@ -34,3 +53,10 @@ type PlaylistSpec struct {
 	Items    []PlaylistItem `json:"items"`
 	Title    string         `json:"title"`
 }
+
+// PlaylistTimeRange defines model for PlaylistTimeRange.
+// +k8s:openapi-gen=true
+type PlaylistTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
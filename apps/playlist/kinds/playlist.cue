@@ -17,7 +17,7 @@ externalName: {
 			schema: {
 				#Item: {
 					// type of the item.
-					type: "dashboard_by_tag" | "dashboard_by_uid" | "dashboard_by_id" @cuetsy(kind="enum")
+					type: "dashboard_by_tag" | "dashboard_by_uid" | "dashboard_by_id" | "dashboard_by_search" @cuetsy(kind="enum")
 					// Value depends on type and describes the playlist item.
 					//  - dashboard_by_id: The value is an internal numerical identifier set by Grafana. This
 					//  is not portable as the numerical identifier is non-deterministic between different instances.
@@ -25,7 +25,25 @@ externalName: {
 					//  - dashboard_by_tag: The value is a tag which is set on any number of dashboards. All
 					//  dashboards behind the tag will be added to the playlist.
 					//  - dashboard_by_uid: The value is the dashboard UID
+					//  - dashboard_by_search: The value is a JSON encoded search. All
+					//  dashboards matching the search will be added to the playlist.
 					value: string
+					// Options override how the dashboards of the item are displayed.
+					options?: #ItemOptions
+				}
+
+				#ItemOptions: {
+					// Interval overrides the time between switching the dashboards of the item.
+					interval?: string
+					// TimeRange overrides the time range of the dashboards.
+					timeRange?: #TimeRange
+					// Variables override the values of the dashboard variables.
+					variables?: [string]: [...string]
+				}
+
+				#TimeRange: {
+					from: string
+					to: string
 				}
 								
 				spec: {
@@ -548,7 +548,12 @@ func (hs *HTTPServer) registerRoutes() {
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
-		apiRoute.Post("/short-urls", routing.Wrap(hs.createShortURL))
+		apiRoute.Group("/short-urls", func(shortURLRoute routing.RouteRegister) {
+			shortURLRoute.Post("/", routing.Wrap(hs.createShortURL))
+			shortURLRoute.Get("/", routing.Wrap(hs.searchShortURLs))
+			shortURLRoute.Get("/:uid", routing.Wrap(hs.getShortURL))
+			shortURLRoute.Delete("/:uid", routing.Wrap(hs.deleteShortURL))
+		})
 	}, reqSignedIn)
 
 	// admin api
@@ -601,6 +606,7 @@ func (hs *HTTPServer) registerRoutes() {
 
 	// Snapshots
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrSignedIn, hs.getCreatedSnapshotHandler())
+	r.Post("/api/snapshots/render", reqSignedIn, hs.RenderDashboardSnapshot)
 	r.Get("/api/snapshot/shared-options/", reqSignedIn, hs.GetSharingOptions)
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
 	r.Get("/api/snapshots-delete/:deleteKey", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.DeleteDashboardSnapshotByDeleteKey))
@@ -92,6 +92,79 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) {
 	}, cmd, hs.dashboardsnapshotsService)
 }
 
+// swagger:route POST /snapshots/render snapshots renderDashboardSnapshot
+//
+// Create a snapshot of a stored dashboard, running the queries of its panels on the server and embedding their results.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RenderDashboardSnapshot(c *contextmodel.ReqContext) {
+	cmd := dashboardsnapshots.RenderDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		c.JsonApiErr(http.StatusBadRequest, "bad request data", err)
+		return
+	}
+
+	if !hs.Cfg.SnapshotEnabled {
+		c.JsonApiErr(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+		return
+	}
+
+	evaluator := ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(cmd.DashboardUID))
+	if canSave, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator); err != nil || !canSave {
+		c.JsonApiErr(http.StatusForbidden, "forbidden", err)
+		return
+	}
+
+	dash, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			c.JsonApiErr(http.StatusNotFound, "Dashboard not found", err)
+			return
+		}
+		c.JsonApiErr(http.StatusInternalServerError, "Failed to get dashboard", err)
+		return
+	}
+
+	renderer := dashboardsnapshots.NewSnapshotRenderer(hs.queryDataService, hs.DataSourcesService, hs.LibraryElementService)
+	model, err := renderer.Render(c.Req.Context(), c.SignedInUser, dash.Data, &cmd)
+	if err != nil {
+		if errors.Is(err, dashboardsnapshots.ErrInvalidRenderCommand) {
+			c.JsonApiErr(http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		c.JsonApiErr(http.StatusInternalServerError, "Failed to render snapshot", err)
+		return
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = dash.Title
+	}
+
+	createCmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      name,
+			Dashboard: model,
+			Expires:   cmd.Expires,
+		},
+		Key:       cmd.Key,
+		DeleteKey: cmd.DeleteKey,
+	}
+
+	dashboardsnapshots.CreateDashboardSnapshot(c, dashboardsnapshot.SnapshotSharingOptions{
+		SnapshotsEnabled:     hs.Cfg.SnapshotEnabled,
+		ExternalEnabled:      hs.Cfg.ExternalEnabled,
+		ExternalSnapshotName: hs.Cfg.ExternalSnapshotName,
+		ExternalSnapshotURL:  hs.Cfg.ExternalSnapshotUrl,
+	}, createCmd, hs.dashboardsnapshotsService)
+}
+
 // GET /api/snapshots/:key
 // swagger:route GET /snapshots/{key} snapshots getDashboardSnapshot
 //
@@ -314,6 +387,13 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters renderDashboardSnapshot
+type RenderSnapshotParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.RenderDashboardSnapshotCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
@@ -74,6 +74,7 @@ type MetricRequest struct {
 	// queries.datasourceId – Specifies the data source to be queried. Each query in the request must have an unique datasourceId.
 	// queries.maxDataPoints - Species maximum amount of data points that dashboard panel can render. Is optional and default to 100.
 	// queries.intervalMs - Specifies the time interval in milliseconds of time series. Is optional and defaults to 1000.
+	// queries.savedQuery - References a query of the query library by uid, and optionally version and refId. The query is replaced with the saved query.
 	// required: true
 	// example: [ { "refId": "A", "intervalMs": 86400000, "maxDataPoints": 1092, "datasource":{ "uid":"PD8C576611E62080A" }, "rawSql": "SELECT 1 as valueOne, 2 as valueTwo", "format": "table" } ]
 	Queries []*simplejson.Json `json:"queries"`
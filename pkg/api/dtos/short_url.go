@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 type ShortURL struct {
 	UID string `json:"uid"`
 	URL string `json:"url"`
@@ -7,4 +9,20 @@ type ShortURL struct {
 
 type CreateShortURLCmd struct {
 	Path string `json:"path"`
+	// Slug is the human-readable uid of the short URL. A random uid is generated when empty.
+	Slug string `json:"slug,omitempty"`
+	// ExpiresAt is the time the short URL stops redirecting. The short URL never expires when empty.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type ShortURLDetails struct {
+	UID        string     `json:"uid"`
+	URL        string     `json:"url"`
+	Path       string     `json:"path"`
+	CreatedBy  int64      `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	Expired    bool       `json:"expired"`
+	HitCount   int64      `json:"hitCount"`
 }
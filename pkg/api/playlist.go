@@ -34,6 +34,7 @@ func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 			playlistRoute.Get("/", handler.searchPlaylists)
 			playlistRoute.Get("/:uid", handler.getPlaylist)
 			playlistRoute.Get("/:uid/items", handler.getPlaylistItems)
+			playlistRoute.Get("/:uid/resolve", handler.resolvePlaylist)
 			playlistRoute.Delete("/:uid", handler.deletePlaylist)
 			playlistRoute.Put("/:uid", handler.updatePlaylist)
 			playlistRoute.Post("/", handler.createPlaylist)
@@ -42,6 +43,7 @@ func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 			playlistRoute.Get("/", routing.Wrap(hs.SearchPlaylists))
 			playlistRoute.Get("/:uid", hs.validateOrgPlaylist, routing.Wrap(hs.GetPlaylist))
 			playlistRoute.Get("/:uid/items", hs.validateOrgPlaylist, routing.Wrap(hs.GetPlaylistItems))
+			playlistRoute.Get("/:uid/resolve", hs.validateOrgPlaylist, routing.Wrap(hs.ResolvePlaylist))
 			playlistRoute.Delete("/:uid", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.DeletePlaylist))
 			playlistRoute.Put("/:uid", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.UpdatePlaylist))
 			playlistRoute.Post("/", middleware.ReqEditorRole, routing.Wrap(hs.CreatePlaylist))
@@ -143,6 +145,37 @@ func (hs *HTTPServer) GetPlaylistItems(c *contextmodel.ReqContext) response.Resp
 	return response.JSON(http.StatusOK, dto.Items)
 }
 
+// swagger:route GET /playlists/{uid}/resolve playlists resolvePlaylist
+//
+// Resolve the playlist items into the ordered list of dashboards to display.
+//
+// Responses:
+// 200: resolvePlaylistResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) ResolvePlaylist(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	cmd := playlist.GetPlaylistByUidQuery{UID: uid, OrgId: c.SignedInUser.GetOrgID()}
+
+	dto, err := hs.playlistService.Get(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Playlist not found", err)
+	}
+
+	resolved, err := hs.playlistService.Resolve(c.Req.Context(), &playlist.ResolvePlaylistQuery{
+		Playlist:     dto,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to resolve playlist", err)
+	}
+
+	return response.JSON(http.StatusOK, resolved)
+}
+
 // swagger:route DELETE /playlists/{uid} playlists deletePlaylist
 //
 // Delete playlist.
@@ -246,6 +279,13 @@ type GetPlaylistItemsParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters resolvePlaylist
+type ResolvePlaylistParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
 // swagger:parameters getPlaylistDashboards
 type GetPlaylistDashboardsParams struct {
 	// in:path
@@ -298,6 +338,13 @@ type GetPlaylistItemsResponse struct {
 	Body []playlist.PlaylistItemDTO `json:"body"`
 }
 
+// swagger:response resolvePlaylistResponse
+type ResolvePlaylistResponse struct {
+	// The response message
+	// in: body
+	Body *playlist.ResolvedPlaylist `json:"body"`
+}
+
 // swagger:response getPlaylistDashboardsResponse
 type GetPlaylistDashboardsResponse struct {
 	// The response message
@@ -323,6 +370,7 @@ type playlistK8sHandler struct {
 	namespacer           request.NamespaceMapper
 	gvr                  schema.GroupVersionResource
 	clientConfigProvider grafanaapiserver.DirectRestConfigProvider
+	playlistService      playlist.Service
 }
 
 //-----------------------------------------------------------------------------------------
@@ -339,6 +387,7 @@ func newPlaylistK8sHandler(hs *HTTPServer) *playlistK8sHandler {
 		gvr:                  gvr,
 		namespacer:           request.GetNamespaceMapper(hs.Cfg),
 		clientConfigProvider: hs.clientConfigProvider,
+		playlistService:      hs.playlistService,
 	}
 }
 
@@ -396,6 +445,28 @@ func (pk8s *playlistK8sHandler) getPlaylistItems(c *contextmodel.ReqContext) {
 	c.JSON(http.StatusOK, internalplaylist.UnstructuredToLegacyPlaylistDTO(*out).Items)
 }
 
+func (pk8s *playlistK8sHandler) resolvePlaylist(c *contextmodel.ReqContext) {
+	client, ok := pk8s.getClient(c)
+	if !ok {
+		return // error is already sent
+	}
+	uid := web.Params(c.Req)[":uid"]
+	out, err := client.Get(c.Req.Context(), uid, v1.GetOptions{})
+	if err != nil {
+		pk8s.writeError(c, err)
+		return
+	}
+	resolved, err := pk8s.playlistService.Resolve(c.Req.Context(), &playlist.ResolvePlaylistQuery{
+		Playlist:     internalplaylist.UnstructuredToLegacyPlaylistDTO(*out),
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		pk8s.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resolved)
+}
+
 func (pk8s *playlistK8sHandler) deletePlaylist(c *contextmodel.ReqContext) {
 	client, ok := pk8s.getClient(c)
 	if !ok {
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -21,12 +23,19 @@ func (hs *HTTPServer) createShortURL(c *contextmodel.ReqContext) response.Respon
 		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("bad request data: %w", err))
 	}
 	hs.log.Debug("Received request to create short URL", "path", cmd.Path)
-	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, cmd.Path)
+	createCmd := shorturls.CreateShortURLCommand{
+		Path: cmd.Path,
+		Slug: cmd.Slug,
+	}
+	if cmd.ExpiresAt != nil {
+		createCmd.ExpiresAt = cmd.ExpiresAt.Unix()
+	}
+	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, &createCmd)
 	if err != nil {
 		return response.Err(err)
 	}
 
-	url := fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(hs.Cfg.AppURL, "/"), shortURL.Uid, c.SignedInUser.GetOrgID())
+	url := hs.shortURLLink(shortURL)
 	c.Logger.Debug("Created short URL", "url", url)
 
 	dto := dtos.ShortURL{
@@ -55,6 +64,11 @@ func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 		return
 	}
 
+	if shortURL.IsExpired(time.Now()) {
+		hs.log.Debug("Not redirecting short URL since expired")
+		return
+	}
+
 	// Failure to update LastSeenAt should still allow to redirect
 	if err := hs.ShortURLService.UpdateLastSeenAt(c.Req.Context(), shortURL); err != nil {
 		hs.log.Error("Failed to update short URL last seen at", "error", err)
@@ -63,3 +77,90 @@ func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 	hs.log.Debug("Redirecting short URL", "path", shortURL.Path)
 	c.Redirect(setting.ToAbsUrl(shortURL.Path), 302)
 }
+
+// searchShortURLs handles requests to list the short URLs of the org. Org admins see all the short URLs, while
+// other users only see the short URLs they created.
+func (hs *HTTPServer) searchShortURLs(c *contextmodel.ReqContext) response.Response {
+	query := shorturls.SearchShortURLsQuery{
+		OrgID: c.SignedInUser.GetOrgID(),
+		Query: c.Query("query"),
+		Limit: c.QueryInt("limit"),
+		Page:  c.QueryInt("page"),
+	}
+	if !c.HasUserRole(org.RoleAdmin) {
+		query.CreatedBy = c.SignedInUser.UserID
+	}
+
+	shortURLs, err := hs.ShortURLService.SearchShortURLs(c.Req.Context(), &query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to search short URLs", err)
+	}
+
+	now := time.Now()
+	result := make([]dtos.ShortURLDetails, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		result = append(result, hs.shortURLDetails(shortURL, now))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// getShortURL handles requests to get the details of a short URL.
+func (hs *HTTPServer) getShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURL, errResp := hs.getOwnedShortURL(c)
+	if errResp != nil {
+		return errResp
+	}
+	return response.JSON(http.StatusOK, hs.shortURLDetails(shortURL, time.Now()))
+}
+
+// deleteShortURL handles requests to revoke a short URL.
+func (hs *HTTPServer) deleteShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURL, errResp := hs.getOwnedShortURL(c)
+	if errResp != nil {
+		return errResp
+	}
+	if err := hs.ShortURLService.DeleteShortURL(c.Req.Context(), c.SignedInUser, shortURL.Uid); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete short URL", err)
+	}
+
+	hs.log.Info("Deleted short URL", "uid", shortURL.Uid, "path", shortURL.Path, "userId", c.SignedInUser.UserID)
+	return response.Success("Short URL deleted")
+}
+
+// getOwnedShortURL returns the short URL of the request if the user is an org admin or created it.
+func (hs *HTTPServer) getOwnedShortURL(c *contextmodel.ReqContext) (*shorturls.ShortUrl, response.Response) {
+	uid := web.Params(c.Req)[":uid"]
+	shortURL, err := hs.ShortURLService.GetShortURLByUID(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get short URL", err)
+	}
+	if !c.HasUserRole(org.RoleAdmin) && shortURL.CreatedBy != c.SignedInUser.UserID {
+		return nil, response.Error(http.StatusForbidden, "Only org admins can manage the short URLs of other users", nil)
+	}
+	return shortURL, nil
+}
+
+func (hs *HTTPServer) shortURLLink(shortURL *shorturls.ShortUrl) string {
+	return fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(hs.Cfg.AppURL, "/"), shortURL.Uid, shortURL.OrgId)
+}
+
+func (hs *HTTPServer) shortURLDetails(shortURL *shorturls.ShortUrl, now time.Time) dtos.ShortURLDetails {
+	dto := dtos.ShortURLDetails{
+		UID:       shortURL.Uid,
+		URL:       hs.shortURLLink(shortURL),
+		Path:      shortURL.Path,
+		CreatedBy: shortURL.CreatedBy,
+		CreatedAt: time.Unix(shortURL.CreatedAt, 0),
+		Expired:   shortURL.IsExpired(now),
+		HitCount:  shortURL.HitCount,
+	}
+	if shortURL.LastSeenAt != 0 {
+		lastSeenAt := time.Unix(shortURL.LastSeenAt, 0)
+		dto.LastSeenAt = &lastSeenAt
+	}
+	if shortURL.ExpiresAt != 0 {
+		expiresAt := time.Unix(shortURL.ExpiresAt, 0)
+		dto.ExpiresAt = &expiresAt
+	}
+	return dto
+}
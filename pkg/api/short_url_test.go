@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,9 +14,11 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestShortURLAPIEndpoint(t *testing.T) {
@@ -31,7 +34,7 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 			Path:  cmd.Path,
 		}
 		service := &fakeShortURLService{
-			createShortURLFunc: func(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error) {
+			createShortURLFunc: func(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
 				return createResp, nil
 			},
 		}
@@ -49,6 +52,66 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestShortURLManagementAPIEndpoints(t *testing.T) {
+	service := &fakeShortURLService{shortURLs: []*shorturls.ShortUrl{
+		{Id: 1, OrgId: testOrgID, Uid: "incident-42", Path: "d/abc/incident", CreatedBy: 1, CreatedAt: 1700000000, ExpiresAt: 1700000001, HitCount: 3},
+		{Id: 2, OrgId: testOrgID, Uid: "N1u6L4eGz", Path: "d/def/other", CreatedBy: 2, CreatedAt: 1700000000},
+	}}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.ShortURLService = service
+		hs.log = log.New("test")
+	})
+	viewer := &user.SignedInUser{UserID: 1, OrgID: testOrgID, OrgRole: org.RoleViewer}
+	admin := &user.SignedInUser{UserID: 3, OrgID: testOrgID, OrgRole: org.RoleAdmin}
+
+	search := func(t *testing.T, usr *user.SignedInUser) []dtos.ShortURLDetails {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/short-urls"), usr))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var result []dtos.ShortURLDetails
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		require.NoError(t, res.Body.Close())
+		return result
+	}
+
+	t.Run("org admins can list all the short URLs", func(t *testing.T) {
+		require.Len(t, search(t, admin), 2)
+	})
+
+	t.Run("users can only list their short URLs", func(t *testing.T) {
+		result := search(t, viewer)
+		require.Len(t, result, 1)
+		require.Equal(t, "incident-42", result[0].UID)
+		require.Equal(t, "/goto/incident-42?orgId=1", result[0].URL)
+		require.Equal(t, int64(3), result[0].HitCount)
+		require.True(t, result[0].Expired)
+		require.Nil(t, result[0].LastSeenAt)
+	})
+
+	t.Run("users cannot revoke the short URLs of other users", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/short-urls/N1u6L4eGz", nil), viewer))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		require.Empty(t, service.deleted)
+	})
+
+	t.Run("org admins can revoke any short URL", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/short-urls/N1u6L4eGz", nil), admin))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, []string{"N1u6L4eGz"}, service.deleted)
+	})
+
+	t.Run("revoking an unknown short URL returns not found", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/short-urls/unknown", nil), admin))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func callCreateShortURL(sc *scenarioContext) {
 	sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
 }
@@ -78,16 +141,23 @@ func createShortURLScenario(t *testing.T, desc string, url string, routePattern
 }
 
 type fakeShortURLService struct {
-	createShortURLFunc func(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error)
+	createShortURLFunc func(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error)
+	shortURLs          []*shorturls.ShortUrl
+	deleted            []string
 }
 
 func (s *fakeShortURLService) GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error) {
-	return nil, nil
+	for _, shortURL := range s.shortURLs {
+		if shortURL.Uid == uid {
+			return shortURL, nil
+		}
+	}
+	return nil, shorturls.ErrShortURLNotFound.Errorf("short URL not found")
 }
 
-func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error) {
+func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
 	if s.createShortURLFunc != nil {
-		return s.createShortURLFunc(ctx, user, path)
+		return s.createShortURLFunc(ctx, user, cmd)
 	}
 
 	return nil, nil
@@ -97,6 +167,21 @@ func (s *fakeShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *sh
 	return nil
 }
 
+func (s *fakeShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error) {
+	result := []*shorturls.ShortUrl{}
+	for _, shortURL := range s.shortURLs {
+		if query.CreatedBy == 0 || shortURL.CreatedBy == query.CreatedBy {
+			result = append(result, shortURL)
+		}
+	}
+	return result, nil
+}
+
+func (s *fakeShortURLService) DeleteShortURL(ctx context.Context, user *user.SignedInUser, uid string) error {
+	s.deleted = append(s.deleted, uid)
+	return nil
+}
+
 func (s *fakeShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return nil
 }
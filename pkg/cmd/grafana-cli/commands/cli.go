@@ -48,6 +48,12 @@ func CLICommand(version string) *cli.Command {
 				Value:   "https://grafana.com/api/plugins",
 				EnvVars: []string{"GF_PLUGIN_REPO"},
 			},
+			&cli.StringFlag{
+				Name:    "repoIndex",
+				Usage:   "Path or URL of a plugin repository index to install plugins from instead of the plugin repository",
+				Value:   "",
+				EnvVars: []string{"GF_PLUGIN_REPO_INDEX"},
+			},
 			&cli.StringFlag{
 				Name:    "pluginUrl",
 				Usage:   "Full url to the plugin zip file instead of downloading the plugin from grafana.com/api",
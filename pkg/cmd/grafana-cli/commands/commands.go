@@ -164,6 +164,12 @@ var adminCommands = []*cli.Command{
 				Name:   "re-encrypt-data-keys",
 				Usage:  "Rotates persisted data encryption keys. Returns ok unless there is an error. Safe to execute multiple times.",
 				Action: runRunnerCommand(secretsmigrations.ReEncryptDEKS),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "provider",
+						Usage: "Re-encrypts the data encryption keys with this provider instead of the current encryption provider, e.g. keyring.v1. The provider must be listed in available_encryption_providers",
+					},
+				},
 			},
 		},
 	},
@@ -88,6 +88,7 @@ func installCommand(c utils.CommandLine) error {
 type pluginInstallOpts struct {
 	insecure  bool
 	repoURL   string
+	repoIndex string
 	pluginURL string
 	pluginDir string
 }
@@ -96,13 +97,14 @@ func newInstallPluginOpts(c utils.CommandLine) pluginInstallOpts {
 	return pluginInstallOpts{
 		insecure:  c.Bool("insecure"),
 		repoURL:   c.PluginRepoURL(),
+		repoIndex: c.PluginRepoIndex(),
 		pluginURL: c.PluginURL(),
 		pluginDir: c.PluginDirectory(),
 	}
 }
 
-// installPlugin downloads the plugin code as a zip file from the Grafana.com API
-// and then extracts the zip into the plugin's directory.
+// installPlugin downloads the plugin code as a zip file from the Grafana.com API,
+// or from the repository index if one is set, and then extracts the zip into the plugin's directory.
 func installPlugin(ctx context.Context, pluginID, version string, o pluginInstallOpts) error {
 	return doInstallPlugin(ctx, pluginID, version, o, map[string]bool{})
 }
@@ -131,15 +133,8 @@ func doInstallPlugin(ctx context.Context, pluginID, version string, o pluginInst
 		}
 	}
 
-	repository := repo.NewManager(repo.ManagerCfg{
-		SkipTLSVerify: o.insecure,
-		BaseURL:       o.repoURL,
-		Logger:        services.Logger,
-	})
-
-	// FIXME: Re-enable grafanaVersion. This check was broken in 10.2 so disabling it for the moment.
-	// Expected to be re-enabled in 12.x.
-	compatOpts := repo.NewCompatOpts("", runtime.GOOS, runtime.GOARCH)
+	repository := pluginRepository(o)
+	compatOpts := pluginCompatOpts()
 
 	var archive *repo.PluginArchive
 	var err error
@@ -181,6 +176,7 @@ func doInstallPlugin(ctx context.Context, pluginID, version string, o pluginInst
 		err = doInstallPlugin(ctx, dep.ID, dep.Version, pluginInstallOpts{
 			insecure:  o.insecure,
 			repoURL:   o.repoURL,
+			repoIndex: o.repoIndex,
 			pluginDir: o.pluginDir,
 		}, installing)
 		if err != nil {
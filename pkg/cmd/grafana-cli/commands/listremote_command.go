@@ -1,15 +1,16 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
-	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 )
 
 // listRemoteCommand prints out all plugins in the remote repo with latest version supported on current platform.
 // If there are no supported versions for plugin it is skipped.
 func listRemoteCommand(c utils.CommandLine) error {
-	plugin, err := services.ListAllPlugins(c.PluginRepoURL())
+	plugin, err := listRemotePlugins(context.Background(), c, c.PluginRepoURL())
 	if err != nil {
 		return err
 	}
@@ -1,10 +1,10 @@
 package commands
 
 import (
+	"context"
 	"errors"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
-	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 )
 
@@ -24,7 +24,7 @@ func listVersionsCommand(c utils.CommandLine) error {
 
 	pluginToList := c.Args().First()
 
-	plugin, err := services.GetPluginInfoFromRepo(pluginToList, c.String("repo"))
+	plugin, err := getRemotePlugin(context.Background(), c, pluginToList, c.String("repo"))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/plugins/repo"
+)
+
+// pluginCompatOpts returns the compatibility options used to select plugin versions.
+func pluginCompatOpts() repo.CompatOpts {
+	// FIXME: Re-enable grafanaVersion. This check was broken in 10.2 so disabling it for the moment.
+	// Expected to be re-enabled in 12.x.
+	return repo.NewCompatOpts("", runtime.GOOS, runtime.GOARCH)
+}
+
+// pluginRepository returns the repository to install plugins from: the repository index if one is set,
+// or the plugin repository otherwise.
+func pluginRepository(o pluginInstallOpts) repo.Service {
+	if o.repoIndex != "" {
+		return repo.NewIndexRepository(repo.IndexRepositoryCfg{
+			SkipTLSVerify: o.insecure,
+			Location:      o.repoIndex,
+			Logger:        services.Logger,
+		})
+	}
+	return repo.NewManager(repo.ManagerCfg{
+		SkipTLSVerify: o.insecure,
+		BaseURL:       o.repoURL,
+		Logger:        services.Logger,
+	})
+}
+
+// listRemotePlugins returns the plugins of the repository index if one is set, or of the plugin repository at repoURL otherwise.
+func listRemotePlugins(ctx context.Context, c utils.CommandLine, repoURL string) (models.PluginRepo, error) {
+	index := c.PluginRepoIndex()
+	if index == "" {
+		return services.ListAllPlugins(repoURL)
+	}
+
+	indexPlugins, err := listIndexPlugins(ctx, c, index)
+	if err != nil {
+		return models.PluginRepo{}, err
+	}
+	pluginRepo := models.PluginRepo{Plugins: make([]models.Plugin, 0, len(indexPlugins))}
+	for _, p := range indexPlugins {
+		pluginRepo.Plugins = append(pluginRepo.Plugins, pluginFromIndex(p))
+	}
+	return pluginRepo, nil
+}
+
+// getRemotePlugin returns a plugin of the repository index if one is set, or of the plugin repository at repoURL otherwise.
+func getRemotePlugin(ctx context.Context, c utils.CommandLine, pluginID, repoURL string) (models.Plugin, error) {
+	index := c.PluginRepoIndex()
+	if index == "" {
+		return services.GetPluginInfoFromRepo(pluginID, repoURL)
+	}
+
+	indexPlugins, err := listIndexPlugins(ctx, c, index)
+	if err != nil {
+		return models.Plugin{}, err
+	}
+	for _, p := range indexPlugins {
+		if p.ID == pluginID {
+			return pluginFromIndex(p), nil
+		}
+	}
+	return models.Plugin{}, fmt.Errorf("failed to find requested plugin, check if the plugin_id (%s) is correct: %w", pluginID, services.ErrNotFoundError)
+}
+
+func listIndexPlugins(ctx context.Context, c utils.CommandLine, index string) ([]repo.IndexPlugin, error) {
+	repository := repo.NewIndexRepository(repo.IndexRepositoryCfg{
+		SkipTLSVerify: c.Bool("insecure"),
+		Location:      index,
+		Logger:        services.Logger,
+	})
+	return repository.ListPlugins(repo.WithRequestOrigin(ctx, "cli"), pluginCompatOpts())
+}
+
+// pluginFromIndex converts a plugin of a repository index, leaving out the versions incompatible with Grafana.
+func pluginFromIndex(p repo.IndexPlugin) models.Plugin {
+	plugin := models.Plugin{ID: p.ID, Versions: make([]models.Version, 0, len(p.Versions))}
+	for _, v := range p.Versions {
+		if v.IsCompatible != nil && !*v.IsCompatible {
+			continue
+		}
+		version := models.Version{Version: v.Version, URL: v.URL}
+		if v.Arch != nil {
+			version.Arch = make(map[string]models.ArchMeta, len(v.Arch))
+			for arch, meta := range v.Arch {
+				version.Arch[arch] = models.ArchMeta{SHA256: meta.SHA256}
+			}
+		}
+		plugin.Versions = append(plugin.Versions, version)
+	}
+	return plugin
+}
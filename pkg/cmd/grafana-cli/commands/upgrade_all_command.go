@@ -35,7 +35,8 @@ func upgradeAllCommand(c utils.CommandLine) error {
 
 	localPlugins := services.GetLocalPlugins(pluginsDir)
 
-	remotePlugins, err := services.ListAllPlugins(c.String("repo"))
+	ctx := context.Background()
+	remotePlugins, err := listRemotePlugins(ctx, c, c.String("repo"))
 	if err != nil {
 		return err
 	}
@@ -54,7 +55,6 @@ func upgradeAllCommand(c utils.CommandLine) error {
 		}
 	}
 
-	ctx := context.Background()
 	for _, p := range pluginsToUpgrade {
 		logger.Infof("Updating %v \n", p.JSONData.ID)
 
@@ -25,7 +25,7 @@ func upgradeCommand(c utils.CommandLine) error {
 		return err
 	}
 
-	plugin, err := services.GetPluginInfoFromRepo(pluginID, c.PluginRepoURL())
+	plugin, err := getRemotePlugin(ctx, c, pluginID, c.PluginRepoURL())
 	if err != nil {
 		return err
 	}
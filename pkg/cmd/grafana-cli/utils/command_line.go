@@ -25,6 +25,7 @@ type CommandLine interface {
 
 	PluginDirectory() string
 	PluginRepoURL() string
+	PluginRepoIndex() string
 	PluginURL() string
 }
 
@@ -74,24 +75,50 @@ func (c *ContextCommandLine) PluginRepoURL() string {
 	}
 
 	// if --config flag is set, try to get the GrafanaComAPIURL setting
-	if c.ConfigFile() != "" {
-		configOptions := strings.Split(c.String("configOverrides"), " ")
-		cfg, err := setting.NewCfgFromArgs(setting.CommandLineArgs{
-			Config:   c.ConfigFile(),
-			HomePath: c.HomePath(),
-			Args:     append(configOptions, c.Args().Slice()...),
-		})
-
-		if err != nil {
-			logger.Debug("Could not parse config file", err)
-		} else if cfg.GrafanaComAPIURL != "" {
-			return cfg.GrafanaComAPIURL + "/plugins"
-		}
+	if cfg := c.cfg(); cfg != nil && cfg.GrafanaComAPIURL != "" {
+		return cfg.GrafanaComAPIURL + "/plugins"
 	}
 	// fallback to default value
 	return c.String("repo")
 }
 
+/*
+The plugin repository index is determined in the following order:
+1. --repoIndex flag value, also set via the environment variable called "GF_PLUGIN_REPO_INDEX"
+2. --config parameter, from which we are looking at the repository_index setting of the [plugins] section
+If no index is set, plugins are installed from the plugin repository.
+**/
+
+func (c *ContextCommandLine) PluginRepoIndex() string {
+	if index := c.String("repoIndex"); index != "" {
+		return index
+	}
+
+	if cfg := c.cfg(); cfg != nil {
+		return cfg.PluginRepositoryIndex
+	}
+	return ""
+}
+
+// cfg returns the configuration of the --config and --configOverrides flags, or nil if they are not set.
+func (c *ContextCommandLine) cfg() *setting.Cfg {
+	if c.ConfigFile() == "" {
+		return nil
+	}
+
+	configOptions := strings.Split(c.String("configOverrides"), " ")
+	cfg, err := setting.NewCfgFromArgs(setting.CommandLineArgs{
+		Config:   c.ConfigFile(),
+		HomePath: c.HomePath(),
+		Args:     append(configOptions, c.Args().Slice()...),
+	})
+	if err != nil {
+		logger.Debug("Could not parse config file", err)
+		return nil
+	}
+	return cfg
+}
+
 func (c *ContextCommandLine) PluginURL() string {
 	return c.String("pluginUrl")
 }
@@ -118,6 +118,20 @@ func (_m *MockCommandLine) PluginDirectory() string {
 	return r0
 }
 
+// PluginRepoIndex provides a mock function with given fields:
+func (_m *MockCommandLine) PluginRepoIndex() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // PluginRepoURL provides a mock function with given fields:
 func (_m *MockCommandLine) PluginRepoURL() string {
 	ret := _m.Called()
@@ -0,0 +1,304 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AnomalyMethod is the way the expected range of a series is calculated.
+// +enum
+type AnomalyMethod string
+
+const (
+	// Mean and standard deviation of the trailing window
+	AnomalyMethodZScore AnomalyMethod = "zscore"
+
+	// Median and median absolute deviation of the trailing window
+	AnomalyMethodMAD AnomalyMethod = "mad"
+
+	// Mean and standard deviation of the same window in previous seasons
+	AnomalyMethodSeasonal AnomalyMethod = "seasonal"
+)
+
+// AnomalyOutput selects the series returned by the anomaly expression.
+// +enum
+type AnomalyOutput string
+
+const (
+	// Upper band, lower band and whether the value is anomalous
+	AnomalyOutputAll AnomalyOutput = "all"
+
+	// Only whether the value is anomalous
+	AnomalyOutputAnomalous AnomalyOutput = "anomalous"
+)
+
+const (
+	// AnomalyLabel is the label added to each output series to tell them apart.
+	AnomalyLabel = "anomaly"
+
+	AnomalyUpperBand = "upper"
+	AnomalyLowerBand = "lower"
+	AnomalyIsAnom    = "is_anomalous"
+
+	defaultAnomalyDeviations = 3.0
+	defaultAnomalySeason     = 7 * 24 * time.Hour
+
+	// madScale makes the median absolute deviation comparable to the standard deviation of normally distributed data.
+	madScale = 1.4826
+)
+
+var supportedAnomalyMethods = []string{
+	string(AnomalyMethodZScore),
+	string(AnomalyMethodMAD),
+	string(AnomalyMethodSeasonal),
+}
+
+// AnomalyCommand is an expression command that detects anomalies in time series without
+// any external service. For every point it calculates an expected range from the points
+// before it, and returns the upper and lower bands of that range together with a series that
+// is 1 where the value is outside of the range and 0 where it is inside.
+type AnomalyCommand struct {
+	RefID        string
+	ReferenceVar string
+	Method       AnomalyMethod
+	Window       time.Duration
+	Season       time.Duration
+	Deviations   float64
+	Output       AnomalyOutput
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand. rawSeason is only used by the seasonal method
+// and defaults to one week. deviations defaults to 3 when nil.
+func NewAnomalyCommand(refID, referenceVar string, method AnomalyMethod, rawWindow, rawSeason string, deviations *float64, output AnomalyOutput) (*AnomalyCommand, error) {
+	switch method {
+	case AnomalyMethodZScore, AnomalyMethodMAD, AnomalyMethodSeasonal:
+	default:
+		return nil, fmt.Errorf("expected anomaly method to be one of [%s], got %s", strings.Join(supportedAnomalyMethods, ", "), method)
+	}
+
+	window, err := gtime.ParseDuration(rawWindow)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse anomaly "window" duration field %q: %w`, rawWindow, err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf(`anomaly "window" must be positive, got %q`, rawWindow)
+	}
+
+	season := defaultAnomalySeason
+	if rawSeason != "" {
+		season, err = gtime.ParseDuration(rawSeason)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "season" duration field %q: %w`, rawSeason, err)
+		}
+		if season <= 0 {
+			return nil, fmt.Errorf(`anomaly "season" must be positive, got %q`, rawSeason)
+		}
+	}
+
+	d := defaultAnomalyDeviations
+	if deviations != nil {
+		d = *deviations
+		if d <= 0 || math.IsNaN(d) || math.IsInf(d, 0) {
+			return nil, fmt.Errorf(`anomaly "deviations" must be a positive number, got %v`, d)
+		}
+	}
+
+	switch output {
+	case "":
+		output = AnomalyOutputAll
+	case AnomalyOutputAll, AnomalyOutputAnomalous:
+	default:
+		return nil, fmt.Errorf("expected anomaly output to be one of [%s, %s], got %s", AnomalyOutputAll, AnomalyOutputAnomalous, output)
+	}
+
+	return &AnomalyCommand{
+		RefID:        refID,
+		ReferenceVar: referenceVar,
+		Method:       method,
+		Window:       window,
+		Season:       season,
+		Deviations:   d,
+		Output:       output,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	q := AnomalyQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the anomaly command: %w", err)
+	}
+	referenceVar, err := getReferenceVar(q.Expression, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnomalyCommand(rn.RefID, referenceVar, q.Method, q.Window, q.Season, q.Deviations, q.Output)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("method", string(ac.Method)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.ReferenceVar].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			upper, lower, anomalous := ac.detect(v)
+			if ac.Output == AnomalyOutputAll {
+				newRes.Values = append(newRes.Values, upper, lower)
+			}
+			newRes.Values = append(newRes.Values, anomalous)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (ac *AnomalyCommand) Type() string {
+	return TypeAnomaly.String()
+}
+
+// detect returns the upper band, lower band and is anomalous series for s. Bands are null
+// where there are fewer than two previous values to calculate them from, and the is anomalous
+// series is null where either the value or the bands are null.
+func (ac *AnomalyCommand) detect(s mathexp.Series) (mathexp.Series, mathexp.Series, mathexp.Series) {
+	points := sortedPoints(s)
+
+	upper := mathexp.NewSeries(ac.RefID, anomalyLabels(s.GetLabels(), AnomalyUpperBand), len(points))
+	lower := mathexp.NewSeries(ac.RefID, anomalyLabels(s.GetLabels(), AnomalyLowerBand), len(points))
+	anomalous := mathexp.NewSeries(ac.RefID, anomalyLabels(s.GetLabels(), AnomalyIsAnom), len(points))
+
+	for i, p := range points {
+		var u, l, a *float64
+		if center, spread, ok := ac.baseline(points, i); ok {
+			uv, lv := center+ac.Deviations*spread, center-ac.Deviations*spread
+			u, l = &uv, &lv
+			if p.v != nil && !math.IsNaN(*p.v) {
+				av := 0.0
+				if *p.v > uv || *p.v < lv {
+					av = 1
+				}
+				a = &av
+			}
+		}
+		upper.SetPoint(i, p.t, u)
+		lower.SetPoint(i, p.t, l)
+		anomalous.SetPoint(i, p.t, a)
+	}
+	return upper, lower, anomalous
+}
+
+// baseline returns the center and spread of the expected range for points[i].
+func (ac *AnomalyCommand) baseline(points []anomalyPoint, i int) (float64, float64, bool) {
+	var values []float64
+	switch ac.Method {
+	case AnomalyMethodSeasonal:
+		// the window before the same time in every previous season
+		for offset := ac.Season; !points[i].t.Add(-offset).Before(points[0].t); offset += ac.Season {
+			end := points[i].t.Add(-offset)
+			values = append(values, valuesBetween(points[:i], end.Add(-ac.Window), end)...)
+		}
+	default:
+		values = valuesBetween(points[:i], points[i].t.Add(-ac.Window), points[i].t)
+	}
+	if len(values) < 2 {
+		return 0, 0, false
+	}
+
+	if ac.Method == AnomalyMethodMAD {
+		m := median(values)
+		deviations := make([]float64, len(values))
+		for j, v := range values {
+			deviations[j] = math.Abs(v - m)
+		}
+		return m, madScale * median(deviations), true
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values))), true
+}
+
+type anomalyPoint struct {
+	t time.Time
+	v *float64
+}
+
+// sortedPoints returns the points of s ordered by time.
+func sortedPoints(s mathexp.Series) []anomalyPoint {
+	points := make([]anomalyPoint, s.Len())
+	for i := range points {
+		points[i].t, points[i].v = s.GetPoint(i)
+	}
+	sort.SliceStable(points, func(a, b int) bool {
+		return points[a].t.Before(points[b].t)
+	})
+	return points
+}
+
+// valuesBetween returns the non-null values of the points with a time in (from, to].
+func valuesBetween(points []anomalyPoint, from, to time.Time) []float64 {
+	start := sort.Search(len(points), func(i int) bool { return points[i].t.After(from) })
+	var values []float64
+	for _, p := range points[start:] {
+		if p.t.After(to) {
+			break
+		}
+		if p.v != nil && !math.IsNaN(*p.v) {
+			values = append(values, *p.v)
+		}
+	}
+	return values
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func anomalyLabels(labels data.Labels, output string) data.Labels {
+	l := labels.Copy()
+	if l == nil {
+		l = data.Labels{}
+	}
+	l[AnomalyLabel] = output
+	return l
+}
@@ -0,0 +1,213 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewAnomalyCommand(t *testing.T) {
+	cases := []struct {
+		description   string
+		method        AnomalyMethod
+		window        string
+		season        string
+		deviations    *float64
+		output        AnomalyOutput
+		expectedError string
+	}{
+		{
+			description: "zscore with defaults",
+			method:      AnomalyMethodZScore,
+			window:      "1h",
+		},
+		{
+			description: "seasonal with season",
+			method:      AnomalyMethodSeasonal,
+			window:      "30m",
+			season:      "1d",
+			deviations:  util.Pointer(2.5),
+			output:      AnomalyOutputAnomalous,
+		},
+		{
+			description:   "unknown method",
+			method:        "prophet",
+			window:        "1h",
+			expectedError: "expected anomaly method to be one of [zscore, mad, seasonal], got prophet",
+		},
+		{
+			description:   "missing window",
+			method:        AnomalyMethodMAD,
+			expectedError: `failed to parse anomaly "window" duration field`,
+		},
+		{
+			description:   "negative season",
+			method:        AnomalyMethodSeasonal,
+			window:        "1h",
+			season:        "-1d",
+			expectedError: `anomaly "season" must be positive`,
+		},
+		{
+			description:   "zero deviations",
+			method:        AnomalyMethodZScore,
+			window:        "1h",
+			deviations:    util.Pointer(0.0),
+			expectedError: `anomaly "deviations" must be a positive number`,
+		},
+		{
+			description:   "unknown output",
+			method:        AnomalyMethodZScore,
+			window:        "1h",
+			output:        "bands",
+			expectedError: "expected anomaly output to be one of [all, anomalous], got bands",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			cmd, err := NewAnomalyCommand("B", "A", tc.method, tc.window, tc.season, tc.deviations, tc.output)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+			require.Equal(t, "anomaly", cmd.Type())
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, "1h", "", nil, "")
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, cmd.Window)
+		require.Equal(t, 7*24*time.Hour, cmd.Season)
+		require.Equal(t, 3.0, cmd.Deviations)
+		require.Equal(t, AnomalyOutputAll, cmd.Output)
+	})
+}
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	rn := &rawNode{
+		RefID: "B",
+		QueryRaw: []byte(`{
+			"expression": "$A",
+			"type": "anomaly",
+			"method": "mad",
+			"window": "2h",
+			"deviations": 4
+		}`),
+	}
+	require.NoError(t, json.Unmarshal(rn.QueryRaw, &rn.Query))
+
+	cmd, err := UnmarshalAnomalyCommand(rn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A"}, cmd.NeedsVars())
+	require.Equal(t, AnomalyMethodMAD, cmd.Method)
+	require.Equal(t, 2*time.Hour, cmd.Window)
+	require.Equal(t, 4.0, cmd.Deviations)
+
+	commandType, err := GetExpressionCommandType(rn.Query)
+	require.NoError(t, err)
+	require.Equal(t, TypeAnomaly, commandType)
+}
+
+func TestAnomalyExecute(t *testing.T) {
+	execute := func(t *testing.T, cmd *AnomalyCommand, values ...mathexp.Value) mathexp.Results {
+		t.Helper()
+		vars := mathexp.Vars{"A": newResults(values...)}
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		return res
+	}
+	valuesOf := func(s mathexp.Series) []*float64 {
+		values := make([]*float64, s.Len())
+		for i := range values {
+			values[i] = s.GetValue(i)
+		}
+		return values
+	}
+
+	t.Run("zscore", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, "1m", "", util.Pointer(2.0), "")
+		require.NoError(t, err)
+
+		res := execute(t, cmd, newSeriesWithLabels(data.Labels{"host": "a"},
+			util.Pointer(1.0), util.Pointer(3.0), util.Pointer(1.0), util.Pointer(3.0), util.Pointer(1.0), util.Pointer(20.0)))
+		require.Len(t, res.Values, 3)
+
+		upper, lower, anomalous := res.Values[0].(mathexp.Series), res.Values[1].(mathexp.Series), res.Values[2].(mathexp.Series)
+		assert.Equal(t, data.Labels{"host": "a", AnomalyLabel: AnomalyUpperBand}, upper.GetLabels())
+		assert.Equal(t, data.Labels{"host": "a", AnomalyLabel: AnomalyLowerBand}, lower.GetLabels())
+		assert.Equal(t, data.Labels{"host": "a", AnomalyLabel: AnomalyIsAnom}, anomalous.GetLabels())
+
+		// mean 2 and standard deviation 1 of the points before the fifth one
+		assert.Equal(t, util.Pointer(4.0), upper.GetValue(4))
+		assert.Equal(t, util.Pointer(0.0), lower.GetValue(4))
+		assert.Equal(t, []*float64{nil, nil, util.Pointer(0.0), util.Pointer(0.0), util.Pointer(0.0), util.Pointer(1.0)}, valuesOf(anomalous))
+	})
+
+	t.Run("mad is not skewed by earlier outliers", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodMAD, "1m", "", nil, AnomalyOutputAnomalous)
+		require.NoError(t, err)
+
+		res := execute(t, cmd, newSeries(10, 11, 9, 10, 50, 10))
+		require.Len(t, res.Values, 1)
+		assert.Equal(t, []*float64{nil, nil, util.Pointer(0.0), util.Pointer(0.0), util.Pointer(1.0), util.Pointer(0.0)}, valuesOf(res.Values[0].(mathexp.Series)))
+	})
+
+	t.Run("seasonal", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodSeasonal, "10m", "1h", nil, "")
+		require.NoError(t, err)
+
+		points := []struct {
+			offset time.Duration
+			value  float64
+		}{
+			{0, 10},
+			{5 * time.Minute, 20},
+			{10 * time.Minute, 30},
+			{time.Hour, 100},
+			{time.Hour + 5*time.Minute, 16},
+			{time.Hour + 10*time.Minute, 45},
+		}
+		s := mathexp.NewSeries("", nil, len(points))
+		for i, p := range points {
+			s.SetPoint(i, time.Unix(0, 0).Add(p.offset), util.Pointer(p.value))
+		}
+
+		res := execute(t, cmd, s)
+		require.Len(t, res.Values, 3)
+		upper, lower, anomalous := res.Values[0].(mathexp.Series), res.Values[1].(mathexp.Series), res.Values[2].(mathexp.Series)
+
+		// 1:05 is compared to the values in (0:55, 1:05] an hour earlier
+		assert.Equal(t, util.Pointer(30.0), upper.GetValue(4))
+		assert.Equal(t, util.Pointer(0.0), lower.GetValue(4))
+		assert.Equal(t, []*float64{nil, nil, nil, nil, util.Pointer(0.0), util.Pointer(1.0)}, valuesOf(anomalous))
+	})
+
+	t.Run("no data is passed through", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, "1m", "", nil, "")
+		require.NoError(t, err)
+
+		res := execute(t, cmd, mathexp.NewNoData())
+		require.True(t, res.IsNoData())
+	})
+
+	t.Run("numbers are not supported", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, "1m", "", nil, "")
+		require.NoError(t, err)
+
+		vars := mathexp.Vars{"A": newResults(newNumber(nil, util.Pointer(1.0)))}
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.ErrorContains(t, err, "can only detect anomalies in type series")
+	})
+}
@@ -216,22 +216,37 @@ type ResampleCommand struct {
 	VarToResample string
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
+	MaxGap        time.Duration
 	TimeRange     TimeRange
 	refID         string
 }
 
-// NewResampleCommand creates a new ResampleCMD.
-func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
-	// TODO: validate reducer here, before execution
+// NewResampleCommand creates a new ResampleCMD. rawMaxGap is optional and only
+// used by the linear and nearest upsamplers.
+func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, rawMaxGap string, tr TimeRange) (*ResampleCommand, error) {
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	var maxGap time.Duration
+	if rawMaxGap != "" {
+		maxGap, err = gtime.ParseDuration(rawMaxGap)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse resample "maxGap" duration field %q: %w`, rawMaxGap, err)
+		}
+		if maxGap < 0 {
+			return nil, fmt.Errorf(`resample "maxGap" must not be negative, got %q`, rawMaxGap)
+		}
+	}
+	if _, err := mathexp.GetReduceFunc(downsampler); err != nil {
+		return nil, fmt.Errorf("invalid resample downsampler: %w", err)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
+		MaxGap:        maxGap,
 		TimeRange:     tr,
 		refID:         refID,
 	}, nil
@@ -280,10 +295,19 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
+	maxGap := ""
+	if rawMaxGap, ok := rn.Query["maxGap"]; ok {
+		maxGap, ok = rawMaxGap.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample maxGap to be a string, got type %T", rawMaxGap)
+		}
+	}
+
 	return NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
+		maxGap,
 		rn.TimeRange)
 }
 
@@ -306,7 +330,7 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 		}
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To)
+			num, err := v.ResampleWithMaxGap(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, gr.MaxGap, timeRange.From, timeRange.To)
 			if err != nil {
 				return newRes, err
 			}
@@ -343,6 +367,8 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeAnomaly is the CMDType for detecting anomalies in time series
+	TypeAnomaly
 )
 
 func (gt CommandType) String() string {
@@ -359,6 +385,8 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeAnomaly:
+		return "anomaly"
 	default:
 		return "unknown"
 	}
@@ -379,6 +407,8 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "anomaly":
+		return TypeAnomaly, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
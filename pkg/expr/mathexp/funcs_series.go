@@ -0,0 +1,276 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// checkDurationArg validates at parse time that the argument at idx is a valid duration.
+// If positive is set the duration must also be greater than zero.
+func checkDurationArg(idx int, positive bool) func(*parse.Tree, *parse.FuncNode) error {
+	return func(_ *parse.Tree, f *parse.FuncNode) error {
+		s, ok := f.Args[idx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration for argument %v of %s", idx, f.Name)
+		}
+		d, err := gtime.ParseDuration(s.Text)
+		if err != nil {
+			return fmt.Errorf("parse: invalid duration %q for %s: %w", s.Text, f.Name, err)
+		}
+		if positive && d <= 0 {
+			return fmt.Errorf("parse: duration for %s must be positive, got %q", f.Name, s.Text)
+		}
+		return nil
+	}
+}
+
+// rate returns the per-second rate of increase between consecutive points of each series.
+// A decrease is treated as a counter reset, in which case the new value is used as the increase.
+// The first point of each series is null.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		return pairwise(e, s, func(prevT, t time.Time, prev, cur float64) *float64 {
+			seconds := t.Sub(prevT).Seconds()
+			if seconds <= 0 {
+				return nil
+			}
+			increase := cur - prev
+			if cur < prev {
+				increase = cur
+			}
+			r := increase / seconds
+			return &r
+		})
+	})
+}
+
+// delta returns the difference between consecutive points of each series.
+// The first point of each series is null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		return pairwise(e, s, func(_, _ time.Time, prev, cur float64) *float64 {
+			d := cur - prev
+			return &d
+		})
+	})
+}
+
+// cumsum returns the running total of each series. Null points stay null and do not
+// change the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		idx := sortedIndex(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(idx))
+		total := float64(0)
+		for i, j := range idx {
+			t, f := s.GetPoint(j)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			total += *f
+			v := total
+			newSeries.SetPoint(i, t, &v)
+		}
+		return newSeries
+	})
+}
+
+// shift moves every point of each series forward in time by the duration, so that
+// $A - shift($A, 1d) compares each point to the one a day earlier.
+func shift(e *State, varSet Results, rawDuration string) (Results, error) {
+	d, err := gtime.ParseDuration(rawDuration)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "shift", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns the average of the non-null values in the trailing window of each point.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	return movingWindow(e, "moving_avg", varSet, rawWindow, func(values []float64) float64 {
+		sum := float64(0)
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	})
+}
+
+// movingSum returns the sum of the non-null values in the trailing window of each point.
+func movingSum(e *State, varSet Results, rawWindow string) (Results, error) {
+	return movingWindow(e, "moving_sum", varSet, rawWindow, func(values []float64) float64 {
+		sum := float64(0)
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	})
+}
+
+// movingMin returns the minimum of the non-null values in the trailing window of each point.
+func movingMin(e *State, varSet Results, rawWindow string) (Results, error) {
+	return movingWindow(e, "moving_min", varSet, rawWindow, func(values []float64) float64 {
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	})
+}
+
+// movingMax returns the maximum of the non-null values in the trailing window of each point.
+func movingMax(e *State, varSet Results, rawWindow string) (Results, error) {
+	return movingWindow(e, "moving_max", varSet, rawWindow, func(values []float64) float64 {
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	})
+}
+
+// clamp limits each value in NumberSet, SeriesSet, or Scalar to the range [min, max].
+func clamp(e *State, varSet Results, minRes Results, maxRes Results) (Results, error) {
+	lower, err := scalarArg("clamp", minRes)
+	if err != nil {
+		return Results{}, err
+	}
+	upper, err := scalarArg("clamp", maxRes)
+	if err != nil {
+		return Results{}, err
+	}
+	if lower > upper {
+		return Results{}, fmt.Errorf("clamp: min %v is greater than max %v", lower, upper)
+	}
+
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			if math.IsNaN(f) {
+				return f
+			}
+			return math.Max(lower, math.Min(upper, f))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// perSeries applies seriesF to every series in varSet. NoData is passed through and
+// any other type is an error.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch res.Type() {
+		case parse.TypeSeriesSet:
+			newRes.Values = append(newRes.Values, seriesF(res.(Series)))
+		case parse.TypeNoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series but got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// pairwise builds a series where each point is calculated from the point and the
+// previous non-null point. The first point, and any point with a null value, is null.
+func pairwise(e *State, s Series, pointF func(prevT, t time.Time, prev, cur float64) *float64) Series {
+	idx := sortedIndex(s)
+	newSeries := NewSeries(e.RefID, s.GetLabels(), len(idx))
+
+	var prevT time.Time
+	var prev *float64
+	for i, j := range idx {
+		t, f := s.GetPoint(j)
+		var v *float64
+		if f != nil && prev != nil {
+			v = pointF(prevT, t, *prev, *f)
+		}
+		newSeries.SetPoint(i, t, v)
+		if f != nil {
+			prevT, prev = t, f
+		}
+	}
+	return newSeries
+}
+
+// movingWindow applies windowF to the non-null values within (t-window, t] for each
+// point t. Points with no values in their window are null.
+func movingWindow(e *State, name string, varSet Results, rawWindow string, windowF func(values []float64) float64) (Results, error) {
+	window, err := gtime.ParseDuration(rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	if window <= 0 {
+		return Results{}, fmt.Errorf("%s: window must be positive, got %q", name, rawWindow)
+	}
+
+	return perSeries(e, name, varSet, func(s Series) Series {
+		idx := sortedIndex(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(idx))
+
+		start := 0
+		values := make([]float64, 0)
+		for i, j := range idx {
+			t := s.GetTime(j)
+			for t.Sub(s.GetTime(idx[start])) >= window {
+				start++
+			}
+			values = values[:0]
+			for _, k := range idx[start : i+1] {
+				if f := s.GetValue(k); f != nil {
+					values = append(values, *f)
+				}
+			}
+			if len(values) == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			v := windowF(values)
+			newSeries.SetPoint(i, t, &v)
+		}
+		return newSeries
+	})
+}
+
+// sortedIndex returns the point indices of s ordered by time, leaving s untouched.
+func sortedIndex(s Series) []int {
+	idx := make([]int, s.Len())
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return s.GetTime(idx[a]).Before(s.GetTime(idx[b]))
+	})
+	return idx
+}
+
+// scalarArg returns the value of a scalar function argument.
+func scalarArg(name string, res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("%s: expected a scalar argument", name)
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("%s: scalar argument must not be null", name)
+	}
+	return *f, nil
+}
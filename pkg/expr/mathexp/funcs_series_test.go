@@ -0,0 +1,210 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestSeriesFuncs(t *testing.T) {
+	counter := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(10)},
+				tp{time.Unix(10, 0), float64Pointer(30)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), float64Pointer(5)},
+			),
+		),
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "rate handles nulls and counter resets",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(0.25)},
+				),
+			),
+		},
+		{
+			name:      "delta",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(-25)},
+				),
+			),
+		},
+		{
+			name:      "cumsum",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(40)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(45)},
+				),
+			),
+		},
+		{
+			name:      "shift with unquoted duration",
+			expr:      "shift($A, 10s)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(10)},
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(5)},
+				),
+			),
+		},
+		{
+			name:      "compare with shifted series",
+			expr:      "$A - shift($A, 10s)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil},
+				),
+			),
+		},
+		{
+			name:      "moving_avg skips nulls in the window",
+			expr:      `moving_avg($A, "20s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(30, 0), float64Pointer(5)},
+				),
+			),
+		},
+		{
+			name:      "moving_max",
+			expr:      "moving_max($A, 30s)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(30)},
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(30, 0), float64Pointer(30)},
+				),
+			),
+		},
+		{
+			name: "clamp on series",
+			expr: "clamp($A, 8, 20)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(5)},
+					),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(8)},
+				),
+			),
+		},
+		{
+			name:      "clamp on number",
+			expr:      "clamp($A, -1, 1)",
+			vars:      Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(-7)))},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(-1))),
+		},
+		{
+			name:      "clamp with min greater than max",
+			expr:      "clamp($A, 2, 1)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "rate on number",
+			expr:      "rate($A)",
+			vars:      Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(1)))},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with invalid window",
+			expr:     `moving_avg($A, "soon")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg without window",
+			expr:     "moving_avg($A)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "duration outside of a function",
+			expr:     "$A + 5m",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				if tt.results.Values != nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
@@ -56,8 +56,9 @@ const (
 	itemRightParen
 	itemString
 	itemFunc
-	itemVar // e.g. $A
-	itemPow // '**'
+	itemVar      // e.g. $A
+	itemPow      // '**'
+	itemDuration // e.g. 5m, 1h30m
 )
 
 const eof = -1
@@ -210,10 +211,31 @@ func lexNumber(l *lexer) stateFn {
 	if !l.scanNumber() {
 		return l.errorf("bad number syntax: %q", l.input[l.start:l.pos])
 	}
+	if l.scanDuration() {
+		l.emit(itemDuration)
+		return lexItem
+	}
 	l.emit(itemNumber)
 	return lexItem
 }
 
+const durationUnits = "smhdwy"
+
+// scanDuration consumes the unit suffix of a duration such as 5m, including
+// compound durations like 1h30m. It reports whether a unit was found.
+func (l *lexer) scanDuration() bool {
+	if !l.accept(durationUnits) {
+		return false
+	}
+	for {
+		l.acceptRun(durationUnits)
+		if !l.accept("0123456789") {
+			return true
+		}
+		l.acceptRun("0123456789")
+	}
+}
+
 func (l *lexer) scanNumber() bool {
 	// Is it hex?
 	digits := "0123456789"
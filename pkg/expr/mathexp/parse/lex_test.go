@@ -33,6 +33,7 @@ var itemName = map[itemType]string{
 	itemRightParen: ")",
 	itemString:     "string",
 	itemFunc:       "func",
+	itemDuration:   "duration",
 }
 
 func (i itemType) String() string {
@@ -97,6 +98,12 @@ var lexTests = []lexTest{
 		{itemNumber, 0, "1.2e-4"},
 		tEOF,
 	}},
+	{"durations", "5m 1h30m 2d", []item{
+		{itemDuration, 0, "5m"},
+		{itemDuration, 0, "1h30m"},
+		{itemDuration, 0, "2d"},
+		tEOF,
+	}},
 	{"curly brace var", "${My Var}", []item{
 		{itemVar, 0, "${My Var}"},
 		tEOF,
@@ -329,6 +329,9 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemDuration:
+			// durations are only valid as function arguments and are passed as strings
+			f.append(newString(token.pos, token.val, token.val))
 		case itemRightParen:
 			return
 		}
@@ -15,18 +15,29 @@ type ReducerFunc = func(fv *Float64Field) *float64
 type ReducerID string
 
 const (
-	ReducerSum    ReducerID = "sum"
-	ReducerMean   ReducerID = "mean"
-	ReducerMin    ReducerID = "min"
-	ReducerMax    ReducerID = "max"
-	ReducerCount  ReducerID = "count"
-	ReducerLast   ReducerID = "last"
-	ReducerMedian ReducerID = "median"
+	ReducerSum          ReducerID = "sum"
+	ReducerMean         ReducerID = "mean"
+	ReducerMin          ReducerID = "min"
+	ReducerMax          ReducerID = "max"
+	ReducerCount        ReducerID = "count"
+	ReducerLast         ReducerID = "last"
+	ReducerMedian       ReducerID = "median"
+	ReducerP90          ReducerID = "p90"
+	ReducerP95          ReducerID = "p95"
+	ReducerP99          ReducerID = "p99"
+	ReducerStdDev       ReducerID = "stddev"
+	ReducerFirst        ReducerID = "first"
+	ReducerDiff         ReducerID = "diff"
+	ReducerRange        ReducerID = "range"
+	ReducerCountNonNull ReducerID = "count_non_null"
 )
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{
+		ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerP90, ReducerP95, ReducerP99, ReducerStdDev, ReducerFirst, ReducerDiff, ReducerRange, ReducerCountNonNull,
+	}
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +137,97 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+// Percentile returns a ReducerFunc that calculates the p-th percentile (0-100) of the values,
+// interpolating linearly between the closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := sortedValues(fv)
+		if !ok || len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		v := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &v
+	}
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Range returns the difference between the largest and the smallest value.
+func Range(fv *Float64Field) *float64 {
+	minV, maxV := Min(fv), Max(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// CountNonNull returns the number of values that are not null.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		if fv.GetValue(i) != nil {
+			f++
+		}
+	}
+	return &f
+}
+
+// sortedValues returns the values in ascending order. It returns false if any of them is null or NaN.
+func sortedValues(fv *Float64Field) ([]float64, bool) {
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	sort.Float64s(values)
+	return values, true
+}
+
+// GetReduceFunc returns the reduction function of the reducer. The reducers offered by the expression editor are
+// checked against the implemented ones in public/app/features/expressions/types.test.ts.
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,6 +244,22 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerP90:
+		return Percentile(90), nil
+	case ReducerP95:
+		return Percentile(95), nil
+	case ReducerP99:
+		return Percentile(99), nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -543,3 +543,177 @@ func sortedFloat64(f []float64) []float64 {
 	sort.Float64s(f)
 	return f
 }
+
+func TestSeriesReduceStatistics(t *testing.T) {
+	unordered := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("temp", nil,
+				tp{time.Unix(5, 0), float64Pointer(3)},
+				tp{time.Unix(10, 0), float64Pointer(1)},
+				tp{time.Unix(15, 0), float64Pointer(4)},
+				tp{time.Unix(20, 0), float64Pointer(2)},
+				tp{time.Unix(25, 0), float64Pointer(10)},
+			),
+		),
+	}
+
+	var tests = []struct {
+		name        string
+		red         ReducerID
+		mapper      ReduceMapper
+		vars        Vars
+		varToReduce string
+		results     Results
+	}{
+		{
+			name:        "p90 series",
+			red:         "p90",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(7.6))),
+		},
+		{
+			name:        "p95 series",
+			red:         "p95",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(8.8))),
+		},
+		{
+			name:        "p99 series",
+			red:         "p99",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(9.76))),
+		},
+		{
+			name:        "p99 series with a nil value",
+			red:         "p99",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "dropNN: p99 series with a nil value",
+			red:         "p99",
+			mapper:      DropNonNumber{},
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "dropNN: p90 empty series",
+			red:         "p90",
+			mapper:      DropNonNumber{},
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(math.Sqrt(10)))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "replaceNN: stddev series with a nil value",
+			red:         "stddev",
+			mapper:      ReplaceNonNumberWithValue{Value: 4},
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(3))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(7))),
+		},
+		{
+			name:        "diff series with a nil value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "dropNN: diff series with a nil value",
+			red:         "diff",
+			mapper:      DropNonNumber{},
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0))),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        unordered,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(9))),
+		},
+		{
+			name:        "range empty series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "replaceNN: count_non_null series with a nil value",
+			red:         "count_non_null",
+			mapper:      ReplaceNonNumberWithValue{Value: 4},
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Results{}
+			seriesSet := tt.vars[tt.varToReduce]
+			for _, series := range seriesSet.Values {
+				ns, err := series.Value().(*Series).Reduce("", tt.red, tt.mapper)
+				require.NoError(t, err)
+				results.Values = append(results.Values, ns)
+			}
+			opt := cmp.Comparer(func(x, y float64) bool {
+				return (math.IsNaN(x) && math.IsNaN(y)) || math.Abs(x-y) < 1e-9
+			})
+			options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+			if diff := cmp.Diff(tt.results, results, options...); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,10 +20,24 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Interpolate linearly between the previous and next value
+	UpsamplerLinear Upsampler = "linear"
+
+	// Use the value closest in time
+	UpsamplerNearest Upsampler = "nearest"
 )
 
 // Resample turns the Series into a Number based on the given reduction function
 func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
+	return s.ResampleWithMaxGap(refID, interval, downsampler, upsampler, 0, from, to)
+}
+
+// ResampleWithMaxGap is like Resample, but limits how far the linear and nearest upsamplers
+// reach for a value. For linear the previous and next value must be at most maxGap apart,
+// for nearest the closest value must be at most maxGap away. Otherwise the value is null.
+// A maxGap of 0 means there is no limit.
+func (s Series) ResampleWithMaxGap(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, maxGap time.Duration, from, to time.Time) (Series, error) {
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
@@ -31,6 +45,8 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
+	hasLastSeen := false
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -47,6 +63,8 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
+			hasLastSeen = true
 			vals = append(vals, v)
 		}
 		var value *float64
@@ -66,6 +84,21 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear:
+				if hasLastSeen && sIdx < s.Len() {
+					nextTime, next := s.GetPoint(sIdx)
+					value = interpolate(t, lastSeenTime, lastSeen, nextTime, next, maxGap)
+				}
+			case UpsamplerNearest:
+				var prev, next *point
+				if hasLastSeen {
+					prev = &point{t: lastSeenTime, v: lastSeen}
+				}
+				if sIdx < s.Len() {
+					nextTime, nextValue := s.GetPoint(sIdx)
+					next = &point{t: nextTime, v: nextValue}
+				}
+				value = nearest(t, prev, next, maxGap)
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
@@ -74,22 +107,11 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 		} else { // downsampling
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
+			reduceFunc, err := GetReduceFunc(downsampler)
+			if err != nil {
 				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
 			}
-			value = tmp
+			value = reduceFunc(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
@@ -97,3 +119,40 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	}
 	return resampled, nil
 }
+
+type point struct {
+	t time.Time
+	v *float64
+}
+
+// interpolate returns the value at t on the line between the previous and the next point,
+// or nil if either value is null or the points are more than maxGap apart.
+func interpolate(t, prevTime time.Time, prev *float64, nextTime time.Time, next *float64, maxGap time.Duration) *float64 {
+	if prev == nil || next == nil {
+		return nil
+	}
+	span := nextTime.Sub(prevTime)
+	if span <= 0 || (maxGap > 0 && span > maxGap) {
+		return nil
+	}
+	ratio := float64(t.Sub(prevTime)) / float64(span)
+	v := *prev + (*next-*prev)*ratio
+	return &v
+}
+
+// nearest returns the value of the point closest to t, preferring the previous point on a tie,
+// or nil if there is no point within maxGap.
+func nearest(t time.Time, prev, next *point, maxGap time.Duration) *float64 {
+	var closest *point
+	var distance time.Duration
+	if prev != nil {
+		closest, distance = prev, t.Sub(prev.t)
+	}
+	if next != nil && (closest == nil || next.t.Sub(t) < distance) {
+		closest, distance = next, next.t.Sub(t)
+	}
+	if closest == nil || (maxGap > 0 && distance > maxGap) {
+		return nil
+	}
+	return closest.v
+}
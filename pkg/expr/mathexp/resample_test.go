@@ -290,3 +290,86 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestResampleSeriesInterpolation(t *testing.T) {
+	seriesToResample := makeSeries("", nil, tp{
+		time.Unix(0, 0), float64Pointer(0),
+	}, tp{
+		time.Unix(4, 0), float64Pointer(8),
+	}, tp{
+		time.Unix(12, 0), float64Pointer(4),
+	})
+	timeRange := backend.TimeRange{
+		From: time.Unix(0, 0),
+		To:   time.Unix(12, 0),
+	}
+
+	var tests = []struct {
+		name      string
+		upsampler Upsampler
+		maxGap    time.Duration
+		series    Series
+	}{
+		{
+			name:      "resample series: upsampling (linear)",
+			upsampler: "linear",
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(4)},
+				tp{time.Unix(4, 0), float64Pointer(8)},
+				tp{time.Unix(6, 0), float64Pointer(7)},
+				tp{time.Unix(8, 0), float64Pointer(6)},
+				tp{time.Unix(10, 0), float64Pointer(5)},
+				tp{time.Unix(12, 0), float64Pointer(4)},
+			),
+		},
+		{
+			name:      "resample series: upsampling (linear) with max gap",
+			upsampler: "linear",
+			maxGap:    5 * time.Second,
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(4)},
+				tp{time.Unix(4, 0), float64Pointer(8)},
+				tp{time.Unix(6, 0), nil},
+				tp{time.Unix(8, 0), nil},
+				tp{time.Unix(10, 0), nil},
+				tp{time.Unix(12, 0), float64Pointer(4)},
+			),
+		},
+		{
+			name:      "resample series: upsampling (nearest)",
+			upsampler: "nearest",
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(0)},
+				tp{time.Unix(4, 0), float64Pointer(8)},
+				tp{time.Unix(6, 0), float64Pointer(8)},
+				tp{time.Unix(8, 0), float64Pointer(8)},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+				tp{time.Unix(12, 0), float64Pointer(4)},
+			),
+		},
+		{
+			name:      "resample series: upsampling (nearest) with max gap",
+			upsampler: "nearest",
+			maxGap:    3 * time.Second,
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(0)},
+				tp{time.Unix(4, 0), float64Pointer(8)},
+				tp{time.Unix(6, 0), float64Pointer(8)},
+				tp{time.Unix(8, 0), nil},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+				tp{time.Unix(12, 0), float64Pointer(4)},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := seriesToResample.ResampleWithMaxGap("", 2*time.Second, "mean", tt.upsampler, tt.maxGap, timeRange.From, timeRange.To)
+			require.NoError(t, err)
+			assert.Equal(t, tt.series, series)
+		})
+	}
+}
@@ -29,6 +29,9 @@ const (
 
 	// SQL query via DuckDB
 	QueryTypeSQL QueryType = "sql"
+
+	// Detect anomalies in time series
+	QueryTypeAnomaly QueryType = "anomaly"
 )
 
 type MathQuery struct {
@@ -60,6 +63,9 @@ type ResampleQuery struct {
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// The largest gap the linear and nearest upsamplers will fill
+	MaxGap string `json:"maxGap,omitempty" jsonschema:"example=5m"`
 }
 
 type ThresholdQuery struct {
@@ -79,6 +85,27 @@ type SQLExpression struct {
 	Expression string `json:"expression" jsonschema:"minLength=1,example=SELECT * FROM A LIMIT 1"`
 }
 
+// QueryType = anomaly
+type AnomalyQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// How the expected range is calculated
+	Method AnomalyMethod `json:"method"`
+
+	// The trailing window the expected range is calculated from
+	Window string `json:"window" jsonschema:"minLength=1,example=1h,example=30m"`
+
+	// The length of a season for the seasonal method, defaults to 1w
+	Season string `json:"season,omitempty" jsonschema:"example=1w,example=1d"`
+
+	// How many deviations from the center a value can be before it is anomalous, defaults to 3
+	Deviations *float64 `json:"deviations,omitempty"`
+
+	// Which series to return, defaults to all
+	Output AnomalyOutput `json:"output,omitempty"`
+}
+
 //-------------------------------
 // Non-query commands
 //-------------------------------
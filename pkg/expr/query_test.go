@@ -26,6 +26,8 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				reflect.TypeOf(mathexp.UpsamplerPad), // pick an example value (not the root)
 				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
+				reflect.TypeOf(AnomalyMethodZScore),
+				reflect.TypeOf(AnomalyOutputAll),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
 			},
 		})
@@ -92,6 +94,21 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAnomaly),
+			GoType:         reflect.TypeOf(&AnomalyQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "compare A to the same hour last week",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Method:     AnomalyMethodSeasonal,
+						Window:     "1h",
+						Season:     "1w",
+					}),
+				},
+			},
+		},
 		schemabuilder.QueryTypeInfo{
 			Discriminators: data.NewDiscriminators("type", QueryTypeClassic),
 			GoType:         reflect.TypeOf(&ClassicQuery{}),
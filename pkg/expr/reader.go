@@ -110,6 +110,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 				referenceVar,
 				q.Downsampler,
 				q.Upsampler,
+				q.MaxGap,
 				AbsoluteTimeRange{
 					From: tr.GetFromAsTimeUTC(),
 					To:   tr.GetToAsTimeUTC(),
@@ -128,7 +129,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 	case QueryTypeSQL:
 		enabled := enableSqlExpressions(h)
 		if !enabled {
-			return eq, fmt.Errorf("sqlExpressions is not implemented")
+			return eq, fmt.Errorf("sqlExpressions feature is not enabled")
 		}
 		q := &SQLExpression{}
 		err = iter.ReadVal(q)
@@ -137,6 +138,24 @@ func (h *ExpressionQueryReader) ReadQuery(
 			eq.Command, err = NewSQLCommand(common.RefID, q.Expression)
 		}
 
+	case QueryTypeAnomaly:
+		q := &AnomalyQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewAnomalyCommand(common.RefID,
+				referenceVar,
+				q.Method,
+				q.Window,
+				q.Season,
+				q.Deviations,
+				q.Output,
+			)
+		}
+
 	case QueryTypeThreshold:
 		q := &ThresholdQuery{}
 		err = iter.ReadVal(q)
@@ -192,9 +211,5 @@ func getReferenceVar(exp string, refId string) (string, error) {
 }
 
 func enableSqlExpressions(h *ExpressionQueryReader) bool {
-	enabled := !h.features.IsEnabledGlobally(featuremgmt.FlagSqlExpressions)
-	if enabled {
-		return false
-	}
-	return false
+	return h.features.IsEnabledGlobally(featuremgmt.FlagSqlExpressions)
 }
@@ -1,26 +1,544 @@
 package sql
 
 import (
+	"context"
+	gosql "database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
 )
 
+// sqliteRecursive is the authorizer action code for recursive CTEs, which the
+// sqlite3 driver does not export.
+const sqliteRecursive = 33
+
+const defaultTimeout = 30 * time.Second
+
+// DB is an embedded, in-memory SQL engine used to run SQL expressions
+// against data frames. Every call works on its own private database, so
+// nothing is shared between queries.
 type DB struct {
+	// Timeout bounds how long a single query may run.
+	Timeout time.Duration
 }
 
+// TablesList returns the tables referenced by the SQL statement. The statement
+// is first checked for syntax errors.
 func (db *DB) TablesList(rawSQL string) ([]string, error) {
-	return nil, errors.New("not implemented")
+	ctx, cancel := db.withTimeout(context.Background())
+	defer cancel()
+
+	conn, closeFn, err := db.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+
+	// Tables do not exist yet, so only syntax errors are relevant here;
+	// unknown tables, columns and functions are reported on execution.
+	stmt, err := conn.PrepareContext(ctx, rawSQL)
+	if err != nil {
+		if isSyntaxError(err) {
+			return nil, fmt.Errorf("error in sql: %w", err)
+		}
+	} else {
+		_ = stmt.Close()
+	}
+
+	return tablesFromTokens(tokenize(rawSQL))
 }
 
+// RunCommands executes the commands in order on a fresh database and returns the
+// rows of the last command as JSON.
 func (db *DB) RunCommands(commands []string) (string, error) {
-	return "", errors.New("not implemented")
+	if len(commands) == 0 {
+		return "", nil
+	}
+
+	ctx, cancel := db.withTimeout(context.Background())
+	defer cancel()
+
+	conn, closeFn, err := db.open(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer closeFn()
+
+	last := len(commands) - 1
+	for _, cmd := range commands[:last] {
+		if _, err := conn.ExecContext(ctx, cmd); err != nil {
+			return "", err
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, commands[last])
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	result := []map[string]any{}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return "", err
+		}
+		row := make(map[string]any, len(columns))
+		for i, c := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[c] = values[i]
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
 }
 
-func (db *DB) QueryFramesInto(name string, query string, frames []*data.Frame, f *data.Frame) error {
-	return errors.New("not implemented")
+// QueryFramesInto loads every frame as a table named after its RefID, runs the
+// query and writes the result into f. Frames sharing a RefID are merged into a
+// single table, and labels become string columns.
+func (db *DB) QueryFramesInto(ctx context.Context, name string, query string, frames []*data.Frame, f *data.Frame) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	conn, closeFn, err := db.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	for _, t := range tablesFromFrames(frames) {
+		if err := t.load(ctx, conn); err != nil {
+			return fmt.Errorf("failed to load table %q: %w", t.name, err)
+		}
+	}
+
+	// Everything after this point runs user input, which may only read.
+	err = conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+		c.RegisterAuthorizer(readOnlyAuthorizer)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	f.Name = name
+	f.Fields = nil
+	return readRows(rows, f)
 }
 
+// NewInMemoryDB returns a DB with default settings.
 func NewInMemoryDB() *DB {
-	return &DB{}
+	return &DB{
+		Timeout: defaultTimeout,
+	}
+}
+
+func (db *DB) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := db.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (db *DB) open(ctx context.Context) (*gosql.Conn, func(), error) {
+	sqlDB, err := gosql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, nil, err
+	}
+	// Every connection to :memory: is a separate database, so use one.
+	sqlDB.SetMaxOpenConns(1)
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, nil, err
+	}
+
+	return conn, func() {
+		_ = conn.Close()
+		_ = sqlDB.Close()
+	}, nil
+}
+
+func readOnlyAuthorizer(action int, _, _, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+		return sqlite3.SQLITE_OK
+	default:
+		return sqlite3.SQLITE_DENY
+	}
+}
+
+func isSyntaxError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	msg := sqliteErr.Error()
+	return strings.Contains(msg, "syntax error") ||
+		strings.Contains(msg, "incomplete input") ||
+		strings.Contains(msg, "unrecognized token")
+}
+
+type column struct {
+	name    string
+	sqlType string
+}
+
+type table struct {
+	name    string
+	columns []column
+	index   map[string]int
+	rows    []map[string]any
+}
+
+func (t *table) addColumn(name, sqlType string) {
+	if _, ok := t.index[name]; ok {
+		return
+	}
+	t.index[name] = len(t.columns)
+	t.columns = append(t.columns, column{name: name, sqlType: sqlType})
+}
+
+// tablesFromFrames groups frames by RefID, keeping the order in which the
+// RefIDs were first seen.
+func tablesFromFrames(frames []*data.Frame) []*table {
+	tables := []*table{}
+	byName := map[string]*table{}
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		t, ok := byName[frame.RefID]
+		if !ok {
+			t = &table{name: frame.RefID, index: map[string]int{}}
+			byName[frame.RefID] = t
+			tables = append(tables, t)
+		}
+		t.addFrame(frame)
+	}
+	return tables
+}
+
+func (t *table) addFrame(frame *data.Frame) {
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeWide && hasLabels(frame) {
+		if long, err := data.WideToLong(frame); err == nil {
+			frame = long
+		}
+	}
+
+	names := make([]string, len(frame.Fields))
+	labels := map[string]string{}
+	seen := map[string]bool{}
+	for i, field := range frame.Fields {
+		base := field.Name
+		if base == "" {
+			base = "value"
+		}
+		name := base
+		for j := 1; seen[name]; j++ {
+			name = fmt.Sprintf("%s_%d", base, j)
+		}
+		seen[name] = true
+		names[i] = name
+		t.addColumn(name, sqlType(field.Type()))
+		for k, v := range field.Labels {
+			if _, ok := labels[k]; !ok {
+				labels[k] = v
+			}
+		}
+	}
+
+	labelKeys := make([]string, 0, len(labels))
+	for k := range labels {
+		if !seen[k] {
+			labelKeys = append(labelKeys, k)
+		}
+	}
+	sort.Strings(labelKeys)
+	for _, k := range labelKeys {
+		t.addColumn(k, "TEXT")
+	}
+
+	for row := 0; row < frame.Rows(); row++ {
+		values := make(map[string]any, len(names)+len(labelKeys))
+		for i, field := range frame.Fields {
+			v, ok := field.ConcreteAt(row)
+			if !ok {
+				continue
+			}
+			values[names[i]] = sqlValue(v)
+		}
+		for _, k := range labelKeys {
+			values[k] = labels[k]
+		}
+		t.rows = append(t.rows, values)
+	}
+}
+
+func (t *table) load(ctx context.Context, conn *gosql.Conn) error {
+	if len(t.columns) == 0 {
+		return nil
+	}
+
+	defs := make([]string, len(t.columns))
+	names := make([]string, len(t.columns))
+	params := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = quoteIdentifier(c.name)
+		defs[i] = names[i] + " " + c.sqlType
+		params[i] = "?"
+	}
+
+	create := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(t.name), strings.Join(defs, ", "))
+	if _, err := conn.ExecContext(ctx, create); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(t.name), strings.Join(names, ", "), strings.Join(params, ", "))
+	stmt, err := tx.PrepareContext(ctx, insert)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	args := make([]any, len(t.columns))
+	for _, row := range t.rows {
+		for i, c := range t.columns {
+			args[i] = row[c.name]
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func hasLabels(frame *data.Frame) bool {
+	for _, field := range frame.Fields {
+		if len(field.Labels) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sqlType(ft data.FieldType) string {
+	switch ft.NonNullableType() {
+	case data.FieldTypeTime:
+		return "TIMESTAMP"
+	case data.FieldTypeBool:
+		return "BOOLEAN"
+	case data.FieldTypeInt8, data.FieldTypeInt16, data.FieldTypeInt32, data.FieldTypeInt64,
+		data.FieldTypeUint8, data.FieldTypeUint16, data.FieldTypeUint32, data.FieldTypeUint64:
+		return "INTEGER"
+	case data.FieldTypeFloat32, data.FieldTypeFloat64:
+		return "REAL"
+	default:
+		return "TEXT"
+	}
+}
+
+func sqlValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC()
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case json.RawMessage:
+		return string(v)
+	default:
+		return v
+	}
+}
+
+// readRows converts the result set into fields on f. SQLite is dynamically
+// typed, so the field type is picked from the values that came back, falling
+// back to the declared column type when there are none.
+func readRows(rows *gosql.Rows, f *data.Frame) error {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	values := make([][]any, len(columnTypes))
+	for rows.Next() {
+		row := make([]any, len(columnTypes))
+		pointers := make([]any, len(columnTypes))
+		for i := range row {
+			pointers[i] = &row[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		for i, v := range row {
+			values[i] = append(values[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, ct := range columnTypes {
+		f.Fields = append(f.Fields, fieldFromValues(ct.Name(), ct.DatabaseTypeName(), values[i]))
+	}
+	return nil
+}
+
+func fieldFromValues(name string, dbType string, values []any) *data.Field {
+	ft := fieldTypeFromValues(dbType, values)
+	field := data.NewFieldFromFieldType(ft, len(values))
+	field.Name = name
+
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		switch ft {
+		case data.FieldTypeNullableTime:
+			t := v.(time.Time).UTC()
+			field.Set(i, &t)
+		case data.FieldTypeNullableBool:
+			var b bool
+			switch v := v.(type) {
+			case bool:
+				b = v
+			case int64:
+				b = v != 0
+			}
+			field.Set(i, &b)
+		case data.FieldTypeNullableInt64:
+			n := v.(int64)
+			field.Set(i, &n)
+		case data.FieldTypeNullableFloat64:
+			var n float64
+			switch v := v.(type) {
+			case float64:
+				n = v
+			case int64:
+				n = float64(v)
+			}
+			field.Set(i, &n)
+		default:
+			var s string
+			switch v := v.(type) {
+			case string:
+				s = v
+			case []byte:
+				s = string(v)
+			default:
+				s = fmt.Sprintf("%v", v)
+			}
+			field.Set(i, &s)
+		}
+	}
+	return field
+}
+
+func fieldTypeFromValues(dbType string, values []any) data.FieldType {
+	var hasInt, hasFloat, hasTime, hasBool, hasOther bool
+	for _, v := range values {
+		switch v.(type) {
+		case nil:
+		case int64:
+			hasInt = true
+		case float64:
+			hasFloat = true
+		case time.Time:
+			hasTime = true
+		case bool:
+			hasBool = true
+		default:
+			hasOther = true
+		}
+	}
+
+	switch {
+	case hasOther:
+		return data.FieldTypeNullableString
+	case hasTime && !hasInt && !hasFloat && !hasBool:
+		return data.FieldTypeNullableTime
+	case hasBool && !hasFloat && !hasTime:
+		return data.FieldTypeNullableBool
+	case hasFloat && !hasTime && !hasBool:
+		return data.FieldTypeNullableFloat64
+	case hasInt && !hasTime && !hasBool:
+		return data.FieldTypeNullableInt64
+	case hasInt || hasFloat || hasTime || hasBool:
+		return data.FieldTypeNullableString
+	}
+
+	switch strings.ToUpper(dbType) {
+	case "TIMESTAMP", "DATETIME", "DATE":
+		return data.FieldTypeNullableTime
+	case "BOOLEAN":
+		return data.FieldTypeNullableBool
+	case "INTEGER":
+		return data.FieldTypeNullableInt64
+	case "REAL":
+		return data.FieldTypeNullableFloat64
+	default:
+		return data.FieldTypeNullableString
+	}
 }
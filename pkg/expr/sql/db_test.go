@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFramesInto(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	seriesA := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{now, now.Add(time.Minute)}),
+		data.NewField("value", data.Labels{"host": "a"}, []float64{1, 2}),
+	)
+	seriesA.RefID = "A"
+	seriesB := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{now, now.Add(time.Minute)}),
+		data.NewField("value", data.Labels{"host": "b"}, []float64{10, 20}),
+	)
+	seriesB.RefID = "A"
+	lookup := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b"}),
+		data.NewField("team", nil, []string{"red", "blue"}),
+	)
+	lookup.RefID = "B"
+
+	frames := []*data.Frame{seriesA, seriesB, lookup}
+
+	t.Run("join and group by", func(t *testing.T) {
+		db := NewInMemoryDB()
+		f := &data.Frame{}
+		err := db.QueryFramesInto(context.Background(), "C", `
+			SELECT B.team, sum(A.value) AS total
+			FROM A JOIN B ON A.host = B.host
+			GROUP BY B.team
+			ORDER BY B.team`, frames, f)
+		require.NoError(t, err)
+
+		require.Equal(t, "C", f.Name)
+		require.Len(t, f.Fields, 2)
+		require.Equal(t, 2, f.Rows())
+		assert.Equal(t, "team", f.Fields[0].Name)
+		assert.Equal(t, "blue", *f.Fields[0].At(0).(*string))
+		assert.Equal(t, "red", *f.Fields[0].At(1).(*string))
+		assert.Equal(t, 30.0, *f.Fields[1].At(0).(*float64))
+		assert.Equal(t, 3.0, *f.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("window functions and time columns", func(t *testing.T) {
+		db := NewInMemoryDB()
+		f := &data.Frame{}
+		err := db.QueryFramesInto(context.Background(), "C", `
+			SELECT time, host, value - lag(value) OVER (PARTITION BY host ORDER BY time) AS delta
+			FROM A
+			WHERE host = 'b'
+			ORDER BY time`, frames, f)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, f.Rows())
+		assert.Equal(t, data.FieldTypeNullableTime, f.Fields[0].Type())
+		assert.Equal(t, now.Add(time.Minute), *f.Fields[0].At(1).(*time.Time))
+		assert.Nil(t, f.Fields[2].At(0))
+		assert.Equal(t, 10.0, *f.Fields[2].At(1).(*float64))
+	})
+
+	t.Run("integers stay integers", func(t *testing.T) {
+		db := NewInMemoryDB()
+		f := &data.Frame{}
+		err := db.QueryFramesInto(context.Background(), "C", `SELECT count(*) AS n FROM A`, frames, f)
+		require.NoError(t, err)
+
+		require.Equal(t, data.FieldTypeNullableInt64, f.Fields[0].Type())
+		assert.Equal(t, int64(4), *f.Fields[0].At(0).(*int64))
+	})
+
+	t.Run("writes are not allowed", func(t *testing.T) {
+		for _, query := range []string{
+			"DELETE FROM A",
+			"DROP TABLE B",
+			"ATTACH DATABASE 'other.db' AS other",
+			"PRAGMA table_info(A)",
+		} {
+			db := NewInMemoryDB()
+			err := db.QueryFramesInto(context.Background(), "C", query, frames, &data.Frame{})
+			assert.Error(t, err, query)
+		}
+	})
+
+	t.Run("unknown table", func(t *testing.T) {
+		db := NewInMemoryDB()
+		err := db.QueryFramesInto(context.Background(), "C", "SELECT * FROM Z", frames, &data.Frame{})
+		assert.ErrorContains(t, err, "no such table")
+	})
+}
+
+func TestRunCommands(t *testing.T) {
+	db := NewInMemoryDB()
+	out, err := db.RunCommands([]string{
+		"CREATE TABLE t (a INTEGER, b TEXT)",
+		"INSERT INTO t VALUES (1, 'x')",
+		"SELECT a, b FROM t",
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"a":1,"b":"x"}]`, out)
+}
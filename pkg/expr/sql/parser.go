@@ -1,82 +1,261 @@
 package sql
 
 import (
-	"encoding/json"
-	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/log"
-	"github.com/jeremywohl/flatten"
-)
-
-const (
-	TABLE_NAME    = "table_name"
-	ERROR         = ".error"
-	ERROR_MESSAGE = ".error_message"
 )
 
 var logger = log.New("sql_expr")
 
 // TablesList returns a list of tables for the sql statement
 func TablesList(rawSQL string) ([]string, error) {
-	duckDB := NewInMemoryDB()
-	rawSQL = strings.Replace(rawSQL, "'", "''", -1)
-	cmd := fmt.Sprintf("SELECT json_serialize_sql('%s')", rawSQL)
-	ret, err := duckDB.RunCommands([]string{cmd})
+	tables, err := NewInMemoryDB().TablesList(rawSQL)
 	if err != nil {
-		logger.Error("error serializing sql", "error", err.Error(), "sql", rawSQL, "cmd", cmd)
-		return nil, fmt.Errorf("error serializing sql: %s", err.Error())
+		logger.Error("error parsing sql", "error", err.Error(), "sql", rawSQL)
+		return nil, err
 	}
 
-	ast := []map[string]any{}
-	err = json.Unmarshal([]byte(ret), &ast)
-	if err != nil {
-		logger.Error("error converting json sql to ast", "error", err.Error(), "ret", ret)
-		return nil, fmt.Errorf("error converting json to ast: %s", err.Error())
+	logger.Debug("tables found in sql", "tables", tables)
+
+	return tables, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// is reports whether the token is the given keyword or punctuation.
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokenIdent:
+		return strings.EqualFold(t.value, s)
+	case tokenPunct:
+		return t.value == s
+	default:
+		return false
 	}
+}
 
-	return tablesFromAST(ast)
+func (t token) isName() bool {
+	return t.kind == tokenQuotedIdent || (t.kind == tokenIdent && !isKeyword(t.value))
 }
 
-// tablesFromAST returns a list of tables from the ast
-func tablesFromAST(ast []map[string]any) ([]string, error) {
-	flat, err := flatten.Flatten(ast[0], "", flatten.DotStyle)
-	if err != nil {
-		logger.Error("error flattening ast", "error", err.Error(), "ast", ast)
-		return nil, fmt.Errorf("error flattening ast: %s", err.Error())
+// tokenize splits the statement into tokens, dropping whitespace and comments.
+func tokenize(rawSQL string) []token {
+	tokens := []token{}
+	s := rawSQL
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				i = len(s)
+			} else {
+				i += end + 4
+			}
+		case c == '\'':
+			value, n := readQuoted(s[i:], '\'')
+			tokens = append(tokens, token{kind: tokenString, value: value})
+			i += n
+		case c == '"' || c == '`':
+			value, n := readQuoted(s[i:], c)
+			tokens = append(tokens, token{kind: tokenQuotedIdent, value: value})
+			i += n
+		case c == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				end = len(s) - i
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdent, value: s[i+1 : i+end]})
+			i += end + 1
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && isIdentPart(s[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: s[i:j]})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(s) && (isIdentPart(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: s[i:j]})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, value: string(c)})
+			i++
+		}
 	}
+	return tokens
+}
+
+// readQuoted reads a quoted string starting at s[0], where a doubled quote
+// character is an escaped quote. It returns the unquoted value and the number
+// of bytes consumed.
+func readQuoted(s string, quote byte) (string, int) {
+	var sb strings.Builder
+	i := 1
+	for i < len(s) {
+		if s[i] == quote {
+			if i+1 < len(s) && s[i+1] == quote {
+				sb.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return sb.String(), i + 1
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+	return sb.String(), i
+}
 
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c == '$' || (c >= '0' && c <= '9')
+}
+
+// tablesFromTokens returns the sorted, de-duplicated list of tables that the
+// statement reads from. Names defined by common table expressions are not
+// tables and are left out.
+func tablesFromTokens(tokens []token) ([]string, error) {
+	ctes := map[string]bool{}
 	tables := []string{}
-	for k, v := range flat {
-		if strings.HasSuffix(k, ERROR) {
-			v, ok := v.(bool)
-			if ok && v {
-				logger.Error("error in sql", "error", k)
-				return nil, astError(k, flat)
+
+	at := func(i int) token {
+		if i < 0 || i >= len(tokens) {
+			return token{kind: tokenPunct}
+		}
+		return tokens[i]
+	}
+
+	expectTable := false
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		// name [(columns)] AS ( ... ) following WITH, RECURSIVE or a comma
+		if tok.isName() && (at(i-1).is("WITH") || at(i-1).is("RECURSIVE") || at(i-1).is(",")) {
+			j := i + 1
+			if at(j).is("(") {
+				j = skipParens(tokens, j)
+			}
+			if at(j).is("AS") && at(j+1).is("(") {
+				ctes[strings.ToLower(tok.value)] = true
+				continue
 			}
 		}
-		if strings.Contains(k, TABLE_NAME) {
-			table, ok := v.(string)
-			if ok && !existsInList(table, tables) {
-				tables = append(tables, v.(string))
+
+		if tok.is("FROM") || tok.is("JOIN") {
+			expectTable = true
+			continue
+		}
+		if !expectTable {
+			continue
+		}
+
+		switch {
+		case tok.is("("):
+			// a parenthesised join keeps looking for a table, a subquery does not
+			next := at(i + 1)
+			if next.is("SELECT") || next.is("WITH") || next.is("VALUES") {
+				expectTable = false
+			}
+		case tok.isName():
+			name := tok.value
+			// schema qualified: keep the table part
+			for at(i+1).is(".") && at(i+2).isName() {
+				name = at(i + 2).value
+				i += 2
 			}
+			if at(i + 1).is("(") {
+				// table-valued function
+				i = skipParens(tokens, i+1) - 1
+			} else if !existsInList(name, tables) {
+				tables = append(tables, name)
+			}
+			// optional alias
+			if at(i + 1).is("AS") {
+				i++
+			}
+			if at(i + 1).isName() {
+				i++
+			}
+			// comma separated tables continue the FROM list
+			if at(i + 1).is(",") {
+				i++
+			} else {
+				expectTable = false
+			}
+		default:
+			expectTable = false
 		}
 	}
-	sort.Strings(tables)
 
-	logger.Debug("tables found in sql", "tables", tables)
+	result := []string{}
+	for _, t := range tables {
+		if !ctes[strings.ToLower(t)] {
+			result = append(result, t)
+		}
+	}
+	sort.Strings(result)
 
-	return tables, nil
+	return result, nil
 }
 
-func astError(k string, flat map[string]any) error {
-	key := strings.Replace(k, ERROR, "", 1)
-	message, ok := flat[key+ERROR_MESSAGE]
-	if !ok {
-		message = "unknown error in sql"
+// skipParens returns the index after the parenthesis group that starts at i.
+func skipParens(tokens []token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
 	}
-	return fmt.Errorf("error in sql: %s", message)
+	return i
+}
+
+func isKeyword(s string) bool {
+	_, ok := keywords[strings.ToUpper(s)]
+	return ok
+}
+
+// keywords that may follow a table reference and so can not be an alias.
+var keywords = map[string]struct{}{
+	"ALL": {}, "AND": {}, "AS": {}, "ASC": {}, "BY": {}, "CROSS": {}, "DESC": {},
+	"EXCEPT": {}, "FROM": {}, "FULL": {}, "GROUP": {}, "HAVING": {}, "IN": {},
+	"INDEXED": {}, "INNER": {}, "INTERSECT": {}, "JOIN": {}, "LEFT": {}, "LIMIT": {},
+	"NATURAL": {}, "NOT": {}, "OFFSET": {}, "ON": {}, "OR": {}, "ORDER": {},
+	"OUTER": {}, "RECURSIVE": {}, "RIGHT": {}, "SELECT": {}, "UNION": {}, "USING": {},
+	"VALUES": {}, "WHERE": {}, "WINDOW": {}, "WITH": {},
 }
 
 func existsInList(table string, list []string) bool {
@@ -7,7 +7,6 @@ import (
 )
 
 func TestParse(t *testing.T) {
-	t.Skip()
 	sql := "select * from foo"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -16,7 +15,6 @@ func TestParse(t *testing.T) {
 }
 
 func TestParseWithComma(t *testing.T) {
-	t.Skip()
 	sql := "select * from foo,bar"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -26,7 +24,6 @@ func TestParseWithComma(t *testing.T) {
 }
 
 func TestParseWithCommas(t *testing.T) {
-	t.Skip()
 	sql := "select * from foo,bar,baz"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -37,7 +34,6 @@ func TestParseWithCommas(t *testing.T) {
 }
 
 func TestArray(t *testing.T) {
-	t.Skip()
 	sql := "SELECT array_value(1, 2, 3)"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -46,7 +42,6 @@ func TestArray(t *testing.T) {
 }
 
 func TestArray2(t *testing.T) {
-	t.Skip()
 	sql := "SELECT array_value(1, 2, 3)[2]"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -54,9 +49,8 @@ func TestArray2(t *testing.T) {
 	assert.Equal(t, 0, len(tables))
 }
 
-func TestXxx(t *testing.T) {
-	t.Skip()
-	sql := "SELECT [3, 2, 1]::INT[3];"
+func TestCast(t *testing.T) {
+	sql := "SELECT CAST('3' AS INTEGER);"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
 
@@ -64,7 +58,6 @@ func TestXxx(t *testing.T) {
 }
 
 func TestParseSubquery(t *testing.T) {
-	t.Skip()
 	sql := "select * from (select * from people limit 1)"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -74,7 +67,6 @@ func TestParseSubquery(t *testing.T) {
 }
 
 func TestJoin(t *testing.T) {
-	t.Skip()
 	sql := `select * from A
 	JOIN B ON A.name = B.name
 	LIMIT 10`
@@ -87,7 +79,6 @@ func TestJoin(t *testing.T) {
 }
 
 func TestRightJoin(t *testing.T) {
-	t.Skip()
 	sql := `select * from A
 	RIGHT JOIN B ON A.name = B.name
 	LIMIT 10`
@@ -100,7 +91,6 @@ func TestRightJoin(t *testing.T) {
 }
 
 func TestAliasWithJoin(t *testing.T) {
-	t.Skip()
 	sql := `select * from A as X
 	RIGHT JOIN B ON A.name = X.name
 	LIMIT 10`
@@ -113,7 +103,6 @@ func TestAliasWithJoin(t *testing.T) {
 }
 
 func TestAlias(t *testing.T) {
-	t.Skip()
 	sql := `select * from A as X LIMIT 10`
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -123,14 +112,12 @@ func TestAlias(t *testing.T) {
 }
 
 func TestError(t *testing.T) {
-	t.Skip()
 	sql := `select * from zzz aaa zzz`
 	_, err := TablesList((sql))
 	assert.NotNil(t, err)
 }
 
 func TestParens(t *testing.T) {
-	t.Skip()
 	sql := `SELECT  t1.Col1,
 	t2.Col1,
 	t3.Col1
@@ -149,7 +136,6 @@ func TestParens(t *testing.T) {
 }
 
 func TestWith(t *testing.T) {
-	t.Skip()
 	sql := `WITH
 
 	current_month AS (
@@ -191,14 +177,13 @@ func TestWith(t *testing.T) {
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
 
-	assert.Equal(t, 5, len(tables))
+	assert.Equal(t, 3, len(tables))
 	assert.Equal(t, "A", tables[0])
 	assert.Equal(t, "B", tables[1])
 	assert.Equal(t, "BEE", tables[2])
 }
 
 func TestWithQuote(t *testing.T) {
-	t.Skip()
 	sql := "select *,'junk' from foo"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
@@ -207,10 +192,26 @@ func TestWithQuote(t *testing.T) {
 }
 
 func TestWithQuote2(t *testing.T) {
-	t.Skip()
 	sql := "SELECT json_serialize_sql('SELECT 1')"
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
 
 	assert.Equal(t, 0, len(tables))
 }
+
+func TestTableValuedFunction(t *testing.T) {
+	sql := "select value from A, json_each(A.list)"
+	tables, err := TablesList((sql))
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"A"}, tables)
+}
+
+func TestComments(t *testing.T) {
+	sql := `-- from foo
+	select * /* from bar */ from "B"`
+	tables, err := TablesList((sql))
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"B"}, tables)
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/authlib/claims"
+
+	"github.com/grafana/grafana/pkg/middleware/ratelimit"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultRateLimitGroup = "api"
+	deviceIDHeader        = "X-Grafana-Device-Id"
+)
+
+// RateLimit returns a middleware that limits the rate of the HTTP API requests of each identity, separately for
+// each route group. Signed in users, service accounts and API keys are limited by identity, anonymous users by
+// device and other requests by client IP. Requests are let through if the state of the limits can't be read.
+func RateLimit(cfg setting.RateLimitingSettings, limiter *ratelimit.Limiter) web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		path := c.Req.URL.Path
+		if !strings.HasPrefix(path, "/api/") {
+			return
+		}
+
+		if cfg.ExemptServerAdmins && c.IsSignedIn && c.SignedInUser != nil && c.SignedInUser.GetIsGrafanaAdmin() {
+			return
+		}
+
+		group, limit := rateLimitGroup(cfg, path)
+		if limit.RequestsPerSecond <= 0 {
+			return
+		}
+
+		res, err := limiter.Take(c.Req.Context(), group+":"+rateLimitKey(c), limit)
+		if err != nil {
+			c.Logger.Warn("Failed to check rate limit", "group", group, "error", err)
+			return
+		}
+
+		header := c.Resp.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(ceilSeconds(res.Reset.Seconds()), 10))
+
+		if !res.Allowed {
+			header.Set("Retry-After", strconv.FormatInt(ceilSeconds(res.RetryAfter.Seconds()), 10))
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many requests, please retry later", nil)
+			return
+		}
+	}
+}
+
+// rateLimitGroup returns the route group of the path with the longest matching path prefix,
+// or the default group if none matches.
+func rateLimitGroup(cfg setting.RateLimitingSettings, path string) (string, setting.RateLimit) {
+	group, limit, longest := defaultRateLimitGroup, cfg.Limit, 0
+	for _, g := range cfg.Groups {
+		for _, prefix := range g.PathPrefixes {
+			if len(prefix) > longest && strings.HasPrefix(path, prefix) {
+				group, limit, longest = g.Name, g.RateLimit, len(prefix)
+			}
+		}
+	}
+	return group, limit
+}
+
+// rateLimitKey returns the key identifying the requester.
+func rateLimitKey(c *contextmodel.ReqContext) string {
+	if c.SignedInUser != nil {
+		if c.SignedInUser.IsIdentityType(claims.TypeAnonymous) {
+			if deviceID := c.Req.Header.Get(deviceIDHeader); deviceID != "" {
+				return "anonymous:" + deviceID
+			}
+		} else if c.IsSignedIn {
+			return c.SignedInUser.GetID()
+		}
+	}
+	return "ip:" + c.RemoteAddr()
+}
+
+func ceilSeconds(seconds float64) int64 {
+	return int64(math.Ceil(seconds))
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/grafana/authlib/claims"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/middleware/ratelimit"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestMiddlewareRateLimit(t *testing.T) {
+	cfg := setting.RateLimitingSettings{
+		Enabled: true,
+		Limit:   setting.RateLimit{RequestsPerSecond: 0.001, Burst: 2},
+		Groups: []setting.RateLimitGroup{
+			{Name: "query", PathPrefixes: []string{"/api/ds/query"}, RateLimit: setting.RateLimit{RequestsPerSecond: 0.001, Burst: 1}},
+		},
+	}
+
+	setUp := func(sc *scenarioContext, cfg setting.RateLimitingSettings) {
+		rateLimit := RateLimit(cfg, ratelimit.NewLimiter(remotecache.NewFakeCacheStorage()))
+		sc.m.Get("/api/dashboards", rateLimit, sc.defaultHandler)
+		sc.m.Post("/api/ds/query", rateLimit, sc.defaultHandler)
+		sc.m.Get("/public/build/app.js", rateLimit, sc.defaultHandler)
+	}
+
+	middlewareScenario(t, "should reject requests over the limit with rate limit headers", func(t *testing.T, sc *scenarioContext) {
+		sc.withIdentity(&authn.Identity{ID: "1", Type: claims.TypeUser})
+		setUp(sc, cfg)
+
+		sc.fakeReq("GET", "/api/dashboards").exec()
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "2", sc.resp.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "1", sc.resp.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, sc.resp.Header().Get("X-RateLimit-Reset"))
+
+		sc.fakeReq("GET", "/api/dashboards").exec()
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+
+		sc.fakeReq("GET", "/api/dashboards").exec()
+		assert.Equal(t, http.StatusTooManyRequests, sc.resp.Code)
+		assert.Equal(t, "0", sc.resp.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "1000", sc.resp.Header().Get("Retry-After"))
+	})
+
+	middlewareScenario(t, "should limit route groups separately", func(t *testing.T, sc *scenarioContext) {
+		sc.withIdentity(&authn.Identity{ID: "1", Type: claims.TypeUser})
+		setUp(sc, cfg)
+
+		sc.fakeReq("POST", "/api/ds/query").exec()
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "1", sc.resp.Header().Get("X-RateLimit-Limit"))
+
+		sc.fakeReq("POST", "/api/ds/query").exec()
+		assert.Equal(t, http.StatusTooManyRequests, sc.resp.Code)
+
+		sc.fakeReq("GET", "/api/dashboards").exec()
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "should limit each identity separately", func(t *testing.T, sc *scenarioContext) {
+		setUp(sc, cfg)
+
+		sc.withIdentity(&authn.Identity{ID: "1", Type: claims.TypeUser})
+		sc.fakeReq("POST", "/api/ds/query").exec()
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+
+		sc.withIdentity(&authn.Identity{ID: "2", Type: claims.TypeServiceAccount})
+		sc.fakeReq("POST", "/api/ds/query").exec()
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "should limit unauthenticated requests by client IP", func(t *testing.T, sc *scenarioContext) {
+		setUp(sc, cfg)
+
+		for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+			sc.fakeReq("POST", "/api/ds/query")
+			sc.req.Header.Set("X-Real-IP", ip)
+			sc.exec()
+			assert.NotEqual(t, http.StatusTooManyRequests, sc.resp.Code)
+		}
+
+		sc.fakeReq("POST", "/api/ds/query")
+		sc.req.Header.Set("X-Real-IP", "10.0.0.1")
+		sc.exec()
+		assert.Equal(t, http.StatusTooManyRequests, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "should not limit requests outside of the API", func(t *testing.T, sc *scenarioContext) {
+		setUp(sc, cfg)
+
+		for i := 0; i < 3; i++ {
+			sc.fakeReq("GET", "/public/build/app.js").exec()
+			assert.Equal(t, http.StatusOK, sc.resp.Code)
+			assert.Empty(t, sc.resp.Header().Get("X-RateLimit-Limit"))
+		}
+	})
+
+	middlewareScenario(t, "should not limit server admins if they are exempt", func(t *testing.T, sc *scenarioContext) {
+		sc.withIdentity(&authn.Identity{ID: "1", Type: claims.TypeUser, IsGrafanaAdmin: util.Pointer(true)})
+		exempt := cfg
+		exempt.ExemptServerAdmins = true
+		setUp(sc, exempt)
+
+		for i := 0; i < 3; i++ {
+			sc.fakeReq("GET", "/api/dashboards").exec()
+			assert.Equal(t, http.StatusOK, sc.resp.Code)
+		}
+	})
+}
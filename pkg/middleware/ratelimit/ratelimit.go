@@ -0,0 +1,122 @@
+// Package ratelimit implements token bucket rate limits whose state is stored in the remote cache,
+// so that the limits hold across the replicas of a high availability setup.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	cacheKeyPrefix = "ratelimit:"
+	lockStripes    = 64
+)
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	// Allowed is true if a token was taken from the bucket
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit int
+	// Remaining is the number of tokens left in the bucket
+	Remaining int
+	// RetryAfter is how long to wait for the next token if the request was not allowed
+	RetryAfter time.Duration
+	// Reset is how long it takes for the bucket to be full again
+	Reset time.Duration
+}
+
+// bucket is the state of a token bucket as stored in the remote cache.
+type bucket struct {
+	Tokens float64 `json:"tokens"`
+	// Updated is the time the tokens were last counted, in Unix nanoseconds
+	Updated int64 `json:"updated"`
+}
+
+// Limiter takes tokens from the token buckets stored in a remote cache.
+//
+// The remote cache has no atomic read-modify-write operation, so concurrent requests of the same identity to
+// different replicas may take the same token. The limits are therefore approximate in high availability setups,
+// with the requests of a single replica being counted exactly.
+type Limiter struct {
+	cache remotecache.CacheStorage
+	now   func() time.Time
+
+	locks [lockStripes]sync.Mutex
+}
+
+func NewLimiter(cache remotecache.CacheStorage) *Limiter {
+	return &Limiter{
+		cache: cache,
+		now:   time.Now,
+	}
+}
+
+// Take takes a token from the bucket identified by key, refilling it first at the rate of the limit.
+// Buckets start full.
+func (l *Limiter) Take(ctx context.Context, key string, limit setting.RateLimit) (Result, error) {
+	cacheKey := cacheKeyPrefix + key
+
+	lock := l.lock(cacheKey)
+	lock.Lock()
+	defer lock.Unlock()
+
+	now := l.now()
+	burst := float64(limit.Burst)
+	b := bucket{Tokens: burst, Updated: now.UnixNano()}
+
+	data, err := l.cache.Get(ctx, cacheKey)
+	switch {
+	case errors.Is(err, remotecache.ErrCacheItemNotFound):
+	case err != nil:
+		return Result{}, err
+	default:
+		var stored bucket
+		if err := json.Unmarshal(data, &stored); err == nil {
+			elapsed := max(0, now.Sub(time.Unix(0, stored.Updated)).Seconds())
+			b.Tokens = min(burst, stored.Tokens+elapsed*limit.RequestsPerSecond)
+		}
+	}
+
+	res := Result{Limit: limit.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = toDuration((1 - b.Tokens) / limit.RequestsPerSecond)
+	}
+	res.Remaining = int(math.Floor(b.Tokens))
+	res.Reset = toDuration((burst - b.Tokens) / limit.RequestsPerSecond)
+
+	data, err = json.Marshal(b)
+	if err != nil {
+		return Result{}, err
+	}
+	// The bucket is full again once it expires, so it's safe to drop it from the cache.
+	if err := l.cache.Set(ctx, cacheKey, data, max(res.Reset, time.Second)); err != nil {
+		return Result{}, err
+	}
+
+	return res, nil
+}
+
+func (l *Limiter) lock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &l.locks[h.Sum32()%lockStripes]
+}
+
+func toDuration(seconds float64) time.Duration {
+	if math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0
+	}
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLimiter_Take(t *testing.T) {
+	ctx := context.Background()
+	limit := setting.RateLimit{RequestsPerSecond: 2, Burst: 3}
+
+	setup := func() (*Limiter, *time.Time) {
+		now := time.Now()
+		l := NewLimiter(remotecache.NewFakeCacheStorage())
+		l.now = func() time.Time { return now }
+		return l, &now
+	}
+
+	t.Run("should allow a burst of requests and then reject them", func(t *testing.T) {
+		l, _ := setup()
+
+		for i := 2; i >= 0; i-- {
+			res, err := l.Take(ctx, "user:1", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 3, res.Limit)
+			assert.Equal(t, i, res.Remaining)
+		}
+
+		res, err := l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+		assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+		assert.Equal(t, 1500*time.Millisecond, res.Reset)
+	})
+
+	t.Run("should refill the bucket at the rate of the limit", func(t *testing.T) {
+		l, now := setup()
+
+		for i := 0; i < 3; i++ {
+			_, err := l.Take(ctx, "user:1", limit)
+			require.NoError(t, err)
+		}
+
+		*now = now.Add(500 * time.Millisecond)
+		res, err := l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+
+		res, err = l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+
+		// The bucket does not fill beyond its size.
+		*now = now.Add(time.Hour)
+		res, err = l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2, res.Remaining)
+	})
+
+	t.Run("should keep a bucket per key", func(t *testing.T) {
+		l, _ := setup()
+
+		for i := 0; i < 3; i++ {
+			_, err := l.Take(ctx, "api:user:1", limit)
+			require.NoError(t, err)
+		}
+
+		res, err := l.Take(ctx, "api:user:2", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+
+		res, err = l.Take(ctx, "query:user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("should share the buckets through the remote cache", func(t *testing.T) {
+		cache := remotecache.NewFakeCacheStorage()
+		replica1, replica2 := NewLimiter(cache), NewLimiter(cache)
+
+		for i := 0; i < 3; i++ {
+			_, err := replica1.Take(ctx, "user:1", limit)
+			require.NoError(t, err)
+		}
+
+		res, err := replica2.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+	})
+}
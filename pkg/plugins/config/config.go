@@ -20,6 +20,9 @@ type PluginManagementCfg struct {
 
 	GrafanaComAPIURL string
 
+	// PluginRepositoryIndex is the location of a repository index to install plugins from instead of grafana.com.
+	PluginRepositoryIndex string
+
 	GrafanaAppURL string
 
 	Features Features
@@ -39,6 +42,7 @@ type Features struct {
 func NewPluginManagementCfg(devMode bool, pluginsPath string, pluginSettings setting.PluginSettings, pluginsAllowUnsigned []string,
 	pluginsCDNURLTemplate string, appURL string, features Features, angularSupportEnabled bool,
 	grafanaComAPIURL string, disablePlugins []string, hideAngularDeprecation []string, forwardHostEnvVars []string,
+	pluginRepositoryIndex string,
 ) *PluginManagementCfg {
 	return &PluginManagementCfg{
 		PluginsPath:            pluginsPath,
@@ -53,5 +57,6 @@ func NewPluginManagementCfg(devMode bool, pluginsPath string, pluginSettings set
 		AngularSupportEnabled:  angularSupportEnabled,
 		HideAngularDeprecation: hideAngularDeprecation,
 		ForwardHostEnvVars:     forwardHostEnvVars,
+		PluginRepositoryIndex:  pluginRepositoryIndex,
 	}
 }
@@ -35,4 +35,19 @@ var (
 	// Exposed as a base error to wrap it with plugin cancelled errors.
 	ErrPluginRequestCanceledErrorBase = errutil.ClientClosedRequest("plugin.requestCanceled",
 		errutil.WithPublicMessage("Plugin request canceled"))
+
+	// ErrPluginRequestLimitExceeded error returned when a plugin request is rejected because too many
+	// requests to the same data source or organization are in flight.
+	ErrPluginRequestLimitExceeded = errutil.TooManyRequests("plugin.requestLimitExceeded",
+		errutil.WithPublicMessage("Too many concurrent requests to the data source, try again later"))
+
+	// ErrPluginRequestTimeout error returned when a plugin request does not complete within the
+	// configured query timeout.
+	ErrPluginRequestTimeout = errutil.GatewayTimeout("plugin.requestTimeout",
+		errutil.WithPublicMessage("Data source request timed out"))
+
+	// ErrPluginCircuitOpen error returned without calling the plugin while the circuit breaker of a
+	// data source is open after repeated errors.
+	ErrPluginCircuitOpen = errutil.BadGateway("plugin.circuitOpen",
+		errutil.WithPublicMessage("Data source is temporarily unavailable after repeated errors, try again later"))
 )
@@ -0,0 +1,233 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+// IndexFileName is the name of the index file looked up when the location of an IndexRepository is a directory.
+const IndexFileName = "index.json"
+
+// Index is the index file of a plugin repository served by an IndexRepository.
+type Index struct {
+	Plugins []IndexPlugin `json:"plugins"`
+}
+
+// IndexPlugin lists the versions of a plugin in an Index. The download URL of each package is either absolute
+// or relative to the location of the index file.
+type IndexPlugin struct {
+	ID       string    `json:"id"`
+	Versions []Version `json:"versions"`
+}
+
+// IndexRepository is a plugin repository that reads plugin versions from an index file instead of grafana.com,
+// so that plugins can be installed without internet access. The index and the plugin archives are read from
+// a local directory or from any HTTP server, such as a plain file server.
+type IndexRepository struct {
+	client   *Client
+	location string
+
+	log log.PrettyLogger
+}
+
+type IndexRepositoryCfg struct {
+	SkipTLSVerify bool
+	// Location is a local path or an HTTP(S) URL of either the index file or the directory containing it.
+	Location string
+	Logger   log.PrettyLogger
+}
+
+func NewIndexRepository(cfg IndexRepositoryCfg) *IndexRepository {
+	return &IndexRepository{
+		client:   NewClient(cfg.SkipTLSVerify, cfg.Logger),
+		location: cfg.Location,
+		log:      cfg.Logger,
+	}
+}
+
+// GetPluginArchive fetches the requested plugin archive
+func (r *IndexRepository) GetPluginArchive(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginArchive, error) {
+	dlOpts, err := r.GetPluginArchiveInfo(ctx, pluginID, version, compatOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.client.Download(ctx, dlOpts.URL, dlOpts.Checksum, compatOpts)
+}
+
+// GetPluginArchiveByURL fetches the requested plugin archive from the provided `pluginZipURL`
+func (r *IndexRepository) GetPluginArchiveByURL(ctx context.Context, pluginZipURL string, compatOpts CompatOpts) (*PluginArchive, error) {
+	return r.client.Download(ctx, pluginZipURL, "", compatOpts)
+}
+
+// GetPluginArchiveInfo returns the options for downloading the requested plugin (with optional `version`)
+func (r *IndexRepository) GetPluginArchiveInfo(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginArchiveInfo, error) {
+	v, err := r.PluginVersion(ctx, pluginID, version, compatOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, exists := v.Arch[compatOpts.system.OSAndArch()]
+	if !exists {
+		pkg = v.Arch["any"]
+	}
+	if pkg.DownloadURL == "" {
+		return nil, fmt.Errorf("plugin %s v%s has no download URL for %s in the repository index", pluginID, v.Version, compatOpts.system.OSAndArch())
+	}
+	u, err := r.resolve(pkg.DownloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PluginArchiveInfo{
+		Version:  v.Version,
+		Checksum: v.Checksum,
+		URL:      u,
+	}, nil
+}
+
+// PluginVersion will return plugin version based on the requested information
+func (r *IndexRepository) PluginVersion(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (VersionData, error) {
+	plugins, err := r.ListPlugins(ctx, compatOpts)
+	if err != nil {
+		return VersionData{}, err
+	}
+
+	idx := slices.IndexFunc(plugins, func(p IndexPlugin) bool { return p.ID == pluginID })
+	if idx < 0 || len(plugins[idx].Versions) == 0 {
+		return VersionData{}, newErrResponse4xx(http.StatusNotFound).withMessage("Plugin not found")
+	}
+
+	return SelectSystemCompatibleVersion(r.log, plugins[idx].Versions, pluginID, version, compatOpts)
+}
+
+// ListPlugins returns the plugins of the index. The versions of each plugin are sorted so the newest version
+// is first, and are flagged as incompatible if their Grafana dependency excludes the requested Grafana version.
+func (r *IndexRepository) ListPlugins(ctx context.Context, compatOpts CompatOpts) ([]IndexPlugin, error) {
+	body, err := r.readIndex(ctx, compatOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var index Index
+	if err = json.Unmarshal(body, &index); err != nil {
+		r.log.Error("Failed to unmarshal plugin repository index", err)
+		return nil, fmt.Errorf("invalid plugin repository index: %w", err)
+	}
+
+	grafanaVersion, _ := compatOpts.GrafanaVersion()
+	for i := range index.Plugins {
+		versions := index.Plugins[i].Versions
+		for j := range versions {
+			if versions[j].IsCompatible == nil {
+				versions[j].IsCompatible = isGrafanaCompatible(versions[j].GrafanaDependency, grafanaVersion)
+			}
+		}
+		slices.SortStableFunc(versions, compareVersionsDesc)
+	}
+	return index.Plugins, nil
+}
+
+func (r *IndexRepository) readIndex(ctx context.Context, compatOpts CompatOpts) ([]byte, error) {
+	indexLocation := r.indexLocation()
+	if u, ok := parseHTTPURL(indexLocation); ok {
+		return r.client.SendReq(ctx, u, compatOpts)
+	}
+
+	// We can ignore this gosec G304 warning since the location of the index is provided by the configuration or the
+	// command line.
+	// nolint:gosec
+	body, err := os.ReadFile(indexLocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin repository index: %w", err)
+	}
+	return body, nil
+}
+
+// indexLocation returns the location of the index file.
+func (r *IndexRepository) indexLocation() string {
+	if strings.HasSuffix(r.location, ".json") {
+		return r.location
+	}
+	if u, ok := parseHTTPURL(r.location); ok {
+		return u.JoinPath(IndexFileName).String()
+	}
+	return filepath.Join(r.location, IndexFileName)
+}
+
+// resolve returns the location of a package download URL, resolving relative URLs against the index file.
+func (r *IndexRepository) resolve(downloadURL string) (string, error) {
+	if _, ok := parseHTTPURL(downloadURL); ok || filepath.IsAbs(downloadURL) {
+		return downloadURL, nil
+	}
+
+	indexLocation := r.indexLocation()
+	if u, ok := parseHTTPURL(indexLocation); ok {
+		ref, err := url.Parse(downloadURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid download URL %q in plugin repository index: %w", downloadURL, err)
+		}
+		return u.ResolveReference(ref).String(), nil
+	}
+	return filepath.Join(filepath.Dir(indexLocation), filepath.FromSlash(downloadURL)), nil
+}
+
+func parseHTTPURL(s string) (*url.URL, bool) {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, false
+	}
+	return u, true
+}
+
+// isGrafanaCompatible returns whether the Grafana version satisfies the Grafana dependency of a plugin version,
+// or nil if either is unknown.
+func isGrafanaCompatible(grafanaDependency, grafanaVersion string) *bool {
+	if grafanaDependency == "" || grafanaVersion == "" {
+		return nil
+	}
+	constraint, err := semver.NewConstraint(grafanaDependency)
+	if err != nil {
+		return nil
+	}
+	v, err := semver.NewVersion(grafanaVersion)
+	if err != nil {
+		return nil
+	}
+	// Pre-releases of Grafana are compatible with the plugins of the release.
+	if v.Prerelease() != "" {
+		if release, err := v.SetPrerelease(""); err == nil {
+			v = &release
+		}
+	}
+	compatible := constraint.Check(v)
+	return &compatible
+}
+
+// compareVersionsDesc sorts plugin versions so the newest version is first. Versions that are not valid semantic
+// versions come last.
+func compareVersionsDesc(a, b Version) int {
+	va, errA := semver.NewVersion(a.Version)
+	vb, errB := semver.NewVersion(b.Version)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return 1
+	case errB != nil:
+		return -1
+	default:
+		return vb.Compare(va)
+	}
+}
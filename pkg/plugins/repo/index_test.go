@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+func TestIndexRepository(t *testing.T) {
+	const pluginID = "grafana-test-datasource"
+
+	pluginZip := createPluginArchive(t)
+	archive, err := os.ReadFile(pluginZip.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, pluginZip.Close())
+		require.NoError(t, os.RemoveAll(pluginZip.Name()))
+	})
+	sha := fmt.Sprintf("%x", sha256.Sum256(archive))
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "archives"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "archives", "test-1.0.0.zip"), archive, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "archives", "test-1.1.0.linux-amd64.zip"), archive, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, IndexFileName), []byte(fmt.Sprintf(`{
+		"plugins": [{
+			"id": "%[1]s",
+			"versions": [
+				{
+					"version": "1.0.0",
+					"grafanaDependency": ">=10.0.0",
+					"packages": {"any": {"sha256": "%[2]s", "downloadUrl": "archives/test-1.0.0.zip"}}
+				},
+				{
+					"version": "1.1.0",
+					"grafanaDependency": ">=10.0.0",
+					"packages": {"linux-amd64": {"sha256": "%[2]s", "downloadUrl": "archives/test-1.1.0.linux-amd64.zip"}}
+				},
+				{
+					"version": "2.0.0",
+					"grafanaDependency": ">=12.0.0",
+					"packages": {"any": {"sha256": "%[2]s", "downloadUrl": "archives/test-2.0.0.zip"}}
+				},
+				{
+					"version": "0.9.0",
+					"packages": {"any": {"sha256": "invalid", "downloadUrl": "archives/test-1.0.0.zip"}}
+				}
+			]
+		}]
+	}`, pluginID, sha)), 0o600))
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(srv.Close)
+
+	for name, location := range map[string]string{
+		"directory":   dir,
+		"index file":  filepath.Join(dir, IndexFileName),
+		"file server": srv.URL,
+		"index URL":   srv.URL + "/" + IndexFileName,
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := NewIndexRepository(IndexRepositoryCfg{Location: location, Logger: log.NewTestPrettyLogger()})
+			ctx := context.Background()
+
+			t.Run("Should list plugins with the newest version first", func(t *testing.T) {
+				plugins, err := r.ListPlugins(ctx, NewCompatOpts("11.3.0-pre", "linux", "amd64"))
+				require.NoError(t, err)
+				require.Len(t, plugins, 1)
+				versions := make([]string, 0, len(plugins[0].Versions))
+				for _, v := range plugins[0].Versions {
+					versions = append(versions, v.Version)
+				}
+				require.Equal(t, []string{"2.0.0", "1.1.0", "1.0.0", "0.9.0"}, versions)
+				require.False(t, *plugins[0].Versions[0].IsCompatible)
+				require.True(t, *plugins[0].Versions[1].IsCompatible)
+				require.Nil(t, plugins[0].Versions[3].IsCompatible)
+			})
+
+			t.Run("Should select the latest version compatible with Grafana and the system", func(t *testing.T) {
+				info, err := r.GetPluginArchiveInfo(ctx, pluginID, "", NewCompatOpts("11.3.0", "linux", "amd64"))
+				require.NoError(t, err)
+				require.Equal(t, "1.1.0", info.Version)
+				require.Equal(t, sha, info.Checksum)
+
+				info, err = r.GetPluginArchiveInfo(ctx, pluginID, "", NewCompatOpts("11.3.0", "darwin", "arm64"))
+				require.NoError(t, err)
+				require.Equal(t, "1.0.0", info.Version)
+			})
+
+			t.Run("Should download and verify the archive", func(t *testing.T) {
+				archive, err := r.GetPluginArchive(ctx, pluginID, "1.0.0", NewCompatOpts("11.3.0", "linux", "amd64"))
+				require.NoError(t, err)
+				verifyArchive(t, archive)
+
+				_, err = r.GetPluginArchive(ctx, pluginID, "0.9.0", NewCompatOpts("11.3.0", "linux", "amd64"))
+				require.ErrorIs(t, err, ErrChecksumMismatchBase)
+			})
+
+			t.Run("Should return not found for unknown plugins and versions", func(t *testing.T) {
+				_, err := r.PluginVersion(ctx, "unknown-plugin", "", NewCompatOpts("11.3.0", "linux", "amd64"))
+				var errResponse ErrResponse4xx
+				require.ErrorAs(t, err, &errResponse)
+				require.Equal(t, http.StatusNotFound, errResponse.StatusCode())
+
+				_, err = r.PluginVersion(ctx, pluginID, "3.0.0", NewCompatOpts("11.3.0", "linux", "amd64"))
+				require.ErrorIs(t, err, ErrVersionNotFoundBase)
+			})
+		})
+	}
+}
@@ -20,7 +20,15 @@ type Manager struct {
 	log log.PrettyLogger
 }
 
-func ProvideService(cfg *config.PluginManagementCfg) (*Manager, error) {
+func ProvideService(cfg *config.PluginManagementCfg) (Service, error) {
+	if cfg.PluginRepositoryIndex != "" {
+		return NewIndexRepository(IndexRepositoryCfg{
+			SkipTLSVerify: false,
+			Location:      cfg.PluginRepositoryIndex,
+			Logger:        log.NewPrettyLogger("plugin.repository"),
+		}), nil
+	}
+
 	baseURL, err := url.JoinPath(cfg.GrafanaComAPIURL, "/plugins")
 	if err != nil {
 		return nil, err
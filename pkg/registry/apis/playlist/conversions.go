@@ -19,12 +19,16 @@ import (
 )
 
 func LegacyUpdateCommandToUnstructured(cmd playlistsvc.UpdatePlaylistCommand) unstructured.Unstructured {
-	items := []map[string]string{}
+	items := []any{}
 	for _, item := range cmd.Items {
-		items = append(items, map[string]string{
+		v := map[string]any{
 			"type":  item.Type,
 			"value": item.Value,
-		})
+		}
+		if options := itemOptionsToUnstructured(item.Options); options != nil {
+			v["options"] = options
+		}
+		items = append(items, v)
 	}
 	obj := unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -77,8 +81,9 @@ func convertToK8sResource(v *playlistsvc.PlaylistDTO, namespacer request.Namespa
 	}
 	for _, item := range v.Items {
 		spec.Items = append(spec.Items, playlist.PlaylistItem{
-			Type:  playlist.PlaylistItemType(item.Type),
-			Value: item.Value,
+			Type:    playlist.PlaylistItemType(item.Type),
+			Value:   item.Value,
+			Options: convertItemOptionsToK8s(item.Options),
 		})
 	}
 
@@ -122,13 +127,68 @@ func convertToLegacyUpdateCommand(p *playlist.Playlist, orgId int64) (*playlists
 			return nil, fmt.Errorf("unsupported item type: %s", item.Type)
 		}
 		cmd.Items = append(cmd.Items, playlistsvc.PlaylistItem{
-			Type:  string(item.Type),
-			Value: item.Value,
+			Type:    string(item.Type),
+			Value:   item.Value,
+			Options: convertItemOptionsToLegacy(item.Options),
 		})
 	}
 	return cmd, nil
 }
 
+// itemOptionsToUnstructured returns the options of a playlist item in their JSON form.
+func itemOptionsToUnstructured(options *playlistsvc.PlaylistItemOptions) map[string]any {
+	if options == nil {
+		return nil
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		return nil
+	}
+	v := map[string]any{}
+	if err := json.Unmarshal(b, &v); err != nil || len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func convertItemOptionsToK8s(options *playlistsvc.PlaylistItemOptions) *playlist.PlaylistItemOptions {
+	if options == nil {
+		return nil
+	}
+	out := &playlist.PlaylistItemOptions{
+		Variables: options.Variables,
+	}
+	if options.Interval != "" {
+		out.Interval = &options.Interval
+	}
+	if options.TimeRange != nil {
+		out.TimeRange = &playlist.PlaylistTimeRange{
+			From: options.TimeRange.From,
+			To:   options.TimeRange.To,
+		}
+	}
+	return out
+}
+
+func convertItemOptionsToLegacy(options *playlist.PlaylistItemOptions) *playlistsvc.PlaylistItemOptions {
+	if options == nil {
+		return nil
+	}
+	out := &playlistsvc.PlaylistItemOptions{
+		Variables: options.Variables,
+	}
+	if options.Interval != nil {
+		out.Interval = *options.Interval
+	}
+	if options.TimeRange != nil {
+		out.TimeRange = &playlistsvc.PlaylistTimeRange{
+			From: options.TimeRange.From,
+			To:   options.TimeRange.To,
+		}
+	}
+	return out
+}
+
 // Read legacy ID from metadata annotations
 func getLegacyID(item *unstructured.Unstructured) int64 {
 	meta, err := utils.MetaAccessor(item)
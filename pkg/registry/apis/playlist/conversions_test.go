@@ -1,11 +1,17 @@
 package playlist
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
 
+	playlistv0alpha1 "github.com/grafana/grafana/apps/playlist/apis/playlist/v0alpha1"
 	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
 	"github.com/grafana/grafana/pkg/services/playlist"
 )
@@ -69,3 +75,72 @@ func TestPlaylistConversion(t *testing.T) {
 		"status": {}
 	  }`, string(out))
 }
+
+func TestPlaylistConversionRoundTrip(t *testing.T) {
+	items := []playlist.PlaylistItem{
+		{Type: "dashboard_by_uid", Value: "UID0"},
+		{Type: "dashboard_by_tag", Value: "tagA", Options: &playlist.PlaylistItemOptions{
+			Interval:  "1m",
+			TimeRange: &playlist.PlaylistTimeRange{From: "now-1h", To: "now"},
+			Variables: map[string][]string{"host": {"a", "b"}},
+		}},
+		{Type: "dashboard_by_search", Value: `{"query":"cpu","tags":["prod"]}`, Options: &playlist.PlaylistItemOptions{
+			Interval: "30s",
+		}},
+	}
+	service := &roundTripPlaylistService{}
+	storage := &legacyStorage{service: service, namespacer: request.GetNamespaceMapper(nil)}
+	ctx := k8srequest.WithNamespace(context.Background(), "default")
+
+	// The same conversions as the playlist API does through the k8s client
+	obj := LegacyUpdateCommandToUnstructured(playlist.UpdatePlaylistCommand{
+		UID:      "abc",
+		Name:     "MyPlaylists",
+		Interval: "10s",
+		Items:    items,
+	})
+	p := &playlistv0alpha1.Playlist{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, p))
+
+	out, err := storage.Create(ctx, p, nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, items, service.created.Items)
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(out)
+	require.NoError(t, err)
+	dto := UnstructuredToLegacyPlaylistDTO(unstructured.Unstructured{Object: u})
+
+	require.Equal(t, "abc", dto.Uid)
+	require.Equal(t, []playlist.PlaylistItemDTO{
+		{Type: "dashboard_by_uid", Value: "UID0"},
+		{Type: "dashboard_by_tag", Value: "tagA", Options: items[1].Options},
+		{Type: "dashboard_by_search", Value: `{"query":"cpu","tags":["prod"]}`, Options: items[2].Options},
+	}, dto.Items)
+}
+
+// roundTripPlaylistService returns the playlist it was last created with.
+type roundTripPlaylistService struct {
+	playlist.Service
+	created *playlist.CreatePlaylistCommand
+}
+
+func (s *roundTripPlaylistService) Create(_ context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
+	s.created = cmd
+	return &playlist.Playlist{UID: cmd.UID, Name: cmd.Name, Interval: cmd.Interval, OrgId: cmd.OrgId}, nil
+}
+
+func (s *roundTripPlaylistService) Get(_ context.Context, q *playlist.GetPlaylistByUidQuery) (*playlist.PlaylistDTO, error) {
+	if s.created == nil || s.created.UID != q.UID {
+		return nil, playlist.ErrPlaylistNotFound
+	}
+	dto := &playlist.PlaylistDTO{
+		Uid:      s.created.UID,
+		Name:     s.created.Name,
+		Interval: s.created.Interval,
+		OrgID:    s.created.OrgId,
+	}
+	for _, item := range s.created.Items {
+		dto.Items = append(dto.Items, playlist.PlaylistItemDTO{Type: item.Type, Value: item.Value, Options: item.Options})
+	}
+	return dto, nil
+}
@@ -9,15 +9,16 @@ import (
 )
 
 var sqlIDAcceptList = map[string]struct{}{
-	"id":               {},
-	"org_user.user_id": {},
-	"role.uid":         {},
-	"t.id":             {},
-	"team.id":          {},
-	"u.id":             {},
-	"\"user\".\"id\"":  {}, // For Postgres
-	"`user`.`id`":      {}, // For MySQL and SQLite
-	"dashboard.uid":    {},
+	"id":                {},
+	"org_user.user_id":  {},
+	"role.uid":          {},
+	"t.id":              {},
+	"team.id":           {},
+	"u.id":              {},
+	"\"user\".\"id\"":   {}, // For Postgres
+	"`user`.`id`":       {}, // For MySQL and SQLite
+	"dashboard.uid":     {},
+	"query_library.uid": {},
 }
 
 var (
@@ -0,0 +1,150 @@
+package caching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	queryCacheKeyPrefix    = "query-cache"
+	resourceCacheKeyPrefix = "resource-cache"
+
+	// queryCachingTTLField overrides the caching TTL, in milliseconds, in the data source JSON data or a query.
+	queryCachingTTLField = "queryCachingTTL"
+)
+
+// dataSourceCachingOptions are the caching options read from the JSON data of a data source.
+type dataSourceCachingOptions struct {
+	// Disabled turns caching off for the data source
+	Disabled bool `json:"queryCachingDisabled"`
+	// TTL in milliseconds, the configured TTL is used when 0
+	TTL int64 `json:"queryCachingTTL"`
+	// OAuthPassThru data sources forward the identity of the user, so results are cached per user
+	OAuthPassThru bool `json:"oauthPassThru"`
+}
+
+func dataSourceOptions(jsonData json.RawMessage) (dataSourceCachingOptions, error) {
+	opts := dataSourceCachingOptions{}
+	if len(jsonData) == 0 {
+		return opts, nil
+	}
+	err := json.Unmarshal(jsonData, &opts)
+	return opts, err
+}
+
+// queryTTL returns the shortest TTL set by the queries, falling back to the TTL of the data source
+// and then to the configured default.
+func queryTTL(queries []backend.DataQuery, opts dataSourceCachingOptions, defaultTTL time.Duration) time.Duration {
+	var ttl time.Duration
+	for _, q := range queries {
+		model := map[string]any{}
+		if err := json.Unmarshal(q.JSON, &model); err != nil {
+			continue
+		}
+		ms, ok := model[queryCachingTTLField].(float64)
+		if !ok || ms <= 0 {
+			continue
+		}
+		if qttl := time.Duration(ms) * time.Millisecond; ttl == 0 || qttl < ttl {
+			ttl = qttl
+		}
+	}
+	if ttl > 0 {
+		return ttl
+	}
+	if opts.TTL > 0 {
+		return time.Duration(opts.TTL) * time.Millisecond
+	}
+	return defaultTTL
+}
+
+type queryKey struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	Interval      time.Duration   `json:"interval"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	JSON          json.RawMessage `json:"json"`
+}
+
+type requestKey struct {
+	OrgID    int64      `json:"orgId"`
+	PluginID string     `json:"pluginId"`
+	UID      string     `json:"uid"`
+	Updated  time.Time  `json:"updated"`
+	User     string     `json:"user,omitempty"`
+	Queries  []queryKey `json:"queries,omitempty"`
+	URL      string     `json:"url,omitempty"`
+}
+
+// queryCacheKey returns the key of the results of req. The time range of each query is aligned down
+// to its interval, so requests for a moving "now" share their results within one interval.
+func queryCacheKey(req *backend.QueryDataRequest, opts dataSourceCachingOptions) (string, error) {
+	k := newRequestKey(req.PluginContext, opts)
+	for _, q := range req.Queries {
+		interval := q.Interval
+		if interval < time.Second {
+			interval = time.Second
+		}
+		model := q.JSON
+		if len(model) > 0 {
+			// drop insignificant whitespace so equal queries share a key
+			var v any
+			if err := json.Unmarshal(model, &v); err != nil {
+				return "", err
+			}
+			compact, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			model = compact
+		}
+		k.Queries = append(k.Queries, queryKey{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			From:          q.TimeRange.From.Truncate(interval).UnixMilli(),
+			To:            q.TimeRange.To.Truncate(interval).UnixMilli(),
+			Interval:      q.Interval,
+			MaxDataPoints: q.MaxDataPoints,
+			JSON:          model,
+		})
+	}
+	return hashKey(queryCacheKeyPrefix, k)
+}
+
+// resourceCacheKey returns the key of the response to req.
+func resourceCacheKey(req *backend.CallResourceRequest, opts dataSourceCachingOptions) (string, error) {
+	k := newRequestKey(req.PluginContext, opts)
+	k.URL = req.URL
+	return hashKey(resourceCacheKeyPrefix, k)
+}
+
+func newRequestKey(pCtx backend.PluginContext, opts dataSourceCachingOptions) requestKey {
+	k := requestKey{
+		OrgID:    pCtx.OrgID,
+		PluginID: pCtx.PluginID,
+	}
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil {
+		k.UID = ds.UID
+		// the results of a data source are invalidated when its settings change
+		k.Updated = ds.Updated
+	}
+	if opts.OAuthPassThru && pCtx.User != nil {
+		k.User = pCtx.User.Login
+	}
+	return k
+}
+
+func hashKey(prefix string, k requestKey) (string, error) {
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return prefix + ":" + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,21 @@
+package caching
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type cachingMetrics struct {
+	requests *prometheus.CounterVec
+}
+
+func newCachingMetrics(reg prometheus.Registerer) *cachingMetrics {
+	return &cachingMetrics{
+		requests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "caching",
+			Name:      "requests_total",
+			Help:      "Number of query and resource requests handled by the cache, by cache status (HIT, MISS, BYPASS, ERROR or DISABLED).",
+		}, []string{"type", "datasource_type", "status"}),
+	}
+}
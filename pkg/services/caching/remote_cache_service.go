@@ -0,0 +1,240 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	requestTypeQuery    = "query"
+	requestTypeResource = "resource"
+)
+
+func ProvideCachingService(cfg *setting.Cfg, cache remotecache.CacheStorage, reg prometheus.Registerer) *OSSCachingService {
+	return &OSSCachingService{
+		cfg:     cfg.QueryCaching,
+		cache:   cache,
+		metrics: newCachingMetrics(reg),
+		log:     log.New("caching"),
+	}
+}
+
+// OSSCachingService caches query results and resource responses in the remote cache.
+// The zero value, and a service with caching disabled in the configuration, never caches anything.
+type OSSCachingService struct {
+	cfg     setting.QueryCachingSettings
+	cache   remotecache.CacheStorage
+	metrics *cachingMetrics
+	log     log.Logger
+}
+
+// HandleQueryRequest looks up the results of req in the cache. Results are keyed on the data source,
+// the queries and their time ranges aligned to the query interval, so that dashboards refreshing
+// within one interval share their results.
+func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
+	if !s.enabled() || req == nil || req.PluginContext.DataSourceInstanceSettings == nil {
+		return false, CachedQueryDataResponse{}
+	}
+	ds := req.PluginContext.DataSourceInstanceSettings
+	reqCtx := contexthandler.FromContext(ctx)
+	setStatus := func(status string) {
+		s.setStatus(reqCtx, requestTypeQuery, ds.Type, status)
+	}
+
+	opts, err := dataSourceOptions(ds.JSONData)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to read caching options of data source", "datasource", ds.UID, "error", err)
+		setStatus(StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+	if opts.Disabled {
+		setStatus(StatusDisabled)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req, opts)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to create query cache key", "datasource", ds.UID, "error", err)
+		setStatus(StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	skipRead, skipWrite := bypassCache(reqCtx)
+	if !skipRead {
+		data, err := s.cache.Get(ctx, key)
+		switch {
+		case err == nil:
+			resp := &backend.QueryDataResponse{}
+			if err := json.Unmarshal(data, resp); err == nil {
+				setStatus(StatusHit)
+				return true, CachedQueryDataResponse{Response: resp}
+			}
+			s.log.FromContext(ctx).Warn("Failed to decode cached query response", "datasource", ds.UID, "error", err)
+		case !errors.Is(err, remotecache.ErrCacheItemNotFound):
+			s.log.FromContext(ctx).Warn("Failed to read query response from cache", "datasource", ds.UID, "error", err)
+			setStatus(StatusError)
+			return false, CachedQueryDataResponse{}
+		}
+	}
+
+	if skipRead {
+		setStatus(StatusBypass)
+	} else {
+		setStatus(StatusMiss)
+	}
+	if skipWrite {
+		return false, CachedQueryDataResponse{}
+	}
+
+	ttl := queryTTL(req.Queries, opts, s.cfg.TTL)
+	return false, CachedQueryDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.QueryDataResponse) {
+			if !cacheableQueryResponse(resp) {
+				return
+			}
+			s.store(ctx, key, resp, ttl)
+		},
+	}
+}
+
+// HandleResourceRequest looks up the response to a GET resource request of a data source in the cache.
+// Responses are only cached when the plugin sends a single successful response.
+func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
+	if !s.enabled() || req == nil || req.Method != http.MethodGet || req.PluginContext.DataSourceInstanceSettings == nil {
+		return false, CachedResourceDataResponse{}
+	}
+	ds := req.PluginContext.DataSourceInstanceSettings
+	reqCtx := contexthandler.FromContext(ctx)
+	setStatus := func(status string) {
+		s.setStatus(reqCtx, requestTypeResource, ds.Type, status)
+	}
+
+	opts, err := dataSourceOptions(ds.JSONData)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to read caching options of data source", "datasource", ds.UID, "error", err)
+		setStatus(StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+	if opts.Disabled {
+		setStatus(StatusDisabled)
+		return false, CachedResourceDataResponse{}
+	}
+
+	key, err := resourceCacheKey(req, opts)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to create resource cache key", "datasource", ds.UID, "error", err)
+		setStatus(StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	skipRead, skipWrite := bypassCache(reqCtx)
+	if !skipRead {
+		data, err := s.cache.Get(ctx, key)
+		switch {
+		case err == nil:
+			resp := &backend.CallResourceResponse{}
+			if err := json.Unmarshal(data, resp); err == nil {
+				setStatus(StatusHit)
+				return true, CachedResourceDataResponse{Response: resp}
+			}
+			s.log.FromContext(ctx).Warn("Failed to decode cached resource response", "datasource", ds.UID, "error", err)
+		case !errors.Is(err, remotecache.ErrCacheItemNotFound):
+			s.log.FromContext(ctx).Warn("Failed to read resource response from cache", "datasource", ds.UID, "error", err)
+			setStatus(StatusError)
+			return false, CachedResourceDataResponse{}
+		}
+	}
+
+	if skipRead {
+		setStatus(StatusBypass)
+	} else {
+		setStatus(StatusMiss)
+	}
+	if skipWrite {
+		return false, CachedResourceDataResponse{}
+	}
+
+	// The caching middleware only calls UpdateCacheFn for resource calls that sent a single response.
+	return false, CachedResourceDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.CallResourceResponse) {
+			if resp == nil || resp.Status < http.StatusOK || resp.Status >= http.StatusMultipleChoices {
+				return
+			}
+			s.store(ctx, key, resp, s.cfg.ResourcesTTL)
+		},
+	}
+}
+
+func (s *OSSCachingService) enabled() bool {
+	return s.cfg.Enabled && s.cache != nil
+}
+
+func (s *OSSCachingService) store(ctx context.Context, key string, value any, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to encode response for the cache", "error", err)
+		return
+	}
+	if s.cfg.MaxValueSize > 0 && int64(len(data)) > s.cfg.MaxValueSize {
+		s.log.FromContext(ctx).Debug("Response is too large to be cached", "size", len(data), "max", s.cfg.MaxValueSize)
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, ttl); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to write response to cache", "error", err)
+	}
+}
+
+func (s *OSSCachingService) setStatus(reqCtx *contextmodel.ReqContext, requestType, dsType, status string) {
+	if reqCtx != nil && reqCtx.Context != nil && reqCtx.Resp != nil {
+		reqCtx.Resp.Header().Set(XCacheHeader, status)
+	}
+	if s.metrics != nil {
+		s.metrics.requests.WithLabelValues(requestType, dsType, status).Inc()
+	}
+}
+
+// cacheableQueryResponse returns true when none of the queries failed.
+func cacheableQueryResponse(resp *backend.QueryDataResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, r := range resp.Responses {
+		if r.Error != nil || r.Status >= 400 {
+			return false
+		}
+	}
+	return true
+}
+
+// bypassCache returns whether the request asked to skip reading from and writing to the cache.
+// X-Cache-Skip and Cache-Control: no-cache skip reading, Cache-Control: no-store skips both.
+func bypassCache(reqCtx *contextmodel.ReqContext) (skipRead bool, skipWrite bool) {
+	if reqCtx == nil || reqCtx.Context == nil || reqCtx.Req == nil {
+		return false, false
+	}
+	if strings.EqualFold(reqCtx.Req.Header.Get(XCacheSkipHeader), "true") {
+		skipRead = true
+	}
+	for _, directive := range strings.Split(reqCtx.Req.Header.Get("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache":
+			skipRead = true
+		case "no-store":
+			skipRead, skipWrite = true, true
+		}
+	}
+	return skipRead, skipWrite
+}
@@ -0,0 +1,309 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func newTestService(t *testing.T) (*OSSCachingService, remotecache.FakeCacheStorage) {
+	t.Helper()
+	cfg := setting.NewCfg()
+	cfg.QueryCaching = setting.QueryCachingSettings{
+		Enabled:      true,
+		TTL:          time.Minute,
+		ResourcesTTL: time.Minute,
+	}
+	store := remotecache.NewFakeCacheStorage()
+	return ProvideCachingService(cfg, store, prometheus.NewRegistry()), store
+}
+
+func newTestContext(t *testing.T, headers map[string]string) (context.Context, *contextmodel.ReqContext) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "/api/ds/query", nil)
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	reqCtx := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req:  req,
+			Resp: web.NewResponseWriter(req.Method, httptest.NewRecorder()),
+		},
+	}
+	return ctxkey.Set(context.Background(), reqCtx), reqCtx
+}
+
+func newQueryRequest(from, to time.Time, jsonData string) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			OrgID:    1,
+			PluginID: "prometheus",
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				UID:      "ds-uid",
+				Type:     "prometheus",
+				JSONData: json.RawMessage(jsonData),
+			},
+		},
+		Queries: []backend.DataQuery{{
+			RefID:     "A",
+			Interval:  15 * time.Second,
+			TimeRange: backend.TimeRange{From: from, To: to},
+			JSON:      json.RawMessage(`{"refId": "A", "expr": "up"}`),
+		}},
+	}
+}
+
+func TestHandleQueryRequest(t *testing.T) {
+	to := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	from := to.Add(-time.Hour)
+	response := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("up", data.NewField("value", nil, []float64{1}))}},
+	}}
+
+	t.Run("caches a miss and returns it on the next request within the interval", func(t *testing.T) {
+		s, store := newTestService(t)
+
+		ctx, reqCtx := newTestContext(t, nil)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		require.False(t, hit)
+		require.Equal(t, StatusMiss, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.NotNil(t, cr.UpdateCacheFn)
+		cr.UpdateCacheFn(ctx, response)
+		require.Len(t, store.Storage, 1)
+
+		ctx, reqCtx = newTestContext(t, nil)
+		hit, cr = s.HandleQueryRequest(ctx, newQueryRequest(from.Add(5*time.Second), to.Add(5*time.Second), `{}`))
+		require.True(t, hit)
+		require.Equal(t, StatusHit, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.Len(t, cr.Response.Responses["A"].Frames, 1)
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.requests.WithLabelValues(requestTypeQuery, "prometheus", StatusHit)))
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.requests.WithLabelValues(requestTypeQuery, "prometheus", StatusMiss)))
+	})
+
+	t.Run("does not share results across intervals or data source versions", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		ctx, _ := newTestContext(t, nil)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		cr.UpdateCacheFn(ctx, response)
+
+		hit, _ := s.HandleQueryRequest(ctx, newQueryRequest(from.Add(15*time.Second), to.Add(15*time.Second), `{}`))
+		require.False(t, hit)
+
+		req := newQueryRequest(from, to, `{}`)
+		req.PluginContext.DataSourceInstanceSettings.Updated = to
+		hit, _ = s.HandleQueryRequest(ctx, req)
+		require.False(t, hit)
+	})
+
+	t.Run("does not cache failed queries", func(t *testing.T) {
+		s, store := newTestService(t)
+
+		ctx, _ := newTestContext(t, nil)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": backend.ErrDataResponse(backend.StatusBadRequest, "bad query"),
+		}})
+		require.Empty(t, store.Storage)
+	})
+
+	t.Run("does not cache responses larger than the max value size", func(t *testing.T) {
+		s, store := newTestService(t)
+		s.cfg.MaxValueSize = 10
+
+		ctx, _ := newTestContext(t, nil)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		cr.UpdateCacheFn(ctx, response)
+		require.Empty(t, store.Storage)
+	})
+
+	t.Run("bypass headers", func(t *testing.T) {
+		s, store := newTestService(t)
+
+		ctx, _ := newTestContext(t, nil)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		cr.UpdateCacheFn(ctx, response)
+
+		ctx, reqCtx := newTestContext(t, map[string]string{XCacheSkipHeader: "true"})
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		require.False(t, hit)
+		require.Equal(t, StatusBypass, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.NotNil(t, cr.UpdateCacheFn, "the fresh response is still cached")
+
+		store.Storage = map[string][]byte{}
+		ctx, reqCtx = newTestContext(t, map[string]string{"Cache-Control": "no-cache, no-store"})
+		hit, cr = s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		require.False(t, hit)
+		require.Equal(t, StatusBypass, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.Nil(t, cr.UpdateCacheFn)
+	})
+
+	t.Run("disabled for the data source", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		ctx, reqCtx := newTestContext(t, nil)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{"queryCachingDisabled": true}`))
+		require.False(t, hit)
+		require.Nil(t, cr.UpdateCacheFn)
+		require.Equal(t, StatusDisabled, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("disabled in the configuration", func(t *testing.T) {
+		s, _ := newTestService(t)
+		s.cfg.Enabled = false
+
+		ctx, reqCtx := newTestContext(t, nil)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		require.False(t, hit)
+		require.Nil(t, cr.UpdateCacheFn)
+		require.Empty(t, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("reports cache errors", func(t *testing.T) {
+		s, _ := newTestService(t)
+		s.cache = &failingCacheStorage{}
+
+		ctx, reqCtx := newTestContext(t, nil)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest(from, to, `{}`))
+		require.False(t, hit)
+		require.Nil(t, cr.UpdateCacheFn)
+		require.Equal(t, StatusError, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+}
+
+func TestQueryTTL(t *testing.T) {
+	queries := func(models ...string) []backend.DataQuery {
+		qs := make([]backend.DataQuery, 0, len(models))
+		for _, m := range models {
+			qs = append(qs, backend.DataQuery{JSON: json.RawMessage(m)})
+		}
+		return qs
+	}
+
+	require.Equal(t, 5*time.Minute, queryTTL(queries(`{}`), dataSourceCachingOptions{}, 5*time.Minute))
+	require.Equal(t, 30*time.Second, queryTTL(queries(`{}`), dataSourceCachingOptions{TTL: 30000}, 5*time.Minute))
+	require.Equal(t, 10*time.Second, queryTTL(queries(`{"queryCachingTTL": 20000}`, `{"queryCachingTTL": 10000}`), dataSourceCachingOptions{TTL: 30000}, 5*time.Minute))
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	to := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	req := newQueryRequest(to.Add(-time.Hour), to, `{}`)
+
+	key, err := queryCacheKey(req, dataSourceCachingOptions{})
+	require.NoError(t, err)
+
+	t.Run("ignores whitespace in the query", func(t *testing.T) {
+		other := newQueryRequest(to.Add(-time.Hour), to, `{}`)
+		other.Queries[0].JSON = json.RawMessage(`{ "refId":"A",  "expr":"up" }`)
+		otherKey, err := queryCacheKey(other, dataSourceCachingOptions{})
+		require.NoError(t, err)
+		require.Equal(t, key, otherKey)
+	})
+
+	t.Run("is per user for oauth pass through data sources", func(t *testing.T) {
+		req.PluginContext.User = &backend.User{Login: "admin"}
+		adminKey, err := queryCacheKey(req, dataSourceCachingOptions{OAuthPassThru: true})
+		require.NoError(t, err)
+		req.PluginContext.User = &backend.User{Login: "viewer"}
+		viewerKey, err := queryCacheKey(req, dataSourceCachingOptions{OAuthPassThru: true})
+		require.NoError(t, err)
+		require.NotEqual(t, adminKey, viewerKey)
+	})
+}
+
+func TestHandleResourceRequest(t *testing.T) {
+	newResourceRequest := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:    1,
+				PluginID: "prometheus",
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:  "ds-uid",
+					Type: "prometheus",
+				},
+			},
+			Path:   "api/v1/labels",
+			Method: method,
+			URL:    "api/v1/labels?match[]=up",
+		}
+	}
+	response := &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`{"data":["job"]}`)}
+
+	t.Run("caches GET responses", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		ctx, reqCtx := newTestContext(t, nil)
+		hit, cr := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		require.False(t, hit)
+		require.Equal(t, StatusMiss, reqCtx.Resp.Header().Get(XCacheHeader))
+		cr.UpdateCacheFn(ctx, response)
+
+		ctx, reqCtx = newTestContext(t, nil)
+		hit, cr = s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		require.True(t, hit)
+		require.Equal(t, StatusHit, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.Equal(t, response, cr.Response)
+	})
+
+	t.Run("ignores other methods", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		ctx, reqCtx := newTestContext(t, nil)
+		hit, cr := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodPost))
+		require.False(t, hit)
+		require.Nil(t, cr.UpdateCacheFn)
+		require.Empty(t, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		s, store := newTestService(t)
+
+		ctx, _ := newTestContext(t, nil)
+		_, cr := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusInternalServerError})
+		require.Empty(t, store.Storage)
+	})
+}
+
+func TestZeroValueServiceDoesNothing(t *testing.T) {
+	s := &OSSCachingService{}
+	ctx, reqCtx := newTestContext(t, nil)
+
+	hit, cr := s.HandleQueryRequest(ctx, newQueryRequest(time.Now().Add(-time.Hour), time.Now(), `{}`))
+	require.False(t, hit)
+	require.Nil(t, cr.UpdateCacheFn)
+	require.Empty(t, reqCtx.Resp.Header().Get(XCacheHeader))
+}
+
+type failingCacheStorage struct{}
+
+func (f *failingCacheStorage) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (f *failingCacheStorage) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (f *failingCacheStorage) Delete(context.Context, string) error {
+	return errors.New("connection refused")
+}
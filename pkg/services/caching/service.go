@@ -13,6 +13,10 @@ const (
 	StatusBypass   = "BYPASS"
 	StatusError    = "ERROR"
 	StatusDisabled = "DISABLED"
+
+	// XCacheSkipHeader can be set to "true" on a request to skip reading from the cache.
+	// The fresh response is still written to the cache.
+	XCacheSkipHeader = "X-Cache-Skip"
 )
 
 type CacheQueryResponseFn func(context.Context, *backend.QueryDataResponse)
@@ -35,10 +39,6 @@ type CachedResourceDataResponse struct {
 	UpdateCacheFn CacheResourceResponseFn
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
-}
-
 type CachingService interface {
 	// HandleQueryRequest uses a QueryDataRequest to check the cache for any existing results for that query.
 	// If none are found, it should return false and a CachedQueryDataResponse with an UpdateCacheFn which can be used to update the results cache after the fact.
@@ -49,16 +49,4 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
-type OSSCachingService struct {
-}
-
-func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
-}
-
-func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
-}
-
 var _ CachingService = &OSSCachingService{}
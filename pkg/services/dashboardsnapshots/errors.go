@@ -5,3 +5,5 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var ErrInvalidRenderCommand = errutil.BadRequest("dashboardsnapshots.invalid-render-command")
@@ -69,6 +69,47 @@ type CreateDashboardSnapshotCommand struct {
 	DashboardEncrypted []byte `json:"-"`
 }
 
+// RenderDashboardSnapshotCommand creates a snapshot of a stored dashboard, with the results of its panel queries
+// run on the server.
+// swagger:model
+type RenderDashboardSnapshotCommand struct {
+	// UID of the dashboard.
+	// required:true
+	DashboardUID string `json:"dashboardUid" binding:"Required"`
+
+	// Start of the time range, in epoch milliseconds or relative using Grafana time units. Defaults to the dashboard time range.
+	// required:false
+	// example: now-1h
+	From string `json:"from"`
+
+	// End of the time range, in epoch milliseconds or relative using Grafana time units. Defaults to the dashboard time range.
+	// required:false
+	// example: now
+	To string `json:"to"`
+
+	// Snapshot name
+	// required:false
+	Name string `json:"name"`
+
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+
+	// Define the unique key.
+	// required:false
+	Key string `json:"key"`
+
+	// Unique key used to delete the snapshot.
+	// required:false
+	DeleteKey string `json:"deleteKey"`
+
+	// Maximum number of data points of the panels that don't set their own.
+	// required:false
+	// default:1000
+	MaxDataPoints int64 `json:"maxDataPoints"`
+}
+
 type DeleteDashboardSnapshotCommand struct {
 	DeleteKey string `json:"-"`
 }
@@ -0,0 +1,458 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	libraryelementsmodel "github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+const (
+	mixedDatasourceUID = "-- Mixed --"
+	// snapshotQueryType is the query type of the Grafana data source queries returning the frames embedded in a snapshot.
+	snapshotQueryType = "snapshot"
+	// defaultRenderMaxDataPoints is the number of data points requested for the panels that don't set their own.
+	defaultRenderMaxDataPoints = 1000
+)
+
+var variableRegex = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?::\w+)?\}|\[\[(\w+)(?::\w+)?\]\]`)
+
+// SnapshotRenderer runs the panel queries of stored dashboards on the server and embeds their results into the
+// dashboard model of a snapshot, the same way the frontend does when a snapshot is shared.
+type SnapshotRenderer struct {
+	queryService          query.Service
+	dataSourceService     datasources.DataSourceService
+	libraryElementService libraryelements.Service
+	log                   log.Logger
+}
+
+func NewSnapshotRenderer(queryService query.Service, dataSourceService datasources.DataSourceService, libraryElementService libraryelements.Service) *SnapshotRenderer {
+	return &SnapshotRenderer{
+		queryService:          queryService,
+		dataSourceService:     dataSourceService,
+		libraryElementService: libraryElementService,
+		log:                   log.New("dashboardsnapshots.renderer"),
+	}
+}
+
+// Render returns the snapshot model of the dashboard for the time range of the command, defaulting to the
+// time range of the dashboard. Panel queries run with the permissions of the user, and the panels whose
+// queries fail are embedded with a notice holding the error.
+func (r *SnapshotRenderer) Render(ctx context.Context, user identity.Requester, dashboard *simplejson.Json, cmd *RenderDashboardSnapshotCommand) (*common.Unstructured, error) {
+	model, err := copyDashboard(dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := cmd.From, cmd.To
+	if from == "" {
+		from = model.GetPath("time", "from").MustString("now-6h")
+	}
+	if to == "" {
+		to = model.GetPath("time", "to").MustString("now")
+	}
+	timeRange := gtime.NewTimeRange(from, to)
+	fromTime, err := timeRange.ParseFrom()
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid from time %q", ErrInvalidRenderCommand, from)
+	}
+	toTime, err := timeRange.ParseTo()
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid to time %q", ErrInvalidRenderCommand, to)
+	}
+	if !fromTime.Before(toTime) {
+		return nil, fmt.Errorf("%w: from time must be before to time", ErrInvalidRenderCommand)
+	}
+
+	maxDataPoints := cmd.MaxDataPoints
+	if maxDataPoints <= 0 {
+		maxDataPoints = defaultRenderMaxDataPoints
+	}
+
+	pr := &panelRenderer{
+		renderer:      r,
+		user:          user,
+		from:          fromTime,
+		to:            toTime,
+		maxDataPoints: maxDataPoints,
+		variables:     currentVariableValues(model),
+	}
+	for _, panel := range model.Get("panels").MustArray() {
+		if err := pr.renderPanel(ctx, simplejson.NewFromAny(panel)); err != nil {
+			return nil, err
+		}
+	}
+
+	trimDashboardForSnapshot(model, fromTime, toTime)
+
+	return &common.Unstructured{Object: model.MustMap()}, nil
+}
+
+type panelRenderer struct {
+	renderer      *SnapshotRenderer
+	user          identity.Requester
+	from          time.Time
+	to            time.Time
+	maxDataPoints int64
+	variables     map[string]string
+	defaultDSUID  string
+}
+
+func (pr *panelRenderer) renderPanel(ctx context.Context, panel *simplejson.Json) error {
+	if uid := panel.GetPath("libraryPanel", "uid").MustString(); uid != "" {
+		if err := pr.loadLibraryPanel(ctx, panel, uid); err != nil {
+			return err
+		}
+	}
+
+	panel.Set("links", []any{})
+
+	if panel.Get("type").MustString() == "row" {
+		for _, nested := range panel.Get("panels").MustArray() {
+			if err := pr.renderPanel(ctx, simplejson.NewFromAny(nested)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	targets := panel.Get("targets").MustArray()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	queries, err := pr.panelQueries(ctx, panel, targets)
+	if err != nil {
+		return err
+	}
+
+	var frames data.Frames
+	if len(queries) > 0 {
+		frames = pr.runQueries(ctx, panel, queries)
+	}
+
+	snapshot := make([]any, 0, len(frames))
+	for _, frame := range frames {
+		frameJSON, err := frameToSnapshotJSON(frame)
+		if err != nil {
+			return err
+		}
+		snapshot = append(snapshot, frameJSON)
+	}
+
+	datasource := map[string]any{"name": grafanads.DatasourceUID, "uid": grafanads.DatasourceUID}
+	panel.Set("datasource", datasource)
+	panel.Set("targets", []any{
+		map[string]any{
+			"refId":      "A",
+			"datasource": datasource,
+			"queryType":  snapshotQueryType,
+			"snapshot":   snapshot,
+		},
+	})
+	return nil
+}
+
+// loadLibraryPanel replaces the panel with the model of its library panel, keeping its id and position. The dashboard
+// only holds a reference to library panels, and snapshots embed them as regular panels, like the frontend does.
+func (pr *panelRenderer) loadLibraryPanel(ctx context.Context, panel *simplejson.Json, uid string) error {
+	panelID := panel.Get("id").MustInt64()
+	element, err := pr.renderer.libraryElementService.GetElement(ctx, pr.user, libraryelementsmodel.GetLibraryElementCommand{UID: uid})
+	if err != nil {
+		if errors.Is(err, libraryelementsmodel.ErrLibraryElementNotFound) {
+			return fmt.Errorf("%w: library panel %q of panel %d not found", ErrInvalidRenderCommand, uid, panelID)
+		}
+		return fmt.Errorf("failed to get library panel %q of panel %d: %w", uid, panelID, err)
+	}
+
+	libraryPanel, err := simplejson.NewJson(element.Model)
+	if err != nil {
+		return fmt.Errorf("failed to parse library panel %q of panel %d: %w", uid, panelID, err)
+	}
+	for key, value := range libraryPanel.MustMap() {
+		if key == "id" || key == "gridPos" {
+			continue
+		}
+		panel.Set(key, value)
+	}
+	panel.Del("libraryPanel")
+	return nil
+}
+
+// panelQueries returns the queries of the panel targets, with their data source, variables and interval resolved.
+func (pr *panelRenderer) panelQueries(ctx context.Context, panel *simplejson.Json, targets []any) ([]*simplejson.Json, error) {
+	panelDSUID := pr.interpolate(datasourceUID(panel))
+	hasExpression := false
+	for _, target := range targets {
+		if expr.NodeTypeFromDatasourceUID(datasourceUID(simplejson.NewFromAny(target))) == expr.TypeCMDNode {
+			hasExpression = true
+		}
+	}
+
+	minInterval := time.Millisecond
+	if interval, err := gtime.ParseInterval(pr.interpolate(panel.Get("interval").MustString())); err == nil && interval > minInterval {
+		minInterval = interval
+	}
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(pr.maxDataPoints)
+	if maxDataPoints <= 0 {
+		maxDataPoints = pr.maxDataPoints
+	}
+	interval := time.Duration(pr.to.Sub(pr.from).Nanoseconds() / maxDataPoints)
+	if interval < minInterval {
+		interval = minInterval
+	} else {
+		interval = gtime.RoundInterval(interval)
+	}
+
+	queries := make([]*simplejson.Json, 0, len(targets))
+	for _, target := range targets {
+		q := simplejson.NewFromAny(pr.interpolateValue(target))
+
+		// Hidden queries are kept when the panel has expressions, which may use them.
+		if !hasExpression && q.Get("hide").MustBool() {
+			continue
+		}
+
+		dsUID := datasourceUID(q)
+		if dsUID == "" {
+			dsUID = panelDSUID
+		}
+		switch dsUID {
+		case mixedDatasourceUID:
+			// The targets of mixed panels must set their own data source.
+			continue
+		case grafanads.DatasourceName:
+			dsUID = grafanads.DatasourceUID
+		case "":
+			var err error
+			if dsUID, err = pr.defaultDatasourceUID(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		q.Set("datasource", map[string]any{"uid": dsUID})
+		q.Set("intervalMs", interval.Milliseconds())
+		q.Set("maxDataPoints", maxDataPoints)
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
+
+// runQueries returns the frames of the queries in the order of their targets. If the queries fail,
+// a frame with a notice holding the error is returned instead.
+func (pr *panelRenderer) runQueries(ctx context.Context, panel *simplejson.Json, queries []*simplejson.Json) data.Frames {
+	res, err := pr.renderer.queryService.QueryData(ctx, pr.user, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(pr.from.UnixMilli(), 10),
+		To:      strconv.FormatInt(pr.to.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		pr.renderer.log.Warn("Failed to run panel queries", "panelId", panel.Get("id").MustInt64(), "error", err)
+		return data.Frames{errorFrame("", err)}
+	}
+
+	var frames data.Frames
+	for _, q := range queries {
+		refID := q.Get("refId").MustString("A")
+		dr, ok := res.Responses[refID]
+		if !ok {
+			continue
+		}
+		if dr.Error != nil {
+			frames = append(frames, errorFrame(refID, dr.Error))
+			continue
+		}
+		for _, frame := range dr.Frames {
+			frame.RefID = refID
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+func (pr *panelRenderer) defaultDatasourceUID(ctx context.Context) (string, error) {
+	if pr.defaultDSUID != "" {
+		return pr.defaultDSUID, nil
+	}
+
+	dataSources, err := pr.renderer.dataSourceService.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: pr.user.GetOrgID()})
+	if err != nil {
+		return "", err
+	}
+	for _, ds := range dataSources {
+		if ds.IsDefault {
+			pr.defaultDSUID = ds.UID
+			return ds.UID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: panel without data source and no default data source", ErrInvalidRenderCommand)
+}
+
+// interpolate replaces the dashboard variables in s with their current values. Variables with several
+// values are replaced with the values in the glob format, for example {a,b}.
+func (pr *panelRenderer) interpolate(s string) string {
+	if !strings.ContainsAny(s, "$[") {
+		return s
+	}
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		for _, name := range groups[1:] {
+			if value, ok := pr.variables[name]; ok {
+				return value
+			}
+		}
+		return match
+	})
+}
+
+func (pr *panelRenderer) interpolateValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return pr.interpolate(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, value := range v {
+			out[k] = pr.interpolateValue(value)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = pr.interpolateValue(value)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// currentVariableValues returns the current values of the dashboard variables by name.
+func currentVariableValues(dashboard *simplejson.Json) map[string]string {
+	values := map[string]string{}
+	for _, variable := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(variable)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		current := v.GetPath("current", "value")
+		if s, err := current.String(); err == nil {
+			values[name] = s
+		} else if arr, err := current.StringArray(); err == nil {
+			if len(arr) == 1 {
+				values[name] = arr[0]
+			} else {
+				values[name] = "{" + strings.Join(arr, ",") + "}"
+			}
+		}
+	}
+	return values
+}
+
+// trimDashboardForSnapshot removes the parts of the dashboard that snapshots don't use,
+// like the frontend does before sharing a snapshot.
+func trimDashboardForSnapshot(dashboard *simplejson.Json, from, to time.Time) {
+	dashboard.Set("time", map[string]any{
+		"from": from.UTC().Format(time.RFC3339Nano),
+		"to":   to.UTC().Format(time.RFC3339Nano),
+	})
+	dashboard.Set("links", []any{})
+
+	if list, ok := dashboard.Get("annotations").CheckGet("list"); ok {
+		annotations := []any{}
+		for _, annotation := range list.MustArray() {
+			a := simplejson.NewFromAny(annotation)
+			if !a.Get("enable").MustBool() {
+				continue
+			}
+			annotations = append(annotations, map[string]any{
+				"name":      a.Get("name").Interface(),
+				"enable":    true,
+				"iconColor": a.Get("iconColor").Interface(),
+				"type":      a.Get("type").Interface(),
+				"builtIn":   a.Get("builtIn").Interface(),
+				"hide":      a.Get("hide").Interface(),
+				// Annotation queries are not run, so the annotations are left out of the snapshot.
+				"snapshotData": []any{},
+			})
+		}
+		dashboard.SetPath([]string{"annotations", "list"}, annotations)
+	}
+
+	for _, variable := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(variable)
+		if _, ok := v.CheckGet("query"); ok {
+			v.Set("query", "")
+		}
+		if _, ok := v.CheckGet("options"); ok {
+			if current, ok := v.CheckGet("current"); ok && len(current.MustMap()) > 0 {
+				v.Set("options", []any{current.Interface()})
+			} else {
+				v.Set("options", []any{})
+			}
+		}
+		if _, ok := v.CheckGet("refresh"); ok {
+			v.Set("refresh", 0)
+		}
+	}
+}
+
+func datasourceUID(j *simplejson.Json) string {
+	uid := j.Get("datasource").Get("uid").MustString()
+
+	// before 8.3 special types could be sent as datasource (expr)
+	if uid == "" {
+		uid = j.Get("datasource").MustString()
+	}
+
+	return uid
+}
+
+func errorFrame(refID string, err error) *data.Frame {
+	frame := data.NewFrame("").SetMeta(&data.FrameMeta{
+		Notices: []data.Notice{{Severity: data.NoticeSeverityError, Text: err.Error()}},
+	})
+	frame.RefID = refID
+	return frame
+}
+
+// frameToSnapshotJSON returns the frame in the DataFrameJSON format of the snapshot queries.
+func frameToSnapshotJSON(frame *data.Frame) (any, error) {
+	b, err := json.Marshal(frame)
+	if err != nil {
+		return nil, err
+	}
+	var frameJSON any
+	if err := json.Unmarshal(b, &frameJSON); err != nil {
+		return nil, err
+	}
+	return frameJSON, nil
+}
+
+func copyDashboard(dashboard *simplejson.Json) (*simplejson.Json, error) {
+	b, err := dashboard.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return simplejson.NewJson(b)
+}
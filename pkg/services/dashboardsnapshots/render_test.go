@@ -0,0 +1,221 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	libraryelementsfake "github.com/grafana/grafana/pkg/services/libraryelements/fake"
+	libraryelementsmodel "github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const testDashboard = `{
+	"uid": "dash",
+	"title": "Incident",
+	"time": {"from": "now-1h", "to": "now"},
+	"links": [{"title": "runbook"}],
+	"annotations": {"list": [
+		{"name": "Annotations & Alerts", "enable": true, "builtIn": 1, "datasource": {"uid": "grafana"}, "target": {"limit": 100}},
+		{"name": "Deploys", "enable": false, "datasource": {"uid": "loki"}}
+	]},
+	"templating": {"list": [
+		{"name": "host", "type": "query", "query": "label_values(host)", "refresh": 1,
+		 "current": {"text": "server1", "value": "server1"}, "options": [{"text": "server1", "value": "server1"}, {"text": "server2", "value": "server2"}]},
+		{"name": "env", "type": "custom", "current": {"text": ["dev", "prod"], "value": ["dev", "prod"]}}
+	]},
+	"panels": [
+		{"id": 1, "type": "timeseries", "datasource": {"uid": "prom"}, "interval": "1m", "links": [{"title": "details"}],
+		 "targets": [{"refId": "A", "expr": "up{host=\"$host\", env=~\"${env}\"}"}, {"refId": "B", "expr": "down", "hide": true}]},
+		{"id": 2, "type": "row", "collapsed": true, "panels": [
+			{"id": 3, "type": "stat", "targets": [{"refId": "A", "expr": "errors"}]}
+		]},
+		{"id": 4, "type": "text"}
+	]
+}`
+
+func TestSnapshotRenderer(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(testDashboard))
+	require.NoError(t, err)
+	signedInUser := &user.SignedInUser{OrgID: 1}
+	dsService := &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{UID: "prom", OrgID: 1},
+		{UID: "default", OrgID: 1, IsDefault: true},
+	}}
+
+	newFrame := func(name string) *data.Frame {
+		return data.NewFrame(name,
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", nil, []float64{1}),
+		)
+	}
+
+	t.Run("should embed the query results of the panels", func(t *testing.T) {
+		qs := &query.FakeQueryService{}
+		var requests []dtos.MetricRequest
+		qs.On("QueryData", mock.Anything, signedInUser, false, mock.Anything).Run(func(args mock.Arguments) {
+			requests = append(requests, args.Get(3).(dtos.MetricRequest))
+		}).Return(func(_ context.Context, _ identity.Requester, _ bool, req dtos.MetricRequest) *backend.QueryDataResponse {
+			res := backend.NewQueryDataResponse()
+			for _, q := range req.Queries {
+				refID := q.Get("refId").MustString()
+				res.Responses[refID] = backend.DataResponse{Frames: data.Frames{newFrame(q.Get("expr").MustString())}}
+			}
+			return res
+		}, nil)
+
+		renderer := NewSnapshotRenderer(qs, dsService, &libraryelementsfake.LibraryElementService{})
+		model, err := renderer.Render(context.Background(), signedInUser, dashboard, &RenderDashboardSnapshotCommand{
+			DashboardUID: "dash",
+			From:         "1700000000000",
+			To:           "1700003600000",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, requests, 2)
+		assert.Equal(t, "1700000000000", requests[0].From)
+		assert.Equal(t, "1700003600000", requests[0].To)
+		require.Len(t, requests[0].Queries, 1)
+		q := requests[0].Queries[0]
+		assert.Equal(t, `up{host="server1", env=~"{dev,prod}"}`, q.Get("expr").MustString())
+		assert.Equal(t, "prom", q.GetPath("datasource", "uid").MustString())
+		assert.Equal(t, int64(60000), q.Get("intervalMs").MustInt64())
+		assert.Equal(t, int64(defaultRenderMaxDataPoints), q.Get("maxDataPoints").MustInt64())
+		require.Len(t, requests[1].Queries, 1)
+		assert.Equal(t, "default", requests[1].Queries[0].GetPath("datasource", "uid").MustString())
+
+		b, err := json.Marshal(model)
+		require.NoError(t, err)
+		snapshot, err := simplejson.NewJson(b)
+		require.NoError(t, err)
+
+		panel := simplejson.NewFromAny(snapshot.Get("panels").MustArray()[0])
+		assert.Equal(t, "grafana", panel.GetPath("datasource", "uid").MustString())
+		assert.Empty(t, panel.Get("links").MustArray())
+		targets := panel.Get("targets").MustArray()
+		require.Len(t, targets, 1)
+		target := simplejson.NewFromAny(targets[0])
+		assert.Equal(t, "snapshot", target.Get("queryType").MustString())
+		frames := target.Get("snapshot").MustArray()
+		require.Len(t, frames, 1)
+		frame := simplejson.NewFromAny(frames[0])
+		assert.Equal(t, `up{host="server1", env=~"{dev,prod}"}`, frame.GetPath("schema", "name").MustString())
+		assert.Equal(t, "A", frame.GetPath("schema", "refId").MustString())
+
+		nested := simplejson.NewFromAny(simplejson.NewFromAny(snapshot.Get("panels").MustArray()[1]).Get("panels").MustArray()[0])
+		assert.Equal(t, "snapshot", simplejson.NewFromAny(nested.Get("targets").MustArray()[0]).Get("queryType").MustString())
+
+		text := simplejson.NewFromAny(snapshot.Get("panels").MustArray()[2])
+		_, ok := text.CheckGet("targets")
+		assert.False(t, ok)
+
+		assert.Equal(t, "2023-11-14T22:13:20Z", snapshot.GetPath("time", "from").MustString())
+		assert.Equal(t, "2023-11-14T23:13:20Z", snapshot.GetPath("time", "to").MustString())
+		assert.Empty(t, snapshot.Get("links").MustArray())
+		annotations := snapshot.GetPath("annotations", "list").MustArray()
+		require.Len(t, annotations, 1)
+		_, ok = simplejson.NewFromAny(annotations[0]).CheckGet("target")
+		assert.False(t, ok)
+		variable := simplejson.NewFromAny(snapshot.GetPath("templating", "list").MustArray()[0])
+		assert.Equal(t, "", variable.Get("query").MustString())
+		assert.Len(t, variable.Get("options").MustArray(), 1)
+		assert.Equal(t, 0, variable.Get("refresh").MustInt())
+
+		// The stored dashboard is left unchanged.
+		assert.Equal(t, "prom", simplejson.NewFromAny(dashboard.Get("panels").MustArray()[0]).GetPath("datasource", "uid").MustString())
+	})
+
+	t.Run("should embed the query errors as notices", func(t *testing.T) {
+		qs := &query.FakeQueryService{}
+		qs.On("QueryData", mock.Anything, signedInUser, false, mock.Anything).Return(nil, errors.New("data source not found"))
+
+		renderer := NewSnapshotRenderer(qs, dsService, &libraryelementsfake.LibraryElementService{})
+		model, err := renderer.Render(context.Background(), signedInUser, dashboard, &RenderDashboardSnapshotCommand{DashboardUID: "dash"})
+		require.NoError(t, err)
+
+		b, err := json.Marshal(model)
+		require.NoError(t, err)
+		snapshot, err := simplejson.NewJson(b)
+		require.NoError(t, err)
+
+		target := simplejson.NewFromAny(simplejson.NewFromAny(snapshot.Get("panels").MustArray()[0]).Get("targets").MustArray()[0])
+		frame := simplejson.NewFromAny(target.Get("snapshot").MustArray()[0])
+		notice := simplejson.NewFromAny(frame.GetPath("schema", "meta", "notices").MustArray()[0])
+		assert.Equal(t, "data source not found", notice.Get("text").MustString())
+	})
+
+	t.Run("should run the queries of library panels", func(t *testing.T) {
+		libraryPanels := &libraryelementsfake.LibraryElementService{}
+		_, err := libraryPanels.CreateElement(context.Background(), signedInUser, libraryelementsmodel.CreateLibraryElementCommand{
+			UID:   "cpu",
+			Name:  "CPU",
+			Kind:  int64(libraryelementsmodel.PanelElement),
+			Model: json.RawMessage(`{"id": 10, "gridPos": {"x": 12}, "type": "timeseries", "title": "CPU", "datasource": {"uid": "prom"}, "targets": [{"refId": "A", "expr": "cpu"}]}`),
+		})
+		require.NoError(t, err)
+		withLibraryPanel, err := simplejson.NewJson([]byte(`{
+			"panels": [{"id": 1, "gridPos": {"x": 0}, "libraryPanel": {"uid": "cpu", "name": "CPU"}}]
+		}`))
+		require.NoError(t, err)
+
+		qs := &query.FakeQueryService{}
+		var requests []dtos.MetricRequest
+		qs.On("QueryData", mock.Anything, signedInUser, false, mock.Anything).Run(func(args mock.Arguments) {
+			requests = append(requests, args.Get(3).(dtos.MetricRequest))
+		}).Return(backend.NewQueryDataResponse(), nil)
+
+		renderer := NewSnapshotRenderer(qs, dsService, libraryPanels)
+		model, err := renderer.Render(context.Background(), signedInUser, withLibraryPanel, &RenderDashboardSnapshotCommand{DashboardUID: "dash"})
+		require.NoError(t, err)
+
+		require.Len(t, requests, 1)
+		require.Len(t, requests[0].Queries, 1)
+		assert.Equal(t, "cpu", requests[0].Queries[0].Get("expr").MustString())
+		assert.Equal(t, "prom", requests[0].Queries[0].GetPath("datasource", "uid").MustString())
+
+		b, err := json.Marshal(model)
+		require.NoError(t, err)
+		snapshot, err := simplejson.NewJson(b)
+		require.NoError(t, err)
+		panel := simplejson.NewFromAny(snapshot.Get("panels").MustArray()[0])
+		assert.Equal(t, int64(1), panel.Get("id").MustInt64())
+		assert.Equal(t, int64(0), panel.GetPath("gridPos", "x").MustInt64())
+		assert.Equal(t, "CPU", panel.Get("title").MustString())
+		_, ok := panel.CheckGet("libraryPanel")
+		assert.False(t, ok)
+		assert.Equal(t, "snapshot", simplejson.NewFromAny(panel.Get("targets").MustArray()[0]).Get("queryType").MustString())
+	})
+
+	t.Run("should fail if a library panel does not exist", func(t *testing.T) {
+		withLibraryPanel, err := simplejson.NewJson([]byte(`{"panels": [{"id": 1, "libraryPanel": {"uid": "missing"}}]}`))
+		require.NoError(t, err)
+
+		renderer := NewSnapshotRenderer(&query.FakeQueryService{}, dsService, &libraryelementsfake.LibraryElementService{})
+		_, err = renderer.Render(context.Background(), signedInUser, withLibraryPanel, &RenderDashboardSnapshotCommand{DashboardUID: "dash"})
+		require.ErrorIs(t, err, ErrInvalidRenderCommand)
+		require.ErrorContains(t, err, `library panel "missing"`)
+	})
+
+	t.Run("should reject invalid time ranges", func(t *testing.T) {
+		renderer := NewSnapshotRenderer(&query.FakeQueryService{}, dsService, &libraryelementsfake.LibraryElementService{})
+		_, err := renderer.Render(context.Background(), signedInUser, dashboard, &RenderDashboardSnapshotCommand{DashboardUID: "dash", From: "now", To: "now-1h"})
+		require.ErrorIs(t, err, ErrInvalidRenderCommand)
+
+		_, err = renderer.Render(context.Background(), signedInUser, dashboard, &RenderDashboardSnapshotCommand{DashboardUID: "dash", From: "yesterday"})
+		require.ErrorIs(t, err, ErrInvalidRenderCommand)
+	})
+}
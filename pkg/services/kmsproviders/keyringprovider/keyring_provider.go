@@ -0,0 +1,216 @@
+// Package keyringprovider implements a kms provider that encrypts data keys
+// with the keys of a local keyring file.
+//
+// The keyring file is a JSON document listing the keys by identifier:
+//
+//	{
+//	  "primary": "2024-06",
+//	  "keys": [
+//	    {"id": "2024-01", "secret": "..."},
+//	    {"id": "2024-06", "secret": "..."}
+//	  ]
+//	}
+//
+// The primary key, or the last key if no primary key is set, is used to encrypt.
+// Every encrypted blob is prefixed with the identifier of its key, so it can be decrypted
+// as long as its key remains in the keyring. The keyring file is reloaded whenever it
+// changes, so keys are rotated by adding a new primary key to the file and re-encrypting
+// the data keys with `grafana cli admin secrets-migration re-encrypt-data-keys`.
+package keyringprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const keyIdDelimiter = '#'
+
+var b64 = base64.RawStdEncoding
+
+// Keyring is the content of a keyring file.
+type Keyring struct {
+	// Primary is the identifier of the key used to encrypt. Defaults to the last key.
+	Primary string `json:"primary,omitempty"`
+	Keys    []Key  `json:"keys"`
+}
+
+// Key is a key of a keyring file.
+type Key struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type keyringProvider struct {
+	path       string
+	encryption encryption.Internal
+
+	mtx     sync.Mutex
+	modTime time.Time
+	size    int64
+	primary string
+	keys    map[string]string
+
+	log log.Logger
+}
+
+// New returns the keyring provider configured in the [security.encryption.<providerID>] section,
+// whose path setting is the location of the keyring file.
+func New(cfg *setting.Cfg, encryption encryption.Internal, providerID secrets.ProviderID) (secrets.Provider, error) {
+	path := cfg.SectionWithEnvOverrides(fmt.Sprintf("security.encryption.%s", providerID)).Key("path").String()
+	if path == "" {
+		return nil, fmt.Errorf("missing keyring file path for encryption provider %s", providerID)
+	}
+
+	p := &keyringProvider{
+		path:       path,
+		encryption: encryption,
+		log:        log.New("secrets.keyring", "provider", providerID),
+	}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *keyringProvider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	id, secret, err := p.key("")
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := p.encryption.Encrypt(ctx, blob, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, b64.EncodedLen(len(id))+2)
+	b64.Encode(prefix[1:], []byte(id))
+	prefix[0] = keyIdDelimiter
+	prefix[len(prefix)-1] = keyIdDelimiter
+
+	return append(prefix, encrypted...), nil
+}
+
+func (p *keyringProvider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	if len(blob) == 0 || blob[0] != keyIdDelimiter {
+		return nil, errors.New("keyring encrypted payload is missing the key identifier")
+	}
+
+	blob = blob[1:]
+	endOfKey := bytes.IndexByte(blob, keyIdDelimiter)
+	if endOfKey == -1 {
+		return nil, errors.New("keyring encrypted payload has an invalid key identifier")
+	}
+
+	b64Id := blob[:endOfKey]
+	id := make([]byte, b64.DecodedLen(len(b64Id)))
+	if _, err := b64.Decode(id, b64Id); err != nil {
+		return nil, err
+	}
+
+	_, secret, err := p.key(string(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.encryption.Decrypt(ctx, blob[endOfKey+1:], secret)
+}
+
+// key returns the identifier and the secret of the key with the given identifier,
+// or of the primary key if id is empty, reloading the keyring file if it changed.
+func (p *keyringProvider) key(id string) (string, string, error) {
+	if err := p.reload(); err != nil {
+		p.log.Warn("Failed to reload keyring file, using the previously loaded keys", "path", p.path, "error", err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if id == "" {
+		id = p.primary
+	}
+	secret, ok := p.keys[id]
+	if !ok {
+		return "", "", fmt.Errorf("key %q not found in keyring file %s", id, p.path)
+	}
+	return id, secret, nil
+}
+
+// reload loads the keyring file if it changed since it was last loaded.
+func (p *keyringProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read keyring file: %w", err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.keys != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return nil
+	}
+
+	keyring, err := readKeyring(p.path)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]string, len(keyring.Keys))
+	for _, k := range keyring.Keys {
+		if k.ID == "" || k.Secret == "" {
+			return fmt.Errorf("invalid keyring file %s: keys must have an id and a secret", p.path)
+		}
+		if _, exists := keys[k.ID]; exists {
+			return fmt.Errorf("invalid keyring file %s: duplicate key %q", p.path, k.ID)
+		}
+		keys[k.ID] = k.Secret
+	}
+
+	primary := keyring.Primary
+	if primary == "" {
+		primary = keyring.Keys[len(keyring.Keys)-1].ID
+	}
+	if _, ok := keys[primary]; !ok {
+		return fmt.Errorf("invalid keyring file %s: primary key %q not found", p.path, primary)
+	}
+
+	if p.keys != nil && p.primary != primary {
+		p.log.Info("Keyring primary key changed", "previous", p.primary, "current", primary)
+	}
+
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	p.primary = primary
+	p.keys = keys
+	return nil
+}
+
+func readKeyring(path string) (Keyring, error) {
+	// We can ignore the gosec G304 warning since the path of the keyring file is provided by the configuration.
+	// nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Keyring{}, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+
+	var keyring Keyring
+	if err := json.Unmarshal(data, &keyring); err != nil {
+		return Keyring{}, fmt.Errorf("invalid keyring file %s: %w", path, err)
+	}
+	if len(keyring.Keys) == 0 {
+		return Keyring{}, fmt.Errorf("invalid keyring file %s: no keys", path)
+	}
+	return keyring, nil
+}
@@ -0,0 +1,98 @@
+package keyringprovider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestKeyringProvider(t *testing.T) {
+	ctx := context.Background()
+	enc := encryptionservice.SetupTestService(t)
+
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	writeKeyring := func(t *testing.T, keyring Keyring, modTime time.Time) {
+		t.Helper()
+		data, err := json.Marshal(keyring)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	raw, err := ini.Load([]byte(`
+		[security.encryption.keyring.v1]
+		path = ` + path))
+	require.NoError(t, err)
+	cfg := &setting.Cfg{Raw: raw}
+
+	start := time.Now()
+	writeKeyring(t, Keyring{Keys: []Key{{ID: "k1", Secret: "secret1"}}}, start)
+
+	provider, err := New(cfg, enc, "keyring.v1")
+	require.NoError(t, err)
+
+	encrypted, err := provider.Encrypt(ctx, []byte("grafana"))
+	require.NoError(t, err)
+
+	t.Run("should decrypt with the key of the payload", func(t *testing.T) {
+		decrypted, err := provider.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
+	})
+
+	t.Run("should use the new primary key once the keyring file changed", func(t *testing.T) {
+		writeKeyring(t, Keyring{
+			Primary: "k2",
+			Keys:    []Key{{ID: "k1", Secret: "secret1"}, {ID: "k2", Secret: "secret2"}},
+		}, start.Add(time.Minute))
+
+		rotated, err := provider.Encrypt(ctx, []byte("grafana"))
+		require.NoError(t, err)
+		assert.Equal(t, "#"+b64.EncodeToString([]byte("k2"))+"#", string(rotated[:len(b64.EncodeToString([]byte("k2")))+2]))
+
+		for _, payload := range [][]byte{encrypted, rotated} {
+			decrypted, err := provider.Decrypt(ctx, payload)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("grafana"), decrypted)
+		}
+	})
+
+	t.Run("should fail to decrypt once the key is removed", func(t *testing.T) {
+		writeKeyring(t, Keyring{Keys: []Key{{ID: "k2", Secret: "secret2"}}}, start.Add(2*time.Minute))
+
+		_, err := provider.Decrypt(ctx, encrypted)
+		require.ErrorContains(t, err, `key "k1" not found`)
+	})
+
+	t.Run("should keep the previous keys if the keyring file is invalid", func(t *testing.T) {
+		writeKeyring(t, Keyring{Primary: "unknown", Keys: []Key{{ID: "k3", Secret: "secret3"}}}, start.Add(3*time.Minute))
+
+		_, err := provider.Encrypt(ctx, []byte("grafana"))
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail to decrypt payloads without key identifier", func(t *testing.T) {
+		_, err := provider.Decrypt(ctx, []byte("grafana"))
+		require.Error(t, err)
+	})
+
+	t.Run("should fail to create provider with an invalid keyring file", func(t *testing.T) {
+		writeKeyring(t, Keyring{Keys: []Key{{ID: "k1", Secret: "secret1"}, {ID: "k1", Secret: "secret2"}}}, start)
+
+		_, err := New(cfg, enc, "keyring.v1")
+		require.ErrorContains(t, err, "duplicate key")
+
+		_, err = New(cfg, enc, "keyring.v2")
+		require.ErrorContains(t, err, "missing keyring file path")
+	})
+}
@@ -12,6 +12,16 @@ const (
 	// which fallbacks to Grafana's secret key. See the
 	// defaultprovider package for further information.
 	Default = "secretKey.v1"
+
+	// Keyring is the kind of the kms providers that use
+	// the keys of a local keyring file. See the
+	// keyringprovider package for further information.
+	Keyring = "keyring"
+
+	// HashicorpVault is the kind of the kms providers that
+	// use a Hashicorp Vault transit secrets engine. See the
+	// vaultprovider package for further information.
+	HashicorpVault = "hashicorpvault"
 )
 
 type Service interface {
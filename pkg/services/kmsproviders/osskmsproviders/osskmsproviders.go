@@ -1,10 +1,16 @@
 package osskmsproviders
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
 	grafana "github.com/grafana/grafana/pkg/services/kmsproviders/defaultprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/keyringprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/vaultprovider"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -13,6 +19,7 @@ type Service struct {
 	enc      encryption.Internal
 	cfg      *setting.Cfg
 	features featuremgmt.FeatureToggles
+	log      log.Logger
 }
 
 func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featuremgmt.FeatureToggles) Service {
@@ -20,11 +27,45 @@ func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featurem
 		enc:      enc,
 		cfg:      cfg,
 		features: features,
+		log:      log.New("kmsproviders"),
 	}
 }
 
+// Provide returns the default provider, along with the keyring and Hashicorp Vault providers
+// listed in the available_encryption_providers setting. Providers of other kinds are not
+// supported and are skipped.
 func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.cfg, s.enc),
-	}, nil
+	}
+
+	available := s.cfg.SectionWithEnvOverrides("security").Key("available_encryption_providers").String()
+	for _, id := range strings.Fields(strings.ReplaceAll(available, ",", " ")) {
+		providerID := kmsproviders.NormalizeProviderID(secrets.ProviderID(id))
+		if _, exists := providers[providerID]; exists {
+			continue
+		}
+
+		kind, err := providerID.Kind()
+		if err != nil {
+			return nil, err
+		}
+
+		var provider secrets.Provider
+		switch kind {
+		case kmsproviders.Keyring:
+			provider, err = keyringprovider.New(s.cfg, s.enc, providerID)
+		case kmsproviders.HashicorpVault:
+			provider, err = vaultprovider.New(s.cfg, providerID)
+		default:
+			s.log.Warn("Skipping unsupported encryption provider", "provider", providerID)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize encryption provider %s: %w", providerID, err)
+		}
+		providers[providerID] = provider
+	}
+
+	return providers, nil
 }
@@ -0,0 +1,57 @@
+package osskmsproviders
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Provide(t *testing.T) {
+	enc := encryptionservice.SetupTestService(t)
+
+	keyringPath := filepath.Join(t.TempDir(), "keyring.json")
+	require.NoError(t, os.WriteFile(keyringPath, []byte(`{"keys": [{"id": "k1", "secret": "secret1"}]}`), 0o600))
+
+	t.Run("should provide the available providers of supported kinds", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+			[security]
+			available_encryption_providers = secretKey keyring.v1 hashicorpvault.v1 awskms.v1
+
+			[security.encryption.keyring.v1]
+			path = ` + keyringPath + `
+
+			[security.encryption.hashicorpvault.v1]
+			url = http://localhost:8200
+			token = root
+			key_ring = grafana`))
+		require.NoError(t, err)
+
+		providers, err := ProvideService(enc, &setting.Cfg{Raw: raw}, featuremgmt.WithFeatures()).Provide()
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(providers))
+		for id := range providers {
+			ids = append(ids, string(id))
+		}
+		assert.ElementsMatch(t, []string{kmsproviders.Default, "keyring.v1", "hashicorpvault.v1"}, ids)
+	})
+
+	t.Run("should fail if an available provider is misconfigured", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+			[security]
+			available_encryption_providers = keyring.v1`))
+		require.NoError(t, err)
+
+		_, err = ProvideService(enc, &setting.Cfg{Raw: raw}, featuremgmt.WithFeatures()).Provide()
+		require.ErrorContains(t, err, "failed to initialize encryption provider keyring.v1")
+	})
+}
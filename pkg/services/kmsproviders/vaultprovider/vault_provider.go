@@ -0,0 +1,180 @@
+// Package vaultprovider implements a kms provider that encrypts data keys
+// with a named encryption key of a Hashicorp Vault transit secrets engine.
+package vaultprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	defaultTransitEnginePath    = "transit"
+	defaultTokenRenewalInterval = 5 * time.Minute
+	requestTimeout              = 30 * time.Second
+)
+
+type vaultProvider struct {
+	client               *http.Client
+	url                  *url.URL
+	token                string
+	transitEnginePath    string
+	keyRing              string
+	tokenRenewalInterval time.Duration
+
+	log log.Logger
+}
+
+// New returns the Hashicorp Vault provider configured in the [security.encryption.<providerID>] section.
+func New(cfg *setting.Cfg, providerID secrets.ProviderID) (secrets.Provider, error) {
+	section := cfg.SectionWithEnvOverrides(fmt.Sprintf("security.encryption.%s", providerID))
+
+	rawURL := section.Key("url").String()
+	if rawURL == "" {
+		return nil, fmt.Errorf("missing Hashicorp Vault url for encryption provider %s", providerID)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Hashicorp Vault url for encryption provider %s: %w", providerID, err)
+	}
+
+	token := section.Key("token").String()
+	if token == "" {
+		return nil, fmt.Errorf("missing Hashicorp Vault token for encryption provider %s", providerID)
+	}
+
+	keyRing := section.Key("key_ring").String()
+	if keyRing == "" {
+		return nil, fmt.Errorf("missing Hashicorp Vault key ring for encryption provider %s", providerID)
+	}
+
+	return &vaultProvider{
+		client:               &http.Client{Timeout: requestTimeout},
+		url:                  u,
+		token:                token,
+		transitEnginePath:    strings.Trim(section.Key("transit_engine_path").MustString(defaultTransitEnginePath), "/"),
+		keyRing:              keyRing,
+		tokenRenewalInterval: section.Key("token_renewal_interval").MustDuration(defaultTokenRenewalInterval),
+		log:                  log.New("secrets.hashicorpvault", "provider", providerID),
+	}, nil
+}
+
+type encryptRequest struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type decryptRequest struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+type transitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+}
+
+// Encrypt returns the ciphertext of the transit secrets engine, which starts with the version of the key.
+func (p *vaultProvider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp transitResponse
+	err := p.do(ctx, p.url.JoinPath("v1", p.transitEnginePath, "encrypt", p.keyRing), encryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(blob),
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with Hashicorp Vault: %w", err)
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, errors.New("failed to encrypt with Hashicorp Vault: empty ciphertext")
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+func (p *vaultProvider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp transitResponse
+	err := p.do(ctx, p.url.JoinPath("v1", p.transitEnginePath, "decrypt", p.keyRing), decryptRequest{
+		Ciphertext: string(blob),
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with Hashicorp Vault: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+// Run renews the token periodically, so that periodic service tokens do not expire.
+func (p *vaultProvider) Run(ctx context.Context) error {
+	if p.tokenRenewalInterval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(p.tokenRenewalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.do(ctx, p.url.JoinPath("v1", "auth", "token", "renew-self"), struct{}{}, nil); err != nil {
+				p.log.Error("Failed to renew Hashicorp Vault token", "error", err)
+				continue
+			}
+			p.log.Debug("Hashicorp Vault token renewed")
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (p *vaultProvider) do(ctx context.Context, u *url.URL, body any, result any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			p.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		if err := json.Unmarshal(respBody, &vaultErr); err == nil && len(vaultErr.Errors) > 0 {
+			return fmt.Errorf("status %d: %s", resp.StatusCode, strings.Join(vaultErr.Errors, ", "))
+		}
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, result)
+}
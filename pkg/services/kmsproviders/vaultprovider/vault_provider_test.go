@@ -0,0 +1,130 @@
+package vaultprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testToken = "test-token"
+
+// newTransitServer returns a server implementing the encrypt, decrypt and token renewal endpoints
+// of Hashicorp Vault, with a transit secrets engine mounted at grafana-transit.
+func newTransitServer(t *testing.T, renewals *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	writeError := func(w http.ResponseWriter, status int, msg string) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {msg}})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/grafana-transit/encrypt/grafana-key", func(w http.ResponseWriter, r *http.Request) {
+		var req encryptRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"ciphertext": "vault:v1:" + req.Plaintext}})
+	})
+	mux.HandleFunc("POST /v1/grafana-transit/decrypt/grafana-key", func(w http.ResponseWriter, r *http.Request) {
+		var req decryptRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		plaintext, ok := strings.CutPrefix(req.Ciphertext, "vault:v1:")
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid ciphertext: no prefix")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"plaintext": plaintext}})
+	})
+	mux.HandleFunc("POST /v1/auth/token/renew-self", func(w http.ResponseWriter, r *http.Request) {
+		renewals.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"auth": map[string]any{"lease_duration": 3600}})
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testToken {
+			writeError(w, http.StatusForbidden, "permission denied")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestProvider(t *testing.T, url, token string) *vaultProvider {
+	t.Helper()
+
+	raw, err := ini.Load([]byte(`
+		[security.encryption.hashicorpvault.v1]
+		url = ` + url + `
+		token = ` + token + `
+		transit_engine_path = /grafana-transit/
+		key_ring = grafana-key
+		token_renewal_interval = 10ms`))
+	require.NoError(t, err)
+
+	provider, err := New(&setting.Cfg{Raw: raw}, "hashicorpvault.v1")
+	require.NoError(t, err)
+	return provider.(*vaultProvider)
+}
+
+func TestVaultProvider(t *testing.T) {
+	ctx := context.Background()
+	var renewals atomic.Int32
+	srv := newTransitServer(t, &renewals)
+
+	t.Run("should encrypt and decrypt with the transit secrets engine", func(t *testing.T) {
+		provider := newTestProvider(t, srv.URL, testToken)
+
+		encrypted, err := provider.Encrypt(ctx, []byte("grafana"))
+		require.NoError(t, err)
+		assert.Equal(t, "vault:v1:"+base64.StdEncoding.EncodeToString([]byte("grafana")), string(encrypted))
+
+		decrypted, err := provider.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
+	})
+
+	t.Run("should return the errors of Hashicorp Vault", func(t *testing.T) {
+		provider := newTestProvider(t, srv.URL, testToken)
+		_, err := provider.Decrypt(ctx, []byte("invalid"))
+		require.ErrorContains(t, err, "status 400: invalid ciphertext: no prefix")
+
+		provider = newTestProvider(t, srv.URL, "invalid-token")
+		_, err = provider.Encrypt(ctx, []byte("grafana"))
+		require.ErrorContains(t, err, "status 403: permission denied")
+	})
+
+	t.Run("should renew the token periodically", func(t *testing.T) {
+		provider := newTestProvider(t, srv.URL, testToken)
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() { done <- provider.Run(ctx) }()
+
+		require.Eventually(t, func() bool { return renewals.Load() >= 2 }, time.Second, 10*time.Millisecond)
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("should fail to create provider with missing settings", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+			[security.encryption.hashicorpvault.v1]
+			url = ` + srv.URL))
+		require.NoError(t, err)
+
+		_, err = New(&setting.Cfg{Raw: raw}, "hashicorpvault.v1")
+		require.ErrorContains(t, err, "missing Hashicorp Vault token")
+	})
+}
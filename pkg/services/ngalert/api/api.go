@@ -50,34 +50,36 @@ type RuleAccessControlService interface {
 
 // API handlers.
 type API struct {
-	Cfg                  *setting.Cfg
-	DatasourceCache      datasources.CacheService
-	DatasourceService    datasources.DataSourceService
-	RouteRegister        routing.RouteRegister
-	QuotaService         quota.Service
-	TransactionManager   provisioning.TransactionManager
-	ProvenanceStore      provisioning.ProvisioningStore
-	RuleStore            RuleStore
-	AlertingStore        store.AlertingStore
-	AdminConfigStore     store.AdminConfigurationStore
-	DataProxy            *datasourceproxy.DataSourceProxyService
-	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
-	StateManager         *state.Manager
-	Scheduler            StatusReader
-	AccessControl        ac.AccessControl
-	Policies             *provisioning.NotificationPolicyService
-	ReceiverService      *notifier.ReceiverService
-	ContactPointService  *provisioning.ContactPointService
-	Templates            *provisioning.TemplateService
-	MuteTimings          *provisioning.MuteTimingService
-	AlertRules           *provisioning.AlertRuleService
-	AlertsRouter         *sender.AlertsRouter
-	EvaluatorFactory     eval.EvaluatorFactory
-	ConditionValidator   *eval.ConditionValidator
-	FeatureManager       featuremgmt.FeatureToggles
-	Historian            Historian
-	Tracer               tracing.Tracer
-	AppUrl               *url.URL
+	Cfg                   *setting.Cfg
+	DatasourceCache       datasources.CacheService
+	DatasourceService     datasources.DataSourceService
+	RouteRegister         routing.RouteRegister
+	QuotaService          quota.Service
+	TransactionManager    provisioning.TransactionManager
+	ProvenanceStore       provisioning.ProvisioningStore
+	RuleStore             RuleStore
+	AlertingStore         store.AlertingStore
+	AdminConfigStore      store.AdminConfigurationStore
+	DataProxy             *datasourceproxy.DataSourceProxyService
+	MultiOrgAlertmanager  *notifier.MultiOrgAlertmanager
+	StateManager          *state.Manager
+	Scheduler             StatusReader
+	AccessControl         ac.AccessControl
+	Policies              *provisioning.NotificationPolicyService
+	ReceiverService       *notifier.ReceiverService
+	ContactPointService   *provisioning.ContactPointService
+	Templates             *provisioning.TemplateService
+	MuteTimings           *provisioning.MuteTimingService
+	AlertRules            *provisioning.AlertRuleService
+	AlertsRouter          *sender.AlertsRouter
+	EvaluatorFactory      eval.EvaluatorFactory
+	ConditionValidator    *eval.ConditionValidator
+	FeatureManager        featuremgmt.FeatureToggles
+	Historian             Historian
+	NotificationHistorian NotificationHistorian
+	SilenceSchedules      SilenceScheduleService
+	Tracer                tracing.Tracer
+	AppUrl                *url.URL
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -168,6 +170,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		alertInstances:      api.StateManager,
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
 	}), m)
@@ -178,8 +181,11 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	}), m)
 
 	api.RegisterNotificationsApiEndpoints(NewNotificationsApi(&NotificationSrv{
-		logger:            logger,
-		receiverService:   api.ReceiverService,
-		muteTimingService: api.MuteTimings,
+		logger:                       logger,
+		receiverService:              api.ReceiverService,
+		muteTimingService:            api.MuteTimings,
+		notificationHistorian:        api.NotificationHistorian,
+		notificationHistoryRetention: api.Cfg.UnifiedAlerting.NotificationHistory.Retention,
+		silenceSchedules:             api.SilenceSchedules,
 	}), m)
 }
@@ -2,19 +2,26 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 type NotificationSrv struct {
-	logger            log.Logger
-	receiverService   ReceiverService
-	muteTimingService MuteTimingService // defined in api_provisioning.go
+	logger                       log.Logger
+	receiverService              ReceiverService
+	muteTimingService            MuteTimingService // defined in api_provisioning.go
+	notificationHistorian        NotificationHistorian
+	notificationHistoryRetention time.Duration
+	silenceSchedules             SilenceScheduleService
 }
 
 type ReceiverService interface {
@@ -22,6 +29,18 @@ type ReceiverService interface {
 	ListReceivers(ctx context.Context, q models.ListReceiversQuery, user identity.Requester) ([]*models.Receiver, error)
 }
 
+type NotificationHistorian interface {
+	Query(ctx context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+}
+
+type SilenceScheduleService interface {
+	ListSilenceSchedules(ctx context.Context, user identity.Requester) ([]*models.SilenceSchedule, error)
+	GetSilenceSchedule(ctx context.Context, user identity.Requester, uid string) (*models.SilenceSchedule, error)
+	CreateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) (*models.SilenceSchedule, error)
+	UpdateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) (*models.SilenceSchedule, error)
+	DeleteSilenceSchedule(ctx context.Context, user identity.Requester, uid string) error
+}
+
 func (srv *NotificationSrv) RouteGetTimeInterval(c *contextmodel.ReqContext, name string) response.Response {
 	muteTimeInterval, err := srv.muteTimingService.GetMuteTiming(c.Req.Context(), name, c.OrgID)
 	if err != nil {
@@ -78,3 +97,120 @@ func (srv *NotificationSrv) RouteGetReceivers(c *contextmodel.ReqContext) respon
 
 	return response.JSON(http.StatusOK, gettables)
 }
+
+// RouteGetNotificationHistory returns the notifications sent by the contact points that the user can read, the latest first.
+// The time range of the query is limited to the retention of the notification history.
+func (srv *NotificationSrv) RouteGetNotificationHistory(c *contextmodel.ReqContext) response.Response {
+	now := time.Now()
+	to := now
+	if v := c.QueryInt64("to"); v > 0 {
+		to = time.Unix(v, 0)
+	}
+	from := now.Add(-srv.notificationHistoryRetention)
+	if v := c.QueryInt64("from"); v > 0 && time.Unix(v, 0).After(from) {
+		from = time.Unix(v, 0)
+	}
+	if from.After(to) {
+		return ErrResp(http.StatusBadRequest, errors.New("the start of the time range must be before its end"), "")
+	}
+
+	status := models.NotificationStatus(c.Query("status"))
+	if status != "" && status != models.NotificationStatusSuccess && status != models.NotificationStatusFailure {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status '%s', expected '%s' or '%s'", status, models.NotificationStatusSuccess, models.NotificationStatusFailure), "")
+	}
+
+	query := models.NotificationHistoryQuery{
+		OrgID:            c.SignedInUser.GetOrgID(),
+		Receiver:         c.Query("receiver"),
+		Integration:      c.Query("integration"),
+		Status:           status,
+		GroupKey:         c.Query("groupKey"),
+		AlertFingerprint: c.Query("fingerprint"),
+		From:             from,
+		To:               to,
+		Limit:            c.QueryInt("limit"),
+		SignedInUser:     c.SignedInUser,
+	}
+
+	// Only the history of the contact points the user can read is returned.
+	receivers, err := srv.receiverService.ListReceivers(c.Req.Context(), models.ListReceiversQuery{OrgID: query.OrgID}, c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get receivers", err)
+	}
+	readable := make(map[string]struct{}, len(receivers))
+	for _, r := range receivers {
+		readable[r.Name] = struct{}{}
+	}
+
+	entries, err := srv.notificationHistorian.Query(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to query notification history", err)
+	}
+
+	result := make(definitions.GettableNotificationHistory, 0, len(entries))
+	for _, e := range entries {
+		if _, ok := readable[e.Receiver]; !ok {
+			continue
+		}
+		result = append(result, GettableNotificationHistoryEntryFromModel(e))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *NotificationSrv) RouteGetSilenceSchedules(c *contextmodel.ReqContext) response.Response {
+	schedules, err := srv.silenceSchedules.ListSilenceSchedules(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return silenceScheduleErrorResponse(err, "failed to list silence schedules")
+	}
+
+	result := make(definitions.GettableSilenceSchedules, 0, len(schedules))
+	for _, s := range schedules {
+		result = append(result, GettableSilenceScheduleFromModel(s))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *NotificationSrv) RouteGetSilenceSchedule(c *contextmodel.ReqContext, uid string) response.Response {
+	schedule, err := srv.silenceSchedules.GetSilenceSchedule(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return silenceScheduleErrorResponse(err, "failed to get silence schedule")
+	}
+	return response.JSON(http.StatusOK, GettableSilenceScheduleFromModel(schedule))
+}
+
+func (srv *NotificationSrv) RouteCreateSilenceSchedule(c *contextmodel.ReqContext, body definitions.PostableSilenceSchedule) response.Response {
+	schedule, err := srv.silenceSchedules.CreateSilenceSchedule(c.Req.Context(), c.SignedInUser, SilenceScheduleFromPostable(body))
+	if err != nil {
+		return silenceScheduleErrorResponse(err, "failed to create silence schedule")
+	}
+	return response.JSON(http.StatusCreated, GettableSilenceScheduleFromModel(schedule))
+}
+
+func (srv *NotificationSrv) RouteUpdateSilenceSchedule(c *contextmodel.ReqContext, body definitions.PostableSilenceSchedule, uid string) response.Response {
+	update := SilenceScheduleFromPostable(body)
+	update.UID = uid
+	schedule, err := srv.silenceSchedules.UpdateSilenceSchedule(c.Req.Context(), c.SignedInUser, update)
+	if err != nil {
+		return silenceScheduleErrorResponse(err, "failed to update silence schedule")
+	}
+	return response.JSON(http.StatusOK, GettableSilenceScheduleFromModel(schedule))
+}
+
+func (srv *NotificationSrv) RouteDeleteSilenceSchedule(c *contextmodel.ReqContext, uid string) response.Response {
+	if err := srv.silenceSchedules.DeleteSilenceSchedule(c.Req.Context(), c.SignedInUser, uid); err != nil {
+		return silenceScheduleErrorResponse(err, "failed to delete silence schedule")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func silenceScheduleErrorResponse(err error, msg string) response.Response {
+	switch {
+	case errors.Is(err, models.ErrSilenceScheduleNotFound):
+		return ErrResp(http.StatusNotFound, err, "")
+	case errors.Is(err, models.ErrSilenceScheduleInvalid):
+		return ErrResp(http.StatusBadRequest, err, "")
+	case errors.Is(err, models.ErrSilenceScheduleVersionConflict):
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, msg, err)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -24,8 +26,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -384,6 +389,243 @@ func createNotificationSrvSutFromEnv(t *testing.T, env *testEnvironment) Notific
 	}
 }
 
+func TestRouteGetNotificationHistory(t *testing.T) {
+	now := time.Now()
+	entries := []models.NotificationHistoryEntry{
+		{
+			Timestamp:   now.Add(-time.Minute),
+			OrgID:       1,
+			GroupKey:    "{}:{alertname=\"test\"}",
+			Receiver:    "receiver1",
+			Integration: "slack",
+			Status:      models.NotificationStatusFailure,
+			Error:       "unexpected status code 500",
+			Retry:       true,
+			Duration:    1500 * time.Millisecond,
+			Alerts: []models.NotificationHistoryAlert{
+				{Fingerprint: "abc", Status: "firing", Labels: map[string]string{"alertname": "test"}},
+			},
+		},
+		{
+			Timestamp:   now.Add(-2 * time.Minute),
+			OrgID:       1,
+			Receiver:    "receiver2",
+			Integration: "email",
+			Status:      models.NotificationStatusSuccess,
+		},
+	}
+
+	setup := func(readable ...string) (*NotificationsApiHandler, *fakeNotificationHistorian) {
+		fakeReceiverSvc := fakes.NewFakeReceiverService()
+		fakeReceiverSvc.ListReceiversFn = func(ctx context.Context, q models.ListReceiversQuery, u identity.Requester) ([]*models.Receiver, error) {
+			result := make([]*models.Receiver, 0, len(readable))
+			for _, name := range readable {
+				result = append(result, &models.Receiver{Name: name})
+			}
+			return result, nil
+		}
+		historian := &fakeNotificationHistorian{entries: entries}
+		srv := newNotificationSrv(fakeReceiverSvc)
+		srv.notificationHistorian = historian
+		srv.notificationHistoryRetention = 24 * time.Hour
+		return NewNotificationsApi(srv), historian
+	}
+
+	t.Run("returns the history of the readable receivers", func(t *testing.T) {
+		handler, _ := setup("receiver1")
+		rc := testReqCtx("GET")
+		resp := handler.handleRouteGetNotificationHistory(&rc)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result definitions.GettableNotificationHistory
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result, 1)
+		require.Equal(t, "receiver1", result[0].Receiver)
+		require.Equal(t, "failure", result[0].Status)
+		require.Equal(t, "unexpected status code 500", result[0].Error)
+		require.Equal(t, int64(1500), result[0].Duration)
+		require.True(t, result[0].Retry)
+		require.Len(t, result[0].Alerts, 1)
+		require.Equal(t, "abc", result[0].Alerts[0].Fingerprint)
+	})
+
+	t.Run("builds query from request context", func(t *testing.T) {
+		handler, historian := setup("receiver1", "receiver2")
+		rc := testReqCtx("GET")
+		rc.Context.Req.Form.Set("receiver", "receiver1")
+		rc.Context.Req.Form.Set("integration", "slack")
+		rc.Context.Req.Form.Set("status", "failure")
+		rc.Context.Req.Form.Set("groupKey", "key")
+		rc.Context.Req.Form.Set("fingerprint", "abc")
+		rc.Context.Req.Form.Set("limit", "10")
+		rc.Context.Req.Form.Set("from", strconv.FormatInt(now.Add(-time.Hour).Unix(), 10))
+		resp := handler.handleRouteGetNotificationHistory(&rc)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		q := historian.lastQuery
+		require.Equal(t, int64(1), q.OrgID)
+		require.Equal(t, "receiver1", q.Receiver)
+		require.Equal(t, "slack", q.Integration)
+		require.Equal(t, models.NotificationStatusFailure, q.Status)
+		require.Equal(t, "key", q.GroupKey)
+		require.Equal(t, "abc", q.AlertFingerprint)
+		require.Equal(t, 10, q.Limit)
+		require.Equal(t, now.Add(-time.Hour).Unix(), q.From.Unix())
+	})
+
+	t.Run("limits the time range to the retention", func(t *testing.T) {
+		handler, historian := setup("receiver1")
+		rc := testReqCtx("GET")
+		rc.Context.Req.Form.Set("from", strconv.FormatInt(now.Add(-48*time.Hour).Unix(), 10))
+		resp := handler.handleRouteGetNotificationHistory(&rc)
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.WithinDuration(t, now.Add(-24*time.Hour), historian.lastQuery.From, time.Minute)
+	})
+
+	t.Run("rejects invalid queries", func(t *testing.T) {
+		handler, _ := setup("receiver1")
+		rc := testReqCtx("GET")
+		rc.Context.Req.Form.Set("status", "unknown")
+		resp := handler.handleRouteGetNotificationHistory(&rc)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		rc = testReqCtx("GET")
+		rc.Context.Req.Form.Set("from", strconv.FormatInt(now.Add(-time.Hour).Unix(), 10))
+		rc.Context.Req.Form.Set("to", strconv.FormatInt(now.Add(-2*time.Hour).Unix(), 10))
+		resp = handler.handleRouteGetNotificationHistory(&rc)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
+
+func TestRouteSilenceSchedules(t *testing.T) {
+	postable := definitions.PostableSilenceSchedule{
+		Matchers: amv2.Matchers{{
+			Name:    util.Pointer("team"),
+			Value:   util.Pointer("ops"),
+			IsEqual: util.Pointer(true),
+			IsRegex: util.Pointer(false),
+		}},
+		Comment:  "weekly maintenance",
+		Schedule: "0 22 * * SAT",
+		Timezone: "Europe/Berlin",
+		Duration: model.Duration(2 * time.Hour),
+	}
+
+	setup := func() (*NotificationsApiHandler, *fakeSilenceScheduleService) {
+		svc := &fakeSilenceScheduleService{}
+		srv := newNotificationSrv(fakes.NewFakeReceiverService())
+		srv.silenceSchedules = svc
+		return NewNotificationsApi(srv), svc
+	}
+
+	t.Run("create converts the request to a schedule", func(t *testing.T) {
+		handler, svc := setup()
+		svc.result = &models.SilenceSchedule{
+			UID:      "uid",
+			Matchers: postable.Matchers,
+			Schedule: postable.Schedule,
+			Timezone: postable.Timezone,
+			Duration: 2 * time.Hour,
+			Occurrences: []models.SilenceScheduleOccurrence{
+				{SilenceID: "silence", StartsAt: time.Unix(0, 0).UTC(), EndsAt: time.Unix(7200, 0).UTC()},
+			},
+		}
+		rc := testReqCtx("POST")
+		resp := handler.handleRouteCreateSilenceSchedule(&rc, postable)
+		require.Equal(t, http.StatusCreated, resp.Status())
+
+		require.Equal(t, "Europe/Berlin", svc.lastSchedule.Timezone)
+		require.Equal(t, 2*time.Hour, svc.lastSchedule.Duration)
+		require.Equal(t, postable.Matchers, svc.lastSchedule.Matchers)
+
+		var result definitions.GettableSilenceSchedule
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, "uid", result.UID)
+		require.Equal(t, model.Duration(2*time.Hour), result.Duration)
+		require.Len(t, result.Occurrences, 1)
+		require.Equal(t, "silence", result.Occurrences[0].SilenceID)
+	})
+
+	t.Run("update uses the UID of the path", func(t *testing.T) {
+		handler, svc := setup()
+		svc.result = &models.SilenceSchedule{UID: "uid"}
+		rc := testReqCtx("PUT")
+		resp := handler.handleRouteUpdateSilenceSchedule(&rc, postable, "uid")
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, "uid", svc.lastSchedule.UID)
+	})
+
+	t.Run("maps errors to status codes", func(t *testing.T) {
+		testCases := []struct {
+			err    error
+			status int
+		}{
+			{err: models.ErrSilenceScheduleNotFound, status: http.StatusNotFound},
+			{err: fmt.Errorf("%w: invalid schedule", models.ErrSilenceScheduleInvalid), status: http.StatusBadRequest},
+			{err: models.ErrSilenceScheduleVersionConflict, status: http.StatusConflict},
+			{err: errors.New("unexpected"), status: http.StatusInternalServerError},
+		}
+		for _, tc := range testCases {
+			handler, svc := setup()
+			svc.err = tc.err
+
+			rc := testReqCtx("GET")
+			require.Equal(t, tc.status, handler.handleRouteGetSilenceSchedule(&rc, "uid").Status())
+			rc = testReqCtx("PUT")
+			require.Equal(t, tc.status, handler.handleRouteUpdateSilenceSchedule(&rc, postable, "uid").Status())
+			rc = testReqCtx("DELETE")
+			require.Equal(t, tc.status, handler.handleRouteDeleteSilenceSchedule(&rc, "uid").Status())
+		}
+	})
+
+	t.Run("delete returns no content", func(t *testing.T) {
+		handler, _ := setup()
+		rc := testReqCtx("DELETE")
+		require.Equal(t, http.StatusNoContent, handler.handleRouteDeleteSilenceSchedule(&rc, "uid").Status())
+	})
+}
+
+type fakeSilenceScheduleService struct {
+	result       *models.SilenceSchedule
+	err          error
+	lastSchedule models.SilenceSchedule
+}
+
+func (f *fakeSilenceScheduleService) ListSilenceSchedules(_ context.Context, _ identity.Requester) ([]*models.SilenceSchedule, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*models.SilenceSchedule{f.result}, nil
+}
+
+func (f *fakeSilenceScheduleService) GetSilenceSchedule(_ context.Context, _ identity.Requester, _ string) (*models.SilenceSchedule, error) {
+	return f.result, f.err
+}
+
+func (f *fakeSilenceScheduleService) CreateSilenceSchedule(_ context.Context, _ identity.Requester, schedule models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	f.lastSchedule = schedule
+	return f.result, f.err
+}
+
+func (f *fakeSilenceScheduleService) UpdateSilenceSchedule(_ context.Context, _ identity.Requester, schedule models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	f.lastSchedule = schedule
+	return f.result, f.err
+}
+
+func (f *fakeSilenceScheduleService) DeleteSilenceSchedule(_ context.Context, _ identity.Requester, _ string) error {
+	return f.err
+}
+
+type fakeNotificationHistorian struct {
+	entries   []models.NotificationHistoryEntry
+	lastQuery models.NotificationHistoryQuery
+}
+
+func (f *fakeNotificationHistorian) Query(_ context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	f.lastQuery = query
+	return f.entries, nil
+}
+
 func newNotificationSrv(receiverService ReceiverService) *NotificationSrv {
 	return &NotificationSrv{
 		logger:          log.NewNopLogger(),
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
@@ -17,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -30,6 +34,7 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	alertInstances      state.AlertInstanceManager
 	folderSvc           folder.Service
 
 	// XXX: Used to flag recording rules, remove when FT is removed
@@ -54,6 +59,7 @@ type NotificationPolicyService interface {
 	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, string, error)
 	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance, version string) error
 	ResetPolicyTree(ctx context.Context, orgID int64, provenance alerting_models.Provenance) (definitions.Route, error)
+	SimulateRouting(ctx context.Context, orgID int64, draft *definitions.Route, labelSets []model.LabelSet, at time.Time) ([]definitions.RoutingSimulationResult, error)
 }
 
 type MuteTimingService interface {
@@ -132,6 +138,53 @@ func (srv *ProvisioningSrv) RouteResetPolicyTree(c *contextmodel.ReqContext) res
 	return response.JSON(http.StatusAccepted, tree)
 }
 
+func (srv *ProvisioningSrv) RoutePostPolicyTreeSimulation(c *contextmodel.ReqContext, req definitions.RoutingSimulationRequest) response.Response {
+	if (len(req.Labels) == 0) == (req.RuleUID == "") {
+		return ErrResp(http.StatusBadRequest, errors.New("exactly one of labels and ruleUid must be set"), "")
+	}
+
+	var labelSets []model.LabelSet
+	if req.RuleUID != "" {
+		rule, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.SignedInUser, req.RuleUID)
+		if err != nil {
+			if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+		}
+		for _, s := range srv.alertInstances.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			ls := make(model.LabelSet, len(s.Labels))
+			for k, v := range s.Labels {
+				ls[model.LabelName(k)] = model.LabelValue(v)
+			}
+			labelSets = append(labelSets, ls)
+		}
+	} else {
+		ls := make(model.LabelSet, len(req.Labels))
+		for k, v := range req.Labels {
+			ls[model.LabelName(k)] = model.LabelValue(v)
+		}
+		labelSets = append(labelSets, ls)
+	}
+
+	at := time.Now()
+	if req.Time != nil {
+		at = *req.Time
+	}
+	results, err := srv.policies.SimulateRouting(c.Req.Context(), c.SignedInUser.GetOrgID(), req.Route, labelSets, at)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to simulate notification routing", err)
+	}
+
+	return response.JSON(http.StatusOK, definitions.RoutingSimulationResults(results))
+}
+
 func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) response.Response {
 	q := provisioning.ContactPointQuery{
 		Name:  c.Query("name"),
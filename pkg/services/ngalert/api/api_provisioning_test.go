@@ -91,6 +91,62 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 202, response.Status())
 		})
 
+		t.Run("successful simulation returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeSimulation(&rc, definitions.RoutingSimulationRequest{
+				Labels: map[string]string{"alertname": "test"},
+			})
+
+			require.Equal(t, 200, response.Status())
+			var results definitions.RoutingSimulationResults
+			require.NoError(t, json.Unmarshal(response.Body(), &results))
+			require.Len(t, results, 1)
+			require.Equal(t, map[string]string{"alertname": "test"}, results[0].Labels)
+			require.Equal(t, "default-receiver", results[0].Routes[0].Receiver)
+		})
+
+		t.Run("simulation uses the draft policy tree", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeSimulation(&rc, definitions.RoutingSimulationRequest{
+				Labels: map[string]string{"alertname": "test"},
+				Route:  &definitions.Route{Receiver: "draft-receiver"},
+			})
+
+			require.Equal(t, 200, response.Status())
+			var results definitions.RoutingSimulationResults
+			require.NoError(t, json.Unmarshal(response.Body(), &results))
+			require.Equal(t, "draft-receiver", results[0].Routes[0].Receiver)
+		})
+
+		t.Run("simulation returns 400 if not exactly one of labels and rule UID is set", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeSimulation(&rc, definitions.RoutingSimulationRequest{})
+			require.Equal(t, 400, response.Status())
+
+			response = sut.RoutePostPolicyTreeSimulation(&rc, definitions.RoutingSimulationRequest{
+				Labels:  map[string]string{"alertname": "test"},
+				RuleUID: "rule-uid",
+			})
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("simulation returns 404 if the rule does not exist", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeSimulation(&rc, definitions.RoutingSimulationRequest{
+				RuleUID: "does-not-exist",
+			})
+
+			require.Equal(t, 404, response.Status())
+		})
+
 		t.Run("when new policy tree is invalid", func(t *testing.T) {
 			t.Run("PUT returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -106,6 +162,19 @@ func TestProvisioningApi(t *testing.T) {
 				require.NoError(t, marshalErr)
 				require.Equal(t, string(expBodyJSON), string(response.Body()))
 			})
+
+			t.Run("simulation returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyTreeSimulation(&rc, definitions.RoutingSimulationRequest{
+					Labels: map[string]string{"alertname": "test"},
+					Route:  &definitions.Route{},
+				})
+
+				require.Equal(t, 400, response.Status())
+			})
 		})
 
 		t.Run("when org has no AM config", func(t *testing.T) {
@@ -2049,6 +2118,28 @@ func (f *fakeNotificationPolicyService) ResetPolicyTree(ctx context.Context, org
 	return f.tree, nil
 }
 
+func (f *fakeNotificationPolicyService) SimulateRouting(ctx context.Context, orgID int64, draft *definitions.Route, labelSets []model.LabelSet, at time.Time) ([]definitions.RoutingSimulationResult, error) {
+	if orgID != 1 {
+		return nil, store.ErrNoAlertmanagerConfiguration
+	}
+	tree := f.tree
+	if draft != nil {
+		tree = *draft
+	}
+	results := make([]definitions.RoutingSimulationResult, 0, len(labelSets))
+	for _, ls := range labelSets {
+		labels := make(map[string]string, len(ls))
+		for k, v := range ls {
+			labels[string(k)] = string(v)
+		}
+		results = append(results, definitions.RoutingSimulationResult{
+			Labels: labels,
+			Routes: []definitions.MatchedRoute{{Path: []int{}, Receiver: tree.Receiver}},
+		})
+	}
+	return results, nil
+}
+
 type fakeFailingNotificationPolicyService struct{}
 
 func (f *fakeFailingNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, string, error) {
@@ -2063,6 +2154,10 @@ func (f *fakeFailingNotificationPolicyService) ResetPolicyTree(ctx context.Conte
 	return definitions.Route{}, fmt.Errorf("something went wrong")
 }
 
+func (f *fakeFailingNotificationPolicyService) SimulateRouting(ctx context.Context, orgID int64, draft *definitions.Route, labelSets []model.LabelSet, at time.Time) ([]definitions.RoutingSimulationResult, error) {
+	return nil, fmt.Errorf("something went wrong")
+}
+
 type fakeRejectingNotificationPolicyService struct{}
 
 func (f *fakeRejectingNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, string, error) {
@@ -2077,6 +2172,10 @@ func (f *fakeRejectingNotificationPolicyService) ResetPolicyTree(ctx context.Con
 	return definitions.Route{}, nil
 }
 
+func (f *fakeRejectingNotificationPolicyService) SimulateRouting(ctx context.Context, orgID int64, draft *definitions.Route, labelSets []model.LabelSet, at time.Time) ([]definitions.RoutingSimulationResult, error) {
+	return nil, fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
+}
+
 func createInvalidContactPoint() definitions.EmbeddedContactPoint {
 	settings, _ := simplejson.NewJson([]byte(`{}`))
 	return definitions.EmbeddedContactPoint{
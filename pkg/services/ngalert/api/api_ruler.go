@@ -366,148 +366,207 @@ func (srv RulerSrv) checkGroupLimits(group apimodels.PostableRuleGroupConfig) er
 
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
 // All operations are performed in a single transaction
-//
-//nolint:gocyclo
 func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
+	finalChanges, err := srv.applyAlertRulesInGroup(c, groupKey, rules, false)
+	if err != nil {
+		return toRuleGroupUpdateErrorResponse(err)
+	}
+	return changesToResponse(finalChanges)
+}
+
+// applyAlertRulesInGroup calculates and authorizes the changes to the group and, unless dryRun is set, saves them.
+func (srv RulerSrv) applyAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, dryRun bool) (*store.GroupDelta, error) {
+	return srv.applyGroupChanges(c, groupKey, dryRun, func(ctx context.Context) (*store.GroupDelta, error) {
+		return store.CalculateChanges(ctx, srv.store, groupKey, rules)
+	})
+}
+
+// applyGroupChanges authorizes the changes to the group returned by calculateChanges and, unless dryRun is set, saves them.
+// The changes are calculated in the same transaction as they are saved.
+func (srv RulerSrv) applyGroupChanges(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, dryRun bool, calculateChanges func(context.Context) (*store.GroupDelta, error)) (*store.GroupDelta, error) {
 	var finalChanges *store.GroupDelta
 	var dbConfig *ngmodels.AlertConfiguration
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
-		id, _ := c.SignedInUser.GetInternalID()
-		userNamespace := c.SignedInUser.GetIdentityType()
-
-		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
-			groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
-		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+		var err error
+		finalChanges, dbConfig, err = srv.prepareGroupChanges(tranCtx, c, calculateChanges)
 		if err != nil {
 			return err
 		}
-
-		if groupChanges.IsEmpty() {
-			finalChanges = groupChanges
-			logger.Info("No changes detected in the request. Do nothing")
+		if dryRun {
+			srv.groupChangesLogger(c, groupKey).Debug("Dry run, changes are not saved", "add", len(finalChanges.New), "update", len(finalChanges.Update), "delete", len(finalChanges.Delete))
 			return nil
 		}
+		return srv.saveGroupChanges(tranCtx, c, finalChanges)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		srv.refreshAlertmanagerConfig(c, groupKey.OrgID, dbConfig)
+	}
+
+	return finalChanges, nil
+}
+
+// prepareGroupChanges calculates the changes to a group, verifies that the user is authorized to do them and validates them.
+// If the changes update notification settings, it also returns the Alertmanager configuration they were validated against.
+func (srv RulerSrv) prepareGroupChanges(tranCtx context.Context, c *contextmodel.ReqContext, calculateChanges func(context.Context) (*store.GroupDelta, error)) (*store.GroupDelta, *ngmodels.AlertConfiguration, error) {
+	groupChanges, err := calculateChanges(tranCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger := srv.groupChangesLogger(c, groupChanges.GroupKey)
+	if groupChanges.IsEmpty() {
+		logger.Info("No changes detected in the request. Do nothing")
+		return groupChanges, nil, nil
+	}
 
-		err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
+		return nil, nil, err
+	}
+
+	var dbConfig *ngmodels.AlertConfiguration
+	newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
+	if len(newOrUpdatedNotificationSettings) > 0 {
+		dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
 		if err != nil {
-			return err
+			return nil, nil, fmt.Errorf("failed to get latest configuration: %w", err)
 		}
-
-		if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
-			return err
+		cfg, err := notifier.Load([]byte(dbConfig.AlertmanagerConfiguration))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse configuration: %w", err)
 		}
-
-		newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
-		if len(newOrUpdatedNotificationSettings) > 0 {
-			dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
-			if err != nil {
-				return fmt.Errorf("failed to get latest configuration: %w", err)
-			}
-			cfg, err := notifier.Load([]byte(dbConfig.AlertmanagerConfiguration))
-			if err != nil {
-				return fmt.Errorf("failed to parse configuration: %w", err)
-			}
-			validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
-			for _, s := range newOrUpdatedNotificationSettings {
-				if err := validator.Validate(s); err != nil {
-					return errors.Join(ngmodels.ErrAlertRuleFailedValidation, err)
-				}
+		validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
+		for _, s := range newOrUpdatedNotificationSettings {
+			if err := validator.Validate(s); err != nil {
+				return nil, nil, errors.Join(ngmodels.ErrAlertRuleFailedValidation, err)
 			}
 		}
+	}
 
-		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
-			return err
-		}
+	if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
+		return nil, nil, err
+	}
 
-		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
-		logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+	return store.UpdateCalculatedRuleFields(groupChanges), dbConfig, nil
+}
 
-		// Delete first as this could prevent future unique constraint violations.
-		if len(finalChanges.Delete) > 0 {
-			UIDs := make([]string, 0, len(finalChanges.Delete))
-			for _, rule := range finalChanges.Delete {
-				UIDs = append(UIDs, rule.UID)
-			}
+// saveGroupChanges saves the changes returned by prepareGroupChanges in the transaction of tranCtx.
+func (srv RulerSrv) saveGroupChanges(tranCtx context.Context, c *contextmodel.ReqContext, finalChanges *store.GroupDelta) error {
+	if finalChanges.IsEmpty() {
+		return nil
+	}
+	logger := srv.groupChangesLogger(c, finalChanges.GroupKey)
 
-			if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
-				return fmt.Errorf("failed to delete rules: %w", err)
-			}
+	// Record the author of the changes so that they are listed in the versions of the rules
+	updatedBy := ngmodels.NewUserUID(c.SignedInUser)
+	for _, rule := range finalChanges.New {
+		rule.UpdatedBy = updatedBy
+	}
+	for _, update := range finalChanges.Update {
+		update.New.UpdatedBy = updatedBy
+	}
+	logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+
+	// Delete first as this could prevent future unique constraint violations.
+	if len(finalChanges.Delete) > 0 {
+		UIDs := make([]string, 0, len(finalChanges.Delete))
+		for _, rule := range finalChanges.Delete {
+			UIDs = append(UIDs, rule.UID)
 		}
 
-		if len(finalChanges.Update) > 0 {
-			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
-			for _, update := range finalChanges.Update {
-				logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
-				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
-			}
-			err = srv.store.UpdateAlertRules(tranCtx, updates)
-			if err != nil {
-				return fmt.Errorf("failed to update rules: %w", err)
-			}
+		if err := srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
+			return fmt.Errorf("failed to delete rules: %w", err)
 		}
+	}
 
-		if len(finalChanges.New) > 0 {
-			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
-			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
-			}
-			added, err := srv.store.InsertAlertRules(tranCtx, inserts)
-			if err != nil {
-				return fmt.Errorf("failed to add rules: %w", err)
-			}
-			if len(added) != len(finalChanges.New) {
-				logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
-			} else {
-				for i, newRule := range finalChanges.New {
-					newRule.ID = added[i].ID
-					newRule.UID = added[i].UID
-				}
-			}
+	if len(finalChanges.Update) > 0 {
+		updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+		for _, update := range finalChanges.Update {
+			logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
+		}
+		if err := srv.store.UpdateAlertRules(tranCtx, updates); err != nil {
+			return fmt.Errorf("failed to update rules: %w", err)
 		}
+	}
 
-		if len(finalChanges.New) > 0 {
-			userID, _ := identity.UserIdentifier(c.SignedInUser.GetID())
-			limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
-				OrgID:  c.SignedInUser.GetOrgID(),
-				UserID: userID,
-			}) // alert rule is table name
-			if err != nil {
-				return fmt.Errorf("failed to get alert rules quota: %w", err)
-			}
-			if limitReached {
-				return ngmodels.ErrQuotaReached
+	if len(finalChanges.New) > 0 {
+		inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
+		for _, rule := range finalChanges.New {
+			inserts = append(inserts, *rule)
+		}
+		added, err := srv.store.InsertAlertRules(tranCtx, inserts)
+		if err != nil {
+			return fmt.Errorf("failed to add rules: %w", err)
+		}
+		if len(added) != len(finalChanges.New) {
+			logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
+		} else {
+			for i, newRule := range finalChanges.New {
+				newRule.ID = added[i].ID
+				newRule.UID = added[i].UID
 			}
 		}
-		return nil
-	})
 
-	if err != nil {
-		if errors.As(err, &errutil.Error{}) {
-			return response.Err(err)
-		} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
-			return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		} else if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		userID, _ := identity.UserIdentifier(c.SignedInUser.GetID())
+		limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
+			OrgID:  c.SignedInUser.GetOrgID(),
+			UserID: userID,
+		}) // alert rule is table name
+		if err != nil {
+			return fmt.Errorf("failed to get alert rules quota: %w", err)
+		}
+		if limitReached {
+			return ngmodels.ErrQuotaReached
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
+	return nil
+}
 
-	if srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingSimplifiedRouting) && dbConfig != nil {
-		// This isn't strictly necessary since the alertmanager config is periodically synced.
-		err := srv.amRefresher.ApplyConfig(c.Req.Context(), groupKey.OrgID, dbConfig)
-		if err != nil {
-			srv.log.Warn("Failed to refresh Alertmanager config for org after change in notification settings", "org", c.SignedInUser.GetOrgID(), "error", err)
-		}
+// refreshAlertmanagerConfig applies the Alertmanager configuration that saved notification settings were validated against.
+func (srv RulerSrv) refreshAlertmanagerConfig(c *contextmodel.ReqContext, orgID int64, dbConfig *ngmodels.AlertConfiguration) {
+	if dbConfig == nil || !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingSimplifiedRouting) {
+		return
 	}
+	// This isn't strictly necessary since the alertmanager config is periodically synced.
+	err := srv.amRefresher.ApplyConfig(c.Req.Context(), orgID, dbConfig)
+	if err != nil {
+		srv.log.Warn("Failed to refresh Alertmanager config for org after change in notification settings", "org", c.SignedInUser.GetOrgID(), "error", err)
+	}
+}
 
-	return changesToResponse(finalChanges)
+func (srv RulerSrv) groupChangesLogger(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey) log.Logger {
+	id, _ := c.SignedInUser.GetInternalID()
+	userNamespace := c.SignedInUser.GetIdentityType()
+	return srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
+		groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
+}
+
+func toRuleGroupUpdateErrorResponse(err error) response.Response {
+	if errors.As(err, &errutil.Error{}) {
+		return response.Err(err)
+	} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
+		return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	} else if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 }
 
 func changesToResponse(finalChanges *store.GroupDelta) response.Response {
@@ -576,14 +635,23 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
 			Record:               ApiRecordFromModelRecord(r.Record),
 			Metadata:             AlertRuleMetadataFromModelMetadata(r.Metadata),
+
+			MissingSeriesEvalsToResolve: r.MissingSeriesEvalsToResolve,
 		},
 	}
+	if r.UpdatedBy != nil {
+		gettableExtendedRuleNode.GrafanaManagedAlert.UpdatedBy = string(*r.UpdatedBy)
+	}
 	forDuration := model.Duration(r.For)
 	gettableExtendedRuleNode.ApiRuleNode = &apimodels.ApiRuleNode{
 		For:         &forDuration,
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
 	return gettableExtendedRuleNode
 }
 
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RouteGetDeletedRules returns the deleted alert rules that can be restored, the most recently deleted first.
+// Only the rules that the user could read in their original folder are returned.
+func (srv RulerSrv) RouteGetDeletedRules(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+
+	deleted, err := srv.store.ListDeletedAlertRules(ctx, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get deleted rules", err)
+	}
+
+	result := make(apimodels.GettableDeletedRules, 0, len(deleted))
+	for _, d := range deleted {
+		ok, err := srv.authz.HasAccessToRuleGroup(ctx, c.SignedInUser, ngmodels.RulesGroup{&d.Rule})
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to deleted rules", err)
+		}
+		if !ok {
+			continue
+		}
+		result = append(result, apimodels.GettableDeletedRule{
+			ID:      d.ID,
+			Deleted: d.Deleted,
+			Expires: d.Deleted.Add(srv.cfg.DeletedRuleRetention),
+			Rule:    toGettableExtendedRuleNode(d.Rule, nil),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteRestoreDeletedRule restores a deleted alert rule with its original UID.
+// The rule is added to its original folder and group, or to the ones given in the request, and goes through the same
+// authorization, provenance and validation checks as any other new rule of the group.
+func (srv RulerSrv) RouteRestoreDeletedRule(c *contextmodel.ReqContext, body apimodels.PostableRestoreDeletedRule, deletedRuleID string) response.Response {
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+
+	id, err := strconv.ParseInt(deletedRuleID, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid deleted rule ID")
+	}
+
+	deleted, err := srv.store.GetDeletedAlertRule(ctx, orgID, id)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrDeletedAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get deleted rule", err)
+	}
+
+	rule := deleted.Rule
+	if body.FolderUID != "" {
+		rule.NamespaceUID = body.FolderUID
+	}
+	if body.RuleGroup != "" {
+		rule.RuleGroup = body.RuleGroup
+	}
+	if _, err := srv.store.GetNamespaceByUID(ctx, rule.NamespaceUID, orgID, c.SignedInUser); err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	_, err = srv.store.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{OrgID: orgID, UID: rule.UID})
+	if err == nil {
+		return ErrResp(http.StatusConflict, fmt.Errorf("alert rule with UID '%s' already exists", rule.UID), "")
+	}
+	if !errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	groupKey := rule.GetGroupKey()
+	group, err := srv.getAuthorizedRuleGroup(ctx, c, groupKey)
+	if err != nil {
+		return toRuleGroupUpdateErrorResponse(err)
+	}
+
+	rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group)+1)
+	rule.RuleGroupIndex = 1
+	for _, r := range group {
+		rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true})
+		// all rules of a group share the interval, and the restored rule goes last
+		rule.IntervalSeconds = r.IntervalSeconds
+		rule.RuleGroupIndex = max(rule.RuleGroupIndex, r.RuleGroupIndex+1)
+	}
+
+	// The rule is added to the group as a new rule, and it gets its original UID back once the changes are calculated
+	// so that the existing references to the rule, e.g. in silences or links, still work.
+	ruleUID := rule.UID
+	rule.ID = 0
+	rule.UID = ""
+	rule.Version = 0
+	if err := rule.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err := rule.ValidateAlertRule(*srv.cfg); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	restored := &ngmodels.AlertRuleWithOptionals{AlertRule: rule, HasPause: true}
+	rules = append(rules, restored)
+
+	var changes *store.GroupDelta
+	var dbConfig *ngmodels.AlertConfiguration
+	err = srv.xactManager.InTransaction(ctx, func(tranCtx context.Context) error {
+		var err error
+		changes, dbConfig, err = srv.prepareGroupChanges(tranCtx, c, func(ctx context.Context) (*store.GroupDelta, error) {
+			delta, err := store.CalculateChanges(ctx, srv.store, groupKey, rules)
+			if err != nil {
+				return nil, err
+			}
+			restored.UID = ruleUID
+			return delta, nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := srv.saveGroupChanges(tranCtx, c, changes); err != nil {
+			return err
+		}
+		// The rule is removed from the deleted rules in the same transaction so that it is never both live and deleted.
+		if err := srv.store.DeleteDeletedAlertRule(tranCtx, orgID, id); err != nil {
+			return fmt.Errorf("failed to delete the restored rule from the deleted rules: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return toRuleGroupUpdateErrorResponse(err)
+	}
+
+	srv.refreshAlertmanagerConfig(c, orgID, dbConfig)
+
+	return changesToResponse(changes)
+}
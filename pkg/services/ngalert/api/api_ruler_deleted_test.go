@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestDeletedRules(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	otherFolder := randFolder()
+	gen := models.RuleGen.With(
+		models.RuleGen.WithOrgID(orgID),
+		models.RuleGen.WithNamespaceUID(folder.UID),
+		models.RuleGen.WithIntervalMatching(10*time.Second),
+		models.RuleGen.WithNoNotificationSettings(),
+	)
+
+	// setup creates two deleted rules, one in each folder.
+	setup := func(t *testing.T) (*fakes.RuleStore, *models.DeletedAlertRule, *models.DeletedAlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder, otherFolder)
+
+		deleted := &models.DeletedAlertRule{ID: 1, Deleted: time.Now().Add(-time.Hour), Rule: gen.Generate()}
+		other := &models.DeletedAlertRule{ID: 2, Deleted: time.Now(), Rule: gen.With(gen.WithNamespaceUID(otherFolder.UID)).Generate()}
+		ruleStore.Deleted[orgID] = []*models.DeletedAlertRule{other, deleted}
+		return ruleStore, deleted, other
+	}
+
+	restorePermissions := func(rules ...models.AlertRule) map[int64]map[string][]string {
+		refs := make([]*models.AlertRule, 0, len(rules))
+		for i := range rules {
+			refs = append(refs, &rules[i])
+		}
+		perms := createPermissionsForRules(refs, orgID)
+		for _, rule := range rules {
+			perms[orgID][ac.ActionAlertingRuleCreate] = append(perms[orgID][ac.ActionAlertingRuleCreate], dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID))
+		}
+		perms[orgID][datasources.ActionQuery] = []string{datasources.ScopeAll}
+		return perms
+	}
+
+	insertedRules := func(ruleStore *fakes.RuleStore) []any {
+		return ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		})
+	}
+
+	t.Run("should list the deleted rules the user can read", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		svc := createService(ruleStore)
+		svc.cfg.DeletedRuleRetention = 24 * time.Hour
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{&deleted.Rule}, orgID), nil)
+
+		response := svc.RouteGetDeletedRules(req)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.GettableDeletedRules
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 1)
+		assert.Equal(t, deleted.ID, result[0].ID)
+		assert.Equal(t, deleted.Rule.UID, result[0].Rule.GrafanaManagedAlert.UID)
+		assert.WithinDuration(t, deleted.Deleted.Add(24*time.Hour), result[0].Expires, time.Second)
+	})
+
+	t.Run("should restore the rule with its original UID", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, restorePermissions(deleted.Rule), nil)
+
+		response := createService(ruleStore).RouteRestoreDeletedRule(req, apimodels.PostableRestoreDeletedRule{}, "1")
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		var result apimodels.UpdateRuleGroupResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Equal(t, []string{deleted.Rule.UID}, result.Created)
+
+		inserts := insertedRules(ruleStore)
+		require.Len(t, inserts, 1)
+		inserted := inserts[0].([]models.AlertRule)
+		require.Len(t, inserted, 1)
+		assert.Equal(t, deleted.Rule.UID, inserted[0].UID)
+		assert.Equal(t, deleted.Rule.Title, inserted[0].Title)
+		assert.Equal(t, deleted.Rule.NamespaceUID, inserted[0].NamespaceUID)
+		assert.Equal(t, deleted.Rule.RuleGroup, inserted[0].RuleGroup)
+
+		assert.Len(t, ruleStore.Deleted[orgID], 1)
+	})
+
+	t.Run("should restore the rule into another folder and group", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		target := deleted.Rule
+		target.NamespaceUID = otherFolder.UID
+		target.RuleGroup = "restored"
+		req := createRequestContextWithPerms(orgID, restorePermissions(deleted.Rule, target), nil)
+
+		body := apimodels.PostableRestoreDeletedRule{FolderUID: otherFolder.UID, RuleGroup: "restored"}
+		response := createService(ruleStore).RouteRestoreDeletedRule(req, body, "1")
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		inserts := insertedRules(ruleStore)
+		require.Len(t, inserts, 1)
+		inserted := inserts[0].([]models.AlertRule)
+		require.Len(t, inserted, 1)
+		assert.Equal(t, deleted.Rule.UID, inserted[0].UID)
+		assert.Equal(t, otherFolder.UID, inserted[0].NamespaceUID)
+		assert.Equal(t, "restored", inserted[0].RuleGroup)
+	})
+
+	t.Run("should fail the restore if the rule cannot be removed from the deleted rules", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		expectedErr := errors.New("test error")
+		ruleStore.Hook = func(cmd any) error {
+			if q, ok := cmd.(fakes.GenericRecordedQuery); ok && q.Name == "DeleteDeletedAlertRule" {
+				return expectedErr
+			}
+			return nil
+		}
+		req := createRequestContextWithPerms(orgID, restorePermissions(deleted.Rule), nil)
+
+		response := createService(ruleStore).RouteRestoreDeletedRule(req, apimodels.PostableRestoreDeletedRule{}, "1")
+		require.Equal(t, http.StatusInternalServerError, response.Status())
+		assert.Len(t, ruleStore.Deleted[orgID], 2)
+	})
+
+	t.Run("should return 409 if a rule with the same UID exists", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		existing := models.CopyRule(&deleted.Rule)
+		ruleStore.PutRule(context.Background(), existing)
+		req := createRequestContextWithPerms(orgID, restorePermissions(deleted.Rule), nil)
+
+		response := createService(ruleStore).RouteRestoreDeletedRule(req, apimodels.PostableRestoreDeletedRule{}, "1")
+		require.Equal(t, http.StatusConflict, response.Status())
+		require.Empty(t, insertedRules(ruleStore))
+	})
+
+	t.Run("should not restore the rule without permission to create rules", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{&deleted.Rule}, orgID), nil)
+
+		response := createService(ruleStore).RouteRestoreDeletedRule(req, apimodels.PostableRestoreDeletedRule{}, "1")
+		require.Equal(t, http.StatusForbidden, response.Status())
+		require.Empty(t, insertedRules(ruleStore))
+	})
+
+	t.Run("should return 404 if the deleted rule does not exist", func(t *testing.T) {
+		ruleStore, deleted, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, restorePermissions(deleted.Rule), nil)
+
+		response := createService(ruleStore).RouteRestoreDeletedRule(req, apimodels.PostableRestoreDeletedRule{}, "10")
+		require.Equal(t, http.StatusNotFound, response.Status())
+
+		response = createService(ruleStore).RouteRestoreDeletedRule(req, apimodels.PostableRestoreDeletedRule{}, "latest")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RouteImportPrometheusRules converts the Prometheus rule groups in the request body to Grafana-managed
+// rules in the namespace. All groups are converted and validated before any of them is saved, and they are saved
+// in a single transaction.
+// With dry_run the changes are calculated and authorized but not saved.
+func (srv RulerSrv) RouteImportPrometheusRules(c *contextmodel.ReqContext, file apimodels.PrometheusRulesFile, namespaceUID string) response.Response {
+	datasourceUID := c.Query("datasource_uid")
+	if datasourceUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("datasource_uid is required"), "")
+	}
+	dryRun := c.QueryBool("dry_run")
+
+	if len(file.Groups) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no rule groups found"), "")
+	}
+
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:   datasourceUID,
+		DefaultInterval: srv.cfg.DefaultRuleEvaluationInterval,
+	})
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	limits := RuleLimitsFromConfig(srv.cfg, srv.featureManager)
+	groups := make(map[string][]*ngmodels.AlertRuleWithOptionals, len(file.Groups))
+	for _, group := range file.Groups {
+		if _, ok := groups[group.Name]; ok {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("rule group %q is defined more than once", group.Name), "")
+		}
+		rules, err := srv.convertPrometheusGroup(converter, group, c.SignedInUser.GetOrgID(), namespace.UID, limits)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid Prometheus rules")
+		}
+		groups[group.Name] = rules
+	}
+
+	// The changes of all groups are calculated and authorized before any of them is saved, in a single transaction,
+	// so that either all groups are imported or none of them.
+	deltas := make([]*store.GroupDelta, 0, len(file.Groups))
+	var dbConfig *ngmodels.AlertConfiguration
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		for _, group := range file.Groups {
+			groupKey := ngmodels.AlertRuleGroupKey{
+				OrgID:        c.SignedInUser.GetOrgID(),
+				NamespaceUID: namespace.UID,
+				RuleGroup:    group.Name,
+			}
+			rules := groups[group.Name]
+			if err := srv.matchExistingRules(tranCtx, groupKey, rules); err != nil {
+				return fmt.Errorf("failed to get existing rules: %w", err)
+			}
+			delta, config, err := srv.prepareGroupChanges(tranCtx, c, func(ctx context.Context) (*store.GroupDelta, error) {
+				return store.CalculateChanges(ctx, srv.store, groupKey, rules)
+			})
+			if err != nil {
+				return fmt.Errorf("rule group %q: %w", group.Name, err)
+			}
+			if config != nil {
+				dbConfig = config
+			}
+			deltas = append(deltas, delta)
+		}
+		if dryRun {
+			return nil
+		}
+		for i, delta := range deltas {
+			if err := srv.saveGroupChanges(tranCtx, c, delta); err != nil {
+				return fmt.Errorf("rule group %q: %w", file.Groups[i].Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return toRuleGroupUpdateErrorResponse(err)
+	}
+	if !dryRun {
+		srv.refreshAlertmanagerConfig(c, c.SignedInUser.GetOrgID(), dbConfig)
+	}
+
+	result := apimodels.ImportPrometheusRulesResponse{
+		DryRun: dryRun,
+		Groups: make([]apimodels.ImportedPrometheusRuleGroup, 0, len(file.Groups)),
+	}
+	for i, group := range file.Groups {
+		result.Groups = append(result.Groups, importedGroupFromDelta(group.Name, groups[group.Name], deltas[i]))
+	}
+
+	if dryRun {
+		result.Message = "dry run, no changes were saved"
+		return response.JSON(http.StatusOK, result)
+	}
+	result.Message = "rules imported successfully"
+	return response.JSON(http.StatusAccepted, result)
+}
+
+// convertPrometheusGroup converts and validates the rules of a Prometheus group.
+func (srv RulerSrv) convertPrometheusGroup(converter *prom.Converter, group apimodels.PrometheusRuleGroup, orgID int64, namespaceUID string, limits RuleLimits) ([]*ngmodels.AlertRuleWithOptionals, error) {
+	if len(group.Name) > store.AlertRuleMaxRuleGroupNameLength {
+		return nil, fmt.Errorf("rule group name %q is too long. Max length is %d", group.Name, store.AlertRuleMaxRuleGroupNameLength)
+	}
+	converted, err := converter.ConvertGroup(orgID, group)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(converted))
+	for _, rule := range converted {
+		rule.NamespaceUID = namespaceUID
+		if len(rule.Title) > store.AlertRuleMaxTitleLength {
+			return nil, fmt.Errorf("rule group %q: rule title %q is too long. Max length is %d", group.Name, rule.Title, store.AlertRuleMaxTitleLength)
+		}
+		if rule.Type() == ngmodels.RuleTypeRecording && !limits.RecordingRulesAllowed {
+			return nil, fmt.Errorf("rule group %q: %w: recording rules cannot be created on this instance", group.Name, ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if err := validateLabels(rule.Labels); err != nil {
+			return nil, fmt.Errorf("rule group %q: rule %q: %w", group.Name, rule.Title, err)
+		}
+		if err := rule.ValidateAlertRule(*srv.cfg); err != nil {
+			return nil, fmt.Errorf("rule group %q: rule %q: %w", group.Name, rule.Title, err)
+		}
+		rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: rule})
+	}
+	return rules, nil
+}
+
+// matchExistingRules sets the UID of each rule to the UID of the existing rule in the group with the same title,
+// so that importing the same file again updates the rules instead of replacing them.
+// Rules with the same title are matched in the order they appear in the group.
+func (srv RulerSrv) matchExistingRules(ctx context.Context, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) error {
+	existing, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         groupKey.OrgID,
+		NamespaceUIDs: []string{groupKey.NamespaceUID},
+		RuleGroups:    []string{groupKey.RuleGroup},
+	})
+	if err != nil {
+		return err
+	}
+	existing.SortByGroupIndex()
+
+	uidsByTitle := make(map[string][]string, len(existing))
+	for _, r := range existing {
+		uidsByTitle[r.Title] = append(uidsByTitle[r.Title], r.UID)
+	}
+	for _, r := range rules {
+		if uids := uidsByTitle[r.Title]; len(uids) > 0 {
+			r.UID = uids[0]
+			uidsByTitle[r.Title] = uids[1:]
+		}
+	}
+	return nil
+}
+
+func importedGroupFromDelta(name string, submitted []*ngmodels.AlertRuleWithOptionals, delta *store.GroupDelta) apimodels.ImportedPrometheusRuleGroup {
+	result := apimodels.ImportedPrometheusRuleGroup{Name: name}
+	// new rules have their UID set once they are saved
+	changed := make(map[string]struct{}, len(delta.New)+len(delta.Update))
+	for _, r := range delta.New {
+		changed[r.UID] = struct{}{}
+		result.Created = append(result.Created, apimodels.ImportedRuleDelta{UID: r.UID, Title: r.Title})
+	}
+	for _, u := range delta.Update {
+		changed[u.Existing.UID] = struct{}{}
+		d := apimodels.ImportedRuleDelta{UID: u.Existing.UID, Title: u.New.Title}
+		for _, diff := range u.Diff {
+			d.Diff = append(d.Diff, apimodels.RuleFieldDiff{
+				Path: diff.Path,
+				Old:  describeDiffValue(diff.Left),
+				New:  describeDiffValue(diff.Right),
+			})
+		}
+		result.Updated = append(result.Updated, d)
+	}
+	for _, r := range delta.Delete {
+		result.Deleted = append(result.Deleted, apimodels.ImportedRuleDelta{UID: r.UID, Title: r.Title})
+	}
+	for _, r := range submitted {
+		if r.UID == "" {
+			continue
+		}
+		if _, ok := changed[r.UID]; !ok {
+			result.Unchanged = append(result.Unchanged, apimodels.ImportedRuleDelta{UID: r.UID, Title: r.Title})
+		}
+	}
+	return result
+}
+
+func describeDiffValue(v reflect.Value) string {
+	if !v.IsValid() || !v.CanInterface() {
+		return ""
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
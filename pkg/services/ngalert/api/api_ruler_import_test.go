@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRouteImportPrometheusRules(t *testing.T) {
+	forDuration := prommodel.Duration(5 * time.Minute)
+	file := apimodels.PrometheusRulesFile{
+		Groups: []apimodels.PrometheusRuleGroup{
+			{
+				Name: "node",
+				Rules: []apimodels.ApiRuleNode{
+					{Alert: "HighLoad", Expr: "node_load1 > 4", For: &forDuration, Labels: map[string]string{"severity": "warning"}},
+					{Alert: "InstanceDown", Expr: "up == 0"},
+				},
+			},
+		},
+	}
+
+	setup := func(t *testing.T) (*RulerSrv, *fakes.RuleStore, int64, string) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		srv := createService(ruleStore)
+		srv.cfg.DefaultRuleEvaluationInterval = time.Minute
+		srv.conditionValidator = &recordingConditionValidator{}
+		return srv, ruleStore, orgID, folder.UID
+	}
+
+	request := func(orgID int64, namespaceUID, query string) *contextmodel.ReqContext {
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(namespaceUID)
+		req := createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				dashboards.ActionFoldersRead: {scope},
+				ac.ActionAlertingRuleRead:    {scope},
+				ac.ActionAlertingRuleCreate:  {scope},
+				ac.ActionAlertingRuleUpdate:  {scope},
+				ac.ActionAlertingRuleDelete:  {scope},
+				datasources.ActionQuery:      {datasources.ScopeAll},
+			},
+		}, nil)
+		req.Req.URL.RawQuery = query
+		return req
+	}
+
+	t.Run("should require datasource_uid", func(t *testing.T) {
+		srv, _, orgID, namespaceUID := setup(t)
+		resp := srv.RouteImportPrometheusRules(request(orgID, namespaceUID, ""), file, namespaceUID)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		srv, _, orgID, namespaceUID := setup(t)
+		invalid := apimodels.PrometheusRulesFile{Groups: []apimodels.PrometheusRuleGroup{
+			{Name: "node", Rules: []apimodels.ApiRuleNode{{Alert: "Broken", Expr: "sum(up"}}},
+		}}
+		resp := srv.RouteImportPrometheusRules(request(orgID, namespaceUID, "datasource_uid=prom"), invalid, namespaceUID)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("dry run should report changes without saving them", func(t *testing.T) {
+		srv, ruleStore, orgID, namespaceUID := setup(t)
+		gen := models.RuleGen
+		existing := gen.With(
+			gen.WithOrgID(orgID),
+			gen.WithNamespaceUID(namespaceUID),
+			gen.WithGroupName("node"),
+			gen.WithTitle("HighLoad"),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), existing)
+
+		resp := srv.RouteImportPrometheusRules(request(orgID, namespaceUID, "datasource_uid=prom&dry_run=true"), file, namespaceUID)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		result := apimodels.ImportPrometheusRulesResponse{}
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.True(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		group := result.Groups[0]
+		require.Equal(t, "node", group.Name)
+		require.Len(t, group.Created, 1)
+		require.Equal(t, "InstanceDown", group.Created[0].Title)
+		require.Len(t, group.Updated, 1)
+		require.Equal(t, existing.UID, group.Updated[0].UID)
+		require.NotEmpty(t, group.Updated[0].Diff)
+		require.Empty(t, group.Deleted)
+
+		rules, err := ruleStore.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, existing, rules[0])
+	})
+
+	t.Run("should not save any group if one of them fails", func(t *testing.T) {
+		srv, ruleStore, orgID, namespaceUID := setup(t)
+		gen := models.RuleGen
+		existing := gen.With(
+			gen.WithOrgID(orgID),
+			gen.WithNamespaceUID(namespaceUID),
+			gen.WithGroupName("node"),
+			gen.WithTitle("HighLoad"),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), existing)
+
+		srv.conditionValidator = &recordingConditionValidator{
+			hook: func(c models.Condition) error {
+				if strings.Contains(string(c.Data[0].Model), "absent") {
+					return errors.New("test")
+				}
+				return nil
+			},
+		}
+		twoGroups := apimodels.PrometheusRulesFile{Groups: []apimodels.PrometheusRuleGroup{
+			file.Groups[0],
+			{Name: "absent", Rules: []apimodels.ApiRuleNode{{Alert: "NoTargets", Expr: "absent(up)"}}},
+		}}
+
+		resp := srv.RouteImportPrometheusRules(request(orgID, namespaceUID, "datasource_uid=prom"), twoGroups, namespaceUID)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Contains(t, string(resp.Body()), `rule group \"absent\"`)
+
+		rules, err := ruleStore.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, existing, rules[0])
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			switch cmd.(type) {
+			case []models.UpdateRule, []models.AlertRule:
+				return cmd, true
+			}
+			return nil, false
+		}))
+	})
+}
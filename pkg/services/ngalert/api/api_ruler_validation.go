@@ -155,6 +155,16 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		return ngmodels.AlertRule{}, err
 	}
 
+	newRule.KeepFiringFor, err = validateKeepFiringForInterval(in)
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+
+	newRule.MissingSeriesEvalsToResolve, err = validateMissingSeriesEvalsToResolve(in)
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+
 	return newRule, nil
 }
 
@@ -183,6 +193,8 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	newRule.ExecErrState = ""
 	newRule.Condition = ""
 	newRule.For = 0
+	newRule.KeepFiringFor = 0
+	newRule.MissingSeriesEvalsToResolve = nil
 	newRule.NotificationSettings = nil
 
 	return newRule, nil
@@ -270,6 +282,37 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringForInterval validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
+// validateMissingSeriesEvalsToResolve validates GrafanaManagedAlert.MissingSeriesEvalsToResolve. If the field is not specified returns nil if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateMissingSeriesEvalsToResolve(ruleNode *apimodels.PostableExtendedRuleNode) (*int64, error) {
+	if ruleNode.GrafanaManagedAlert.MissingSeriesEvalsToResolve == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			evals := int64(-1)
+			return &evals, nil // will be patched later with the real value of the current version of the rule
+		}
+		return nil, nil
+	}
+	evals := *ruleNode.GrafanaManagedAlert.MissingSeriesEvalsToResolve
+	if evals < 1 {
+		return nil, fmt.Errorf("%w: field `missing_series_evals_to_resolve` must be at least 1", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	return &evals, nil
+}
+
 // ValidateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
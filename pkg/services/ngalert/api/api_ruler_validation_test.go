@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -239,6 +241,26 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.True(t, alert.HasPause)
 		}
 	})
+
+	t.Run("should keep missing_series_evals_to_resolve of the existing rule if it is not specified", func(t *testing.T) {
+		rule := validRule()
+		rule.GrafanaManagedAlert.MissingSeriesEvalsToResolve = nil
+		g := validGroup(cfg, rule)
+		groupKey := models.AlertRuleGroupKey{OrgID: orgId, NamespaceUID: folder.UID, RuleGroup: g.Name}
+
+		ruleStore := fakes.NewRuleStore(t)
+		gen := models.RuleGen
+		existing := gen.With(gen.WithGroupKey(groupKey), gen.WithMissingSeriesEvalsToResolve(3)).GenerateRef()
+		existing.UID = rule.GrafanaManagedAlert.UID
+		ruleStore.PutRule(context.Background(), existing)
+
+		alerts, err := ValidateRuleGroup(&g, orgId, folder.UID, limits)
+		require.NoError(t, err)
+		delta, err := store.CalculateChanges(context.Background(), ruleStore, groupKey, alerts)
+		require.NoError(t, err)
+		require.Len(t, delta.Update, 1)
+		require.Equal(t, util.Pointer[int64](3), delta.Update[0].New.MissingSeriesEvalsToResolve)
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RouteGetRuleVersionsByUID returns the versions of the alert rule with the given UID, the most recent first.
+func (srv RulerSrv) RouteGetRuleVersionsByUID(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+
+	versions, err := srv.store.GetAlertRuleVersions(ctx, orgID, rule.UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule versions", err)
+	}
+
+	provenance, err := srv.provenanceStore.GetProvenance(ctx, &rule, orgID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule provenance", err)
+	}
+	provenances := map[string]ngmodels.Provenance{rule.ResourceID(): provenance}
+
+	result := make(apimodels.GettableRuleVersions, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, toGettableExtendedRuleNode(*version, provenances))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersionsDiff returns the differences between two versions of the alert rule with the given UID.
+// The query parameter "from" is required, and "to" defaults to the current version of the rule.
+func (srv RulerSrv) RouteGetRuleVersionsDiff(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid value of query parameter 'from'")
+	}
+	var to int64
+	if s := c.Query("to"); s != "" {
+		if to, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid value of query parameter 'to'")
+		}
+	}
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+	if to == 0 {
+		to = rule.Version
+	}
+
+	fromRule, err := srv.getRuleVersion(c, rule, from)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule version")
+	}
+	toRule, err := srv.getRuleVersion(c, rule, to)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule version")
+	}
+
+	diff := fromRule.Diff(toRule, store.AlertRuleFieldsToIgnoreInDiff[:]...)
+	changes := make([]apimodels.RuleVersionChange, 0, len(diff))
+	for _, d := range diff {
+		changes = append(changes, apimodels.RuleVersionChange{
+			Path: d.Path,
+			From: diffValue(d.Left),
+			To:   diffValue(d.Right),
+		})
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return response.JSON(http.StatusOK, apimodels.RuleVersionDiff{
+		RuleUID: rule.UID,
+		From:    from,
+		To:      to,
+		Changes: changes,
+	})
+}
+
+// RouteRestoreRuleVersion restores a version of the alert rule with the given UID.
+// The restored rule is saved as a new version of the rule, and goes through the same authorization, provenance and validation
+// checks as any other update of the rule group. The rule keeps its current folder, group and pause state.
+func (srv RulerSrv) RouteRestoreRuleVersion(c *contextmodel.ReqContext, ruleUID string, version string) response.Response {
+	ctx := c.Req.Context()
+
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid rule version")
+	}
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+
+	restored, err := srv.store.GetAlertRuleVersion(ctx, rule.OrgID, rule.UID, v)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule version")
+	}
+	restored.ID = rule.ID
+	restored.OrgID = rule.OrgID
+	restored.UID = rule.UID
+	restored.NamespaceUID = rule.NamespaceUID
+	restored.RuleGroup = rule.RuleGroup
+	restored.RuleGroupIndex = rule.RuleGroupIndex
+	restored.IntervalSeconds = rule.IntervalSeconds
+	restored.Version = rule.Version
+	if err := restored.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err := restored.ValidateAlertRule(*srv.cfg); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	groupKey := rule.GetGroupKey()
+	group, err := srv.getAuthorizedRuleGroup(ctx, c, groupKey)
+	if err != nil {
+		return toRuleVersionErrorResponse(err, "failed to get rule group")
+	}
+
+	rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group))
+	for _, r := range group {
+		if r.UID == restored.UID {
+			// HasPause is not set so that the rule keeps its current pause state
+			rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: *restored})
+			continue
+		}
+		rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true})
+	}
+
+	return srv.updateAlertRulesInGroup(c, groupKey, rules)
+}
+
+// getRuleVersion returns the version of the rule. The current rule is returned if it has the requested version.
+func (srv RulerSrv) getRuleVersion(c *contextmodel.ReqContext, rule ngmodels.AlertRule, version int64) (*ngmodels.AlertRule, error) {
+	if rule.Version == version {
+		return &rule, nil
+	}
+	return srv.store.GetAlertRuleVersion(c.Req.Context(), rule.OrgID, rule.UID, version)
+}
+
+func toRuleVersionErrorResponse(err error, fallbackMsg string) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, fallbackMsg, err)
+}
+
+// diffValue returns the value of a field reported by the diff, or nil if the field is missing.
+func diffValue(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
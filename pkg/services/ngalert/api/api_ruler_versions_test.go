@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRuleVersions(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	gen := models.RuleGen.With(
+		models.RuleGen.WithGroupKey(groupKey),
+		models.RuleGen.WithIntervalMatching(10*time.Second),
+		models.RuleGen.WithNoNotificationSettings(),
+		models.RuleGen.WithUniqueID(),
+	)
+
+	// setup creates a rule with three versions. The current version is 3, and the oldest version has a different title.
+	setup := func(t *testing.T) (*fakes.RuleStore, *models.AlertRule, []*models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+
+		rule := gen.With(gen.WithTitle("current")).GenerateRef()
+		rule.Version = 3
+		history := make([]*models.AlertRule, 0, 2)
+		for _, version := range []int64{1, 2} {
+			old := models.CopyRule(rule)
+			old.Version = version
+			history = append(history, old)
+		}
+		history[0].Title = "first"
+		author := models.UserUID("author")
+		history[0].UpdatedBy = &author
+
+		ruleStore.PutRule(context.Background(), rule)
+		ruleStore.History[orgID] = history
+		return ruleStore, rule, history
+	}
+
+	t.Run("should list the versions of the rule", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersionsByUID(req, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.GettableRuleVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 3)
+		for i, version := range []int64{3, 2, 1} {
+			assert.Equal(t, version, result[i].GrafanaManagedAlert.Version)
+			assert.Equal(t, rule.UID, result[i].GrafanaManagedAlert.UID)
+		}
+		assert.Equal(t, "first", result[2].GrafanaManagedAlert.Title)
+		assert.Equal(t, "author", result[2].GrafanaManagedAlert.UpdatedBy)
+	})
+
+	t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersionsByUID(req, "foobar")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return the differences between two versions", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+		req.Req.URL.RawQuery = "from=1"
+
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(req, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersionDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Equal(t, rule.UID, result.RuleUID)
+		assert.Equal(t, int64(1), result.From)
+		assert.Equal(t, int64(3), result.To)
+		require.Len(t, result.Changes, 1)
+		assert.Equal(t, "Title", result.Changes[0].Path)
+		assert.Equal(t, "first", result.Changes[0].From)
+		assert.Equal(t, "current", result.Changes[0].To)
+
+		req.Req.URL.RawQuery = "from=2&to=3"
+		response = createService(ruleStore).RouteGetRuleVersionsDiff(req, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Empty(t, result.Changes)
+	})
+
+	t.Run("should reject invalid versions in the diff", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		req.Req.URL.RawQuery = "to=1"
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(req, rule.UID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+
+		req.Req.URL.RawQuery = "from=5"
+		response = createService(ruleStore).RouteGetRuleVersionsDiff(req, rule.UID)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should restore a version of the rule", func(t *testing.T) {
+		ruleStore, rule, history := setup(t)
+		perms := createPermissionsForRules([]*models.AlertRule{rule}, orgID)
+		perms[orgID][ac.ActionAlertingRuleUpdate] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)}
+		perms[orgID][datasources.ActionQuery] = []string{datasources.ScopeAll}
+		req := createRequestContextWithPerms(orgID, perms, nil)
+
+		response := createService(ruleStore).RouteRestoreRuleVersion(req, rule.UID, "1")
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		var result apimodels.UpdateRuleGroupResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Equal(t, []string{rule.UID}, result.Updated)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		update := updates[0].([]models.UpdateRule)
+		require.Len(t, update, 1)
+		assert.Equal(t, history[0].Title, update[0].New.Title)
+		assert.Equal(t, rule.ID, update[0].New.ID)
+		assert.Equal(t, rule.NamespaceUID, update[0].New.NamespaceUID)
+		assert.Equal(t, rule.RuleGroup, update[0].New.RuleGroup)
+		assert.Equal(t, rule.IsPaused, update[0].New.IsPaused)
+	})
+
+	t.Run("should not restore a version of a provisioned rule", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		perms := createPermissionsForRules([]*models.AlertRule{rule}, orgID)
+		perms[orgID][ac.ActionAlertingRuleUpdate] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)}
+		perms[orgID][datasources.ActionQuery] = []string{datasources.ScopeAll}
+		req := createRequestContextWithPerms(orgID, perms, nil)
+
+		provenanceStore := fakes.NewFakeProvisioningStore()
+		require.NoError(t, provenanceStore.SetProvenance(context.Background(), rule, orgID, models.ProvenanceAPI))
+
+		response := createServiceWithProvenanceStore(ruleStore, provenanceStore).RouteRestoreRuleVersion(req, rule.UID, "1")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		}))
+	})
+
+	t.Run("should not restore a version without permission to update the rule", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(ruleStore).RouteRestoreRuleVersion(req, rule.UID, "1")
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should return 404 if the version does not exist", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := createService(ruleStore).RouteRestoreRuleVersion(req, rule.UID, "10")
+		require.Equal(t, http.StatusNotFound, response.Status())
+
+		response = createService(ruleStore).RouteRestoreRuleVersion(req, rule.UID, "latest")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
@@ -29,12 +29,17 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		NoDataState:          models.NoDataState(a.NoDataState),          // TODO there must be a validation
 		ExecErrState:         models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
 		For:                  time.Duration(a.For),
+		KeepFiringFor:        time.Duration(a.KeepFiringFor),
 		Annotations:          a.Annotations,
 		Labels:               a.Labels,
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
 	}
+	if a.MissingSeriesEvalsToResolve != nil {
+		evals := *a.MissingSeriesEvalsToResolve
+		rule.MissingSeriesEvalsToResolve = &evals
+	}
 
 	if rule.Type() == models.RuleTypeRecording {
 		models.ClearRecordingRuleIgnoredFields(&rule)
@@ -53,6 +58,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		RuleGroup:            rule.RuleGroup,
 		Title:                rule.Title,
 		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
 		Condition:            rule.Condition,
 		Data:                 ApiAlertQueriesFromAlertQueries(rule.Data),
 		Updated:              rule.Updated,
@@ -64,6 +70,8 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+
+		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
 	}
 }
 
@@ -215,10 +223,16 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
 		Record:               AlertRuleRecordExportFromRecord(rule.Record),
+
+		KeepFiringFor:               model.Duration(rule.KeepFiringFor),
+		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
 	}
+	if rule.KeepFiringFor.Seconds() > 0 {
+		result.KeepFiringForString = util.Pointer(model.Duration(rule.KeepFiringFor).String())
+	}
 	if rule.Annotations != nil {
 		result.Annotations = &rule.Annotations
 	}
@@ -575,3 +589,62 @@ func GettableApiReceiversFromReceivers(recvs []*models.Receiver) ([]*definitions
 	}
 	return out, nil
 }
+
+func GettableNotificationHistoryEntryFromModel(e models.NotificationHistoryEntry) definitions.GettableNotificationHistoryEntry {
+	alerts := make([]definitions.GettableNotificationHistoryAlert, 0, len(e.Alerts))
+	for _, a := range e.Alerts {
+		alerts = append(alerts, definitions.GettableNotificationHistoryAlert{
+			Fingerprint: a.Fingerprint,
+			Status:      a.Status,
+			Labels:      a.Labels,
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		})
+	}
+	return definitions.GettableNotificationHistoryEntry{
+		Timestamp:        e.Timestamp,
+		GroupKey:         e.GroupKey,
+		Receiver:         e.Receiver,
+		Integration:      e.Integration,
+		IntegrationIndex: e.IntegrationIndex,
+		Status:           string(e.Status),
+		Error:            e.Error,
+		Retry:            e.Retry,
+		Duration:         e.Duration.Milliseconds(),
+		Alerts:           alerts,
+	}
+}
+
+func SilenceScheduleFromPostable(p definitions.PostableSilenceSchedule) models.SilenceSchedule {
+	return models.SilenceSchedule{
+		Matchers:  p.Matchers,
+		Comment:   p.Comment,
+		CreatedBy: p.CreatedBy,
+		Schedule:  p.Schedule,
+		Timezone:  p.Timezone,
+		Duration:  time.Duration(p.Duration),
+	}
+}
+
+func GettableSilenceScheduleFromModel(s *models.SilenceSchedule) definitions.GettableSilenceSchedule {
+	occurrences := make([]definitions.SilenceScheduleOccurrence, 0, len(s.Occurrences))
+	for _, o := range s.Occurrences {
+		occurrences = append(occurrences, definitions.SilenceScheduleOccurrence{
+			SilenceID: o.SilenceID,
+			StartsAt:  o.StartsAt,
+			EndsAt:    o.EndsAt,
+		})
+	}
+	return definitions.GettableSilenceSchedule{
+		UID:         s.UID,
+		Matchers:    s.Matchers,
+		Comment:     s.Comment,
+		CreatedBy:   s.CreatedBy,
+		Schedule:    s.Schedule,
+		Timezone:    s.Timezone,
+		Duration:    model.Duration(s.Duration),
+		Created:     s.Created,
+		Updated:     s.Updated,
+		Occurrences: occurrences,
+	}
+}
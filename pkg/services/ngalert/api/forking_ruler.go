@@ -97,6 +97,26 @@ func (f *RulerApiHandler) handleRouteGetRuleByUID(ctx *contextmodel.ReqContext,
 	return f.GrafanaRuler.RouteGetRuleByUID(ctx, ruleUID)
 }
 
+func (f *RulerApiHandler) handleRouteGetDeletedRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetDeletedRules(ctx)
+}
+
+func (f *RulerApiHandler) handleRouteRestoreDeletedRule(ctx *contextmodel.ReqContext, body apimodels.PostableRestoreDeletedRule, deletedRuleID string) response.Response {
+	return f.GrafanaRuler.RouteRestoreDeletedRule(ctx, body, deletedRuleID)
+}
+
+func (f *RulerApiHandler) handleRouteGetRuleVersionsByUID(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsByUID(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteGetRuleVersionsDiff(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsDiff(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteRestoreRuleVersion(ctx *contextmodel.ReqContext, ruleUID, version string) response.Response {
+	return f.GrafanaRuler.RouteRestoreRuleVersion(ctx, ruleUID, version)
+}
+
 func (f *RulerApiHandler) handleRoutePostNameGrafanaRulesConfig(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupConfig, namespace string) response.Response {
 	payloadType := conf.Type()
 	if payloadType != apimodels.GrafanaBackend {
@@ -113,6 +133,10 @@ func (f *RulerApiHandler) handleRoutePostRulesGroupForExport(ctx *contextmodel.R
 	return f.GrafanaRuler.ExportFromPayload(ctx, conf, namespace)
 }
 
+func (f *RulerApiHandler) handleRoutePostImportPrometheusRules(ctx *contextmodel.ReqContext, file apimodels.PrometheusRulesFile, namespace string) response.Response {
+	return f.GrafanaRuler.RouteImportPrometheusRules(ctx, file, namespace)
+}
+
 func (f *RulerApiHandler) handleRouteGetRulesForExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.GrafanaRuler.ExportRules(ctx)
 }
@@ -14,17 +14,41 @@ import (
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type NotificationsApi interface {
+	RouteCreateSilenceSchedule(*contextmodel.ReqContext) response.Response
+	RouteDeleteSilenceSchedule(*contextmodel.ReqContext) response.Response
+	RouteGetNotificationHistory(*contextmodel.ReqContext) response.Response
 	RouteGetReceiver(*contextmodel.ReqContext) response.Response
 	RouteGetReceivers(*contextmodel.ReqContext) response.Response
+	RouteGetSilenceSchedule(*contextmodel.ReqContext) response.Response
+	RouteGetSilenceSchedules(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeInterval(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeIntervals(*contextmodel.ReqContext) response.Response
+	RouteUpdateSilenceSchedule(*contextmodel.ReqContext) response.Response
 }
 
+func (f *NotificationsApiHandler) RouteCreateSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableSilenceSchedule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteCreateSilenceSchedule(ctx, conf)
+}
+func (f *NotificationsApiHandler) RouteDeleteSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteSilenceSchedule(ctx, uIDParam)
+}
+
+func (f *NotificationsApiHandler) RouteGetNotificationHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetNotificationHistory(ctx)
+}
 func (f *NotificationsApiHandler) RouteGetReceiver(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -33,6 +57,14 @@ func (f *NotificationsApiHandler) RouteGetReceiver(ctx *contextmodel.ReqContext)
 func (f *NotificationsApiHandler) RouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetReceivers(ctx)
 }
+func (f *NotificationsApiHandler) RouteGetSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetSilenceSchedule(ctx, uIDParam)
+}
+func (f *NotificationsApiHandler) RouteGetSilenceSchedules(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetSilenceSchedules(ctx)
+}
 func (f *NotificationsApiHandler) RouteNotificationsGetTimeInterval(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -41,9 +73,55 @@ func (f *NotificationsApiHandler) RouteNotificationsGetTimeInterval(ctx *context
 func (f *NotificationsApiHandler) RouteNotificationsGetTimeIntervals(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteNotificationsGetTimeIntervals(ctx)
 }
+func (f *NotificationsApiHandler) RouteUpdateSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.PostableSilenceSchedule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteUpdateSilenceSchedule(ctx, conf, uIDParam)
+}
 
 func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/notifications/silence-schedules"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/notifications/silence-schedules"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/notifications/silence-schedules",
+				api.Hooks.Wrap(srv.RouteCreateSilenceSchedule),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/notifications/silence-schedules/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/notifications/silence-schedules/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/notifications/silence-schedules/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteSilenceSchedule),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/notifications/history"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/history",
+				api.Hooks.Wrap(srv.RouteGetNotificationHistory),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/notifications/receivers/{Name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -68,6 +146,30 @@ func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/notifications/silence-schedules/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/silence-schedules/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/silence-schedules/{UID}",
+				api.Hooks.Wrap(srv.RouteGetSilenceSchedule),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/notifications/silence-schedules"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/silence-schedules"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/silence-schedules",
+				api.Hooks.Wrap(srv.RouteGetSilenceSchedules),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/notifications/time-intervals/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -92,5 +194,17 @@ func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metri
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/notifications/silence-schedules/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/notifications/silence-schedules/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/notifications/silence-schedules/{UID}",
+				api.Hooks.Wrap(srv.RouteUpdateSilenceSchedule),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
 type NotificationsApiHandler struct {
@@ -30,3 +31,27 @@ func (f *NotificationsApiHandler) handleRouteGetReceiver(ctx *contextmodel.ReqCo
 func (f *NotificationsApiHandler) handleRouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.notificationSrv.RouteGetReceivers(ctx)
 }
+
+func (f *NotificationsApiHandler) handleRouteGetNotificationHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.notificationSrv.RouteGetNotificationHistory(ctx)
+}
+
+func (f *NotificationsApiHandler) handleRouteGetSilenceSchedules(ctx *contextmodel.ReqContext) response.Response {
+	return f.notificationSrv.RouteGetSilenceSchedules(ctx)
+}
+
+func (f *NotificationsApiHandler) handleRouteGetSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.notificationSrv.RouteGetSilenceSchedule(ctx, uid)
+}
+
+func (f *NotificationsApiHandler) handleRouteCreateSilenceSchedule(ctx *contextmodel.ReqContext, body apimodels.PostableSilenceSchedule) response.Response {
+	return f.notificationSrv.RouteCreateSilenceSchedule(ctx, body)
+}
+
+func (f *NotificationsApiHandler) handleRouteUpdateSilenceSchedule(ctx *contextmodel.ReqContext, body apimodels.PostableSilenceSchedule, uid string) response.Response {
+	return f.notificationSrv.RouteUpdateSilenceSchedule(ctx, body, uid)
+}
+
+func (f *NotificationsApiHandler) handleRouteDeleteSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.notificationSrv.RouteDeleteSilenceSchedule(ctx, uid)
+}
@@ -17,6 +17,8 @@ type RuleStore interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 
 	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
+	GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*ngmodels.AlertRule, error)
+	GetAlertRuleVersion(ctx context.Context, orgID int64, ruleUID string, version int64) (*ngmodels.AlertRule, error)
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) ([]*ngmodels.AlertRule, error)
 	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
 
@@ -26,6 +28,10 @@ type RuleStore interface {
 	UpdateAlertRules(ctx context.Context, rule []ngmodels.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
 
+	ListDeletedAlertRules(ctx context.Context, orgID int64) ([]*ngmodels.DeletedAlertRule, error)
+	GetDeletedAlertRule(ctx context.Context, orgID int64, id int64) (*ngmodels.DeletedAlertRule, error)
+	DeleteDeletedAlertRule(ctx context.Context, orgID int64, id int64) error
+
 	// IncreaseVersionForAllRulesInNamespaces Increases version for all rules that have specified namespace uids
 	IncreaseVersionForAllRulesInNamespaces(ctx context.Context, orgID int64, namespaceUIDs []string) ([]ngmodels.AlertRuleKeyWithVersion, error)
 
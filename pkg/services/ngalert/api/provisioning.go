@@ -116,6 +116,10 @@ func (f *ProvisioningApiHandler) handleRouteResetPolicyTree(ctx *contextmodel.Re
 	return f.svc.RouteResetPolicyTree(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostPolicyTreeSimulation(ctx *contextmodel.ReqContext, req apimodels.RoutingSimulationRequest) response.Response {
+	return f.svc.RoutePostPolicyTreeSimulation(ctx, req)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroup(ctx *contextmodel.ReqContext, folder, group string) response.Response {
 	return f.svc.RouteGetAlertRuleGroup(ctx, folder, group)
 }
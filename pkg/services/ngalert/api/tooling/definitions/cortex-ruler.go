@@ -20,6 +20,72 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetRuleVersionsByUID
+//
+// List the versions of a rule, the most recent first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRuleVersions
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions/diff ruler RouteGetRuleVersionsDiff
+//
+// Get the differences between two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionDiff
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Post /ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RouteRestoreRuleVersion
+//
+// Restore a version of a rule. The restored rule is saved as a new version.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/deleted-rules ruler RouteGetDeletedRules
+//
+// List the deleted rules that can be restored, the most recently deleted first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableDeletedRules
+//       403: ForbiddenError
+
+// swagger:route Post /ruler/grafana/api/v1/deleted-rules/{DeletedRuleID}/restore ruler RouteRestoreDeletedRule
+//
+// Restore a deleted rule into its original folder and group or into other ones.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: description: A rule with the same UID already exists.
+
 // swagger:route Get /ruler/grafana/api/v1/rules ruler RouteGetGrafanaRulesConfig
 //
 // List rule groups
@@ -218,12 +284,77 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
-// swagger:parameters RouteGetRuleByUID
+// swagger:parameters RouteGetRuleByUID RouteGetRuleVersionsByUID RouteGetRuleVersionsDiff RouteRestoreRuleVersion
 type PathGetRuleByUIDParams struct {
 	// in: path
 	RuleUID string
 }
 
+// swagger:parameters RouteGetRuleVersionsDiff
+type RuleVersionsDiffParams struct {
+	// The version to compare from
+	// in: query
+	// required: true
+	From int64 `json:"from"`
+	// The version to compare to. Defaults to the current version of the rule.
+	// in: query
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RouteRestoreRuleVersion
+type PathRestoreRuleVersionParams struct {
+	// in: path
+	Version int64
+}
+
+// swagger:parameters RouteRestoreDeletedRule
+type RestoreDeletedRuleParams struct {
+	// in: path
+	DeletedRuleID int64
+	// in: body
+	Body PostableRestoreDeletedRule
+}
+
+// PostableRestoreDeletedRule is the request to restore a deleted rule.
+// The rule is restored into its original folder and group unless other ones are given.
+// swagger:model
+type PostableRestoreDeletedRule struct {
+	FolderUID string `json:"folderUid,omitempty"`
+	RuleGroup string `json:"ruleGroup,omitempty"`
+}
+
+// swagger:model
+type GettableDeletedRules []GettableDeletedRule
+
+// GettableDeletedRule is a deleted rule that can be restored until it expires.
+type GettableDeletedRule struct {
+	ID      int64                    `json:"id"`
+	Deleted time.Time                `json:"deleted"`
+	Expires time.Time                `json:"expires"`
+	Rule    GettableExtendedRuleNode `json:"rule"`
+}
+
+// swagger:model
+type GettableRuleVersions []GettableExtendedRuleNode
+
+// RuleVersionDiff describes the differences between two versions of a rule.
+// swagger:model
+type RuleVersionDiff struct {
+	RuleUID string              `json:"uid"`
+	From    int64               `json:"from"`
+	To      int64               `json:"to"`
+	Changes []RuleVersionChange `json:"changes"`
+}
+
+// RuleVersionChange is a single difference between two versions of a rule.
+// Path is the path of the changed field, and From and To are its values in the compared versions,
+// which are omitted if the field is missing in one of them.
+type RuleVersionChange struct {
+	Path string `json:"path"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
@@ -530,6 +661,9 @@ type PostableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// The number of consecutive evaluations a series can be missing from the results before it is resolved
+	// minimum: 1
+	MissingSeriesEvalsToResolve *int64 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
 }
 
 // swagger:model
@@ -552,6 +686,10 @@ type GettableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// The number of consecutive evaluations a series can be missing from the results before it is resolved
+	MissingSeriesEvalsToResolve *int64 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	// The UID of the user who made the change, or __provisioning__ if it was made by file provisioning. Empty if the change was made by the system.
+	UpdatedBy string `json:"updated_by,omitempty" yaml:"updated_by,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -0,0 +1,84 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /v1/notifications/history notifications RouteGetNotificationHistory
+//
+// Query the notification history.
+//
+// Returns the notifications sent by the integrations of the contact points and their outcome, the latest first.
+// Only the notifications of the contact points that the user can read are returned.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableNotificationHistory
+//       400: ValidationError
+//       403: PermissionDenied
+
+// swagger:parameters RouteGetNotificationHistory
+type NotificationHistoryParams struct {
+	// The timestamp in seconds of the start point of the time range. It cannot be older than the configured retention.
+	// in:query
+	// required: false
+	From int64 `json:"from"`
+	// The timestamp in seconds of the end point of the time range. Defaults to now.
+	// in:query
+	// required: false
+	To int64 `json:"to"`
+	// Limits the number of notifications that are returned.
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+	// Filter by the name of the contact point.
+	// in:query
+	// required: false
+	Receiver string `json:"receiver"`
+	// Filter by the type of the integration, e.g. "email" or "slack".
+	// in:query
+	// required: false
+	Integration string `json:"integration"`
+	// Filter by the outcome of the notification.
+	// in:query
+	// required: false
+	// enum: success,failure
+	Status string `json:"status"`
+	// Filter by the key of the alert group.
+	// in:query
+	// required: false
+	GroupKey string `json:"groupKey"`
+	// Filter by the fingerprint of an alert of the notification.
+	// in:query
+	// required: false
+	Fingerprint string `json:"fingerprint"`
+}
+
+// swagger:model
+type GettableNotificationHistory []GettableNotificationHistoryEntry
+
+// GettableNotificationHistoryEntry is a notification sent by an integration of a contact point.
+type GettableNotificationHistoryEntry struct {
+	Timestamp        time.Time `json:"timestamp"`
+	GroupKey         string    `json:"groupKey"`
+	Receiver         string    `json:"receiver"`
+	Integration      string    `json:"integration"`
+	IntegrationIndex int       `json:"integrationIndex"`
+	// enum: success,failure
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Retry is true if the notification failed and is retried.
+	Retry bool `json:"retry"`
+	// Duration of the notification in milliseconds.
+	Duration int64                              `json:"duration"`
+	Alerts   []GettableNotificationHistoryAlert `json:"alerts"`
+}
+
+// GettableNotificationHistoryAlert is an alert of a notification.
+type GettableNotificationHistoryAlert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
@@ -0,0 +1,86 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/import ruler RoutePostImportPrometheusRules
+//
+// Converts Prometheus rule groups to Grafana-managed alert rules and saves them in the folder.
+// Each group in the file replaces the Grafana rule group with the same name. Rules are matched to the
+// existing rules of the group by their title, rules that are not in the file are deleted.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: ImportPrometheusRulesResponse
+//       202: ImportPrometheusRulesResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:parameters RoutePostImportPrometheusRules
+type ImportPrometheusRulesParams struct {
+	// The UID of the rule folder
+	// in:path
+	Namespace string
+	// The UID of the Prometheus-compatible data source the rules query
+	// in:query
+	// required:true
+	DatasourceUID string `json:"datasource_uid"`
+	// Calculate and return the changes without saving them
+	// in:query
+	// required:false
+	// default:false
+	DryRun bool `json:"dry_run"`
+	// in:body
+	Body PrometheusRulesFile
+}
+
+// PrometheusRulesFile is a Prometheus rule file, as loaded by the rule_files setting of Prometheus.
+// swagger:model
+type PrometheusRulesFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// swagger:model
+type PrometheusRuleGroup struct {
+	Name     string         `yaml:"name" json:"name"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []ApiRuleNode  `yaml:"rules" json:"rules"`
+}
+
+// swagger:model
+type ImportPrometheusRulesResponse struct {
+	Message string `json:"message"`
+	// DryRun is true when the changes were not saved
+	DryRun bool                          `json:"dryRun"`
+	Groups []ImportedPrometheusRuleGroup `json:"groups"`
+}
+
+// ImportedPrometheusRuleGroup describes the changes the import made, or would make, to a rule group.
+type ImportedPrometheusRuleGroup struct {
+	Name      string              `json:"name"`
+	Created   []ImportedRuleDelta `json:"created,omitempty"`
+	Updated   []ImportedRuleDelta `json:"updated,omitempty"`
+	Deleted   []ImportedRuleDelta `json:"deleted,omitempty"`
+	Unchanged []ImportedRuleDelta `json:"unchanged,omitempty"`
+}
+
+type ImportedRuleDelta struct {
+	// UID of the rule, empty for rules that are not created yet
+	UID   string `json:"uid,omitempty"`
+	Title string `json:"title"`
+	// Diff lists the fields of an updated rule that change
+	Diff []RuleFieldDiff `json:"diff,omitempty"`
+}
+
+type RuleFieldDiff struct {
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
@@ -158,6 +158,12 @@ type ProvisionedAlertRule struct {
 	// required: true
 	// swagger:strfmt duration
 	For model.Duration `json:"for"`
+	// swagger:strfmt duration
+	// example: 5m
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
+	// minimum: 1
+	// example: 2
+	MissingSeriesEvalsToResolve *int64 `json:"missingSeriesEvalsToResolve,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -277,6 +283,10 @@ type AlertRuleExport struct {
 	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	KeepFiringFor        model.Duration                       `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	// KeepFiringForString is used to export the keep_firing_for field for HCL only if it is non-zero.
+	KeepFiringForString         *string `json:"-" yaml:"-" hcl:"keep_firing_for"`
+	MissingSeriesEvalsToResolve *int64  `json:"missingSeriesEvalsToResolve,omitempty" yaml:"missingSeriesEvalsToResolve,omitempty" hcl:"missing_series_evals_to_resolve"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	"github.com/prometheus/alertmanager/config"
 )
 
@@ -48,6 +50,24 @@ import (
 //       200: AlertingFileExport
 //       404: NotFound
 
+// swagger:route POST /v1/provisioning/policies/simulate provisioning stable RoutePostPolicyTreeSimulation
+//
+// Simulate the routing of alerts by the notification policy tree.
+//
+// Matches a label set, or the labels of the current alert instances of an alert rule, against the notification
+// policy tree, or against a draft tree that is not saved. Returns the matching policies and their effective settings.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RoutingSimulationResults
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:parameters RoutePutPolicyTree
 type Policytree struct {
 	// The new notification routing tree to use
@@ -61,6 +81,65 @@ type PolicyTreeHeaders struct {
 	XDisableProvenance string `json:"X-Disable-Provenance"`
 }
 
+// swagger:parameters RoutePostPolicyTreeSimulation
+type RoutingSimulationParams struct {
+	// in:body
+	Body RoutingSimulationRequest
+}
+
+// RoutingSimulationRequest describes the alerts to route. Exactly one of labels and ruleUid must be set.
+// swagger:model
+type RoutingSimulationRequest struct {
+	// The labels of the alert to route.
+	Labels map[string]string `json:"labels,omitempty"`
+	// The UID of an alert rule whose current alert instances are routed.
+	RuleUID string `json:"ruleUid,omitempty"`
+	// A draft of the notification policy tree to route the alerts with. Defaults to the current tree.
+	Route *Route `json:"route,omitempty"`
+	// The time at which the time intervals are evaluated. Defaults to now.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// swagger:model
+type RoutingSimulationResults []RoutingSimulationResult
+
+// RoutingSimulationResult is the routing of a single label set.
+type RoutingSimulationResult struct {
+	Labels map[string]string `json:"labels"`
+	// The policies that match the labels, in the order in which the alert is routed to them.
+	Routes []MatchedRoute `json:"routes"`
+}
+
+// MatchedRoute is a policy that matches an alert, with the settings that it inherits from its parents.
+type MatchedRoute struct {
+	// The position of the policy in the tree, as the indexes of the nested policies from the root. Empty for the root policy
+	// and for the autogenerated policies.
+	Path []int `json:"path"`
+	// Autogenerated is true if the policy is generated from the notification settings of the alert rules that use
+	// simplified routing. These policies are not part of the tree.
+	Autogenerated bool `json:"autogenerated"`
+	// The key of the policy, made of the matchers of the policy and of its parents.
+	Key            string   `json:"key"`
+	Receiver       string   `json:"receiver"`
+	GroupBy        []string `json:"group_by"`
+	GroupWait      string   `json:"group_wait"`
+	GroupInterval  string   `json:"group_interval"`
+	RepeatInterval string   `json:"repeat_interval"`
+	Continue       bool     `json:"continue"`
+	// The mute timings of the policy, and whether they are active at the time of the simulation.
+	MuteTimeIntervals []TimeIntervalStatus `json:"mute_time_intervals"`
+	// The active time intervals of the policy, and whether they are active at the time of the simulation.
+	ActiveTimeIntervals []TimeIntervalStatus `json:"active_time_intervals"`
+	// Muted is true if the notifications of the policy are muted at the time of the simulation.
+	Muted bool `json:"muted"`
+}
+
+// TimeIntervalStatus tells whether a time interval is active.
+type TimeIntervalStatus struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
 // NotificationPolicyExport is the provisioned file export of alerting.NotificiationPolicyV1.
 type NotificationPolicyExport struct {
 	OrgID        int64 `json:"orgId" yaml:"orgId"`
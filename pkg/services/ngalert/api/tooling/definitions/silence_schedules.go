@@ -0,0 +1,138 @@
+package definitions
+
+import (
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/notifications/silence-schedules notifications RouteGetSilenceSchedules
+//
+// Get all the silence schedules.
+//
+// Only the silence schedules whose silences the user can read are returned.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableSilenceSchedules
+//       403: PermissionDenied
+
+// swagger:route GET /v1/notifications/silence-schedules/{UID} notifications RouteGetSilenceSchedule
+//
+// Get a silence schedule by UID.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableSilenceSchedule
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:route POST /v1/notifications/silence-schedules notifications RouteCreateSilenceSchedule
+//
+// Create a silence schedule.
+//
+// The occurrences of the schedule are created as silences ahead of time.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       201: GettableSilenceSchedule
+//       400: ValidationError
+//       403: PermissionDenied
+
+// swagger:route PUT /v1/notifications/silence-schedules/{UID} notifications RouteUpdateSilenceSchedule
+//
+// Update a silence schedule.
+//
+// The silences of the upcoming occurrences are expired and created again.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableSilenceSchedule
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: PublicError
+
+// swagger:route DELETE /v1/notifications/silence-schedules/{UID} notifications RouteDeleteSilenceSchedule
+//
+// Delete a silence schedule.
+//
+// The silences of the occurrences that are not over are expired.
+//
+//     Responses:
+//       204: description: The silence schedule was deleted successfully.
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:parameters RouteGetSilenceSchedule RouteUpdateSilenceSchedule RouteDeleteSilenceSchedule
+type SilenceScheduleUIDReference struct {
+	// Silence schedule UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RouteCreateSilenceSchedule RouteUpdateSilenceSchedule
+type SilenceSchedulePayload struct {
+	// in:body
+	Body PostableSilenceSchedule
+}
+
+// swagger:model
+type PostableSilenceSchedule struct {
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	Comment  string        `json:"comment"`
+	// The author of the silences. Defaults to the login of the user.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// A standard cron expression with five fields. Recurrence rules (RRULE) are not supported.
+	// The occurrences must be at least one minute apart and must not overlap.
+	// required: true
+	// example: 0 22 * * SAT
+	Schedule string `json:"schedule"`
+	// The IANA name of the timezone in which the schedule is evaluated. Defaults to UTC.
+	// example: Europe/Berlin
+	Timezone string `json:"timezone,omitempty"`
+	// The duration of each occurrence.
+	// required: true
+	Duration model.Duration `json:"duration"`
+}
+
+// swagger:model
+type GettableSilenceSchedules []GettableSilenceSchedule
+
+// swagger:model
+type GettableSilenceSchedule struct {
+	UID       string         `json:"uid"`
+	Matchers  amv2.Matchers  `json:"matchers"`
+	Comment   string         `json:"comment"`
+	CreatedBy string         `json:"createdBy"`
+	Schedule  string         `json:"schedule"`
+	Timezone  string         `json:"timezone,omitempty"`
+	Duration  model.Duration `json:"duration"`
+	Created   time.Time      `json:"created"`
+	Updated   time.Time      `json:"updated"`
+	// The occurrences that are created as silences and are not over yet.
+	Occurrences []SilenceScheduleOccurrence `json:"occurrences"`
+}
+
+// SilenceScheduleOccurrence is an occurrence of a silence schedule that is created as a silence.
+type SilenceScheduleOccurrence struct {
+	SilenceID string    `json:"silenceId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+}
@@ -21,6 +21,7 @@ import (
 
 	alertingModels "github.com/grafana/alerting/models"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
@@ -30,6 +31,10 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
+	// ErrDeletedAlertRuleNotFound is an error for an unknown deleted alert rule.
+	ErrDeletedAlertRuleNotFound = errors.New("could not find deleted alert rule")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -164,6 +169,7 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	StateReasonKeepFiring    = "KeepFiring"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -270,6 +276,26 @@ type AlertRule struct {
 	IsPaused             bool
 	NotificationSettings []NotificationSettings
 	Metadata             AlertRuleMetadata
+	// KeepFiringFor is how long a firing series keeps firing after its condition stops being met.
+	KeepFiringFor time.Duration
+	// MissingSeriesEvalsToResolve is the number of consecutive evaluations a series can be missing from
+	// the results before it is resolved with the reason MissingSeries. If nil, the default is used.
+	MissingSeriesEvalsToResolve *int64
+	// UpdatedBy is the UID of the user who made the last change to the rule. It is FileProvisioningUserUID
+	// if the rule was changed by file provisioning, and nil if it was changed by the system.
+	UpdatedBy *UserUID
+}
+
+// UserUID is the UID of a user.
+type UserUID string
+
+// FileProvisioningUserUID is the author of the changes made by file provisioning.
+const FileProvisioningUserUID UserUID = "__provisioning__"
+
+// NewUserUID returns the UID of the user who makes a change.
+func NewUserUID(requester identity.Requester) *UserUID {
+	uid := UserUID(requester.GetRawIdentifier())
+	return &uid
 }
 
 type AlertRuleMetadata struct {
@@ -464,6 +490,14 @@ type AlertRuleKeyWithVersion struct {
 	AlertRuleKey `xorm:"extends"`
 }
 
+// DeletedAlertRule is an alert rule that was deleted and can be restored until the retention period expires.
+type DeletedAlertRule struct {
+	ID      int64
+	Deleted time.Time
+	// Rule is the alert rule as it was when it was deleted.
+	Rule AlertRule
+}
+
 type AlertRuleKeyWithGroup struct {
 	RuleGroup    string
 	AlertRuleKey `xorm:"extends"`
@@ -581,6 +615,14 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
 	}
 
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.MissingSeriesEvalsToResolve != nil && *alertRule.MissingSeriesEvalsToResolve < 1 {
+		return fmt.Errorf("%w: field `missing_series_evals_to_resolve` must be at least 1", ErrAlertRuleFailedValidation)
+	}
+
 	if len(alertRule.Labels) > 0 {
 		for label := range alertRule.Labels {
 			if _, ok := LabelsUserCannotSpecify[label]; ok {
@@ -657,6 +699,8 @@ func ClearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.ExecErrState = ""
 	rule.Condition = ""
 	rule.For = 0
+	rule.KeepFiringFor = 0
+	rule.MissingSeriesEvalsToResolve = nil
 	rule.NotificationSettings = nil
 }
 
@@ -802,6 +846,12 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
+	if ruleToPatch.MissingSeriesEvalsToResolve != nil && *ruleToPatch.MissingSeriesEvalsToResolve == -1 {
+		ruleToPatch.MissingSeriesEvalsToResolve = existingRule.MissingSeriesEvalsToResolve
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
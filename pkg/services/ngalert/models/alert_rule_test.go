@@ -247,6 +247,12 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "MissingSeriesEvalsToResolve is -1",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.MissingSeriesEvalsToResolve = util.Pointer[int64](-1)
+				},
+			},
 			{
 				name: "IsPaused did not come in request",
 				mutator: func(r *AlertRuleWithOptionals) {
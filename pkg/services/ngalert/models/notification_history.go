@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// NotificationStatus is the outcome of a notification sent by an integration.
+type NotificationStatus string
+
+const (
+	NotificationStatusSuccess NotificationStatus = "success"
+	NotificationStatusFailure NotificationStatus = "failure"
+)
+
+// NotificationHistoryEntry records a notification sent by an integration of a contact point, and its outcome.
+type NotificationHistoryEntry struct {
+	Timestamp        time.Time
+	OrgID            int64
+	GroupKey         string
+	Receiver         string
+	Integration      string
+	IntegrationIndex int
+	Status           NotificationStatus
+	Error            string
+	// Retry is true if the integration failed, and the notification will be retried.
+	Retry    bool
+	Duration time.Duration
+	Alerts   []NotificationHistoryAlert
+}
+
+// NotificationHistoryAlert is an alert of a notification.
+type NotificationHistoryAlert struct {
+	Fingerprint string
+	Status      string
+	Labels      map[string]string
+	StartsAt    time.Time
+	EndsAt      time.Time
+}
+
+// HasAlert returns true if the notification contains the alert with the given fingerprint.
+func (e NotificationHistoryEntry) HasAlert(fingerprint string) bool {
+	for _, a := range e.Alerts {
+		if a.Fingerprint == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationHistoryQuery represents a query for the notification history.
+type NotificationHistoryQuery struct {
+	OrgID            int64
+	Receiver         string
+	Integration      string
+	Status           NotificationStatus
+	GroupKey         string
+	AlertFingerprint string
+	From             time.Time
+	To               time.Time
+	Limit            int
+	SignedInUser     identity.Requester
+}
+
+// Matches returns true if the entry matches all filters of the query except for the time range.
+func (q NotificationHistoryQuery) Matches(e NotificationHistoryEntry) bool {
+	if q.OrgID != e.OrgID {
+		return false
+	}
+	if q.Receiver != "" && q.Receiver != e.Receiver {
+		return false
+	}
+	if q.Integration != "" && q.Integration != e.Integration {
+		return false
+	}
+	if q.Status != "" && q.Status != e.Status {
+		return false
+	}
+	if q.GroupKey != "" && q.GroupKey != e.GroupKey {
+		return false
+	}
+	if q.AlertFingerprint != "" && !e.HasAlert(q.AlertFingerprint) {
+		return false
+	}
+	return true
+}
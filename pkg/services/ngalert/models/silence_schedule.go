@@ -0,0 +1,160 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/alerting/notify"
+)
+
+var (
+	// ErrSilenceScheduleNotFound is an error for an unknown silence schedule.
+	ErrSilenceScheduleNotFound = errors.New("could not find silence schedule")
+	// ErrSilenceScheduleInvalid is an error for a silence schedule that does not pass validation.
+	ErrSilenceScheduleInvalid = errors.New("invalid silence schedule")
+	// ErrSilenceScheduleVersionConflict is an error for a silence schedule that was changed concurrently.
+	ErrSilenceScheduleVersionConflict = errors.New("silence schedule was changed concurrently")
+)
+
+const (
+	// MinSilenceScheduleDuration is the shortest duration of the occurrences of a silence schedule, and the shortest
+	// time between two occurrences.
+	MinSilenceScheduleDuration = time.Minute
+	// MaxSilenceScheduleOccurrences is the largest number of occurrences returned by OccurrencesBetween.
+	MaxSilenceScheduleOccurrences = 100
+	// silenceScheduleCheckOccurrences is the number of occurrences checked by the validation for overlaps.
+	silenceScheduleCheckOccurrences = 1000
+	// silenceScheduleCheckPeriod is the period checked by the validation for overlaps.
+	silenceScheduleCheckPeriod = 366 * 24 * time.Hour
+)
+
+// SilenceSchedule is a recurring silence. It starts a silence with the same matchers at every time matched by a cron
+// expression in the timezone of the schedule, for the duration of the schedule. The occurrences are created as regular
+// silences ahead of time.
+type SilenceSchedule struct {
+	ID    int64
+	UID   string
+	OrgID int64
+
+	Matchers  amv2.Matchers
+	Comment   string
+	CreatedBy string
+	// Schedule is a standard cron expression with five fields, e.g. "0 22 * * SAT" for every Saturday at 10 PM.
+	// Recurrence rules (RRULE) are not supported. The occurrences must not overlap.
+	Schedule string
+	// Timezone is the IANA name of the location in which the schedule is evaluated. Empty means UTC.
+	Timezone string
+	Duration time.Duration
+
+	Created time.Time
+	Updated time.Time
+	Version int64
+
+	// MaterializedUntil is the start time of the last occurrence that was created as a silence.
+	MaterializedUntil time.Time
+	// Occurrences are the occurrences created as silences that are not over yet.
+	Occurrences []SilenceScheduleOccurrence
+}
+
+// SilenceScheduleOccurrence is an occurrence of a silence schedule created as a silence.
+type SilenceScheduleOccurrence struct {
+	SilenceID string    `json:"silenceId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+// Validate checks that the silence schedule can be used to create silences.
+func (s SilenceSchedule) Validate() error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrSilenceScheduleInvalid)
+	}
+	if err := s.Matchers.Validate(strfmt.Default); err != nil {
+		return fmt.Errorf("%w: invalid matchers: %s", ErrSilenceScheduleInvalid, err)
+	}
+	if s.Duration < MinSilenceScheduleDuration {
+		return fmt.Errorf("%w: duration must be at least %s", ErrSilenceScheduleInvalid, MinSilenceScheduleDuration)
+	}
+	loc, err := s.location()
+	if err != nil {
+		return fmt.Errorf("%w: invalid timezone: %s", ErrSilenceScheduleInvalid, err)
+	}
+	schedule, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return fmt.Errorf("%w: invalid schedule, only standard cron expressions are supported: %s", ErrSilenceScheduleInvalid, err)
+	}
+	if err := s.checkOverlaps(schedule, loc); err != nil {
+		return fmt.Errorf("%w: %s", ErrSilenceScheduleInvalid, err)
+	}
+	return nil
+}
+
+// checkOverlaps checks that the occurrences of the schedule in the coming year are at least
+// MinSilenceScheduleDuration apart, and that they do not overlap.
+func (s SilenceSchedule) checkOverlaps(schedule cron.Schedule, loc *time.Location) error {
+	minGap := max(s.Duration, MinSilenceScheduleDuration)
+	prev := schedule.Next(time.Now().In(loc))
+	until := prev.Add(silenceScheduleCheckPeriod)
+	for i := 0; i < silenceScheduleCheckOccurrences && !prev.IsZero() && prev.Before(until); i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if gap := next.Sub(prev); gap < minGap {
+			if gap < MinSilenceScheduleDuration {
+				return fmt.Errorf("occurrences must be at least %s apart, but occurrences at %s and %s are %s apart", MinSilenceScheduleDuration, prev.UTC(), next.UTC(), gap)
+			}
+			return fmt.Errorf("occurrences must not overlap, but the occurrence at %s starts %s after the one at %s, which lasts %s", next.UTC(), gap, prev.UTC(), s.Duration)
+		}
+		prev = next
+	}
+	return nil
+}
+
+// OccurrencesBetween returns the start times of the occurrences that start after from and not after to, up to
+// MaxSilenceScheduleOccurrences. The following occurrences can be returned by calling it again from the last one.
+func (s SilenceSchedule) OccurrencesBetween(from, to time.Time) ([]time.Time, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	var result []time.Time
+	// The schedule is evaluated in the timezone of the silence schedule, so that e.g. daylight saving time is honored.
+	for next := schedule.Next(from.In(loc)); !next.IsZero() && !next.After(to) && len(result) < MaxSilenceScheduleOccurrences; next = schedule.Next(next) {
+		result = append(result, next.UTC())
+	}
+	return result, nil
+}
+
+// Silence returns a silence with the matchers, comment and author of the schedule. The times of the silence are set
+// to the occurrence that starts at the given time.
+func (s SilenceSchedule) Silence(startsAt time.Time) Silence {
+	start := strfmt.DateTime(startsAt)
+	end := strfmt.DateTime(startsAt.Add(s.Duration))
+	comment := s.Comment
+	createdBy := s.CreatedBy
+	return Silence{
+		Silence: notify.Silence{
+			Matchers:  s.Matchers,
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			StartsAt:  &start,
+			EndsAt:    &end,
+		},
+	}
+}
+
+func (s SilenceSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
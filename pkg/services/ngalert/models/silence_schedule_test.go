@@ -0,0 +1,174 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSilenceScheduleValidate(t *testing.T) {
+	valid := func() SilenceSchedule {
+		return SilenceSchedule{
+			Matchers: amv2.Matchers{{
+				Name:    util.Pointer("team"),
+				Value:   util.Pointer("ops"),
+				IsEqual: util.Pointer(true),
+				IsRegex: util.Pointer(false),
+			}},
+			Schedule: "0 22 * * SAT",
+			Timezone: "Europe/Berlin",
+			Duration: 2 * time.Hour,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		mutate   func(s *SilenceSchedule)
+		errorMsg string
+	}{
+		{
+			name:   "valid schedule",
+			mutate: func(s *SilenceSchedule) {},
+		},
+		{
+			name:   "empty timezone is UTC",
+			mutate: func(s *SilenceSchedule) { s.Timezone = "" },
+		},
+		{
+			name:     "no matchers",
+			mutate:   func(s *SilenceSchedule) { s.Matchers = nil },
+			errorMsg: "at least one matcher is required",
+		},
+		{
+			name:     "invalid matcher",
+			mutate:   func(s *SilenceSchedule) { s.Matchers[0].Name = nil },
+			errorMsg: "invalid matchers",
+		},
+		{
+			name:     "duration too short",
+			mutate:   func(s *SilenceSchedule) { s.Duration = time.Second },
+			errorMsg: "duration must be at least",
+		},
+		{
+			name:     "unknown timezone",
+			mutate:   func(s *SilenceSchedule) { s.Timezone = "Mars/Olympus_Mons" },
+			errorMsg: "invalid timezone",
+		},
+		{
+			name:     "invalid cron expression",
+			mutate:   func(s *SilenceSchedule) { s.Schedule = "every saturday" },
+			errorMsg: "invalid schedule",
+		},
+		{
+			name:     "recurrence rule",
+			mutate:   func(s *SilenceSchedule) { s.Schedule = "FREQ=WEEKLY;BYDAY=SA;BYHOUR=22" },
+			errorMsg: "only standard cron expressions are supported",
+		},
+		{
+			name:   "descriptor",
+			mutate: func(s *SilenceSchedule) { s.Schedule = "@daily" },
+		},
+		{
+			name:     "occurrences every second",
+			mutate:   func(s *SilenceSchedule) { s.Schedule = "@every 1s" },
+			errorMsg: "occurrences must be at least 1m0s apart",
+		},
+		{
+			name:     "occurrences every 30 seconds",
+			mutate:   func(s *SilenceSchedule) { s.Schedule = "@every 30s" },
+			errorMsg: "occurrences must be at least 1m0s apart",
+		},
+		{
+			name:     "overlapping occurrences",
+			mutate:   func(s *SilenceSchedule) { s.Schedule = "* * * * *" },
+			errorMsg: "occurrences must not overlap",
+		},
+		{
+			name: "occurrences overlapping on some days",
+			mutate: func(s *SilenceSchedule) {
+				s.Schedule = "0 22 * * FRI,SAT"
+				s.Duration = 36 * time.Hour
+			},
+			errorMsg: "occurrences must not overlap",
+		},
+		{
+			name: "occurrences every minute",
+			mutate: func(s *SilenceSchedule) {
+				s.Schedule = "* * * * *"
+				s.Duration = time.Minute
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.mutate(&s)
+			err := s.Validate()
+			if tc.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrSilenceScheduleInvalid)
+			require.ErrorContains(t, err, tc.errorMsg)
+		})
+	}
+}
+
+func TestSilenceScheduleOccurrencesBetween(t *testing.T) {
+	t.Run("returns the occurrences after from and until to", func(t *testing.T) {
+		s := SilenceSchedule{Schedule: "0 */6 * * *"}
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		result, err := s.OccurrencesBetween(from, from.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		}, result)
+	})
+
+	t.Run("evaluates the schedule in its timezone", func(t *testing.T) {
+		// Daylight saving time starts on 2024-03-31 in Europe/Berlin.
+		s := SilenceSchedule{Schedule: "0 12 * * *", Timezone: "Europe/Berlin"}
+		from := time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)
+
+		result, err := s.OccurrencesBetween(from, from.Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 3, 30, 11, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC),
+		}, result)
+	})
+
+	t.Run("returns at most MaxSilenceScheduleOccurrences occurrences", func(t *testing.T) {
+		s := SilenceSchedule{Schedule: "* * * * *"}
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		result, err := s.OccurrencesBetween(from, from.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, result, MaxSilenceScheduleOccurrences)
+		assert.Equal(t, from.Add(time.Minute), result[0])
+		assert.Equal(t, from.Add(MaxSilenceScheduleOccurrences*time.Minute), result[len(result)-1])
+
+		result, err = s.OccurrencesBetween(result[len(result)-1], from.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, from.Add((MaxSilenceScheduleOccurrences+1)*time.Minute), result[0])
+	})
+
+	t.Run("returns nothing if no occurrence starts in the range", func(t *testing.T) {
+		s := SilenceSchedule{Schedule: "0 22 * * *"}
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		result, err := s.OccurrencesBetween(from, from.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
@@ -320,6 +320,18 @@ func (a *AlertRuleMutators) WithForNTimes(timesOfInterval int64) AlertRuleMutato
 	}
 }
 
+func (a *AlertRuleMutators) WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
+func (a *AlertRuleMutators) WithMissingSeriesEvalsToResolve(evals int64) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.MissingSeriesEvalsToResolve = &evals
+	}
+}
+
 func (a *AlertRuleMutators) WithNoDataExecAs(nodata NoDataState) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NoDataState = nodata
@@ -621,9 +633,20 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
 		Record:          r.Record,
 	}
 
+	if r.MissingSeriesEvalsToResolve != nil {
+		evals := *r.MissingSeriesEvalsToResolve
+		result.MissingSeriesEvalsToResolve = &evals
+	}
+
+	if r.UpdatedBy != nil {
+		updatedBy := *r.UpdatedBy
+		result.UpdatedBy = &updatedBy
+	}
+
 	if r.DashboardUID != nil {
 		dash := *r.DashboardUID
 		result.DashboardUID = &dash
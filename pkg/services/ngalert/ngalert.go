@@ -35,6 +35,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	nfhistorian "github.com/grafana/grafana/pkg/services/ngalert/notifier/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/remote"
@@ -148,6 +149,7 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	AlertsRouter         *sender.AlertsRouter
+	silenceSchedules     *notifier.SilenceScheduleService
 	accesscontrol        accesscontrol.AccessControl
 	AccesscontrolService accesscontrol.Service
 	ResourcePermissions  accesscontrol.ReceiverPermissionsService
@@ -309,6 +311,18 @@ func (ng *AlertNG) init() error {
 		}
 	}
 
+	// There are a set of feature toggles available that act as short-circuits for common configurations.
+	// If any are set, override the config accordingly.
+	ApplyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
+
+	notificationHistory, err := configureNotificationHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting, ng.annotationsRepo, ng.Metrics.GetHistorianMetrics(), ng.Log, ng.tracer)
+	if err != nil {
+		return err
+	}
+	if ng.Cfg.UnifiedAlerting.NotificationHistory.Enabled {
+		overrides = append(overrides, notifier.WithNotificationHistorian(notificationHistory))
+	}
+
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	moa, err := notifier.NewMultiOrgAlertmanager(
@@ -391,9 +405,6 @@ func (ng *AlertNG) init() error {
 		RecordingWriter:      ng.RecordingWriter,
 	}
 
-	// There are a set of feature toggles available that act as short-circuits for common configurations.
-	// If any are set, override the config accordingly.
-	ApplyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
 	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log, ng.tracer, ac.NewRuleService(ng.accesscontrol))
 	if err != nil {
 		return err
@@ -456,7 +467,13 @@ func (ng *AlertNG) init() error {
 	)
 
 	// Provisioning
-	policyService := provisioning.NewNotificationPolicyService(configStore, ng.store, ng.store, ng.Cfg.UnifiedAlerting, ng.Log)
+	var policyAutogenFn provisioning.AutogenFn
+	if ng.FeatureToggles.IsEnabled(initCtx, featuremgmt.FlagAlertingSimplifiedRouting) {
+		policyAutogenFn = func(ctx context.Context, logger log.Logger, orgID int64, cfg *definitions.PostableApiAlertingConfig, skipInvalid bool) error {
+			return notifier.AddAutogenConfig(ctx, logger, ng.store, orgID, cfg, skipInvalid)
+		}
+	}
+	policyService := provisioning.NewNotificationPolicyService(configStore, ng.store, ng.store, ng.Cfg.UnifiedAlerting, ng.Log, policyAutogenFn)
 	contactPointService := provisioning.NewContactPointService(configStore, ng.SecretsService, ng.store, ng.store, provisioningReceiverService, ng.Log, ng.store, ng.ResourcePermissions)
 	templateService := provisioning.NewTemplateService(configStore, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(configStore, ng.store, ng.store, ng.Log, ng.store)
@@ -466,36 +483,45 @@ func (ng *AlertNG) init() error {
 		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store),
 		ac.NewRuleService(ng.accesscontrol))
 
+	ng.silenceSchedules = notifier.NewSilenceScheduleService(
+		ac.NewSilenceService(ng.accesscontrol, ng.store),
+		ng.store,
+		ng.MultiOrgAlertmanager,
+		ng.Log,
+	)
+
 	ng.Api = &api.API{
-		Cfg:                  ng.Cfg,
-		DatasourceCache:      ng.DataSourceCache,
-		DatasourceService:    ng.DataSourceService,
-		RouteRegister:        ng.RouteRegister,
-		DataProxy:            ng.DataProxy,
-		QuotaService:         ng.QuotaService,
-		TransactionManager:   ng.store,
-		RuleStore:            ng.store,
-		AlertingStore:        ng.store,
-		AdminConfigStore:     ng.store,
-		ProvenanceStore:      ng.store,
-		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
-		StateManager:         ng.stateManager,
-		Scheduler:            scheduler,
-		AccessControl:        ng.accesscontrol,
-		Policies:             policyService,
-		ReceiverService:      receiverService,
-		ContactPointService:  contactPointService,
-		Templates:            templateService,
-		MuteTimings:          muteTimingService,
-		AlertRules:           alertRuleService,
-		AlertsRouter:         alertsRouter,
-		EvaluatorFactory:     evalFactory,
-		ConditionValidator:   conditionValidator,
-		FeatureManager:       ng.FeatureToggles,
-		AppUrl:               appUrl,
-		Historian:            history,
-		Hooks:                api.NewHooks(ng.Log),
-		Tracer:               ng.tracer,
+		Cfg:                   ng.Cfg,
+		DatasourceCache:       ng.DataSourceCache,
+		DatasourceService:     ng.DataSourceService,
+		RouteRegister:         ng.RouteRegister,
+		DataProxy:             ng.DataProxy,
+		QuotaService:          ng.QuotaService,
+		TransactionManager:    ng.store,
+		RuleStore:             ng.store,
+		AlertingStore:         ng.store,
+		AdminConfigStore:      ng.store,
+		ProvenanceStore:       ng.store,
+		MultiOrgAlertmanager:  ng.MultiOrgAlertmanager,
+		StateManager:          ng.stateManager,
+		Scheduler:             scheduler,
+		AccessControl:         ng.accesscontrol,
+		Policies:              policyService,
+		ReceiverService:       receiverService,
+		ContactPointService:   contactPointService,
+		Templates:             templateService,
+		MuteTimings:           muteTimingService,
+		AlertRules:            alertRuleService,
+		AlertsRouter:          alertsRouter,
+		EvaluatorFactory:      evalFactory,
+		ConditionValidator:    conditionValidator,
+		FeatureManager:        ng.FeatureToggles,
+		AppUrl:                appUrl,
+		Historian:             history,
+		NotificationHistorian: notificationHistory,
+		SilenceSchedules:      ng.silenceSchedules,
+		Hooks:                 api.NewHooks(ng.Log),
+		Tracer:                ng.tracer,
 	}
 	ng.Api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -541,6 +567,9 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.silenceSchedules.Run(subCtx)
+	})
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		// Only Warm() the state manager if we are actually executing alerts.
@@ -643,6 +672,65 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
+// configureNotificationHistorianBackend creates the backend of the notification history.
+// The notification history is written to the backends configured for the state history.
+func configureNotificationHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingSettings, ar annotations.Repository, met *metrics.Historian, l log.Logger, tracer tracing.Tracer) (nfhistorian.Backend, error) {
+	if !cfg.NotificationHistory.Enabled {
+		return nfhistorian.NewNopBackend(), nil
+	}
+	return configureNotificationHistorianStore(ctx, cfg.StateHistory, ar, met, l, tracer)
+}
+
+func configureNotificationHistorianStore(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, met *metrics.Historian, l log.Logger, tracer tracing.Tracer) (nfhistorian.Backend, error) {
+	backend, err := historian.ParseBackendType(cfg.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend {
+	case historian.BackendTypeMultiple:
+		primaryCfg := cfg
+		primaryCfg.Backend = cfg.MultiPrimary
+		primary, err := configureNotificationHistorianStore(ctx, primaryCfg, ar, met, l, tracer)
+		if err != nil {
+			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
+		}
+
+		var secondaries []nfhistorian.Backend
+		for _, b := range cfg.MultiSecondaries {
+			secCfg := cfg
+			secCfg.Backend = b
+			sec, err := configureNotificationHistorianStore(ctx, secCfg, ar, met, l, tracer)
+			if err != nil {
+				return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", b, err)
+			}
+			secondaries = append(secondaries, sec)
+		}
+		return nfhistorian.NewMultipleBackend(primary, secondaries...), nil
+	case historian.BackendTypeAnnotations:
+		return nfhistorian.NewAnnotationBackend(log.New("ngalert.notifier.historian", "backend", "annotations"), ar), nil
+	case historian.BackendTypeLoki:
+		lcfg, err := historian.NewLokiConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote loki configuration: %w", err)
+		}
+		lokiBackendLogger := log.New("ngalert.notifier.historian", "backend", "loki")
+		client := historian.NewLokiClient(lcfg, historian.NewRequester(), met, lokiBackendLogger, tracer)
+		backend := nfhistorian.NewRemoteLokiBackend(lokiBackendLogger, client, lcfg.ExternalLabels)
+
+		testConnCtx, cancelFunc := context.WithTimeout(ctx, 10*time.Second)
+		defer cancelFunc()
+		if err := backend.TestConnection(testConnCtx); err != nil {
+			l.Error("Failed to communicate with configured remote Loki backend, notification history may not be persisted", "error", err)
+		}
+		return backend, nil
+	case historian.BackendTypeNoop:
+		return nfhistorian.NewNopBackend(), nil
+	}
+
+	return nil, fmt.Errorf("unrecognized notification history backend: %s", backend)
+}
+
 // ApplyStateHistoryFeatureToggles edits state history configuration to comply with currently active feature toggles.
 func ApplyStateHistoryFeatureToggles(cfg *setting.UnifiedAlertingStateHistorySettings, ft featuremgmt.FeatureToggles, logger log.Logger) {
 	backend, _ := historian.ParseBackendType(cfg.Backend)
@@ -59,6 +59,9 @@ type alertmanager struct {
 	decryptFn alertingNotify.GetDecryptedValueFn
 	orgID     int64
 
+	// notificationHistorian records the notifications sent by the integrations. It is nil if the history is disabled.
+	notificationHistorian NotificationHistorian
+
 	withAutogen bool
 }
 
@@ -91,7 +94,7 @@ func (m maintenanceOptions) MaintenanceFunc(state alertingNotify.State) (int64,
 
 func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store AlertingStore, stateStore stateStore,
 	peer alertingNotify.ClusterPeer, decryptFn alertingNotify.GetDecryptedValueFn, ns notifications.Service,
-	m *metrics.Alertmanager, withAutogen bool,
+	m *metrics.Alertmanager, notificationHistorian NotificationHistorian, withAutogen bool,
 ) (*alertmanager, error) {
 	nflog, err := stateStore.GetNotificationLog(ctx)
 	if err != nil {
@@ -151,6 +154,8 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		stateStore:          stateStore,
 		logger:              l,
 
+		notificationHistorian: notificationHistorian,
+
 		// TODO: Preferably, logic around autogen would be outside of the specific alertmanager implementation so that remote alertmanager will get it for free.
 		withAutogen: withAutogen,
 	}
@@ -408,6 +413,9 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
+	if am.notificationHistorian != nil {
+		integrations = withNotificationHistory(integrations, receiver.Name, am.orgID, am.notificationHistorian)
+	}
 	return integrations, nil
 }
 
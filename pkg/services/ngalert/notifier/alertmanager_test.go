@@ -52,7 +52,7 @@ func setupAMTest(t *testing.T) *alertmanager {
 	orgID := 1
 	stateStore := NewFileStore(int64(orgID), kvStore)
 
-	am, err := NewAlertmanager(context.Background(), 1, cfg, s, stateStore, &NilPeer{}, decryptFn, nil, m, false)
+	am, err := NewAlertmanager(context.Background(), 1, cfg, s, stateStore, &NilPeer{}, decryptFn, nil, m, nil, false)
 	require.NoError(t, err)
 	return am
 }
@@ -0,0 +1,197 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// NotificationHistoryTag is the tag of the annotations of the notification history.
+	NotificationHistoryTag = "notification-history"
+
+	receiverTagPrefix    = "receiver:"
+	integrationTagPrefix = "integration:"
+	statusTagPrefix      = "status:"
+
+	// annotationQueryBatchSize is the number of annotations fetched at once when the history is filtered by fields that
+	// are not tags. The annotations are fetched in batches until enough of them match the filters.
+	annotationQueryBatchSize = 1000
+)
+
+type AnnotationStore interface {
+	Find(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error)
+	SaveMany(ctx context.Context, items []annotations.Item) error
+}
+
+// AnnotationBackend is a Backend that stores the notification history as organization annotations.
+// The annotations are tagged with the receiver, integration and status of the notification so that they can be filtered.
+type AnnotationBackend struct {
+	store     AnnotationStore
+	log       log.Logger
+	batchSize int
+}
+
+func NewAnnotationBackend(logger log.Logger, store AnnotationStore) *AnnotationBackend {
+	return &AnnotationBackend{
+		store:     store,
+		log:       logger,
+		batchSize: annotationQueryBatchSize,
+	}
+}
+
+// Record writes the notification history entries as annotations.
+func (h *AnnotationBackend) Record(ctx context.Context, entries []ngmodels.NotificationHistoryEntry) <-chan error {
+	logger := h.log.FromContext(ctx)
+	items := make([]annotations.Item, 0, len(entries))
+	for _, e := range entries {
+		item, err := buildAnnotation(e)
+		if err != nil {
+			logger.Error("Failed to construct notification history annotation, skipping", "receiver", e.Receiver, "error", err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	errCh := make(chan error, 1)
+	if len(items) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// The notification is already sent, the history is written in the background with a new context so that
+	// cancelling the notification pipeline does not interrupt the write.
+	writeCtx, cancel := context.WithTimeout(context.Background(), NotificationHistoryWriteTimeout)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		if err := h.store.SaveMany(ctx, items); err != nil {
+			logger.Error("Failed to save notification history", "entries", len(items), "error", err)
+			errCh <- fmt.Errorf("failed to save notification history: %w", err)
+			return
+		}
+		logger.Debug("Done saving notification history", "entries", len(items))
+	}(writeCtx)
+	return errCh
+}
+
+// Query returns the notification history annotations that match the query, the latest first.
+func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error) {
+	tags := []string{NotificationHistoryTag}
+	if query.Receiver != "" {
+		tags = append(tags, receiverTagPrefix+query.Receiver)
+	}
+	if query.Integration != "" {
+		tags = append(tags, integrationTagPrefix+query.Integration)
+	}
+	if query.Status != "" {
+		tags = append(tags, statusTagPrefix+string(query.Status))
+	}
+
+	itemQuery := annotations.ItemQuery{
+		OrgID:        query.OrgID,
+		From:         query.From.UnixMilli(),
+		To:           query.To.UnixMilli(),
+		Tags:         tags,
+		Type:         "annotation",
+		Limit:        int64(query.Limit),
+		SignedInUser: query.SignedInUser,
+	}
+	// The group key and the alert fingerprint are not tags, they are filtered after the annotations are fetched.
+	// The limit of the store cannot be used then, so the annotations are fetched in batches, the latest first, until
+	// enough of them match or there are no more.
+	filtered := query.GroupKey != "" || query.AlertFingerprint != ""
+	if filtered {
+		itemQuery.Limit = int64(h.batchSize)
+	}
+
+	logger := h.log.FromContext(ctx)
+	result := make([]ngmodels.NotificationHistoryEntry, 0, itemQuery.Limit)
+	seen := make(map[int64]struct{})
+	for {
+		// The store changes the query, so each batch gets its own copy.
+		batchQuery := itemQuery
+		items, err := h.store.Find(ctx, &batchQuery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query annotations for notification history: %w", err)
+		}
+
+		added := 0
+		for _, item := range items {
+			// The annotations at the start of the batch were already seen at the end of the previous one.
+			if _, ok := seen[item.ID]; ok {
+				continue
+			}
+			seen[item.ID] = struct{}{}
+			added++
+			if item.Time < itemQuery.To {
+				itemQuery.To = item.Time
+			}
+
+			if item.Data == nil {
+				continue
+			}
+			b, err := item.Data.MarshalJSON()
+			if err != nil {
+				logger.Error("Annotation service gave an annotation with unparseable data, skipping", "id", item.ID, "error", err)
+				continue
+			}
+			var e entry
+			if err := json.Unmarshal(b, &e); err != nil {
+				logger.Error("Annotation is not a notification history entry, skipping", "id", item.ID, "error", err)
+				continue
+			}
+			r := fromEntry(query.OrgID, time.UnixMilli(item.Time), e)
+			if !query.Matches(r) {
+				continue
+			}
+			result = append(result, r)
+		}
+
+		if !filtered || len(items) < h.batchSize || added == 0 || (query.Limit > 0 && len(result) >= query.Limit) {
+			break
+		}
+	}
+	return sortAndLimit(result, query.Limit), nil
+}
+
+func buildAnnotation(e ngmodels.NotificationHistoryEntry) (annotations.Item, error) {
+	b, err := json.Marshal(toEntry(e))
+	if err != nil {
+		return annotations.Item{}, err
+	}
+	data, err := simplejson.NewJson(b)
+	if err != nil {
+		return annotations.Item{}, err
+	}
+
+	text := fmt.Sprintf("Notification of %d alert(s) sent to %s (%s)", len(e.Alerts), e.Receiver, e.Integration)
+	if e.Status == ngmodels.NotificationStatusFailure {
+		text = fmt.Sprintf("Notification of %d alert(s) to %s (%s) failed: %s", len(e.Alerts), e.Receiver, e.Integration, e.Error)
+	}
+	epoch := e.Timestamp.UnixMilli()
+	return annotations.Item{
+		OrgID:    e.OrgID,
+		Epoch:    epoch,
+		EpochEnd: epoch + e.Duration.Milliseconds(),
+		Text:     text,
+		Tags: []string{
+			NotificationHistoryTag,
+			receiverTagPrefix + e.Receiver,
+			integrationTagPrefix + e.Integration,
+			statusTagPrefix + string(e.Status),
+		},
+		Data: data,
+	}, nil
+}
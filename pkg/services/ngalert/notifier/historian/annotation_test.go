@@ -0,0 +1,206 @@
+package historian
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAnnotationBackend(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+
+	t.Run("recorded entries are queryable", func(t *testing.T) {
+		store := &fakeAnnotationStore{}
+		backend := NewAnnotationBackend(log.NewNopLogger(), store)
+
+		entries := []ngmodels.NotificationHistoryEntry{
+			testEntry(now.Add(-time.Minute), "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+			testEntry(now, "receiver1", ngmodels.NotificationStatusFailure, "fp2"),
+		}
+		require.NoError(t, <-backend.Record(context.Background(), entries))
+
+		res, err := backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{
+			OrgID: 1,
+			From:  now.Add(-time.Hour),
+			To:    now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []ngmodels.NotificationHistoryEntry{entries[1], entries[0]}, res)
+	})
+
+	t.Run("annotations are tagged with the receiver, integration and status", func(t *testing.T) {
+		store := &fakeAnnotationStore{}
+		backend := NewAnnotationBackend(log.NewNopLogger(), store)
+
+		e := testEntry(now, "receiver1", ngmodels.NotificationStatusFailure, "fp1")
+		require.NoError(t, <-backend.Record(context.Background(), []ngmodels.NotificationHistoryEntry{e}))
+
+		require.Len(t, store.items, 1)
+		item := store.items[0]
+		require.Equal(t, int64(1), item.OrgID)
+		require.Equal(t, now.UnixMilli(), item.Epoch)
+		require.Equal(t, now.Add(e.Duration).UnixMilli(), item.EpochEnd)
+		require.ElementsMatch(t, []string{"notification-history", "receiver:receiver1", "integration:slack", "status:failure"}, item.Tags)
+		require.Contains(t, item.Text, "failed: unexpected status code 500")
+	})
+
+	t.Run("query filters by tags and by the fields that are not tags", func(t *testing.T) {
+		store := &fakeAnnotationStore{}
+		backend := NewAnnotationBackend(log.NewNopLogger(), store)
+
+		entries := []ngmodels.NotificationHistoryEntry{
+			testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+			testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp2"),
+		}
+		require.NoError(t, <-backend.Record(context.Background(), entries))
+
+		res, err := backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{
+			OrgID:            1,
+			Receiver:         "receiver1",
+			Status:           ngmodels.NotificationStatusSuccess,
+			AlertFingerprint: "fp2",
+			From:             now.Add(-time.Hour),
+			To:               now.Add(time.Hour),
+			Limit:            5,
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, "fp2", res[0].Alerts[0].Fingerprint)
+
+		require.ElementsMatch(t, []string{"notification-history", "receiver:receiver1", "status:success"}, store.lastQuery.Tags)
+		// The fingerprint is not a tag, so the limit is applied after the annotations are filtered.
+		require.Equal(t, int64(annotationQueryBatchSize), store.lastQuery.Limit)
+	})
+
+	t.Run("query applies the limit after filtering by the fields that are not tags", func(t *testing.T) {
+		store := &fakeAnnotationStore{}
+		backend := NewAnnotationBackend(log.NewNopLogger(), store)
+		backend.batchSize = 2
+
+		entries := []ngmodels.NotificationHistoryEntry{
+			testEntry(now.Add(-4*time.Minute), "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+			testEntry(now.Add(-3*time.Minute), "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+			testEntry(now.Add(-2*time.Minute), "receiver1", ngmodels.NotificationStatusSuccess, "fp2"),
+			testEntry(now.Add(-time.Minute), "receiver1", ngmodels.NotificationStatusSuccess, "fp2"),
+			testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp2"),
+		}
+		require.NoError(t, <-backend.Record(context.Background(), entries))
+
+		res, err := backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{
+			OrgID:            1,
+			AlertFingerprint: "fp1",
+			From:             now.Add(-time.Hour),
+			To:               now.Add(time.Hour),
+			Limit:            1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []ngmodels.NotificationHistoryEntry{entries[1]}, res)
+
+		res, err = backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{
+			OrgID:            1,
+			AlertFingerprint: "fp1",
+			From:             now.Add(-time.Hour),
+			To:               now.Add(time.Hour),
+			Limit:            5,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []ngmodels.NotificationHistoryEntry{entries[1], entries[0]}, res)
+	})
+
+	t.Run("returns the error of the store", func(t *testing.T) {
+		store := &fakeAnnotationStore{err: errors.New("boom")}
+		backend := NewAnnotationBackend(log.NewNopLogger(), store)
+
+		err := <-backend.Record(context.Background(), []ngmodels.NotificationHistoryEntry{
+			testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+		})
+		require.ErrorContains(t, err, "boom")
+
+		_, err = backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{OrgID: 1})
+		require.ErrorContains(t, err, "boom")
+	})
+}
+
+func testEntry(ts time.Time, receiver string, status ngmodels.NotificationStatus, fingerprint string) ngmodels.NotificationHistoryEntry {
+	e := ngmodels.NotificationHistoryEntry{
+		Timestamp:   ts,
+		OrgID:       1,
+		GroupKey:    `{}:{alertname="test"}`,
+		Receiver:    receiver,
+		Integration: "slack",
+		Status:      status,
+		Duration:    250 * time.Millisecond,
+		Alerts: []ngmodels.NotificationHistoryAlert{
+			{
+				Fingerprint: fingerprint,
+				Status:      "firing",
+				Labels:      map[string]string{"alertname": "test"},
+				StartsAt:    ts.Add(-time.Hour).UTC(),
+				EndsAt:      ts.Add(time.Hour).UTC(),
+			},
+		},
+	}
+	if status == ngmodels.NotificationStatusFailure {
+		e.Error = "unexpected status code 500"
+		e.Retry = true
+	}
+	return e
+}
+
+type fakeAnnotationStore struct {
+	mtx       sync.Mutex
+	items     []annotations.Item
+	lastQuery *annotations.ItemQuery
+	err       error
+}
+
+func (f *fakeAnnotationStore) Find(_ context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastQuery = query
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make([]*annotations.ItemDTO, 0, len(f.items))
+	for i, item := range f.items {
+		if item.OrgID != query.OrgID {
+			continue
+		}
+		if query.From > 0 && query.To > 0 && (item.Epoch > query.To || item.EpochEnd < query.From) {
+			continue
+		}
+		result = append(result, &annotations.ItemDTO{
+			ID:   int64(i + 1),
+			Time: item.Epoch,
+			Tags: item.Tags,
+			Data: item.Data,
+		})
+	}
+	// Like the annotation store, the latest annotations are returned first, up to the limit.
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time > result[j].Time
+	})
+	if query.Limit > 0 && len(result) > int(query.Limit) {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+func (f *fakeAnnotationStore) SaveMany(_ context.Context, items []annotations.Item) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.items = append(f.items, items...)
+	return nil
+}
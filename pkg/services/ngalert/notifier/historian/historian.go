@@ -0,0 +1,160 @@
+// Package historian contains the backends of the notification history, i.e. the record of the notifications sent by
+// the integrations of the contact points and their outcome. The history is written to the same kinds of stores as the
+// alert state history.
+package historian
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const NotificationHistoryWriteTimeout = time.Minute
+
+// Backend records and queries the notification history.
+type Backend interface {
+	Record(ctx context.Context, entries []ngmodels.NotificationHistoryEntry) <-chan error
+	Query(ctx context.Context, query ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error)
+}
+
+// entry is the serialized form of a notification history entry.
+// It is stored in the Loki log lines and in the data of the annotations.
+type entry struct {
+	SchemaVersion    int     `json:"schemaVersion"`
+	GroupKey         string  `json:"groupKey"`
+	Receiver         string  `json:"receiver"`
+	Integration      string  `json:"integration"`
+	IntegrationIndex int     `json:"integrationIndex"`
+	Status           string  `json:"status"`
+	Error            string  `json:"error,omitempty"`
+	Retry            bool    `json:"retry"`
+	DurationMs       int64   `json:"durationMs"`
+	Alerts           []alert `json:"alerts"`
+}
+
+type alert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+func toEntry(e ngmodels.NotificationHistoryEntry) entry {
+	alerts := make([]alert, 0, len(e.Alerts))
+	for _, a := range e.Alerts {
+		alerts = append(alerts, alert{
+			Fingerprint: a.Fingerprint,
+			Status:      a.Status,
+			Labels:      a.Labels,
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		})
+	}
+	return entry{
+		SchemaVersion:    1,
+		GroupKey:         e.GroupKey,
+		Receiver:         e.Receiver,
+		Integration:      e.Integration,
+		IntegrationIndex: e.IntegrationIndex,
+		Status:           string(e.Status),
+		Error:            e.Error,
+		Retry:            e.Retry,
+		DurationMs:       e.Duration.Milliseconds(),
+		Alerts:           alerts,
+	}
+}
+
+func fromEntry(orgID int64, timestamp time.Time, e entry) ngmodels.NotificationHistoryEntry {
+	alerts := make([]ngmodels.NotificationHistoryAlert, 0, len(e.Alerts))
+	for _, a := range e.Alerts {
+		alerts = append(alerts, ngmodels.NotificationHistoryAlert{
+			Fingerprint: a.Fingerprint,
+			Status:      a.Status,
+			Labels:      a.Labels,
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		})
+	}
+	return ngmodels.NotificationHistoryEntry{
+		Timestamp:        timestamp,
+		OrgID:            orgID,
+		GroupKey:         e.GroupKey,
+		Receiver:         e.Receiver,
+		Integration:      e.Integration,
+		IntegrationIndex: e.IntegrationIndex,
+		Status:           ngmodels.NotificationStatus(e.Status),
+		Error:            e.Error,
+		Retry:            e.Retry,
+		Duration:         time.Duration(e.DurationMs) * time.Millisecond,
+		Alerts:           alerts,
+	}
+}
+
+// sortAndLimit sorts the entries from the latest to the oldest, and keeps at most limit entries if limit is positive.
+func sortAndLimit(entries []ngmodels.NotificationHistoryEntry, limit int) []ngmodels.NotificationHistoryEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// MultipleBackend records the notification history to multiple backends at once.
+// Only the primary backend is used for queries.
+type MultipleBackend struct {
+	primary     Backend
+	secondaries []Backend
+}
+
+func NewMultipleBackend(primary Backend, secondaries ...Backend) *MultipleBackend {
+	return &MultipleBackend{
+		primary:     primary,
+		secondaries: secondaries,
+	}
+}
+
+func (h *MultipleBackend) Record(ctx context.Context, entries []ngmodels.NotificationHistoryEntry) <-chan error {
+	jobs := make([]<-chan error, 0, len(h.secondaries)+1)
+	for _, b := range append([]Backend{h.primary}, h.secondaries...) {
+		jobs = append(jobs, b.Record(ctx, entries))
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		errs := make([]error, 0)
+		for _, ch := range jobs {
+			if err := <-ch; err != nil {
+				errs = append(errs, err)
+			}
+		}
+		errCh <- errors.Join(errs...)
+	}()
+	return errCh
+}
+
+func (h *MultipleBackend) Query(ctx context.Context, query ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error) {
+	return h.primary.Query(ctx, query)
+}
+
+// NoOpBackend is a Backend that drops the notification history, to be used when the history is disabled.
+type NoOpBackend struct{}
+
+func NewNopBackend() *NoOpBackend {
+	return &NoOpBackend{}
+}
+
+func (b *NoOpBackend) Record(_ context.Context, _ []ngmodels.NotificationHistoryEntry) <-chan error {
+	errCh := make(chan error)
+	close(errCh)
+	return errCh
+}
+
+func (b *NoOpBackend) Query(_ context.Context, _ ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error) {
+	return []ngmodels.NotificationHistoryEntry{}, nil
+}
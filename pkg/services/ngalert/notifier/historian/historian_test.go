@@ -0,0 +1,72 @@
+package historian
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestMultipleBackend(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	entries := []ngmodels.NotificationHistoryEntry{
+		testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+	}
+
+	t.Run("records to all backends and queries the primary", func(t *testing.T) {
+		primary := &fakeAnnotationStore{}
+		secondary := &fakeLokiClient{}
+		backend := NewMultipleBackend(
+			NewAnnotationBackend(log.NewNopLogger(), primary),
+			NewRemoteLokiBackend(log.NewNopLogger(), secondary, nil),
+		)
+
+		require.NoError(t, <-backend.Record(context.Background(), entries))
+		require.Len(t, primary.items, 1)
+		require.Len(t, secondary.pushed, 1)
+
+		_, err := backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.NotNil(t, primary.lastQuery)
+		require.Empty(t, secondary.lastQuery)
+	})
+
+	t.Run("returns the errors of all backends", func(t *testing.T) {
+		backend := NewMultipleBackend(
+			NewAnnotationBackend(log.NewNopLogger(), &fakeAnnotationStore{err: errors.New("primary failed")}),
+			NewRemoteLokiBackend(log.NewNopLogger(), &fakeLokiClient{err: errors.New("secondary failed")}, nil),
+		)
+
+		err := <-backend.Record(context.Background(), entries)
+		require.ErrorContains(t, err, "primary failed")
+		require.ErrorContains(t, err, "secondary failed")
+	})
+}
+
+func TestSortAndLimit(t *testing.T) {
+	now := time.Now()
+	entries := []ngmodels.NotificationHistoryEntry{
+		{Timestamp: now.Add(-2 * time.Minute), Receiver: "oldest"},
+		{Timestamp: now, Receiver: "latest"},
+		{Timestamp: now.Add(-time.Minute), Receiver: "middle"},
+	}
+
+	res := sortAndLimit(entries, 0)
+	require.Equal(t, []string{"latest", "middle", "oldest"}, receivers(res))
+
+	res = sortAndLimit(entries, 2)
+	require.Equal(t, []string{"latest", "middle"}, receivers(res))
+}
+
+func receivers(entries []ngmodels.NotificationHistoryEntry) []string {
+	result := make([]string, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.Receiver)
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	statehistorian "github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+)
+
+const (
+	NotificationHistoryLabelValue = "notification-history"
+	ReceiverLabel                 = "receiver"
+)
+
+type remoteLokiClient interface {
+	Ping(context.Context) error
+	Push(context.Context, []statehistorian.Stream) error
+	RangeQuery(ctx context.Context, logQL string, start, end, limit int64) (statehistorian.QueryRes, error)
+}
+
+// RemoteLokiBackend is a Backend that writes the notification history to an external Loki instance.
+// It uses a stream per receiver, next to the streams of the alert state history.
+type RemoteLokiBackend struct {
+	client         remoteLokiClient
+	externalLabels map[string]string
+	log            log.Logger
+}
+
+func NewRemoteLokiBackend(logger log.Logger, client remoteLokiClient, externalLabels map[string]string) *RemoteLokiBackend {
+	return &RemoteLokiBackend{
+		client:         client,
+		externalLabels: externalLabels,
+		log:            logger,
+	}
+}
+
+func (h *RemoteLokiBackend) TestConnection(ctx context.Context) error {
+	return h.client.Ping(ctx)
+}
+
+// Record writes the notification history entries to Loki.
+func (h *RemoteLokiBackend) Record(ctx context.Context, entries []ngmodels.NotificationHistoryEntry) <-chan error {
+	streams := h.entriesToStreams(entries, h.log.FromContext(ctx))
+
+	errCh := make(chan error, 1)
+	if len(streams) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// The notification is already sent, the history is written in the background with a new context so that
+	// cancelling the notification pipeline does not interrupt the write.
+	writeCtx, cancel := context.WithTimeout(context.Background(), NotificationHistoryWriteTimeout)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		if err := h.client.Push(ctx, streams); err != nil {
+			logger.Error("Failed to save notification history", "entries", len(entries), "error", err)
+			errCh <- fmt.Errorf("failed to save notification history: %w", err)
+			return
+		}
+		logger.Debug("Done saving notification history", "entries", len(entries))
+	}(writeCtx)
+	return errCh
+}
+
+// Query returns the notification history entries from Loki that match the query, the latest first.
+func (h *RemoteLokiBackend) Query(ctx context.Context, query ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error) {
+	res, err := h.client.RangeQuery(ctx, BuildLogQuery(query), query.From.UnixNano(), query.To.UnixNano(), int64(query.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	logger := h.log.FromContext(ctx)
+	result := make([]ngmodels.NotificationHistoryEntry, 0)
+	for _, stream := range res.Data.Result {
+		for _, sample := range stream.Values {
+			var e entry
+			if err := json.Unmarshal([]byte(sample.V), &e); err != nil {
+				logger.Error("Failed to parse notification history entry, skipping", "error", err)
+				continue
+			}
+			r := fromEntry(query.OrgID, sample.T, e)
+			if !query.Matches(r) {
+				continue
+			}
+			result = append(result, r)
+		}
+	}
+	return sortAndLimit(result, query.Limit), nil
+}
+
+func (h *RemoteLokiBackend) entriesToStreams(entries []ngmodels.NotificationHistoryEntry, logger log.Logger) []statehistorian.Stream {
+	streams := make([]statehistorian.Stream, 0, 1)
+	byKey := make(map[string]int)
+	for _, e := range entries {
+		line, err := json.Marshal(toEntry(e))
+		if err != nil {
+			logger.Error("Failed to construct notification history entry, skipping", "receiver", e.Receiver, "error", err)
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%s", e.OrgID, e.Receiver)
+		idx, ok := byKey[key]
+		if !ok {
+			labels := make(map[string]string, len(h.externalLabels)+3)
+			for k, v := range h.externalLabels {
+				labels[k] = v
+			}
+			// System-defined labels take precedence over user-defined external labels.
+			labels[statehistorian.StateHistoryLabelKey] = NotificationHistoryLabelValue
+			labels[statehistorian.OrgIDLabel] = fmt.Sprint(e.OrgID)
+			labels[ReceiverLabel] = e.Receiver
+			streams = append(streams, statehistorian.Stream{Stream: labels})
+			idx = len(streams) - 1
+			byKey[key] = idx
+		}
+		streams[idx].Values = append(streams[idx].Values, statehistorian.Sample{
+			T: e.Timestamp,
+			V: string(line),
+		})
+	}
+	return streams
+}
+
+// BuildLogQuery converts the notification history query to a LogQL query.
+// The alert fingerprint is only used as a line filter, the exact match is checked on the results.
+func BuildLogQuery(query ngmodels.NotificationHistoryQuery) string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, `{%s=%q,%s=%q`, statehistorian.OrgIDLabel, fmt.Sprint(query.OrgID), statehistorian.StateHistoryLabelKey, NotificationHistoryLabelValue)
+	if query.Receiver != "" {
+		fmt.Fprintf(&b, `,%s=%q`, ReceiverLabel, query.Receiver)
+	}
+	b.WriteString("}")
+
+	if query.AlertFingerprint != "" {
+		fmt.Fprintf(&b, ` |= %q`, query.AlertFingerprint)
+	}
+	if query.Integration == "" && query.Status == "" && query.GroupKey == "" {
+		return b.String()
+	}
+	b.WriteString(" | json")
+	if query.Integration != "" {
+		fmt.Fprintf(&b, ` | integration=%q`, query.Integration)
+	}
+	if query.Status != "" {
+		fmt.Fprintf(&b, ` | status=%q`, string(query.Status))
+	}
+	if query.GroupKey != "" {
+		fmt.Fprintf(&b, ` | groupKey=%q`, query.GroupKey)
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package historian
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	statehistorian "github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+)
+
+func TestRemoteLokiBackend(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+
+	t.Run("writes a stream per receiver", func(t *testing.T) {
+		client := &fakeLokiClient{}
+		backend := NewRemoteLokiBackend(log.NewNopLogger(), client, map[string]string{"cluster": "test", "receiver": "overridden"})
+
+		entries := []ngmodels.NotificationHistoryEntry{
+			testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+			testEntry(now, "receiver2", ngmodels.NotificationStatusFailure, "fp2"),
+			testEntry(now.Add(time.Second), "receiver1", ngmodels.NotificationStatusSuccess, "fp3"),
+		}
+		require.NoError(t, <-backend.Record(context.Background(), entries))
+
+		require.Len(t, client.pushed, 2)
+		require.Equal(t, map[string]string{
+			"cluster":  "test",
+			"from":     "notification-history",
+			"orgID":    "1",
+			"receiver": "receiver1",
+		}, client.pushed[0].Stream)
+		require.Len(t, client.pushed[0].Values, 2)
+		require.Equal(t, "receiver2", client.pushed[1].Stream["receiver"])
+		require.Len(t, client.pushed[1].Values, 1)
+	})
+
+	t.Run("recorded entries are queryable", func(t *testing.T) {
+		client := &fakeLokiClient{}
+		backend := NewRemoteLokiBackend(log.NewNopLogger(), client, nil)
+
+		entries := []ngmodels.NotificationHistoryEntry{
+			testEntry(now.Add(-time.Minute), "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+			testEntry(now, "receiver2", ngmodels.NotificationStatusFailure, "fp2"),
+		}
+		require.NoError(t, <-backend.Record(context.Background(), entries))
+
+		query := ngmodels.NotificationHistoryQuery{
+			OrgID: 1,
+			From:  now.Add(-time.Hour),
+			To:    now.Add(time.Hour),
+			Limit: 10,
+		}
+		res, err := backend.Query(context.Background(), query)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, entries[1].Receiver, res[0].Receiver)
+		require.Equal(t, entries[1].Error, res[0].Error)
+		require.Equal(t, entries[1].Duration, res[0].Duration)
+		require.Equal(t, entries[1].Alerts, res[0].Alerts)
+		require.Equal(t, entries[0].Receiver, res[1].Receiver)
+
+		require.Equal(t, BuildLogQuery(query), client.lastQuery)
+		require.Equal(t, int64(10), client.lastLimit)
+
+		query.Limit = 1
+		res, err = backend.Query(context.Background(), query)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, "receiver2", res[0].Receiver)
+	})
+
+	t.Run("returns the error of the client", func(t *testing.T) {
+		client := &fakeLokiClient{err: errors.New("boom")}
+		backend := NewRemoteLokiBackend(log.NewNopLogger(), client, nil)
+
+		err := <-backend.Record(context.Background(), []ngmodels.NotificationHistoryEntry{
+			testEntry(now, "receiver1", ngmodels.NotificationStatusSuccess, "fp1"),
+		})
+		require.ErrorContains(t, err, "boom")
+
+		_, err = backend.Query(context.Background(), ngmodels.NotificationHistoryQuery{OrgID: 1})
+		require.ErrorContains(t, err, "boom")
+	})
+}
+
+func TestBuildLogQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		query ngmodels.NotificationHistoryQuery
+		exp   string
+	}{
+		{
+			name:  "org only",
+			query: ngmodels.NotificationHistoryQuery{OrgID: 1},
+			exp:   `{orgID="1",from="notification-history"}`,
+		},
+		{
+			name:  "receiver is a stream selector",
+			query: ngmodels.NotificationHistoryQuery{OrgID: 1, Receiver: "my receiver"},
+			exp:   `{orgID="1",from="notification-history",receiver="my receiver"}`,
+		},
+		{
+			name:  "fingerprint is a line filter",
+			query: ngmodels.NotificationHistoryQuery{OrgID: 1, AlertFingerprint: "abc"},
+			exp:   `{orgID="1",from="notification-history"} |= "abc"`,
+		},
+		{
+			name: "other fields are label filters",
+			query: ngmodels.NotificationHistoryQuery{
+				OrgID:       2,
+				Receiver:    "receiver1",
+				Integration: "slack",
+				Status:      ngmodels.NotificationStatusFailure,
+				GroupKey:    `{}:{alertname="test"}`,
+			},
+			exp: `{orgID="2",from="notification-history",receiver="receiver1"} | json | integration="slack" | status="failure" | groupKey="{}:{alertname=\"test\"}"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, BuildLogQuery(tc.query))
+		})
+	}
+}
+
+type fakeLokiClient struct {
+	mtx       sync.Mutex
+	pushed    []statehistorian.Stream
+	lastQuery string
+	lastLimit int64
+	err       error
+}
+
+func (f *fakeLokiClient) Ping(_ context.Context) error {
+	return f.err
+}
+
+func (f *fakeLokiClient) Push(_ context.Context, streams []statehistorian.Stream) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.pushed = append(f.pushed, streams...)
+	return nil
+}
+
+func (f *fakeLokiClient) RangeQuery(_ context.Context, logQL string, _, _, limit int64) (statehistorian.QueryRes, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastQuery = logQL
+	f.lastLimit = limit
+	if f.err != nil {
+		return statehistorian.QueryRes{}, f.err
+	}
+	return statehistorian.QueryRes{
+		Data: statehistorian.QueryData{Result: f.pushed},
+	}, nil
+}
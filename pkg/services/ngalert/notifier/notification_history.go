@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationHistorian records the notifications sent by the integrations of the contact points.
+type NotificationHistorian interface {
+	Record(ctx context.Context, entries []ngmodels.NotificationHistoryEntry) <-chan error
+}
+
+// WithNotificationHistorian makes the Alertmanagers record every notification they send, and its outcome, to the historian.
+func WithNotificationHistorian(h NotificationHistorian) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.notificationHistorian = h
+	}
+}
+
+// historyNotifier wraps an integration and records the outcome of every notification it sends.
+type historyNotifier struct {
+	integration *alertingNotify.Integration
+	receiver    string
+	orgID       int64
+	historian   NotificationHistorian
+	now         func() time.Time
+}
+
+// withNotificationHistory wraps the integrations of the receiver so that their notifications are recorded to the historian.
+func withNotificationHistory(integrations []*alertingNotify.Integration, receiver string, orgID int64, historian NotificationHistorian) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		n := &historyNotifier{
+			integration: integration,
+			receiver:    receiver,
+			orgID:       orgID,
+			historian:   historian,
+			now:         time.Now,
+		}
+		result = append(result, alertingNotify.NewIntegration(n, n, integration.Name(), integration.Index(), receiver))
+	}
+	return result
+}
+
+// Notify sends the notification with the wrapped integration and records its outcome.
+func (n *historyNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := n.now()
+	retry, err := n.integration.Notify(ctx, alerts...)
+
+	entry := ngmodels.NotificationHistoryEntry{
+		Timestamp:        start,
+		OrgID:            n.orgID,
+		Receiver:         n.receiver,
+		Integration:      n.integration.Name(),
+		IntegrationIndex: n.integration.Index(),
+		Status:           ngmodels.NotificationStatusSuccess,
+		Retry:            retry,
+		Duration:         n.now().Sub(start),
+		Alerts:           make([]ngmodels.NotificationHistoryAlert, 0, len(alerts)),
+	}
+	if groupKey, ok := notify.GroupKey(ctx); ok {
+		entry.GroupKey = groupKey
+	}
+	if err != nil {
+		entry.Status = ngmodels.NotificationStatusFailure
+		entry.Error = err.Error()
+	}
+	for _, a := range alerts {
+		labels := make(map[string]string, len(a.Labels))
+		for k, v := range a.Labels {
+			if strings.HasPrefix(string(k), "__") && strings.HasSuffix(string(k), "__") {
+				continue
+			}
+			labels[string(k)] = string(v)
+		}
+		entry.Alerts = append(entry.Alerts, ngmodels.NotificationHistoryAlert{
+			Fingerprint: a.Fingerprint().String(),
+			Status:      string(a.Status()),
+			Labels:      labels,
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		})
+	}
+
+	// The history is written in the background, and the backends log the errors.
+	// A notification must never fail because its history could not be recorded.
+	n.historian.Record(ctx, []ngmodels.NotificationHistoryEntry{entry})
+	return retry, err
+}
+
+// SendResolved returns true if the wrapped integration sends resolved notifications.
+func (n *historyNotifier) SendResolved() bool {
+	return n.integration.SendResolved()
+}
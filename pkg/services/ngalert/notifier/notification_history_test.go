@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestWithNotificationHistory(t *testing.T) {
+	now := time.Now()
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				"alertname":          "test",
+				"__alert_rule_uid__": "rule-uid",
+				"team":               "a",
+			},
+			StartsAt: now.Add(-time.Hour),
+			EndsAt:   now.Add(time.Hour),
+		},
+	}
+
+	setup := func(retry bool, err error) (*alertingNotify.Integration, *fakeNotificationHistorian, *fakeIntegrationNotifier) {
+		n := &fakeIntegrationNotifier{retry: retry, err: err}
+		historian := &fakeNotificationHistorian{}
+		integrations := withNotificationHistory(
+			[]*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "slack", 2, "receiver1")},
+			"receiver1",
+			1,
+			historian,
+		)
+		require.Len(t, integrations, 1)
+		return integrations[0], historian, n
+	}
+
+	t.Run("records successful notifications", func(t *testing.T) {
+		integration, historian, n := setup(false, nil)
+		ctx := notify.WithGroupKey(context.Background(), "group-key")
+
+		retry, err := integration.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.Equal(t, 1, n.calls)
+		require.Equal(t, "slack", integration.Name())
+		require.Equal(t, 2, integration.Index())
+		require.True(t, integration.SendResolved())
+
+		require.Len(t, historian.entries, 1)
+		e := historian.entries[0]
+		require.Equal(t, int64(1), e.OrgID)
+		require.Equal(t, "group-key", e.GroupKey)
+		require.Equal(t, "receiver1", e.Receiver)
+		require.Equal(t, "slack", e.Integration)
+		require.Equal(t, 2, e.IntegrationIndex)
+		require.Equal(t, ngmodels.NotificationStatusSuccess, e.Status)
+		require.Empty(t, e.Error)
+		require.Len(t, e.Alerts, 1)
+		require.Equal(t, alert.Fingerprint().String(), e.Alerts[0].Fingerprint)
+		require.Equal(t, map[string]string{"alertname": "test", "team": "a"}, e.Alerts[0].Labels)
+		require.Equal(t, alert.StartsAt, e.Alerts[0].StartsAt)
+		require.Equal(t, alert.EndsAt, e.Alerts[0].EndsAt)
+	})
+
+	t.Run("records failed notifications and returns the error", func(t *testing.T) {
+		integration, historian, _ := setup(true, errors.New("unexpected status code 500"))
+
+		retry, err := integration.Notify(context.Background(), alert)
+		require.ErrorContains(t, err, "unexpected status code 500")
+		require.True(t, retry)
+
+		require.Len(t, historian.entries, 1)
+		e := historian.entries[0]
+		require.Equal(t, ngmodels.NotificationStatusFailure, e.Status)
+		require.Equal(t, "unexpected status code 500", e.Error)
+		require.True(t, e.Retry)
+		require.Empty(t, e.GroupKey)
+	})
+}
+
+type fakeIntegrationNotifier struct {
+	calls int
+	retry bool
+	err   error
+}
+
+func (f *fakeIntegrationNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	f.calls++
+	return f.retry, f.err
+}
+
+func (f *fakeIntegrationNotifier) SendResolved() bool {
+	return true
+}
+
+type fakeNotificationHistorian struct {
+	mtx     sync.Mutex
+	entries []ngmodels.NotificationHistoryEntry
+}
+
+func (f *fakeNotificationHistorian) Record(_ context.Context, entries []ngmodels.NotificationHistoryEntry) <-chan error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.entries = append(f.entries, entries...)
+	errCh := make(chan error)
+	close(errCh)
+	return errCh
+}
@@ -0,0 +1,292 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// silenceScheduleLookahead is how long in advance the occurrences of the silence schedules are created as silences.
+	silenceScheduleLookahead = 24 * time.Hour
+	// silenceScheduleInterval is how often the occurrences of the silence schedules are created.
+	silenceScheduleInterval = time.Minute
+)
+
+// SilenceScheduleStore is the storage of the silence schedules.
+type SilenceScheduleStore interface {
+	ListSilenceSchedules(ctx context.Context, orgID int64) ([]*models.SilenceSchedule, error)
+	ListAllSilenceSchedules(ctx context.Context) ([]*models.SilenceSchedule, error)
+	GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (*models.SilenceSchedule, error)
+	InsertSilenceSchedule(ctx context.Context, s models.SilenceSchedule) (*models.SilenceSchedule, error)
+	UpdateSilenceSchedule(ctx context.Context, s models.SilenceSchedule) (*models.SilenceSchedule, error)
+	DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string) error
+}
+
+// SilenceScheduleService manages the recurring silences, and creates their occurrences as regular silences ahead of time.
+// A silence schedule is authorized as the silences it creates: a user who can read, create or update these silences
+// can read, create or update the schedule.
+type SilenceScheduleService struct {
+	authz    SilenceAccessControlService
+	store    SilenceScheduleStore
+	silences SilenceStore
+	log      log.Logger
+	now      func() time.Time
+}
+
+func NewSilenceScheduleService(
+	authz SilenceAccessControlService,
+	store SilenceScheduleStore,
+	silences SilenceStore,
+	log log.Logger,
+) *SilenceScheduleService {
+	return &SilenceScheduleService{
+		authz:    authz,
+		store:    store,
+		silences: silences,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// ListSilenceSchedules returns the silence schedules of the organization of the user that the user can read.
+func (s *SilenceScheduleService) ListSilenceSchedules(ctx context.Context, user identity.Requester) ([]*models.SilenceSchedule, error) {
+	schedules, err := s.store.ListSilenceSchedules(ctx, user.GetOrgID())
+	if err != nil {
+		return nil, err
+	}
+
+	bySilence := make(map[*models.Silence]*models.SilenceSchedule, len(schedules))
+	silences := make([]*models.Silence, 0, len(schedules))
+	for _, schedule := range schedules {
+		silence := schedule.Silence(s.now())
+		bySilence[&silence] = schedule
+		silences = append(silences, &silence)
+	}
+	allowed, err := s.authz.FilterByAccess(ctx, user, silences...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.SilenceSchedule, 0, len(allowed))
+	for _, silence := range allowed {
+		if schedule, ok := bySilence[silence]; ok {
+			result = append(result, schedule)
+		}
+	}
+	return result, nil
+}
+
+// GetSilenceSchedule returns the silence schedule with the given UID.
+func (s *SilenceScheduleService) GetSilenceSchedule(ctx context.Context, user identity.Requester, uid string) (*models.SilenceSchedule, error) {
+	schedule, err := s.store.GetSilenceSchedule(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+
+	silence := schedule.Silence(s.now())
+	if err := s.authz.AuthorizeReadSilence(ctx, user, &silence); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CreateSilenceSchedule creates a silence schedule and the silences of its upcoming occurrences.
+// The user needs permission to create the silences of the schedule.
+func (s *SilenceScheduleService) CreateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	silence := schedule.Silence(s.now())
+	if err := s.authz.AuthorizeCreateSilence(ctx, user, &silence); err != nil {
+		return nil, err
+	}
+
+	schedule.OrgID = user.GetOrgID()
+	if schedule.CreatedBy == "" {
+		schedule.CreatedBy = user.GetLogin()
+	}
+	schedule.MaterializedUntil = time.Time{}
+	schedule.Occurrences = nil
+	created, err := s.store.InsertSilenceSchedule(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	return s.materializeOrLog(ctx, created), nil
+}
+
+// UpdateSilenceSchedule updates a silence schedule. The silences of the occurrences created with the previous version of
+// the schedule are expired, and created again. The user needs permission to update the silences of the schedule before
+// and after the update.
+func (s *SilenceScheduleService) UpdateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.GetSilenceSchedule(ctx, user.GetOrgID(), schedule.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingSilence := existing.Silence(s.now())
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, &existingSilence); err != nil {
+		return nil, err
+	}
+	silence := schedule.Silence(s.now())
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, &silence); err != nil {
+		return nil, err
+	}
+
+	update := *existing
+	update.Matchers = schedule.Matchers
+	update.Comment = schedule.Comment
+	if schedule.CreatedBy != "" {
+		update.CreatedBy = schedule.CreatedBy
+	}
+	update.Schedule = schedule.Schedule
+	update.Timezone = schedule.Timezone
+	update.Duration = schedule.Duration
+	update.MaterializedUntil = time.Time{}
+	update.Occurrences = nil
+	updated, err := s.store.UpdateSilenceSchedule(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+
+	s.expire(ctx, existing.OrgID, existing.Occurrences)
+	return s.materializeOrLog(ctx, updated), nil
+}
+
+// DeleteSilenceSchedule deletes a silence schedule and expires the silences of its occurrences.
+// The user needs permission to update the silences of the schedule.
+func (s *SilenceScheduleService) DeleteSilenceSchedule(ctx context.Context, user identity.Requester, uid string) error {
+	existing, err := s.store.GetSilenceSchedule(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return err
+	}
+
+	silence := existing.Silence(s.now())
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, &silence); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteSilenceSchedule(ctx, existing.OrgID, existing.UID); err != nil {
+		return err
+	}
+	s.expire(ctx, existing.OrgID, existing.Occurrences)
+	return nil
+}
+
+// Run creates the upcoming occurrences of all silence schedules as silences at a regular interval, until the context
+// is cancelled.
+func (s *SilenceScheduleService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(silenceScheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.materializeAll(ctx)
+		}
+	}
+}
+
+func (s *SilenceScheduleService) materializeAll(ctx context.Context) {
+	schedules, err := s.store.ListAllSilenceSchedules(ctx)
+	if err != nil {
+		s.log.Error("Failed to list silence schedules", "error", err)
+		return
+	}
+	for _, schedule := range schedules {
+		if _, err := s.materialize(ctx, schedule); err != nil {
+			// The schedule was changed in the meantime, by a user or by another instance. It is handled on the next run.
+			if errors.Is(err, models.ErrSilenceScheduleVersionConflict) {
+				continue
+			}
+			s.log.Error("Failed to create the silences of a silence schedule", "org", schedule.OrgID, "uid", schedule.UID, "error", err)
+		}
+	}
+}
+
+// materializeOrLog materializes the schedule, and logs the error if it fails. The silences are then created by the
+// next run of the background job.
+func (s *SilenceScheduleService) materializeOrLog(ctx context.Context, schedule *models.SilenceSchedule) *models.SilenceSchedule {
+	result, err := s.materialize(ctx, schedule)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to create the silences of the silence schedule, they will be created later", "uid", schedule.UID, "error", err)
+		return schedule
+	}
+	return result
+}
+
+// materialize creates the silences of the occurrences of the schedule that start within the lookahead, and forgets
+// the occurrences that are over. It returns the schedule with its new state.
+func (s *SilenceScheduleService) materialize(ctx context.Context, schedule *models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	now := s.now()
+
+	occurrences := make([]models.SilenceScheduleOccurrence, 0, len(schedule.Occurrences))
+	for _, o := range schedule.Occurrences {
+		if o.EndsAt.After(now) {
+			occurrences = append(occurrences, o)
+		}
+	}
+
+	// An occurrence that started less than the duration ago is still in progress, so it is created too.
+	from := now.Add(-schedule.Duration)
+	if schedule.MaterializedUntil.After(from) {
+		from = schedule.MaterializedUntil
+	}
+	starts, err := schedule.OccurrencesBetween(from, now.Add(silenceScheduleLookahead))
+	if err != nil {
+		return nil, err
+	}
+	if len(starts) == 0 && len(occurrences) == len(schedule.Occurrences) {
+		return schedule, nil
+	}
+
+	update := *schedule
+	created := make([]models.SilenceScheduleOccurrence, 0, len(starts))
+	for _, start := range starts {
+		silenceID, err := s.silences.CreateSilence(ctx, schedule.OrgID, schedule.Silence(start))
+		if err != nil {
+			s.expire(ctx, schedule.OrgID, created)
+			return nil, fmt.Errorf("failed to create the silence of the occurrence at %s: %w", start, err)
+		}
+		created = append(created, models.SilenceScheduleOccurrence{
+			SilenceID: silenceID,
+			StartsAt:  start,
+			EndsAt:    start.Add(schedule.Duration),
+		})
+		update.MaterializedUntil = start
+	}
+	update.Occurrences = append(occurrences, created...)
+
+	result, err := s.store.UpdateSilenceSchedule(ctx, update)
+	if err != nil {
+		// The silences of the schedule were created concurrently by another instance, or the schedule was changed.
+		// In both cases, the silences created here must not be kept.
+		s.expire(ctx, schedule.OrgID, created)
+		return nil, err
+	}
+	return result, nil
+}
+
+// expire expires the silences of the occurrences that are not over.
+func (s *SilenceScheduleService) expire(ctx context.Context, orgID int64, occurrences []models.SilenceScheduleOccurrence) {
+	now := s.now()
+	for _, o := range occurrences {
+		if !o.EndsAt.After(now) {
+			continue
+		}
+		if err := s.silences.DeleteSilence(ctx, orgID, o.SilenceID); err != nil && !errors.Is(err, ErrSilenceNotFound) {
+			s.log.FromContext(ctx).Error("Failed to expire the silence of a silence schedule", "org", orgID, "silenceID", o.SilenceID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSilenceScheduleService_Materialize(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+
+	t.Run("creates the silences of the occurrences within the lookahead", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+		schedule := store.add(hourlySilenceSchedule())
+
+		result, err := svc.materialize(context.Background(), schedule)
+		require.NoError(t, err)
+
+		// The occurrence that started at 10:00 is still in progress, and the last one starts 24h after now.
+		require.Len(t, result.Occurrences, 25)
+		require.Len(t, silences.Silences, 25)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), result.Occurrences[0].StartsAt)
+		assert.Equal(t, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), result.MaterializedUntil)
+		for _, o := range result.Occurrences {
+			silence := silences.Silences[o.SilenceID]
+			require.NotNil(t, silence)
+			assert.Equal(t, o.StartsAt, time.Time(*silence.StartsAt))
+			assert.Equal(t, o.EndsAt, time.Time(*silence.EndsAt))
+			assert.Equal(t, schedule.Matchers, silence.Matchers)
+		}
+	})
+
+	t.Run("does not create the same occurrence twice", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+		schedule := store.add(hourlySilenceSchedule())
+
+		result, err := svc.materialize(context.Background(), schedule)
+		require.NoError(t, err)
+		result, err = svc.materialize(context.Background(), result)
+		require.NoError(t, err)
+		require.Len(t, result.Occurrences, 25)
+		require.Len(t, silences.Silences, 25)
+
+		svc.now = func() time.Time { return now.Add(time.Hour) }
+		result, err = svc.materialize(context.Background(), result)
+		require.NoError(t, err)
+		// The occurrence at 10:00 is over, the occurrence at 11:00 of the next day is created.
+		require.Len(t, result.Occurrences, 25)
+		require.Len(t, silences.Silences, 26)
+		assert.Equal(t, time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC), result.Occurrences[0].StartsAt)
+		assert.Equal(t, time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC), result.MaterializedUntil)
+	})
+
+	t.Run("creates at most MaxSilenceScheduleOccurrences silences at once", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+		everyMinute := hourlySilenceSchedule()
+		everyMinute.Schedule = "* * * * *"
+		everyMinute.Duration = time.Minute
+		schedule := store.add(everyMinute)
+
+		result, err := svc.materialize(context.Background(), schedule)
+		require.NoError(t, err)
+		require.Len(t, result.Occurrences, models.MaxSilenceScheduleOccurrences)
+		require.Len(t, silences.Silences, models.MaxSilenceScheduleOccurrences)
+		last := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC).Add((models.MaxSilenceScheduleOccurrences - 1) * time.Minute)
+		assert.Equal(t, last, result.MaterializedUntil)
+
+		// The next run continues from the last occurrence.
+		result, err = svc.materialize(context.Background(), result)
+		require.NoError(t, err)
+		require.Len(t, result.Occurrences, 2*models.MaxSilenceScheduleOccurrences)
+		assert.Equal(t, last.Add(models.MaxSilenceScheduleOccurrences*time.Minute), result.MaterializedUntil)
+	})
+
+	t.Run("expires the created silences if the schedule was changed concurrently", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+		schedule := store.add(hourlySilenceSchedule())
+		stale := *schedule
+		stale.Version--
+
+		_, err := svc.materialize(context.Background(), &stale)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleVersionConflict)
+		assert.Empty(t, silences.Silences)
+		assert.Empty(t, store.schedules[schedule.UID].Occurrences)
+	})
+}
+
+func TestSilenceScheduleService_Create(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	user := ac.BackgroundUser("test", 1, org.RoleNone, nil)
+
+	t.Run("stores the schedule and creates its silences", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+
+		created, err := svc.CreateSilenceSchedule(context.Background(), user, hourlySilenceSchedule())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), created.OrgID)
+		assert.Len(t, created.Occurrences, 25)
+		assert.Len(t, silences.Silences, 25)
+		assert.Equal(t, created, store.schedules[created.UID])
+	})
+
+	t.Run("rejects an invalid schedule", func(t *testing.T) {
+		svc, store, _ := newTestSilenceScheduleService(now)
+		schedule := hourlySilenceSchedule()
+		schedule.Schedule = "every hour"
+
+		_, err := svc.CreateSilenceSchedule(context.Background(), user, schedule)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleInvalid)
+		assert.Empty(t, store.schedules)
+	})
+
+	t.Run("rejects a schedule with occurrences too close together", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+		schedule := hourlySilenceSchedule()
+		schedule.Schedule = "@every 1s"
+
+		_, err := svc.CreateSilenceSchedule(context.Background(), user, schedule)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleInvalid)
+		assert.Empty(t, store.schedules)
+		assert.Empty(t, silences.Silences)
+	})
+
+	t.Run("requires permission to create the silences", func(t *testing.T) {
+		svc, store, silences := newTestSilenceScheduleService(now)
+		expectedErr := errors.New("unauthorized")
+		svc.authz.(*fakes.FakeSilenceService).AuthorizeCreateSilenceFunc = func(ctx context.Context, user identity.Requester, silence *models.Silence) error {
+			return expectedErr
+		}
+
+		_, err := svc.CreateSilenceSchedule(context.Background(), user, hourlySilenceSchedule())
+		require.ErrorIs(t, err, expectedErr)
+		assert.Empty(t, store.schedules)
+		assert.Empty(t, silences.Silences)
+	})
+}
+
+func TestSilenceScheduleService_Update(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	user := ac.BackgroundUser("test", 1, org.RoleNone, nil)
+
+	svc, _, silences := newTestSilenceScheduleService(now)
+	created, err := svc.CreateSilenceSchedule(context.Background(), user, hourlySilenceSchedule())
+	require.NoError(t, err)
+	previous := created.Occurrences
+
+	update := hourlySilenceSchedule()
+	update.UID = created.UID
+	update.Schedule = "0 22 * * *"
+	updated, err := svc.UpdateSilenceSchedule(context.Background(), user, update)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0 22 * * *", updated.Schedule)
+	require.Len(t, updated.Occurrences, 1)
+	assert.Equal(t, time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC), updated.Occurrences[0].StartsAt)
+	for _, o := range previous {
+		assert.NotContains(t, silences.Silences, o.SilenceID)
+	}
+	assert.Contains(t, silences.Silences, updated.Occurrences[0].SilenceID)
+}
+
+func TestSilenceScheduleService_Delete(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	user := ac.BackgroundUser("test", 1, org.RoleNone, nil)
+
+	svc, store, silences := newTestSilenceScheduleService(now)
+	created, err := svc.CreateSilenceSchedule(context.Background(), user, hourlySilenceSchedule())
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteSilenceSchedule(context.Background(), user, created.UID))
+	assert.Empty(t, store.schedules)
+	assert.Empty(t, silences.Silences)
+
+	err = svc.DeleteSilenceSchedule(context.Background(), user, created.UID)
+	require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+}
+
+func TestSilenceScheduleService_List(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	user := ac.BackgroundUser("test", 1, org.RoleNone, nil)
+
+	svc, store, _ := newTestSilenceScheduleService(now)
+	allowed := store.add(hourlySilenceSchedule())
+	denied := hourlySilenceSchedule()
+	denied.Comment = "denied"
+	store.add(denied)
+
+	svc.authz.(*fakes.FakeSilenceService).FilterByAccessFunc = func(ctx context.Context, user identity.Requester, silences ...*models.Silence) ([]*models.Silence, error) {
+		result := make([]*models.Silence, 0, len(silences))
+		for _, s := range silences {
+			if *s.Comment != "denied" {
+				result = append(result, s)
+			}
+		}
+		return result, nil
+	}
+
+	result, err := svc.ListSilenceSchedules(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.SilenceSchedule{allowed}, result)
+}
+
+func newTestSilenceScheduleService(now time.Time) (*SilenceScheduleService, *fakeSilenceScheduleStore, *ngfakes.FakeSilenceStore) {
+	store := &fakeSilenceScheduleStore{schedules: map[string]*models.SilenceSchedule{}}
+	silences := &ngfakes.FakeSilenceStore{Silences: map[string]*models.Silence{}}
+	svc := NewSilenceScheduleService(&fakes.FakeSilenceService{}, store, silences, log.NewNopLogger())
+	svc.now = func() time.Time { return now }
+	return svc, store, silences
+}
+
+func hourlySilenceSchedule() models.SilenceSchedule {
+	return models.SilenceSchedule{
+		Matchers: amv2.Matchers{{
+			Name:    util.Pointer("team"),
+			Value:   util.Pointer("ops"),
+			IsEqual: util.Pointer(true),
+			IsRegex: util.Pointer(false),
+		}},
+		Comment:   "maintenance",
+		CreatedBy: "test",
+		Schedule:  "0 * * * *",
+		Duration:  30 * time.Minute,
+	}
+}
+
+type fakeSilenceScheduleStore struct {
+	schedules map[string]*models.SilenceSchedule
+}
+
+func (f *fakeSilenceScheduleStore) add(s models.SilenceSchedule) *models.SilenceSchedule {
+	s.OrgID = 1
+	s.UID = util.GenerateShortUID()
+	s.Version = 1
+	f.schedules[s.UID] = &s
+	return &s
+}
+
+func (f *fakeSilenceScheduleStore) ListSilenceSchedules(_ context.Context, orgID int64) ([]*models.SilenceSchedule, error) {
+	result := make([]*models.SilenceSchedule, 0, len(f.schedules))
+	for _, s := range f.schedules {
+		if s.OrgID == orgID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceScheduleStore) ListAllSilenceSchedules(_ context.Context) ([]*models.SilenceSchedule, error) {
+	result := make([]*models.SilenceSchedule, 0, len(f.schedules))
+	for _, s := range f.schedules {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceScheduleStore) GetSilenceSchedule(_ context.Context, orgID int64, uid string) (*models.SilenceSchedule, error) {
+	s, ok := f.schedules[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrSilenceScheduleNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeSilenceScheduleStore) InsertSilenceSchedule(_ context.Context, s models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	s.UID = util.GenerateShortUID()
+	s.Version = 1
+	f.schedules[s.UID] = &s
+	return &s, nil
+}
+
+func (f *fakeSilenceScheduleStore) UpdateSilenceSchedule(_ context.Context, s models.SilenceSchedule) (*models.SilenceSchedule, error) {
+	existing, ok := f.schedules[s.UID]
+	if !ok || existing.OrgID != s.OrgID || existing.Version != s.Version {
+		return nil, models.ErrSilenceScheduleVersionConflict
+	}
+	s.Version++
+	f.schedules[s.UID] = &s
+	return &s, nil
+}
+
+func (f *fakeSilenceScheduleStore) DeleteSilenceSchedule(_ context.Context, orgID int64, uid string) error {
+	if s, ok := f.schedules[uid]; ok && s.OrgID == orgID {
+		delete(f.schedules, uid)
+	}
+	return nil
+}
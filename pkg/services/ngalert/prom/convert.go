@@ -0,0 +1,227 @@
+// Package prom converts Prometheus alerting and recording rules to Grafana-managed alert rules.
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// QueryRefID is the RefID of the data source query of converted rules.
+	QueryRefID = "query"
+	// MathRefID is the RefID of the expression that turns every series returned by the query into a 1.
+	MathRefID = "prometheus_math"
+	// ThresholdRefID is the RefID of the condition of converted alerting rules.
+	ThresholdRefID = "threshold"
+
+	defaultDatasourceType = "prometheus"
+	defaultFromTimeRange  = 10 * time.Minute
+)
+
+// valueRegex matches $value in templates, which Prometheus sets to the value of the alert.
+// Grafana sets $value to a description of all queries, the value of the query is in $values.
+var valueRegex = regexp.MustCompile(`\$value\b`)
+
+// Config of a Converter.
+type Config struct {
+	// DatasourceUID is the UID of the Prometheus-compatible data source queried by the rules.
+	DatasourceUID string
+	// DatasourceType is the type of the data source, defaults to prometheus.
+	DatasourceType string
+	// DefaultInterval is the evaluation interval of groups that do not set one.
+	DefaultInterval time.Duration
+	// FromTimeRange is how far back the queries look, defaults to 10 minutes.
+	FromTimeRange time.Duration
+	// NoDataState of converted alerting rules, defaults to OK because Prometheus does not alert when a query returns nothing.
+	NoDataState models.NoDataState
+	// ExecErrState of converted alerting rules, defaults to Error.
+	ExecErrState models.ExecutionErrorState
+}
+
+// Converter converts Prometheus rule groups to Grafana alert rules.
+type Converter struct {
+	cfg Config
+}
+
+func NewConverter(cfg Config) (*Converter, error) {
+	if cfg.DatasourceUID == "" {
+		return nil, errors.New("data source UID is required")
+	}
+	if cfg.DefaultInterval <= 0 {
+		return nil, errors.New("default interval must be positive")
+	}
+	if cfg.DatasourceType == "" {
+		cfg.DatasourceType = defaultDatasourceType
+	}
+	if cfg.FromTimeRange <= 0 {
+		cfg.FromTimeRange = defaultFromTimeRange
+	}
+	if cfg.NoDataState == "" {
+		cfg.NoDataState = models.OK
+	}
+	if cfg.ExecErrState == "" {
+		cfg.ExecErrState = models.ErrorErrState
+	}
+	return &Converter{cfg: cfg}, nil
+}
+
+// ConvertGroup converts the rules of a Prometheus rule group to Grafana alert rules of the organization.
+// The namespace and UIDs of the rules are left empty for the caller to set.
+func (c *Converter) ConvertGroup(orgID int64, group apimodels.PrometheusRuleGroup) ([]models.AlertRule, error) {
+	if group.Name == "" {
+		return nil, errors.New("rule group name cannot be empty")
+	}
+	interval := time.Duration(group.Interval)
+	if interval == 0 {
+		interval = c.cfg.DefaultInterval
+	}
+	if interval < 0 {
+		return nil, fmt.Errorf("rule group %q: interval must be positive", group.Name)
+	}
+
+	rules := make([]models.AlertRule, 0, len(group.Rules))
+	for i, r := range group.Rules {
+		rule, err := c.convertRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule group %q: rule [%d]: %w", group.Name, i, err)
+		}
+		rule.OrgID = orgID
+		rule.RuleGroup = group.Name
+		rule.RuleGroupIndex = i + 1
+		rule.IntervalSeconds = int64(interval.Seconds())
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (c *Converter) convertRule(r apimodels.ApiRuleNode) (models.AlertRule, error) {
+	switch {
+	case r.Alert != "" && r.Record != "":
+		return models.AlertRule{}, errors.New("rule cannot be both an alerting and a recording rule")
+	case r.Alert == "" && r.Record == "":
+		return models.AlertRule{}, errors.New("rule must have either alert or record set")
+	case r.Expr == "":
+		return models.AlertRule{}, errors.New("expr cannot be empty")
+	}
+	if _, err := parser.ParseExpr(r.Expr); err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid expr: %w", err)
+	}
+	if r.Record != "" && r.KeepFiringFor != nil && *r.KeepFiringFor != 0 {
+		return models.AlertRule{}, errors.New("keep_firing_for cannot be set for recording rules")
+	}
+
+	query, err := c.query(r.Expr)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	if r.Record != "" {
+		return models.AlertRule{
+			Title:  r.Record,
+			Data:   []models.AlertQuery{query},
+			Labels: r.Labels,
+			Record: &models.Record{
+				Metric: r.Record,
+				From:   QueryRefID,
+			},
+		}, nil
+	}
+
+	expressions, err := alertingExpressions()
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	rule := models.AlertRule{
+		Title:        r.Alert,
+		Condition:    ThresholdRefID,
+		Data:         append([]models.AlertQuery{query}, expressions...),
+		NoDataState:  c.cfg.NoDataState,
+		ExecErrState: c.cfg.ExecErrState,
+		Labels:       r.Labels,
+		Annotations:  convertTemplates(r.Annotations),
+	}
+	if r.For != nil {
+		rule.For = time.Duration(*r.For)
+	}
+	if r.KeepFiringFor != nil {
+		rule.KeepFiringFor = time.Duration(*r.KeepFiringFor)
+	}
+	return rule, nil
+}
+
+func (c *Converter) query(promQL string) (models.AlertQuery, error) {
+	model, err := json.Marshal(map[string]any{
+		"refId":   QueryRefID,
+		"expr":    promQL,
+		"instant": true,
+		"range":   false,
+		"datasource": map[string]string{
+			"type": c.cfg.DatasourceType,
+			"uid":  c.cfg.DatasourceUID,
+		},
+	})
+	if err != nil {
+		return models.AlertQuery{}, err
+	}
+	return models.AlertQuery{
+		RefID:             QueryRefID,
+		DatasourceUID:     c.cfg.DatasourceUID,
+		RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(c.cfg.FromTimeRange)},
+		Model:             model,
+	}, nil
+}
+
+// alertingExpressions returns the expressions that make every series returned by the query fire,
+// which is how Prometheus evaluates alerting rules.
+func alertingExpressions() ([]models.AlertQuery, error) {
+	datasource := map[string]string{"type": expr.DatasourceType, "uid": expr.DatasourceUID}
+	math, err := json.Marshal(map[string]any{
+		"refId":      MathRefID,
+		"type":       "math",
+		"expression": fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", QueryRefID),
+		"datasource": datasource,
+	})
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := json.Marshal(map[string]any{
+		"refId":      ThresholdRefID,
+		"type":       "threshold",
+		"expression": MathRefID,
+		"conditions": []any{
+			map[string]any{
+				"evaluator": map[string]any{"type": "gt", "params": []float64{0}},
+			},
+		},
+		"datasource": datasource,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []models.AlertQuery{
+		{RefID: MathRefID, DatasourceUID: expr.DatasourceUID, Model: math},
+		{RefID: ThresholdRefID, DatasourceUID: expr.DatasourceUID, Model: threshold},
+	}, nil
+}
+
+// convertTemplates replaces $value in the templates with the value of the query.
+func convertTemplates(templates map[string]string) map[string]string {
+	if templates == nil {
+		return nil
+	}
+	result := make(map[string]string, len(templates))
+	for k, v := range templates {
+		result[k] = valueRegex.ReplaceAllString(v, fmt.Sprintf("$$values.%s.Value", QueryRefID))
+	}
+	return result
+}
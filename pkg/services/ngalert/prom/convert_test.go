@@ -0,0 +1,162 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const rulesFile = `
+groups:
+  - name: node
+    interval: 30s
+    rules:
+      - alert: HighLoad
+        expr: node_load1 > 4
+        for: 5m
+        keep_firing_for: 10m
+        labels:
+          severity: warning
+        annotations:
+          summary: "Load of {{ $labels.instance }} is {{ $value }}"
+          description: "{{ $values }} are left alone"
+      - record: instance:node_load1:avg
+        expr: avg by (instance) (node_load1)
+        labels:
+          source: prometheus
+`
+
+func TestConvertGroup(t *testing.T) {
+	file := apimodels.PrometheusRulesFile{}
+	require.NoError(t, yaml.Unmarshal([]byte(rulesFile), &file))
+	require.Len(t, file.Groups, 1)
+
+	c, err := NewConverter(Config{DatasourceUID: "prom-uid", DefaultInterval: time.Minute})
+	require.NoError(t, err)
+
+	rules, err := c.ConvertGroup(1, file.Groups[0])
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	t.Run("alerting rule", func(t *testing.T) {
+		alert := rules[0]
+		require.Equal(t, "HighLoad", alert.Title)
+		require.Equal(t, int64(1), alert.OrgID)
+		require.Equal(t, "node", alert.RuleGroup)
+		require.Equal(t, 1, alert.RuleGroupIndex)
+		require.Equal(t, int64(30), alert.IntervalSeconds)
+		require.Equal(t, 5*time.Minute, alert.For)
+		require.Equal(t, 10*time.Minute, alert.KeepFiringFor)
+		require.Equal(t, ThresholdRefID, alert.Condition)
+		require.Equal(t, models.OK, alert.NoDataState)
+		require.Equal(t, models.ErrorErrState, alert.ExecErrState)
+		require.Equal(t, map[string]string{"severity": "warning"}, alert.Labels)
+		require.Equal(t, map[string]string{
+			"summary":     "Load of {{ $labels.instance }} is {{ $values.query.Value }}",
+			"description": "{{ $values }} are left alone",
+		}, alert.Annotations)
+		require.Nil(t, alert.Record)
+
+		require.Len(t, alert.Data, 3)
+		query := alert.Data[0]
+		require.Equal(t, QueryRefID, query.RefID)
+		require.Equal(t, "prom-uid", query.DatasourceUID)
+		require.Equal(t, models.Duration(10*time.Minute), query.RelativeTimeRange.From)
+		model := map[string]any{}
+		require.NoError(t, json.Unmarshal(query.Model, &model))
+		require.Equal(t, "node_load1 > 4", model["expr"])
+		require.Equal(t, true, model["instant"])
+		require.Equal(t, map[string]any{"type": "prometheus", "uid": "prom-uid"}, model["datasource"])
+
+		require.Equal(t, MathRefID, alert.Data[1].RefID)
+		require.Equal(t, expr.DatasourceUID, alert.Data[1].DatasourceUID)
+		require.Equal(t, ThresholdRefID, alert.Data[2].RefID)
+		require.Equal(t, expr.DatasourceUID, alert.Data[2].DatasourceUID)
+	})
+
+	t.Run("recording rule", func(t *testing.T) {
+		record := rules[1]
+		require.Equal(t, "instance:node_load1:avg", record.Title)
+		require.Equal(t, 2, record.RuleGroupIndex)
+		require.Equal(t, &models.Record{Metric: "instance:node_load1:avg", From: QueryRefID}, record.Record)
+		require.Equal(t, map[string]string{"source": "prometheus"}, record.Labels)
+		require.Empty(t, record.Condition)
+		require.Len(t, record.Data, 1)
+	})
+
+	t.Run("default interval", func(t *testing.T) {
+		group := file.Groups[0]
+		group.Interval = 0
+		rules, err := c.ConvertGroup(1, group)
+		require.NoError(t, err)
+		require.Equal(t, int64(60), rules[0].IntervalSeconds)
+	})
+}
+
+func TestConvertGroupErrors(t *testing.T) {
+	c, err := NewConverter(Config{DatasourceUID: "prom-uid", DefaultInterval: time.Minute})
+	require.NoError(t, err)
+
+	keepFiringFor := prommodel.Duration(time.Minute)
+	cases := []struct {
+		name          string
+		group         apimodels.PrometheusRuleGroup
+		expectedError string
+	}{
+		{
+			name:          "empty name",
+			group:         apimodels.PrometheusRuleGroup{},
+			expectedError: "rule group name cannot be empty",
+		},
+		{
+			name: "alert and record",
+			group: apimodels.PrometheusRuleGroup{Name: "g", Rules: []apimodels.ApiRuleNode{
+				{Alert: "a", Record: "r", Expr: "up"},
+			}},
+			expectedError: "rule cannot be both an alerting and a recording rule",
+		},
+		{
+			name: "neither alert nor record",
+			group: apimodels.PrometheusRuleGroup{Name: "g", Rules: []apimodels.ApiRuleNode{
+				{Expr: "up"},
+			}},
+			expectedError: "rule must have either alert or record set",
+		},
+		{
+			name: "invalid expr",
+			group: apimodels.PrometheusRuleGroup{Name: "g", Rules: []apimodels.ApiRuleNode{
+				{Alert: "a", Expr: "sum(up"},
+			}},
+			expectedError: `rule group "g": rule [0]: invalid expr`,
+		},
+		{
+			name: "keep_firing_for",
+			group: apimodels.PrometheusRuleGroup{Name: "g", Rules: []apimodels.ApiRuleNode{
+				{Alert: "a", Expr: "up == 0", KeepFiringFor: &keepFiringFor},
+			}},
+			expectedError: "keep_firing_for is not supported",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := c.ConvertGroup(1, tc.group)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestNewConverter(t *testing.T) {
+	_, err := NewConverter(Config{DefaultInterval: time.Minute})
+	require.ErrorContains(t, err, "data source UID is required")
+
+	_, err = NewConverter(Config{DatasourceUID: "prom-uid"})
+	require.ErrorContains(t, err, "default interval must be positive")
+}
@@ -220,6 +220,7 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 		return models.AlertRule{}, err
 	}
 	rule.Updated = time.Now()
+	rule.UpdatedBy = updatedBy(user, provenance)
 	if len(rule.NotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
 		if err != nil {
@@ -300,7 +301,7 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, user identity
 }
 
 // UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user identity.Requester, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user identity.Requester, namespaceUID string, ruleGroup string, intervalSeconds int64, provenance models.Provenance) error {
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
@@ -321,6 +322,7 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 			}
 			newRule := *rule
 			newRule.IntervalSeconds = intervalSeconds
+			newRule.UpdatedBy = updatedBy(user, provenance)
 			updateRules = append(updateRules, models.UpdateRule{
 				Existing: rule,
 				New:      newRule,
@@ -477,6 +479,15 @@ func (service *AlertRuleService) calcDelta(ctx context.Context, user identity.Re
 }
 
 func (service *AlertRuleService) persistDelta(ctx context.Context, user identity.Requester, delta *store.GroupDelta, provenance models.Provenance) error {
+	author := updatedBy(user, provenance)
+	for _, rule := range delta.New {
+		if rule != nil {
+			rule.UpdatedBy = author
+		}
+	}
+	for _, update := range delta.Update {
+		update.New.UpdatedBy = author
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		// Delete first as this could prevent future unique constraint violations.
 		if len(delta.Delete) > 0 {
@@ -599,6 +610,7 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 		}
 	}
 	rule.Updated = time.Now()
+	rule.UpdatedBy = updatedBy(user, provenance)
 	rule.ID = storedRule.ID
 	rule.IntervalSeconds = storedRule.IntervalSeconds
 
@@ -867,3 +879,12 @@ func (service *AlertRuleService) ensureNamespace(ctx context.Context, user ident
 
 	return nil
 }
+
+// updatedBy returns the author of the changes that is recorded in the versions of the rules.
+func updatedBy(user identity.Requester, provenance models.Provenance) *models.UserUID {
+	if provenance == models.ProvenanceFile {
+		uid := models.FileProvisioningUserUID
+		return &uid
+	}
+	return models.NewUserUID(user)
+}
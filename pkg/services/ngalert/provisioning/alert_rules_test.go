@@ -68,7 +68,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), u, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), u, rule.NamespaceUID, rule.RuleGroup, 120, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), u, rule.UID)
@@ -125,7 +125,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.NoError(t, err)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), u, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), u, rule.NamespaceUID, rule.RuleGroup, 120, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		rule = dummyRule("test#4-1", orgID)
@@ -156,7 +156,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(1), rule.Version)
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
-		err = ruleService.UpdateRuleGroup(context.Background(), u, namespaceUID, ruleGroup, newInterval)
+		err = ruleService.UpdateRuleGroup(context.Background(), u, namespaceUID, ruleGroup, newInterval, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), u, ruleUID)
@@ -676,6 +676,17 @@ func TestCreateAlertRule(t *testing.T) {
 				require.NoError(t, err)
 				require.Equal(t, models.ProvenanceFile, p)
 			})
+
+			t.Run("records file provisioning as the author", func(t *testing.T) {
+				inserts := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+					a, ok := cmd.([]models.AlertRule)
+					return a, ok
+				})
+				require.Len(t, inserts, 1)
+				cmd := inserts[0].([]models.AlertRule)
+				require.NotNil(t, cmd[0].UpdatedBy)
+				require.Equal(t, models.FileProvisioningUserUID, *cmd[0].UpdatedBy)
+			})
 		})
 		t.Run("and it adds a rule to a group", func(t *testing.T) {
 			rule := gen.With(gen.WithGroupKey(groupKey)).Generate()
@@ -911,6 +922,28 @@ func TestUpdateAlertRule(t *testing.T) {
 		})
 		require.Len(t, updates, 1)
 	})
+	t.Run("should record the user as the author", func(t *testing.T) {
+		rule := models.CopyRule(rules[0])
+		rule.Title = rule.Title + "_new"
+		service, ruleStore, _, ac := initServiceWithData(t)
+
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+
+		author := &user.SignedInUser{OrgID: orgID, UserUID: "author-uid"}
+		_, err := service.UpdateAlertRule(context.Background(), author, *rule, groupProvenance)
+		require.NoError(t, err)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			a, ok := cmd.([]models.UpdateRule)
+			return a, ok
+		})
+		require.Len(t, updates, 1)
+		cmd := updates[0].([]models.UpdateRule)
+		require.NotNil(t, cmd[0].New.UpdatedBy)
+		require.Equal(t, models.UserUID("author-uid"), *cmd[0].New.UpdatedBy)
+	})
 	t.Run("when user cannot write all rules", func(t *testing.T) {
 		rule := models.CopyRule(rules[0])
 		rule.Title = rule.Title + "_new"
@@ -7,8 +7,11 @@ import (
 	"hash"
 	"hash/fnv"
 	"slices"
+	"time"
 	"unsafe"
 
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"golang.org/x/exp/maps"
 
@@ -20,6 +23,9 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// AutogenFn adds the policies autogenerated from the notification settings of the alert rules to the configuration.
+type AutogenFn func(ctx context.Context, logger log.Logger, orgID int64, cfg *definitions.PostableApiAlertingConfig, skipInvalid bool) error
+
 type NotificationPolicyService struct {
 	configStore     alertmanagerConfigStore
 	provenanceStore ProvisioningStore
@@ -27,10 +33,12 @@ type NotificationPolicyService struct {
 	log             log.Logger
 	settings        setting.UnifiedAlertingSettings
 	validator       validation.ProvenanceStatusTransitionValidator
+	// autogen is nil if simplified routing is disabled.
+	autogen AutogenFn
 }
 
 func NewNotificationPolicyService(am alertmanagerConfigStore, prov ProvisioningStore,
-	xact TransactionManager, settings setting.UnifiedAlertingSettings, log log.Logger) *NotificationPolicyService {
+	xact TransactionManager, settings setting.UnifiedAlertingSettings, log log.Logger, autogen AutogenFn) *NotificationPolicyService {
 	return &NotificationPolicyService{
 		configStore:     am,
 		provenanceStore: prov,
@@ -38,6 +46,7 @@ func NewNotificationPolicyService(am alertmanagerConfigStore, prov ProvisioningS
 		log:             log,
 		settings:        settings,
 		validator:       validation.ValidateProvenanceRelaxed,
+		autogen:         autogen,
 	}
 }
 
@@ -86,29 +95,11 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 		return err
 	}
 
-	receivers, err := nps.receiversToMap(revision.Config.AlertmanagerConfig.Receivers)
+	err = nps.validateReferences(revision, tree)
 	if err != nil {
 		return err
 	}
 
-	receivers[""] = struct{}{} // Allow empty receiver (inheriting from parent)
-	err = tree.ValidateReceivers(receivers)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
-	}
-
-	timeIntervals := map[string]struct{}{}
-	for _, mt := range revision.Config.AlertmanagerConfig.MuteTimeIntervals {
-		timeIntervals[mt.Name] = struct{}{}
-	}
-	for _, mt := range revision.Config.AlertmanagerConfig.TimeIntervals {
-		timeIntervals[mt.Name] = struct{}{}
-	}
-	err = tree.ValidateMuteTimes(timeIntervals)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
-	}
-
 	revision.Config.AlertmanagerConfig.Config.Route = &tree
 
 	return nps.xact.InTransaction(ctx, func(ctx context.Context) error {
@@ -159,6 +150,175 @@ func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID
 	return *route, nil
 }
 
+// SimulateRouting matches the label sets against the notification policy tree of the organization, or against the
+// draft tree if it is not nil, with the policies autogenerated for simplified routing. For every label set, it returns
+// the matching policies with the settings they inherit from their parents, and whether their time intervals are active
+// at the given time.
+func (nps *NotificationPolicyService) SimulateRouting(ctx context.Context, orgID int64, draft *definitions.Route, labelSets []model.LabelSet, at time.Time) ([]definitions.RoutingSimulationResult, error) {
+	revision, err := nps.configStore.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := revision.Config.AlertmanagerConfig.Route
+	if draft != nil {
+		if err := draft.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		}
+		if err := nps.validateReferences(revision, *draft); err != nil {
+			return nil, err
+		}
+		tree = draft
+	}
+	if tree == nil {
+		return nil, fmt.Errorf("no route present in current alertmanager config")
+	}
+
+	// The Alertmanager routes the alerts of the rules that use simplified routing with the policies autogenerated from
+	// their notification settings, so they are added to the tree the same way. Only the top level policies of the tree
+	// are changed, the tree itself is kept as is.
+	if nps.autogen != nil {
+		cfg := revision.Config.AlertmanagerConfig
+		root := *tree
+		root.Routes = slices.Clone(tree.Routes)
+		cfg.Route = &root
+		if err := nps.autogen(ctx, nps.log, orgID, &cfg, true); err != nil {
+			return nil, fmt.Errorf("failed to add autogenerated policies: %w", err)
+		}
+		tree = cfg.Route
+	}
+
+	timeIntervals := make(map[string][]timeinterval.TimeInterval)
+	for _, interval := range getTimeIntervals(revision) {
+		timeIntervals[interval.Name] = interval.TimeIntervals
+	}
+
+	// The tree is matched the same way the Alertmanager does. The routes of the dispatcher have the same structure
+	// as the tree, so their position in the tree is recorded to report it.
+	root := dispatch.NewRoute(tree.AsAMRoute(), nil)
+	paths := make(map[*dispatch.Route][]int)
+	autogenerated := make(map[*dispatch.Route]bool)
+	var walk func(r *dispatch.Route, path []int, autogen bool)
+	walk = func(r *dispatch.Route, path []int, autogen bool) {
+		paths[r] = path
+		autogenerated[r] = autogen
+		idx := 0
+		for _, child := range r.Routes {
+			// The autogenerated policies are not part of the tree, so they have no position in it.
+			if autogen || (r == root && isAutogeneratedRoute(child)) {
+				walk(child, nil, true)
+				continue
+			}
+			walk(child, append(slices.Clone(path), idx), false)
+			idx++
+		}
+	}
+	walk(root, []int{}, false)
+
+	results := make([]definitions.RoutingSimulationResult, 0, len(labelSets))
+	for _, ls := range labelSets {
+		labels := make(map[string]string, len(ls))
+		for k, v := range ls {
+			labels[string(k)] = string(v)
+		}
+		matches := root.Match(ls)
+		routes := make([]definitions.MatchedRoute, 0, len(matches))
+		for _, r := range matches {
+			route := simulatedRoute(r, paths[r], timeIntervals, at)
+			route.Autogenerated = autogenerated[r]
+			routes = append(routes, route)
+		}
+		results = append(results, definitions.RoutingSimulationResult{
+			Labels: labels,
+			Routes: routes,
+		})
+	}
+	return results, nil
+}
+
+func simulatedRoute(r *dispatch.Route, path []int, timeIntervals map[string][]timeinterval.TimeInterval, at time.Time) definitions.MatchedRoute {
+	groupBy := make([]string, 0, len(r.RouteOpts.GroupBy))
+	if r.RouteOpts.GroupByAll {
+		groupBy = append(groupBy, "...")
+	} else {
+		for l := range r.RouteOpts.GroupBy {
+			groupBy = append(groupBy, string(l))
+		}
+		slices.Sort(groupBy)
+	}
+
+	isActive := func(name string) bool {
+		for _, interval := range timeIntervals[name] {
+			if interval.ContainsTime(at) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := definitions.MatchedRoute{
+		Path:                path,
+		Key:                 r.Key(),
+		Receiver:            r.RouteOpts.Receiver,
+		GroupBy:             groupBy,
+		GroupWait:           model.Duration(r.RouteOpts.GroupWait).String(),
+		GroupInterval:       model.Duration(r.RouteOpts.GroupInterval).String(),
+		RepeatInterval:      model.Duration(r.RouteOpts.RepeatInterval).String(),
+		Continue:            r.Continue,
+		MuteTimeIntervals:   make([]definitions.TimeIntervalStatus, 0, len(r.RouteOpts.MuteTimeIntervals)),
+		ActiveTimeIntervals: make([]definitions.TimeIntervalStatus, 0, len(r.RouteOpts.ActiveTimeIntervals)),
+	}
+	for _, name := range r.RouteOpts.MuteTimeIntervals {
+		active := isActive(name)
+		// The notifications are muted if any of the mute timings is active.
+		result.Muted = result.Muted || active
+		result.MuteTimeIntervals = append(result.MuteTimeIntervals, definitions.TimeIntervalStatus{Name: name, Active: active})
+	}
+	anyActive := false
+	for _, name := range r.RouteOpts.ActiveTimeIntervals {
+		active := isActive(name)
+		anyActive = anyActive || active
+		result.ActiveTimeIntervals = append(result.ActiveTimeIntervals, definitions.TimeIntervalStatus{Name: name, Active: active})
+	}
+	// The notifications are muted outside of the active time intervals, if there are any.
+	if len(r.RouteOpts.ActiveTimeIntervals) > 0 && !anyActive {
+		result.Muted = true
+	}
+	return result
+}
+
+// isAutogeneratedRoute returns true if the route is the root of the policies autogenerated for simplified routing.
+func isAutogeneratedRoute(r *dispatch.Route) bool {
+	return len(r.Matchers) == 1 && r.Matchers[0].Name == models.AutogeneratedRouteLabel
+}
+
+// validateReferences checks that the receivers and the time intervals used by the tree exist in the revision.
+func (nps *NotificationPolicyService) validateReferences(revision *legacy_storage.ConfigRevision, tree definitions.Route) error {
+	receivers, err := nps.receiversToMap(revision.Config.AlertmanagerConfig.Receivers)
+	if err != nil {
+		return err
+	}
+
+	receivers[""] = struct{}{} // Allow empty receiver (inheriting from parent)
+	err = tree.ValidateReceivers(receivers)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	timeIntervals := map[string]struct{}{}
+	for _, mt := range revision.Config.AlertmanagerConfig.MuteTimeIntervals {
+		timeIntervals[mt.Name] = struct{}{}
+	}
+	for _, mt := range revision.Config.AlertmanagerConfig.TimeIntervals {
+		timeIntervals[mt.Name] = struct{}{}
+	}
+	err = tree.ValidateMuteTimes(timeIntervals)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	return nil
+}
+
 func (nps *NotificationPolicyService) receiversToMap(records []*definitions.PostableApiReceiver) (map[string]struct{}, error) {
 	receivers := map[string]struct{}{}
 	for _, receiver := range records {
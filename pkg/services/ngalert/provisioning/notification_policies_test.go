@@ -4,15 +4,20 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/grafana/alerting/definition"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/setting"
@@ -330,6 +335,169 @@ func TestResetPolicyTree(t *testing.T) {
 	})
 }
 
+func TestSimulateRouting(t *testing.T) {
+	orgID := int64(1)
+	now := time.Now()
+
+	mustMatcher := func(name, value string) *labels.Matcher {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		require.NoError(t, err)
+		return m
+	}
+	groupWait := model.Duration(10 * time.Second)
+	newRevision := func() legacy_storage.ConfigRevision {
+		rev := getDefaultConfigRevision()
+		rev.Config.AlertmanagerConfig.TimeIntervals = []config.TimeInterval{
+			{
+				Name:          "always",
+				TimeIntervals: []timeinterval.TimeInterval{{}},
+			},
+			{
+				Name: "never",
+				TimeIntervals: []timeinterval.TimeInterval{
+					{Years: []timeinterval.YearRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 2000, End: 2000}}}},
+				},
+			},
+		}
+		rev.Config.AlertmanagerConfig.Receivers = append(rev.Config.AlertmanagerConfig.Receivers,
+			&definitions.PostableApiReceiver{Receiver: config.Receiver{Name: "team-a"}},
+			&definitions.PostableApiReceiver{Receiver: config.Receiver{Name: "critical"}},
+		)
+		return rev
+	}
+	draft := func() *definitions.Route {
+		return &definitions.Route{
+			Receiver:   "test-receiver",
+			GroupByStr: []string{"alertname"},
+			Routes: []*definitions.Route{
+				{
+					Receiver:          "team-a",
+					ObjectMatchers:    definitions.ObjectMatchers{mustMatcher("team", "a")},
+					GroupByStr:        []string{"..."},
+					MuteTimeIntervals: []string{"never"},
+					Continue:          true,
+				},
+				{
+					ObjectMatchers: definitions.ObjectMatchers{mustMatcher("severity", "critical")},
+					GroupWait:      &groupWait,
+					Routes: []*definitions.Route{
+						{
+							Receiver:          "critical",
+							ObjectMatchers:    definitions.ObjectMatchers{mustMatcher("team", "a")},
+							MuteTimeIntervals: []string{"always", "never"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("returns the matching policies with their effective settings", func(t *testing.T) {
+		rev := newRevision()
+		sut, store, _ := createNotificationPolicyServiceSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &rev, nil
+		}
+
+		results, err := sut.SimulateRouting(context.Background(), orgID, draft(), []model.LabelSet{
+			{"alertname": "test", "team": "a", "severity": "critical"},
+			{"alertname": "test", "team": "b"},
+		}, now)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		require.Equal(t, map[string]string{"alertname": "test", "team": "a", "severity": "critical"}, results[0].Labels)
+		require.Len(t, results[0].Routes, 2)
+
+		first := results[0].Routes[0]
+		require.Equal(t, []int{0}, first.Path)
+		require.Equal(t, "team-a", first.Receiver)
+		require.Equal(t, []string{"..."}, first.GroupBy)
+		require.True(t, first.Continue)
+		require.Equal(t, []definitions.TimeIntervalStatus{{Name: "never", Active: false}}, first.MuteTimeIntervals)
+		require.False(t, first.Muted)
+
+		second := results[0].Routes[1]
+		require.Equal(t, []int{1, 0}, second.Path)
+		require.Equal(t, "critical", second.Receiver)
+		require.Equal(t, []string{"alertname"}, second.GroupBy)
+		require.Equal(t, "10s", second.GroupWait)
+		require.Equal(t, "5m", second.GroupInterval)
+		require.Equal(t, "4h", second.RepeatInterval)
+		require.Equal(t, []definitions.TimeIntervalStatus{{Name: "always", Active: true}, {Name: "never", Active: false}}, second.MuteTimeIntervals)
+		require.True(t, second.Muted)
+
+		require.Len(t, results[1].Routes, 1)
+		require.Equal(t, []int{}, results[1].Routes[0].Path)
+		require.Equal(t, "test-receiver", results[1].Routes[0].Receiver)
+		require.Equal(t, "30s", results[1].Routes[0].GroupWait)
+	})
+
+	t.Run("uses the current policy tree if there is no draft", func(t *testing.T) {
+		rev := newRevision()
+		sut, store, _ := createNotificationPolicyServiceSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &rev, nil
+		}
+
+		results, err := sut.SimulateRouting(context.Background(), orgID, nil, []model.LabelSet{{"team": "a"}}, now)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Len(t, results[0].Routes, 1)
+		require.Equal(t, "test-receiver", results[0].Routes[0].Receiver)
+	})
+
+	t.Run("matches the autogenerated policies like the Alertmanager", func(t *testing.T) {
+		rev := newRevision()
+		sut, store, _ := createNotificationPolicyServiceSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &rev, nil
+		}
+		sut.autogen = func(ctx context.Context, logger log.Logger, orgID int64, cfg *definitions.PostableApiAlertingConfig, skipInvalid bool) error {
+			return notifier.AddAutogenConfig(ctx, logger, &fakeAlertRuleNotificationStore{}, orgID, cfg, skipInvalid)
+		}
+
+		tree := draft()
+		results, err := sut.SimulateRouting(context.Background(), orgID, tree, []model.LabelSet{
+			{"team": "a", models.AutogeneratedRouteLabel: "true", models.AutogeneratedRouteReceiverNameLabel: "critical"},
+			{"team": "a"},
+		}, now)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		require.Len(t, results[0].Routes, 1)
+		require.Equal(t, "critical", results[0].Routes[0].Receiver)
+		require.True(t, results[0].Routes[0].Autogenerated)
+		require.Nil(t, results[0].Routes[0].Path)
+
+		// The autogenerated policies do not change the position of the policies of the tree.
+		require.Len(t, results[1].Routes, 1)
+		require.Equal(t, "team-a", results[1].Routes[0].Receiver)
+		require.False(t, results[1].Routes[0].Autogenerated)
+		require.Equal(t, []int{0}, results[1].Routes[0].Path)
+
+		require.Len(t, tree.Routes, 2)
+	})
+
+	t.Run("ErrValidation if the draft is invalid", func(t *testing.T) {
+		rev := newRevision()
+		sut, store, _ := createNotificationPolicyServiceSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &rev, nil
+		}
+
+		unknownReceiver := draft()
+		unknownReceiver.Routes[0].Receiver = "unknown"
+		_, err := sut.SimulateRouting(context.Background(), orgID, unknownReceiver, []model.LabelSet{{"team": "a"}}, now)
+		require.ErrorIs(t, err, ErrValidation)
+
+		unknownInterval := draft()
+		unknownInterval.Routes[0].MuteTimeIntervals = []string{"unknown"}
+		_, err = sut.SimulateRouting(context.Background(), orgID, unknownInterval, []model.LabelSet{{"team": "a"}}, now)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
 func createNotificationPolicyServiceSut() (*NotificationPolicyService, *legacy_storage.AlertmanagerConfigStoreFake, *fakes.FakeProvisioningStore) {
 	prov := fakes.NewFakeProvisioningStore()
 	configStore := &legacy_storage.AlertmanagerConfigStoreFake{
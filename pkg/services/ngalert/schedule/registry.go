@@ -317,6 +317,10 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.ID)
 	writeInt(rule.OrgID)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
+	if rule.MissingSeriesEvalsToResolve != nil {
+		writeInt(*rule.MissingSeriesEvalsToResolve)
+	}
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
 	}
@@ -24,6 +24,10 @@ var (
 	ResendDelay = 30 * time.Second
 )
 
+// defaultMissingSeriesEvalsToResolve is the number of evaluations a series can be missing from the results
+// before it is resolved, if the rule does not set one.
+const defaultMissingSeriesEvalsToResolve = 2
+
 // AlertInstanceManager defines the interface for querying the current alert instances.
 type AlertInstanceManager interface {
 	GetAll(orgID int64) []*State
@@ -503,6 +507,14 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		currentState.StateReason = resultStateReason(result, alertRule)
 	}
 
+	if currentState.KeepFiringSince != nil {
+		if currentState.StateReason == "" {
+			currentState.StateReason = ngModels.StateReasonKeepFiring
+		} else {
+			currentState.StateReason = ngModels.ConcatReasons(currentState.StateReason, ngModels.StateReasonKeepFiring)
+		}
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	newlyResolved := false
@@ -585,12 +597,34 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 }
 
 func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Logger, evaluatedAt time.Time, alertRule *ngModels.AlertRule) []StateTransition {
+	// Alerting states of stale series keep firing until keep_firing_for has passed, so they are not deleted yet.
+	var keptStates []*State
 	// If we are removing two or more stale series it makes sense to share the resolved image as the alert rule is the same.
 	// TODO: We will need to change this when we support images without screenshots as each series will have a different image
 	staleStates := st.cache.deleteRuleStates(alertRule.GetKey(), func(s *State) bool {
-		return stateIsStale(evaluatedAt, s.LastEvaluationTime, alertRule.IntervalSeconds)
+		if !stateIsStale(evaluatedAt, s.LastEvaluationTime, alertRule.IntervalSeconds, missingSeriesEvalsToResolve(alertRule)) {
+			return false
+		}
+		if s.State == eval.Alerting && staleStateKeepsFiring(s, alertRule, evaluatedAt) {
+			keptStates = append(keptStates, s)
+			return false
+		}
+		return true
 	})
-	resolvedStates := make([]StateTransition, 0, len(staleStates))
+	transitions := make([]StateTransition, 0, len(staleStates)+len(keptStates))
+
+	for _, s := range keptStates {
+		logger.Debug("Keeping stale state because of keep_firing_for", "cacheID", s.CacheID, "keep_firing_since", *s.KeepFiringSince)
+		oldReason := s.StateReason
+		// The last evaluation time is not updated so that the state is still stale at the next evaluation.
+		s.StateReason = ngModels.StateReasonKeepFiring
+		s.Maintain(alertRule.IntervalSeconds, evaluatedAt)
+		transitions = append(transitions, StateTransition{
+			State:               s,
+			PreviousState:       eval.Alerting,
+			PreviousStateReason: oldReason,
+		})
+	}
 
 	for _, s := range staleStates {
 		logger.Info("Detected stale state entry", "cacheID", s.CacheID, "state", s.State, "reason", s.StateReason)
@@ -601,6 +635,7 @@ func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Lo
 		s.StateReason = ngModels.StateReasonMissingSeries
 		s.EndsAt = evaluatedAt
 		s.LastEvaluationTime = evaluatedAt
+		s.KeepFiringSince = nil
 
 		if oldState == eval.Alerting {
 			s.ResolvedAt = &evaluatedAt
@@ -620,13 +655,35 @@ func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Lo
 			PreviousState:       oldState,
 			PreviousStateReason: oldReason,
 		}
-		resolvedStates = append(resolvedStates, record)
+		transitions = append(transitions, record)
 	}
-	return resolvedStates
+	return transitions
 }
 
-func stateIsStale(evaluatedAt time.Time, lastEval time.Time, intervalSeconds int64) bool {
-	return !lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).After(evaluatedAt)
+// staleStateKeepsFiring returns true if the Alerting state of a stale series should keep firing, and records the time
+// the series was last seen as the time the condition stopped being met.
+func staleStateKeepsFiring(s *State, rule *ngModels.AlertRule, evaluatedAt time.Time) bool {
+	if rule.KeepFiringFor <= 0 {
+		return false
+	}
+	if s.KeepFiringSince == nil {
+		since := s.LastEvaluationTime
+		s.KeepFiringSince = &since
+	}
+	return evaluatedAt.Sub(*s.KeepFiringSince) < rule.KeepFiringFor
+}
+
+// stateIsStale returns true if the series of the state has been missing from the results of the last missingEvals evaluations.
+func stateIsStale(evaluatedAt time.Time, lastEval time.Time, intervalSeconds int64, missingEvals int64) bool {
+	return !lastEval.Add(time.Duration(missingEvals) * time.Duration(intervalSeconds) * time.Second).After(evaluatedAt)
+}
+
+// missingSeriesEvalsToResolve returns the number of evaluations a series of the rule can be missing before it is resolved.
+func missingSeriesEvalsToResolve(rule *ngModels.AlertRule) int64 {
+	if rule.MissingSeriesEvalsToResolve != nil && *rule.MissingSeriesEvalsToResolve > 0 {
+		return *rule.MissingSeriesEvalsToResolve
+	}
+	return defaultMissingSeriesEvalsToResolve
 }
 
 func StatesToRuleStatus(states []*State) ngModels.RuleStatus {
@@ -75,9 +75,22 @@ func TestStateIsStale(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			require.Equal(t, tc.expectedResult, stateIsStale(now, tc.lastEvaluation, intervalSeconds))
+			require.Equal(t, tc.expectedResult, stateIsStale(now, tc.lastEvaluation, intervalSeconds, defaultMissingSeriesEvalsToResolve))
 		})
 	}
+
+	t.Run("uses the number of missing evaluations", func(t *testing.T) {
+		lastEvaluation := now.Add(-time.Duration(intervalSeconds) * time.Second * 3)
+		require.False(t, stateIsStale(now, lastEvaluation, intervalSeconds, 4))
+		require.True(t, stateIsStale(now, lastEvaluation, intervalSeconds, 3))
+		require.True(t, stateIsStale(now, lastEvaluation, intervalSeconds, 1))
+	})
+}
+
+func TestMissingSeriesEvalsToResolve(t *testing.T) {
+	gen := ngmodels.RuleGen
+	require.Equal(t, int64(defaultMissingSeriesEvalsToResolve), missingSeriesEvalsToResolve(gen.GenerateRef()))
+	require.Equal(t, int64(5), missingSeriesEvalsToResolve(gen.With(gen.WithMissingSeriesEvalsToResolve(5)).GenerateRef()))
 }
 
 // TestProcessEvalResults_StateTransitions tests how state.Manager's ProcessEvalResults processes results and creates or changes states.
@@ -1975,6 +1975,116 @@ func TestStaleResults(t *testing.T) {
 	})
 }
 
+func TestKeepFiringFor(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithIntervalSeconds(10), gen.WithKeepFiringFor(30*time.Second)).GenerateRef()
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	labels := data.Labels{"instance": "a"}
+
+	evaluate := func(s eval.State) *state.State {
+		t.Helper()
+		result := eval.ResultGen(eval.WithState(s), eval.WithEvaluatedAt(clk.Now()), eval.WithLabels(labels))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result}, nil, nil)
+		require.Len(t, processed, 1)
+		return processed[0].State
+	}
+
+	s := evaluate(eval.Alerting)
+	require.Equal(t, eval.Alerting, s.State)
+	startsAt := s.StartsAt
+
+	t.Run("should keep firing when the condition is not met", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			clk.Add(interval)
+			s = evaluate(eval.Normal)
+			require.Equal(t, eval.Alerting, s.State)
+			require.Equal(t, models.StateReasonKeepFiring, s.StateReason)
+			require.Equal(t, startsAt, s.StartsAt)
+			require.Nil(t, s.ResolvedAt)
+		}
+	})
+
+	t.Run("should stop keeping firing when the condition is met again", func(t *testing.T) {
+		clk.Add(interval)
+		s = evaluate(eval.Alerting)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Empty(t, s.StateReason)
+		require.Nil(t, s.KeepFiringSince)
+		require.Equal(t, startsAt, s.StartsAt)
+	})
+
+	t.Run("should resolve once keep_firing_for has passed", func(t *testing.T) {
+		clk.Add(interval)
+		first := clk.Now()
+		s = evaluate(eval.Normal)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, first, *s.KeepFiringSince)
+
+		clk.Add(rule.KeepFiringFor)
+		s = evaluate(eval.Normal)
+		require.Equal(t, eval.Normal, s.State)
+		require.Empty(t, s.StateReason)
+		require.Nil(t, s.KeepFiringSince)
+		require.Equal(t, clk.Now(), *s.ResolvedAt)
+	})
+
+	t.Run("should keep firing when the series is missing until keep_firing_for has passed", func(t *testing.T) {
+		missing := data.Labels{"instance": "b"}
+		evaluateOther := func() *state.State {
+			t.Helper()
+			result := eval.ResultGen(eval.WithState(eval.Normal), eval.WithEvaluatedAt(clk.Now()), eval.WithLabels(labels))()
+			processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result}, nil, nil)
+			for _, p := range processed {
+				if p.Labels["instance"] == "b" {
+					return p.State
+				}
+			}
+			return nil
+		}
+
+		clk.Add(interval)
+		lastSeen := clk.Now()
+		result := eval.ResultGen(eval.WithState(eval.Alerting), eval.WithEvaluatedAt(clk.Now()), eval.WithLabels(missing))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result}, nil, nil)
+		require.Len(t, processed, 1)
+		require.Equal(t, eval.Alerting, processed[0].State.State)
+
+		// The series is not stale yet.
+		clk.Add(interval)
+		require.Nil(t, evaluateOther())
+
+		clk.Add(interval)
+		s = evaluateOther()
+		require.NotNil(t, s)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, models.StateReasonKeepFiring, s.StateReason)
+		require.Equal(t, lastSeen, *s.KeepFiringSince)
+		require.Nil(t, s.ResolvedAt)
+
+		clk.Add(interval)
+		s = evaluateOther()
+		require.NotNil(t, s)
+		require.Equal(t, eval.Normal, s.State)
+		require.Equal(t, models.StateReasonMissingSeries, s.StateReason)
+		require.Nil(t, s.KeepFiringSince)
+		require.Equal(t, clk.Now(), *s.ResolvedAt)
+		require.Nil(t, st.Get(rule.OrgID, rule.UID, s.CacheID))
+	})
+}
+
 func TestDeleteStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
@@ -73,6 +73,11 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// KeepFiringSince is set when the condition of an Alerting state stops being met but the state keeps
+	// firing because of the keep_firing_for of the rule. It is reset when the state changes or fires again.
+	// It is not persisted, after a restart the state keeps firing for the full duration again.
+	KeepFiringSince *time.Time
 }
 
 func (a *State) GetRuleKey() models.AlertRuleKey {
@@ -98,6 +103,7 @@ func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = nil
 }
 
 // SetPending the state to Pending. It changes both the start and end time.
@@ -107,6 +113,7 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = nil
 }
 
 // SetNoData sets the state to NoData. It changes both the start and end time.
@@ -116,6 +123,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = nil
 }
 
 // SetError sets the state to Error. It changes both the start and end time.
@@ -125,6 +133,7 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = err
+	a.KeepFiringSince = nil
 }
 
 // SetNormal sets the state to Normal. It changes both the start and end time.
@@ -134,6 +143,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = nil
 }
 
 // Maintain updates the end time using the most recent evaluation.
@@ -263,9 +273,21 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]float6
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
+	} else if state.State == eval.Alerting && keepFiring(state, rule, result.EvaluatedAt) {
+		prevEndsAt := state.EndsAt
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Keeping state because of keep_firing_for",
+			"state",
+			state.State,
+			"keep_firing_since",
+			*state.KeepFiringSince,
+			"previous_ends_at",
+			prevEndsAt,
+			"next_ends_at",
+			state.EndsAt)
 	} else {
 		nextEndsAt := result.EvaluatedAt
 		logger.Debug("Changing state",
@@ -282,10 +304,23 @@ func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger
 	}
 }
 
+// keepFiring returns true if the Alerting state should keep firing although its condition is no longer met,
+// and records the time the condition stopped being met.
+func keepFiring(state *State, rule *models.AlertRule, evaluatedAt time.Time) bool {
+	if rule.KeepFiringFor <= 0 {
+		return false
+	}
+	if state.KeepFiringSince == nil {
+		state.KeepFiringSince = &evaluatedAt
+	}
+	return evaluatedAt.Sub(*state.KeepFiringSince) < rule.KeepFiringFor
+}
+
 func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
 	switch state.State {
 	case eval.Alerting:
 		prevEndsAt := state.EndsAt
+		state.KeepFiringSince = nil
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 		logger.Debug("Keeping state",
 			"state",
@@ -38,9 +38,16 @@ var (
 )
 
 // DeleteAlertRulesByUID is a handler for deleting an alert rule.
+// If the retention of deleted rules is configured, the rules are kept as deleted rules that can be restored.
 func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error {
 	logger := st.Logger.New("org_id", orgID, "rule_uids", ruleUID)
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if st.Cfg.DeletedRuleRetention > 0 {
+			if err := st.saveDeletedAlertRules(sess, orgID, ruleUID); err != nil {
+				return err
+			}
+		}
+
 		rows, err := sess.Table(alertRule{}).Where("org_id = ?", orgID).In("uid", ruleUID).Delete(alertRule{})
 		if err != nil {
 			return err
@@ -139,6 +146,51 @@ func (st DBstore) GetRuleByID(ctx context.Context, query ngmodels.GetAlertRuleBy
 	return result, err
 }
 
+// GetAlertRuleVersions returns the versions of the alert rule identified by UID and organisation ID, latest first.
+// The versions do not have an ID, and it is up to the caller to check that the rule still exists.
+func (st DBstore) GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*ngmodels.AlertRule, error) {
+	var result []*ngmodels.AlertRule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var versions []alertRuleVersion
+		if err := sess.Where("rule_org_id = ? AND rule_uid = ?", orgID, ruleUID).Desc("version").Find(&versions); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.AlertRule, 0, len(versions))
+		for _, version := range versions {
+			r, err := alertRuleToModelsAlertRule(alertRuleVersionToAlertRule(version), st.Logger)
+			if err != nil {
+				return fmt.Errorf("failed to convert version %d of alert rule: %w", version.Version, err)
+			}
+			result = append(result, &r)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetAlertRuleVersion returns a version of the alert rule identified by UID and organisation ID.
+// It returns ngmodels.ErrAlertRuleVersionNotFound if the version does not exist or was deleted.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, orgID int64, ruleUID string, version int64) (*ngmodels.AlertRule, error) {
+	var result *ngmodels.AlertRule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ruleVersion := alertRuleVersion{}
+		has, err := sess.Where("rule_org_id = ? AND rule_uid = ? AND version = ?", orgID, ruleUID, version).Get(&ruleVersion)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		r, err := alertRuleToModelsAlertRule(alertRuleVersionToAlertRule(ruleVersion), st.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to convert version %d of alert rule: %w", version, err)
+		}
+		result = &r
+		return nil
+	})
+	return result, err
+}
+
 // GetAlertRulesGroupByRuleUID is a handler for retrieving a group of alert rules from that database by UID and organisation ID of one of rules that belong to that group.
 func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) (result []*ngmodels.AlertRule, err error) {
 	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
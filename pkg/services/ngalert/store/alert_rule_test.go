@@ -1655,6 +1655,85 @@ func TestIntegration_AlertRuleVersionsCleanup(t *testing.T) {
 	})
 }
 
+func TestIntegration_GetAlertRuleVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{
+		BaseInterval:           time.Duration(rand.Int63n(100)+1) * time.Second,
+		RuleVersionRecordLimit: 10,
+	}
+	sqlStore := db.InitTestDB(t)
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	b := &fakeBus{}
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, b)
+	generator := models.RuleGen
+	generator = generator.With(generator.WithIntervalMatching(store.Cfg.BaseInterval), generator.WithUniqueOrgID())
+
+	rule := createRule(t, store, generator)
+	author := models.UserUID("author")
+	firstRule := models.CopyRule(rule)
+	firstRule.Title = "first"
+	firstRule.UpdatedBy = &author
+	err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+		Existing: rule,
+		New:      *firstRule,
+	}})
+	require.NoError(t, err)
+
+	firstRule.Version = rule.Version + 1
+	secondRule := models.CopyRule(firstRule)
+	secondRule.Title = "second"
+	other := models.UserUID("other")
+	secondRule.UpdatedBy = &other
+	err = store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+		Existing: firstRule,
+		New:      *secondRule,
+	}})
+	require.NoError(t, err)
+
+	t.Run("should return the versions of the rule, latest first", func(t *testing.T) {
+		versions, err := store.GetAlertRuleVersions(context.Background(), rule.OrgID, rule.UID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+
+		assert.Equal(t, rule.Version+2, versions[0].Version)
+		assert.Equal(t, "second", versions[0].Title)
+		require.NotNil(t, versions[0].UpdatedBy)
+		assert.Equal(t, other, *versions[0].UpdatedBy)
+		assert.Equal(t, rule.Version+1, versions[1].Version)
+		assert.Equal(t, "first", versions[1].Title)
+		require.NotNil(t, versions[1].UpdatedBy)
+		assert.Equal(t, author, *versions[1].UpdatedBy)
+		for _, version := range versions {
+			assert.Equal(t, rule.UID, version.UID)
+			assert.Equal(t, rule.NamespaceUID, version.NamespaceUID)
+			assert.Empty(t, version.Diff(rule, "ID", "Version", "Updated", "UpdatedBy", "Title", "DashboardUID", "PanelID"))
+		}
+	})
+
+	t.Run("should return a version of the rule", func(t *testing.T) {
+		version, err := store.GetAlertRuleVersion(context.Background(), rule.OrgID, rule.UID, rule.Version+1)
+		require.NoError(t, err)
+		assert.Equal(t, "first", version.Title)
+	})
+
+	t.Run("should return ErrAlertRuleVersionNotFound if the version does not exist", func(t *testing.T) {
+		_, err := store.GetAlertRuleVersion(context.Background(), rule.OrgID, rule.UID, rule.Version+10)
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+		_, err = store.GetAlertRuleVersion(context.Background(), rule.OrgID+1, rule.UID, rule.Version+1)
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+	})
+
+	t.Run("should store the author of the last change", func(t *testing.T) {
+		current, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.NoError(t, err)
+		require.NotNil(t, current.UpdatedBy)
+		assert.Equal(t, other, *current.UpdatedBy)
+	})
+}
+
 func createTestStore(
 	sqlStore db.DB,
 	folderService folder.Service,
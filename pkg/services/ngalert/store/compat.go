@@ -32,9 +32,20 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		RuleGroup:       ar.RuleGroup,
 		RuleGroupIndex:  ar.RuleGroupIndex,
 		For:             ar.For,
+		KeepFiringFor:   ar.KeepFiringFor,
 		IsPaused:        ar.IsPaused,
 	}
 
+	if ar.MissingSeriesEvalsToResolve != nil {
+		evals := *ar.MissingSeriesEvalsToResolve
+		result.MissingSeriesEvalsToResolve = &evals
+	}
+
+	if ar.UpdatedBy != nil {
+		updatedBy := models.UserUID(*ar.UpdatedBy)
+		result.UpdatedBy = &updatedBy
+	}
+
 	if ar.NoDataState != "" {
 		result.NoDataState, err = models.NoDataStateFromString(ar.NoDataState)
 		if err != nil {
@@ -117,9 +128,20 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 		NoDataState:     ar.NoDataState.String(),
 		ExecErrState:    ar.ExecErrState.String(),
 		For:             ar.For,
+		KeepFiringFor:   ar.KeepFiringFor,
 		IsPaused:        ar.IsPaused,
 	}
 
+	if ar.MissingSeriesEvalsToResolve != nil {
+		evals := *ar.MissingSeriesEvalsToResolve
+		result.MissingSeriesEvalsToResolve = &evals
+	}
+
+	if ar.UpdatedBy != nil {
+		updatedBy := string(*ar.UpdatedBy)
+		result.UpdatedBy = &updatedBy
+	}
+
 	// Serialize complex types to JSON strings
 	data, err := json.Marshal(ar.Data)
 	if err != nil {
@@ -187,10 +209,44 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		NoDataState:          rule.NoDataState,
 		ExecErrState:         rule.ExecErrState,
 		For:                  rule.For,
+		KeepFiringFor:        rule.KeepFiringFor,
 		Annotations:          rule.Annotations,
 		Labels:               rule.Labels,
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: rule.NotificationSettings,
 		Metadata:             rule.Metadata,
+
+		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		UpdatedBy:                   rule.UpdatedBy,
+	}
+}
+
+// alertRuleVersionToAlertRule converts a record of the alert_rule_version table to the alert rule it was a version of.
+// The version table does not keep the ID of the rule nor its dashboard and panel.
+func alertRuleVersionToAlertRule(version alertRuleVersion) alertRule {
+	return alertRule{
+		OrgID:                       version.RuleOrgID,
+		Title:                       version.Title,
+		Condition:                   version.Condition,
+		Data:                        version.Data,
+		Updated:                     version.Created,
+		IntervalSeconds:             version.IntervalSeconds,
+		Version:                     version.Version,
+		UID:                         version.RuleUID,
+		NamespaceUID:                version.RuleNamespaceUID,
+		RuleGroup:                   version.RuleGroup,
+		RuleGroupIndex:              version.RuleGroupIndex,
+		Record:                      version.Record,
+		NoDataState:                 version.NoDataState,
+		ExecErrState:                version.ExecErrState,
+		For:                         version.For,
+		Annotations:                 version.Annotations,
+		Labels:                      version.Labels,
+		IsPaused:                    version.IsPaused,
+		NotificationSettings:        version.NotificationSettings,
+		Metadata:                    version.Metadata,
+		KeepFiringFor:               version.KeepFiringFor,
+		MissingSeriesEvalsToResolve: version.MissingSeriesEvalsToResolve,
+		UpdatedBy:                   version.UpdatedBy,
 	}
 }
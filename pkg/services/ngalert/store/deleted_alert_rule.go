@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// saveDeletedAlertRules keeps the alert rules that are about to be deleted so that they can be restored later.
+func (st DBstore) saveDeletedAlertRules(sess *db.Session, orgID int64, ruleUIDs []string) error {
+	var rules []alertRule
+	if err := sess.Table(alertRule{}).Where("org_id = ?", orgID).In("uid", ruleUIDs).Find(&rules); err != nil {
+		return fmt.Errorf("failed to get the alert rules to delete: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	now := TimeNow().UTC()
+	deleted := make([]deletedAlertRule, 0, len(rules))
+	for _, rule := range rules {
+		b, err := json.Marshal(rule)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert rule %s: %w", rule.UID, err)
+		}
+		deleted = append(deleted, deletedAlertRule{
+			OrgID:        rule.OrgID,
+			RuleUID:      rule.UID,
+			NamespaceUID: rule.NamespaceUID,
+			RuleGroup:    rule.RuleGroup,
+			Title:        rule.Title,
+			Deleted:      now,
+			Rule:         string(b),
+		})
+	}
+	if _, err := sess.Insert(&deleted); err != nil {
+		return fmt.Errorf("failed to save deleted alert rules: %w", err)
+	}
+	return nil
+}
+
+// ListDeletedAlertRules returns the deleted alert rules of the organization that can still be restored, latest first.
+func (st DBstore) ListDeletedAlertRules(ctx context.Context, orgID int64) ([]*ngmodels.DeletedAlertRule, error) {
+	var result []*ngmodels.DeletedAlertRule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []deletedAlertRule
+		if err := sess.Where("org_id = ? AND deleted > ?", orgID, st.deletedRulesExpiry()).Desc("deleted", "id").Find(&rows); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.DeletedAlertRule, 0, len(rows))
+		for _, row := range rows {
+			r, err := st.deletedAlertRuleToModel(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, r)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetDeletedAlertRule returns the deleted alert rule with the given ID.
+// It returns ngmodels.ErrDeletedAlertRuleNotFound if the rule does not exist or cannot be restored anymore.
+func (st DBstore) GetDeletedAlertRule(ctx context.Context, orgID int64, id int64) (*ngmodels.DeletedAlertRule, error) {
+	var result *ngmodels.DeletedAlertRule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := deletedAlertRule{}
+		has, err := sess.Where("org_id = ? AND id = ? AND deleted > ?", orgID, id, st.deletedRulesExpiry()).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrDeletedAlertRuleNotFound
+		}
+		result, err = st.deletedAlertRuleToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// DeleteDeletedAlertRule permanently deletes the deleted alert rule with the given ID, e.g. once it is restored.
+func (st DBstore) DeleteDeletedAlertRule(ctx context.Context, orgID int64, id int64) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND id = ?", orgID, id).Delete(&deletedAlertRule{})
+		return err
+	})
+}
+
+// DeleteExpiredDeletedAlertRules purges the deleted alert rules whose retention period expired.
+// It returns the number of purged rules or an error.
+func (st DBstore) DeleteExpiredDeletedAlertRules(ctx context.Context) (int64, error) {
+	var n int64
+	if err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("deleted <= ?", st.deletedRulesExpiry()).Delete(&deletedAlertRule{})
+		if err != nil {
+			return fmt.Errorf("failed to delete expired deleted alert rules: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
+
+// deletedRulesExpiry returns the time before which the deleted rules are expired.
+func (st DBstore) deletedRulesExpiry() time.Time {
+	return TimeNow().UTC().Add(-st.Cfg.DeletedRuleRetention)
+}
+
+func (st DBstore) deletedAlertRuleToModel(row deletedAlertRule) (*ngmodels.DeletedAlertRule, error) {
+	var rule alertRule
+	if err := json.Unmarshal([]byte(row.Rule), &rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deleted alert rule %d: %w", row.ID, err)
+	}
+	r, err := alertRuleToModelsAlertRule(rule, st.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert deleted alert rule %d: %w", row.ID, err)
+	}
+	return &ngmodels.DeletedAlertRule{
+		ID:      row.ID,
+		Deleted: row.Deleted,
+		Rule:    r,
+	}, nil
+}
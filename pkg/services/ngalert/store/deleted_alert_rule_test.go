@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegration_DeletedAlertRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{
+		BaseInterval:         10 * time.Second,
+		DeletedRuleRetention: 24 * time.Hour,
+	}
+	sqlStore := db.InitTestDB(t)
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, &fakeBus{})
+	generator := models.RuleGen
+	generator = generator.With(generator.WithIntervalMatching(store.Cfg.BaseInterval), generator.WithUniqueOrgID())
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	original := TimeNow
+	TimeNow = func() time.Time { return now }
+	t.Cleanup(func() { TimeNow = original })
+
+	t.Run("should keep the deleted rules until they are restored", func(t *testing.T) {
+		rule := createRule(t, store, generator)
+		require.NoError(t, store.DeleteAlertRulesByUID(context.Background(), rule.OrgID, rule.UID))
+
+		_, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+
+		deleted, err := store.ListDeletedAlertRules(context.Background(), rule.OrgID)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		assert.Equal(t, now, deleted[0].Deleted.UTC())
+		assert.Equal(t, rule.UID, deleted[0].Rule.UID)
+		assert.Equal(t, rule.Title, deleted[0].Rule.Title)
+		assert.Equal(t, rule.NamespaceUID, deleted[0].Rule.NamespaceUID)
+		assert.Equal(t, rule.RuleGroup, deleted[0].Rule.RuleGroup)
+
+		got, err := store.GetDeletedAlertRule(context.Background(), rule.OrgID, deleted[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, deleted[0], got)
+
+		_, err = store.GetDeletedAlertRule(context.Background(), rule.OrgID+1, deleted[0].ID)
+		require.ErrorIs(t, err, models.ErrDeletedAlertRuleNotFound)
+
+		require.NoError(t, store.DeleteDeletedAlertRule(context.Background(), rule.OrgID, deleted[0].ID))
+		_, err = store.GetDeletedAlertRule(context.Background(), rule.OrgID, deleted[0].ID)
+		require.ErrorIs(t, err, models.ErrDeletedAlertRuleNotFound)
+	})
+
+	t.Run("should purge the expired deleted rules", func(t *testing.T) {
+		rule := createRule(t, store, generator)
+		require.NoError(t, store.DeleteAlertRulesByUID(context.Background(), rule.OrgID, rule.UID))
+
+		n, err := store.DeleteExpiredDeletedAlertRules(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+
+		TimeNow = func() time.Time { return now.Add(store.Cfg.DeletedRuleRetention) }
+		t.Cleanup(func() { TimeNow = func() time.Time { return now } })
+
+		deleted, err := store.ListDeletedAlertRules(context.Background(), rule.OrgID)
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+
+		n, err = store.DeleteExpiredDeletedAlertRules(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+
+	t.Run("should delete the rules permanently if the retention is disabled", func(t *testing.T) {
+		store := createTestStore(sqlStore, folderService, &logtest.Fake{}, setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}, &fakeBus{})
+		rule := createRule(t, store, generator)
+		require.NoError(t, store.DeleteAlertRulesByUID(context.Background(), rule.OrgID, rule.UID))
+
+		deleted, err := store.ListDeletedAlertRules(context.Background(), rule.OrgID)
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+	})
+}
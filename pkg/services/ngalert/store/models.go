@@ -27,6 +27,10 @@ type alertRule struct {
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
 	Metadata             string `xorm:"metadata"`
+
+	KeepFiringFor               time.Duration `xorm:"keep_firing_for"`
+	MissingSeriesEvalsToResolve *int64        `xorm:"missing_series_evals_to_resolve"`
+	UpdatedBy                   *string       `xorm:"updated_by"`
 }
 
 func (a alertRule) TableName() string {
@@ -61,8 +65,53 @@ type alertRuleVersion struct {
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
 	Metadata             string `xorm:"metadata"`
+
+	KeepFiringFor               time.Duration `xorm:"keep_firing_for"`
+	MissingSeriesEvalsToResolve *int64        `xorm:"missing_series_evals_to_resolve"`
+	UpdatedBy                   *string       `xorm:"updated_by"`
 }
 
 func (a alertRuleVersion) TableName() string {
 	return "alert_rule_version"
 }
+
+// deletedAlertRule represents a record in alert_rule_deleted table
+type deletedAlertRule struct {
+	ID           int64     `xorm:"pk autoincr 'id'"`
+	OrgID        int64     `xorm:"org_id"`
+	RuleUID      string    `xorm:"rule_uid"`
+	NamespaceUID string    `xorm:"namespace_uid"`
+	RuleGroup    string    `xorm:"rule_group"`
+	Title        string    `xorm:"title"`
+	Deleted      time.Time `xorm:"deleted"`
+	// Rule is the JSON representation of the alert_rule record at the time it was deleted.
+	Rule string `xorm:"rule"`
+}
+
+func (a deletedAlertRule) TableName() string {
+	return "alert_rule_deleted"
+}
+
+// silenceSchedule represents a record in alert_silence_schedule table
+type silenceSchedule struct {
+	ID              int64  `xorm:"pk autoincr 'id'"`
+	OrgID           int64  `xorm:"org_id"`
+	UID             string `xorm:"uid"`
+	Matchers        string `xorm:"matchers"`
+	Comment         string `xorm:"comment"`
+	CreatedBy       string `xorm:"created_by"`
+	Schedule        string `xorm:"schedule"`
+	Timezone        string `xorm:"timezone"`
+	DurationSeconds int64  `xorm:"duration_seconds"`
+	Created         time.Time
+	Updated         time.Time
+	Version         int64
+	// MaterializedUntil is nil if no occurrence was created yet.
+	MaterializedUntil *time.Time `xorm:"materialized_until"`
+	// Occurrences is the JSON representation of the occurrences created as silences.
+	Occurrences string `xorm:"occurrences"`
+}
+
+func (a silenceSchedule) TableName() string {
+	return "alert_silence_schedule"
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ListSilenceSchedules returns the silence schedules of the organization.
+func (st DBstore) ListSilenceSchedules(ctx context.Context, orgID int64) ([]*ngmodels.SilenceSchedule, error) {
+	return st.listSilenceSchedules(ctx, &orgID)
+}
+
+// ListAllSilenceSchedules returns the silence schedules of all organizations.
+func (st DBstore) ListAllSilenceSchedules(ctx context.Context) ([]*ngmodels.SilenceSchedule, error) {
+	return st.listSilenceSchedules(ctx, nil)
+}
+
+func (st DBstore) listSilenceSchedules(ctx context.Context, orgID *int64) ([]*ngmodels.SilenceSchedule, error) {
+	var result []*ngmodels.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Asc("org_id", "id")
+		if orgID != nil {
+			q = q.Where("org_id = ?", *orgID)
+		}
+		var rows []silenceSchedule
+		if err := q.Find(&rows); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.SilenceSchedule, 0, len(rows))
+		for _, row := range rows {
+			s, err := silenceScheduleToModel(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, s)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetSilenceSchedule returns the silence schedule with the given UID.
+// It returns ngmodels.ErrSilenceScheduleNotFound if the schedule does not exist.
+func (st DBstore) GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (*ngmodels.SilenceSchedule, error) {
+	var result *ngmodels.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := silenceSchedule{}
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrSilenceScheduleNotFound
+		}
+		result, err = silenceScheduleToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertSilenceSchedule creates the silence schedule and returns it with its generated UID and timestamps.
+func (st DBstore) InsertSilenceSchedule(ctx context.Context, s ngmodels.SilenceSchedule) (*ngmodels.SilenceSchedule, error) {
+	now := TimeNow().UTC()
+	s.ID = 0
+	s.UID = util.GenerateShortUID()
+	s.Created = now
+	s.Updated = now
+	s.Version = 1
+	row, err := silenceScheduleFromModel(s)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			return fmt.Errorf("failed to insert silence schedule: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.ID = row.ID
+	return &s, nil
+}
+
+// UpdateSilenceSchedule updates the silence schedule if its version did not change since it was read, and returns it
+// with its new version. It returns ngmodels.ErrSilenceScheduleVersionConflict if the schedule was changed in between.
+func (st DBstore) UpdateSilenceSchedule(ctx context.Context, s ngmodels.SilenceSchedule) (*ngmodels.SilenceSchedule, error) {
+	currentVersion := s.Version
+	s.Version++
+	s.Updated = TimeNow().UTC()
+	row, err := silenceScheduleFromModel(s)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ? AND version = ?", s.OrgID, s.UID, currentVersion).
+			Cols("matchers", "comment", "created_by", "schedule", "timezone", "duration_seconds", "updated", "version", "materialized_until", "occurrences").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("failed to update silence schedule: %w", err)
+		}
+		if affected == 0 {
+			return ngmodels.ErrSilenceScheduleVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// DeleteSilenceSchedule deletes the silence schedule with the given UID.
+func (st DBstore) DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&silenceSchedule{})
+		return err
+	})
+}
+
+func silenceScheduleFromModel(s ngmodels.SilenceSchedule) (silenceSchedule, error) {
+	matchers, err := json.Marshal(s.Matchers)
+	if err != nil {
+		return silenceSchedule{}, fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+	occurrences := s.Occurrences
+	if occurrences == nil {
+		occurrences = []ngmodels.SilenceScheduleOccurrence{}
+	}
+	o, err := json.Marshal(occurrences)
+	if err != nil {
+		return silenceSchedule{}, fmt.Errorf("failed to marshal occurrences: %w", err)
+	}
+	row := silenceSchedule{
+		ID:              s.ID,
+		OrgID:           s.OrgID,
+		UID:             s.UID,
+		Matchers:        string(matchers),
+		Comment:         s.Comment,
+		CreatedBy:       s.CreatedBy,
+		Schedule:        s.Schedule,
+		Timezone:        s.Timezone,
+		DurationSeconds: int64(s.Duration / time.Second),
+		Created:         s.Created,
+		Updated:         s.Updated,
+		Version:         s.Version,
+		Occurrences:     string(o),
+	}
+	if !s.MaterializedUntil.IsZero() {
+		t := s.MaterializedUntil.UTC()
+		row.MaterializedUntil = &t
+	}
+	return row, nil
+}
+
+func silenceScheduleToModel(row silenceSchedule) (*ngmodels.SilenceSchedule, error) {
+	var matchers amv2.Matchers
+	if err := json.Unmarshal([]byte(row.Matchers), &matchers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal matchers of silence schedule %s: %w", row.UID, err)
+	}
+	var occurrences []ngmodels.SilenceScheduleOccurrence
+	if row.Occurrences != "" {
+		if err := json.Unmarshal([]byte(row.Occurrences), &occurrences); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal occurrences of silence schedule %s: %w", row.UID, err)
+		}
+	}
+	s := &ngmodels.SilenceSchedule{
+		ID:          row.ID,
+		UID:         row.UID,
+		OrgID:       row.OrgID,
+		Matchers:    matchers,
+		Comment:     row.Comment,
+		CreatedBy:   row.CreatedBy,
+		Schedule:    row.Schedule,
+		Timezone:    row.Timezone,
+		Duration:    time.Duration(row.DurationSeconds) * time.Second,
+		Created:     row.Created,
+		Updated:     row.Updated,
+		Version:     row.Version,
+		Occurrences: occurrences,
+	}
+	if row.MaterializedUntil != nil {
+		s.MaterializedUntil = row.MaterializedUntil.UTC()
+	}
+	return s, nil
+}
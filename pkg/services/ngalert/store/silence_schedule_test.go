@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestIntegration_SilenceSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}
+	sqlStore := db.InitTestDB(t)
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, &fakeBus{})
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	original := TimeNow
+	TimeNow = func() time.Time { return now }
+	t.Cleanup(func() { TimeNow = original })
+
+	newSchedule := func(orgID int64) models.SilenceSchedule {
+		return models.SilenceSchedule{
+			OrgID: orgID,
+			Matchers: amv2.Matchers{
+				{Name: util.Pointer("team"), Value: util.Pointer("a"), IsRegex: util.Pointer(false), IsEqual: util.Pointer(true)},
+			},
+			Comment:   "weekly maintenance",
+			CreatedBy: "admin",
+			Schedule:  "0 22 * * SAT",
+			Timezone:  "Europe/Berlin",
+			Duration:  2 * time.Hour,
+		}
+	}
+
+	t.Run("should create, get and list silence schedules", func(t *testing.T) {
+		created, err := store.InsertSilenceSchedule(context.Background(), newSchedule(1))
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		assert.Equal(t, int64(1), created.Version)
+		assert.Equal(t, now, created.Created)
+
+		got, err := store.GetSilenceSchedule(context.Background(), 1, created.UID)
+		require.NoError(t, err)
+		assert.Equal(t, created.UID, got.UID)
+		assert.Equal(t, created.Matchers, got.Matchers)
+		assert.Equal(t, "0 22 * * SAT", got.Schedule)
+		assert.Equal(t, "Europe/Berlin", got.Timezone)
+		assert.Equal(t, 2*time.Hour, got.Duration)
+		assert.True(t, got.MaterializedUntil.IsZero())
+		assert.Empty(t, got.Occurrences)
+
+		_, err = store.GetSilenceSchedule(context.Background(), 2, created.UID)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+
+		other, err := store.InsertSilenceSchedule(context.Background(), newSchedule(2))
+		require.NoError(t, err)
+
+		list, err := store.ListSilenceSchedules(context.Background(), 2)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, other.UID, list[0].UID)
+
+		all, err := store.ListAllSilenceSchedules(context.Background())
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("should update silence schedules with optimistic concurrency", func(t *testing.T) {
+		created, err := store.InsertSilenceSchedule(context.Background(), newSchedule(3))
+		require.NoError(t, err)
+
+		update := *created
+		update.Duration = time.Hour
+		update.MaterializedUntil = now.Add(time.Hour)
+		update.Occurrences = []models.SilenceScheduleOccurrence{
+			{SilenceID: "silence-1", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+		}
+		updated, err := store.UpdateSilenceSchedule(context.Background(), update)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+
+		got, err := store.GetSilenceSchedule(context.Background(), 3, created.UID)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, got.Duration)
+		assert.Equal(t, now.Add(time.Hour), got.MaterializedUntil)
+		require.Len(t, got.Occurrences, 1)
+		assert.Equal(t, "silence-1", got.Occurrences[0].SilenceID)
+		assert.Equal(t, int64(2), got.Version)
+
+		_, err = store.UpdateSilenceSchedule(context.Background(), update)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleVersionConflict)
+	})
+
+	t.Run("should delete silence schedules", func(t *testing.T) {
+		created, err := store.InsertSilenceSchedule(context.Background(), newSchedule(4))
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteSilenceSchedule(context.Background(), 4, created.UID))
+
+		_, err = store.GetSilenceSchedule(context.Background(), 4, created.UID)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+	})
+}
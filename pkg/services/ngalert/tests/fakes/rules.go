@@ -1,6 +1,7 @@
 package fakes
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"math/rand"
@@ -21,7 +22,11 @@ type RuleStore struct {
 	t   *testing.T
 	mtx sync.Mutex
 	// OrgID -> RuleGroup -> Namespace -> Rules
-	Rules       map[int64][]*models.AlertRule
+	Rules map[int64][]*models.AlertRule
+	// OrgID -> previous versions of the rules. The current versions are in Rules.
+	History map[int64][]*models.AlertRule
+	// OrgID -> deleted rules that can be restored
+	Deleted     map[int64][]*models.DeletedAlertRule
 	Hook        func(cmd any) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []any
 	Folders     map[int64][]*folder.Folder
@@ -34,8 +39,10 @@ type GenericRecordedQuery struct {
 
 func NewRuleStore(t *testing.T) *RuleStore {
 	return &RuleStore{
-		t:     t,
-		Rules: map[int64][]*models.AlertRule{},
+		t:       t,
+		Rules:   map[int64][]*models.AlertRule{},
+		History: map[int64][]*models.AlertRule{},
+		Deleted: map[int64][]*models.DeletedAlertRule{},
 		Hook: func(any) error {
 			return nil
 		},
@@ -124,6 +131,56 @@ func (f *RuleStore) DeleteAlertRulesByUID(_ context.Context, orgID int64, UIDs .
 	return nil
 }
 
+func (f *RuleStore) ListDeletedAlertRules(_ context.Context, orgID int64) ([]*models.DeletedAlertRule, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "ListDeletedAlertRules",
+		Params: []any{orgID},
+	}
+	f.RecordedOps = append(f.RecordedOps, q)
+	if err := f.Hook(q); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Deleted[orgID]), nil
+}
+
+func (f *RuleStore) GetDeletedAlertRule(_ context.Context, orgID int64, id int64) (*models.DeletedAlertRule, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "GetDeletedAlertRule",
+		Params: []any{orgID, id},
+	}
+	f.RecordedOps = append(f.RecordedOps, q)
+	if err := f.Hook(q); err != nil {
+		return nil, err
+	}
+	for _, d := range f.Deleted[orgID] {
+		if d.ID == id {
+			return &models.DeletedAlertRule{ID: d.ID, Deleted: d.Deleted, Rule: *models.CopyRule(&d.Rule)}, nil
+		}
+	}
+	return nil, models.ErrDeletedAlertRuleNotFound
+}
+
+func (f *RuleStore) DeleteDeletedAlertRule(_ context.Context, orgID int64, id int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "DeleteDeletedAlertRule",
+		Params: []any{orgID, id},
+	}
+	f.RecordedOps = append(f.RecordedOps, q)
+	if err := f.Hook(q); err != nil {
+		return err
+	}
+	f.Deleted[orgID] = slices.DeleteFunc(f.Deleted[orgID], func(d *models.DeletedAlertRule) bool {
+		return d.ID == id
+	})
+	return nil
+}
+
 func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -144,6 +201,52 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	return nil, models.ErrAlertRuleNotFound
 }
 
+func (f *RuleStore) GetAlertRuleVersions(_ context.Context, orgID int64, ruleUID string) ([]*models.AlertRule, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "GetAlertRuleVersions",
+		Params: []any{orgID, ruleUID},
+	}
+	f.RecordedOps = append(f.RecordedOps, q)
+	if err := f.Hook(q); err != nil {
+		return nil, err
+	}
+	return f.getAlertRuleVersions(orgID, ruleUID), nil
+}
+
+func (f *RuleStore) GetAlertRuleVersion(_ context.Context, orgID int64, ruleUID string, version int64) (*models.AlertRule, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "GetAlertRuleVersion",
+		Params: []any{orgID, ruleUID, version},
+	}
+	f.RecordedOps = append(f.RecordedOps, q)
+	if err := f.Hook(q); err != nil {
+		return nil, err
+	}
+	for _, rule := range f.getAlertRuleVersions(orgID, ruleUID) {
+		if rule.Version == version {
+			return rule, nil
+		}
+	}
+	return nil, models.ErrAlertRuleVersionNotFound
+}
+
+func (f *RuleStore) getAlertRuleVersions(orgID int64, ruleUID string) []*models.AlertRule {
+	var result []*models.AlertRule
+	for _, rule := range slices.Concat(f.Rules[orgID], f.History[orgID]) {
+		if rule.UID == ruleUID {
+			result = append(result, models.CopyRule(rule))
+		}
+	}
+	slices.SortFunc(result, func(a, b *models.AlertRule) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+	return result
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -1,13 +1,29 @@
 package playlist
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 )
 
 // Typed errors
 var (
 	ErrPlaylistNotFound        = errors.New("Playlist not found")
 	ErrCommandValidationFailed = errors.New("command missing required fields")
+	ErrInvalidPlaylistItem     = errutil.BadRequest("playlist.invalidItem", errutil.WithPublicMessage("Invalid playlist item"))
+)
+
+// Types of the playlist items
+const (
+	ItemTypeDashboardByID     = "dashboard_by_id"
+	ItemTypeDashboardByUID    = "dashboard_by_uid"
+	ItemTypeDashboardByTag    = "dashboard_by_tag"
+	ItemTypeDashboardBySearch = "dashboard_by_search"
 )
 
 // Playlist model
@@ -67,16 +83,129 @@ type PlaylistItemDTO struct {
 	//  - dashboard_by_tag: The value is a tag which is set on any number of dashboards. All
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
+	//  - dashboard_by_search: The value is a JSON encoded search, see PlaylistItemSearch. All
+	//  dashboards matching the search will be added to the playlist.
 	Value string `json:"value"`
+
+	// Options override how the dashboards of the item are displayed.
+	Options *PlaylistItemOptions `json:"options,omitempty"`
 }
 
 type PlaylistItem struct {
-	Id         int64  `db:"id"`
-	PlaylistId int64  `db:"playlist_id"`
-	Type       string `json:"type" db:"type"`
-	Value      string `json:"value" db:"value"`
-	Order      int    `json:"order" db:"order"`
-	Title      string `json:"title" db:"title"`
+	Id         int64                `db:"id"`
+	PlaylistId int64                `db:"playlist_id"`
+	Type       string               `json:"type" db:"type"`
+	Value      string               `json:"value" db:"value"`
+	Order      int                  `json:"order" db:"order"`
+	Title      string               `json:"title" db:"title"`
+	Options    *PlaylistItemOptions `json:"options,omitempty" db:"options"`
+}
+
+// PlaylistItemSearch is the value of the dashboard_by_search items.
+type PlaylistItemSearch struct {
+	// Query matches the dashboard titles.
+	Query string `json:"query,omitempty"`
+
+	// Tags the dashboards must all have.
+	Tags []string `json:"tags,omitempty"`
+
+	// FolderUIDs limits the search to the dashboards of the folders.
+	FolderUIDs []string `json:"folderUids,omitempty"`
+
+	// Starred limits the search to the dashboards starred by the user viewing the playlist.
+	Starred bool `json:"starred,omitempty"`
+}
+
+// ParsePlaylistItemSearch returns the search of a dashboard_by_search item value.
+func ParsePlaylistItemSearch(value string) (*PlaylistItemSearch, error) {
+	search := &PlaylistItemSearch{}
+	if err := json.Unmarshal([]byte(value), search); err != nil {
+		return nil, ErrInvalidPlaylistItem.Errorf("invalid search: %w", err)
+	}
+	if search.Query == "" && len(search.Tags) == 0 && len(search.FolderUIDs) == 0 && !search.Starred {
+		return nil, ErrInvalidPlaylistItem.Errorf("empty search")
+	}
+	return search, nil
+}
+
+// PlaylistItemOptions override how the dashboards of a playlist item are displayed.
+type PlaylistItemOptions struct {
+	// Interval overrides the time between switching the dashboards of the item.
+	Interval string `json:"interval,omitempty"`
+
+	// TimeRange overrides the time range of the dashboards.
+	TimeRange *PlaylistTimeRange `json:"timeRange,omitempty"`
+
+	// Variables override the values of the dashboard variables.
+	Variables map[string][]string `json:"variables,omitempty"`
+}
+
+type PlaylistTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (o *PlaylistItemOptions) FromDB(data []byte) error {
+	dec := json.NewDecoder(bytes.NewBuffer(data))
+	return dec.Decode(o)
+}
+
+func (o *PlaylistItemOptions) Scan(val any) error {
+	switch v := val.(type) {
+	case []byte:
+		if len(v) == 0 {
+			return nil
+		}
+		return json.Unmarshal(v, &o)
+	case string:
+		if len(v) == 0 {
+			return nil
+		}
+		return json.Unmarshal([]byte(v), &o)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+}
+
+func (o *PlaylistItemOptions) Value() (driver.Value, error) {
+	return o.ToDB()
+}
+
+func (o *PlaylistItemOptions) ToDB() ([]byte, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(o)
+}
+
+// ResolvedPlaylist is a playlist with its items expanded into the dashboards to display.
+type ResolvedPlaylist struct {
+	Uid      string `json:"uid"`
+	Name     string `json:"name"`
+	Interval string `json:"interval"`
+
+	// The ordered list of dashboards the playlist will iterate over.
+	Dashboards []ResolvedPlaylistDashboard `json:"dashboards"`
+}
+
+type ResolvedPlaylistDashboard struct {
+	// Index of the playlist item the dashboard was resolved from.
+	Item int `json:"item"`
+
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+
+	// URL of the dashboard including the time range and variable overrides.
+	URL string `json:"url"`
+
+	// Interval is the time the dashboard is displayed.
+	Interval string `json:"interval"`
+
+	TimeRange *PlaylistTimeRange  `json:"timeRange,omitempty"`
+	Variables map[string][]string `json:"variables,omitempty"`
 }
 
 type Playlists []*Playlist
@@ -127,3 +256,8 @@ type GetPlaylistItemsByUidQuery struct {
 	PlaylistUID string
 	OrgId       int64
 }
+
+type ResolvePlaylistQuery struct {
+	Playlist     *PlaylistDTO
+	SignedInUser identity.Requester
+}
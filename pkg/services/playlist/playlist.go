@@ -12,6 +12,9 @@ type Service interface {
 	Search(context.Context, *GetPlaylistsQuery) (Playlists, error)
 	Delete(ctx context.Context, cmd *DeletePlaylistCommand) error
 
+	// Resolve expands the items of a playlist into the ordered list of dashboards the user can view
+	Resolve(context.Context, *ResolvePlaylistQuery) (*ResolvedPlaylist, error)
+
 	// This is optimized for the kubernetes list command that returns full bodies in the list
 	List(ctx context.Context, orgId int64) ([]PlaylistDTO, error)
 }
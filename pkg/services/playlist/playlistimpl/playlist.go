@@ -6,17 +6,20 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/search"
 )
 
 type Service struct {
 	store  store
+	search search.Service
 	tracer tracing.Tracer
 }
 
 var _ playlist.Service = &Service{}
 
-func ProvideService(db db.DB, tracer tracing.Tracer) playlist.Service {
+func ProvideService(db db.DB, tracer tracing.Tracer, searchService *search.SearchService) playlist.Service {
 	return &Service{
+		search: searchService,
 		tracer: tracer,
 		store: &sqlStore{
 			db: db,
@@ -60,6 +63,7 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 	for i := 0; i < len(rawItems); i++ {
 		items[i].Type = rawItems[i].Type
 		items[i].Value = rawItems[i].Value
+		items[i].Options = rawItems[i].Options
 
 		// Add the unused title to the result
 		title := rawItems[i].Title
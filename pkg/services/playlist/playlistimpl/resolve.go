@@ -0,0 +1,121 @@
+package playlistimpl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/search/model"
+)
+
+// maxItemDashboards is the maximum number of dashboards a single playlist item resolves to.
+const maxItemDashboards = 1000
+
+func (s *Service) Resolve(ctx context.Context, q *playlist.ResolvePlaylistQuery) (*playlist.ResolvedPlaylist, error) {
+	ctx, span := s.tracer.Start(ctx, "playlists.Resolve")
+	defer span.End()
+
+	if q.Playlist == nil || q.SignedInUser == nil {
+		return nil, playlist.ErrCommandValidationFailed
+	}
+
+	resolved := &playlist.ResolvedPlaylist{
+		Uid:        q.Playlist.Uid,
+		Name:       q.Playlist.Name,
+		Interval:   q.Playlist.Interval,
+		Dashboards: []playlist.ResolvedPlaylistDashboard{},
+	}
+	for i, item := range q.Playlist.Items {
+		query, err := searchQuery(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		query.OrgId = q.SignedInUser.GetOrgID()
+		query.SignedInUser = q.SignedInUser
+		query.Type = string(model.DashHitDB)
+		query.Permission = dashboardaccess.PERMISSION_VIEW
+		query.Limit = maxItemDashboards
+
+		hits, err := s.search.SearchHandler(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range hits {
+			resolved.Dashboards = append(resolved.Dashboards, resolveDashboard(i, hit, q.Playlist.Interval, item.Options))
+		}
+	}
+	return resolved, nil
+}
+
+// searchQuery returns the search of the dashboards of a playlist item.
+func searchQuery(item playlist.PlaylistItemDTO) (*search.Query, error) {
+	switch item.Type {
+	case playlist.ItemTypeDashboardByUID:
+		return &search.Query{DashboardUIDs: []string{item.Value}}, nil
+	case playlist.ItemTypeDashboardByID:
+		id, err := strconv.ParseInt(item.Value, 10, 64)
+		if err != nil {
+			return nil, playlist.ErrInvalidPlaylistItem.Errorf("invalid dashboard id %q", item.Value)
+		}
+		return &search.Query{DashboardIds: []int64{id}}, nil
+	case playlist.ItemTypeDashboardByTag:
+		return &search.Query{Tags: []string{item.Value}}, nil
+	case playlist.ItemTypeDashboardBySearch:
+		itemSearch, err := playlist.ParsePlaylistItemSearch(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &search.Query{
+			Title:      itemSearch.Query,
+			Tags:       itemSearch.Tags,
+			FolderUIDs: itemSearch.FolderUIDs,
+			IsStarred:  itemSearch.Starred,
+		}, nil
+	default:
+		return nil, playlist.ErrInvalidPlaylistItem.Errorf("unknown type %q", item.Type)
+	}
+}
+
+func resolveDashboard(item int, hit *model.Hit, interval string, options *playlist.PlaylistItemOptions) playlist.ResolvedPlaylistDashboard {
+	dashboard := playlist.ResolvedPlaylistDashboard{
+		Item:     item,
+		UID:      hit.UID,
+		Title:    hit.Title,
+		URL:      hit.URL,
+		Interval: interval,
+	}
+	if options == nil {
+		return dashboard
+	}
+
+	if options.Interval != "" {
+		dashboard.Interval = options.Interval
+	}
+	dashboard.TimeRange = options.TimeRange
+	dashboard.Variables = options.Variables
+
+	params := url.Values{}
+	if options.TimeRange != nil {
+		params.Set("from", options.TimeRange.From)
+		params.Set("to", options.TimeRange.To)
+	}
+	for name, values := range options.Variables {
+		for _, value := range values {
+			params.Add("var-"+name, value)
+		}
+	}
+	if len(params) > 0 {
+		separator := "?"
+		if strings.Contains(dashboard.URL, "?") {
+			separator = "&"
+		}
+		dashboard.URL += separator + params.Encode()
+	}
+	return dashboard
+}
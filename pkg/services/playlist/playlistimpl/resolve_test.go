@@ -0,0 +1,130 @@
+package playlistimpl
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeSearchService struct {
+	queries []*search.Query
+	hits    func(*search.Query) model.HitList
+}
+
+func (f *fakeSearchService) SearchHandler(_ context.Context, q *search.Query) (model.HitList, error) {
+	f.queries = append(f.queries, q)
+	return f.hits(q), nil
+}
+
+func (f *fakeSearchService) SortOptions() []model.SortOption {
+	return nil
+}
+
+func TestResolve(t *testing.T) {
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 2}
+	searchService := &fakeSearchService{hits: func(q *search.Query) model.HitList {
+		switch {
+		case len(q.DashboardUIDs) > 0:
+			return model.HitList{{UID: q.DashboardUIDs[0], Title: "By UID", URL: "/d/" + q.DashboardUIDs[0] + "/by-uid"}}
+		case len(q.DashboardIds) > 0:
+			return model.HitList{{UID: "by-id", Title: "By ID", URL: "/d/by-id/by-id"}}
+		case len(q.Tags) > 0:
+			return model.HitList{
+				{UID: "a", Title: "A", URL: "/d/a/a"},
+				{UID: "b", Title: "B", URL: "/d/b/b"},
+			}
+		default:
+			return model.HitList{{UID: "starred", Title: "Starred", URL: "/d/starred/starred"}}
+		}
+	}}
+	s := &Service{search: searchService, tracer: tracing.InitializeTracerForTest()}
+
+	resolved, err := s.Resolve(context.Background(), &playlist.ResolvePlaylistQuery{
+		SignedInUser: signedInUser,
+		Playlist: &playlist.PlaylistDTO{
+			Uid:      "wall",
+			Name:     "Wall",
+			Interval: "5m",
+			Items: []playlist.PlaylistItemDTO{
+				{Type: playlist.ItemTypeDashboardByUID, Value: "home", Options: &playlist.PlaylistItemOptions{
+					Interval:  "30s",
+					TimeRange: &playlist.PlaylistTimeRange{From: "now-6h", To: "now"},
+					Variables: map[string][]string{"host": {"a", "b"}},
+				}},
+				{Type: playlist.ItemTypeDashboardByTag, Value: "ops"},
+				{Type: playlist.ItemTypeDashboardByID, Value: "3"},
+				{Type: playlist.ItemTypeDashboardBySearch, Value: `{"query":"cpu","tags":["ops","prod"],"folderUids":["f"],"starred":true}`},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, searchService.queries, 4)
+	for _, q := range searchService.queries {
+		assert.Equal(t, int64(2), q.OrgId)
+		assert.Equal(t, signedInUser, q.SignedInUser)
+		assert.Equal(t, string(model.DashHitDB), q.Type)
+	}
+	assert.Equal(t, []string{"home"}, searchService.queries[0].DashboardUIDs)
+	assert.Equal(t, []string{"ops"}, searchService.queries[1].Tags)
+	assert.Equal(t, []int64{3}, searchService.queries[2].DashboardIds)
+	assert.Equal(t, "cpu", searchService.queries[3].Title)
+	assert.Equal(t, []string{"ops", "prod"}, searchService.queries[3].Tags)
+	assert.Equal(t, []string{"f"}, searchService.queries[3].FolderUIDs)
+	assert.True(t, searchService.queries[3].IsStarred)
+
+	assert.Equal(t, "wall", resolved.Uid)
+	require.Len(t, resolved.Dashboards, 5)
+	assert.Equal(t, []string{"home", "a", "b", "by-id", "starred"}, []string{
+		resolved.Dashboards[0].UID,
+		resolved.Dashboards[1].UID,
+		resolved.Dashboards[2].UID,
+		resolved.Dashboards[3].UID,
+		resolved.Dashboards[4].UID,
+	})
+	assert.Equal(t, []int{0, 1, 1, 2, 3}, []int{
+		resolved.Dashboards[0].Item,
+		resolved.Dashboards[1].Item,
+		resolved.Dashboards[2].Item,
+		resolved.Dashboards[3].Item,
+		resolved.Dashboards[4].Item,
+	})
+
+	home := resolved.Dashboards[0]
+	assert.Equal(t, "By UID", home.Title)
+	assert.Equal(t, "30s", home.Interval)
+	assert.Equal(t, "now-6h", home.TimeRange.From)
+	u, err := url.Parse(home.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/d/home/by-uid", u.Path)
+	assert.Equal(t, url.Values{"from": {"now-6h"}, "to": {"now"}, "var-host": {"a", "b"}}, u.Query())
+
+	tagged := resolved.Dashboards[1]
+	assert.Equal(t, "5m", tagged.Interval)
+	assert.Equal(t, "/d/a/a", tagged.URL)
+	assert.Nil(t, tagged.TimeRange)
+
+	t.Run("should reject invalid items", func(t *testing.T) {
+		for _, item := range []playlist.PlaylistItemDTO{
+			{Type: "dashboard_by_magic", Value: "x"},
+			{Type: playlist.ItemTypeDashboardByID, Value: "x"},
+			{Type: playlist.ItemTypeDashboardBySearch, Value: "ops"},
+			{Type: playlist.ItemTypeDashboardBySearch, Value: "{}"},
+		} {
+			_, err := s.Resolve(context.Background(), &playlist.ResolvePlaylistQuery{
+				SignedInUser: signedInUser,
+				Playlist:     &playlist.PlaylistDTO{Items: []playlist.PlaylistItemDTO{item}},
+			})
+			require.ErrorIs(t, err, playlist.ErrInvalidPlaylistItem)
+		}
+	})
+}
@@ -29,7 +29,7 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 
 	t.Run("Can create playlist", func(t *testing.T) {
 		items := []playlist.PlaylistItem{
-			{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag"},
+			{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag", Options: &playlist.PlaylistItemOptions{Interval: "1m"}},
 			{Title: "Backend response times", Value: "3", Type: "dashboard_by_id"},
 		}
 		cmd := playlist.CreatePlaylistCommand{Name: "NYC office", Interval: "10m", OrgId: 1, Items: items}
@@ -51,6 +51,8 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 			storedPlaylistItems, err := playlistStore.GetItems(context.Background(), get)
 			require.NoError(t, err)
 			require.Equal(t, len(items), len(storedPlaylistItems))
+			require.Equal(t, "1m", storedPlaylistItems[0].Options.Interval)
+			require.Nil(t, storedPlaylistItems[1].Options)
 		})
 
 		t.Run("Can update playlist", func(t *testing.T) {
@@ -86,7 +88,7 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 
 	t.Run("Can create playlist with known UID", func(t *testing.T) {
 		items := []playlist.PlaylistItem{
-			{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag"},
+			{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag", Options: &playlist.PlaylistItemOptions{Interval: "1m"}},
 			{Title: "Backend response times", Value: "3", Type: "dashboard_by_id"},
 		}
 		cmd := playlist.CreatePlaylistCommand{Name: "NYC office", Interval: "10m", OrgId: 1,
@@ -62,6 +62,7 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 				Value:      item.Value,
 				Order:      order + 1,
 				Title:      item.Title,
+				Options:    item.Options,
 			})
 		}
 
@@ -118,6 +119,7 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Options:    item.Options,
 			})
 		}
 
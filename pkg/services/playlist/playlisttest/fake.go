@@ -11,6 +11,7 @@ type FakePlaylistService struct {
 	ExpectedPlaylistDTO   *playlist.PlaylistDTO
 	ExpectedPlaylistItems []playlist.PlaylistItem
 	ExpectedPlaylists     playlist.Playlists
+	ExpectedResolved      *playlist.ResolvedPlaylist
 	ExpectedError         error
 }
 
@@ -41,3 +42,7 @@ func (f *FakePlaylistService) Search(context.Context, *playlist.GetPlaylistsQuer
 func (f *FakePlaylistService) Delete(ctx context.Context, cmd *playlist.DeletePlaylistCommand) error {
 	return f.ExpectedError
 }
+
+func (f *FakePlaylistService) Resolve(context.Context, *playlist.ResolvePlaylistQuery) (*playlist.ResolvedPlaylist, error) {
+	return f.ExpectedResolved, f.ExpectedError
+}
@@ -156,11 +156,19 @@ func (m *CachingMiddleware) CallResource(ctx context.Context, req *backend.CallR
 	if cr.UpdateCacheFn == nil {
 		return m.BaseHandler.CallResource(ctx, req, sender)
 	}
-	// Otherwise, intercept the responses in a wrapped sender so we can cache them first
+	// Otherwise, intercept the responses in a wrapped sender so we can cache them once the handler returns.
+	// Only single responses are cached: a streamed or multi-part response would be served truncated on a hit.
+	var responses []*backend.CallResourceResponse
 	cacheSender := backend.CallResourceResponseSenderFunc(func(res *backend.CallResourceResponse) error {
-		cr.UpdateCacheFn(ctx, res)
+		responses = append(responses, res)
 		return sender.Send(res)
 	})
 
-	return m.BaseHandler.CallResource(ctx, req, cacheSender)
+	if err := m.BaseHandler.CallResource(ctx, req, cacheSender); err != nil {
+		return err
+	}
+	if len(responses) == 1 {
+		cr.UpdateCacheFn(ctx, responses[0])
+	}
+	return nil
 }
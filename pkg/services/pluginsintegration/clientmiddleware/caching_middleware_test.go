@@ -265,6 +265,36 @@ func TestCachingMiddleware(t *testing.T) {
 			// Since it was a miss, the middleware called the update func
 			assert.True(t, updateCacheCalled)
 		})
+
+		t.Run("If the resource call sends multiple responses, the update cache function is not called", func(t *testing.T) {
+			updateCacheCalled = false
+			t.Cleanup(func() {
+				cs.Reset()
+			})
+
+			cs.ReturnHit = false
+			cs.ReturnResourceResponse = dataResponse
+			chunks := []*backend.CallResourceResponse{
+				{Status: 200, Body: []byte("first")},
+				{Body: []byte("second")},
+			}
+			multiCdt := handlertest.NewHandlerMiddlewareTest(t,
+				WithReqContext(req, &user.SignedInUser{}),
+				handlertest.WithMiddlewares(NewCachingMiddleware(cs)),
+				handlertest.WithResourceResponses(chunks),
+			)
+
+			var sent []*backend.CallResourceResponse
+			err := multiCdt.MiddlewareHandler.CallResource(req.Context(), crr, backend.CallResourceResponseSenderFunc(func(res *backend.CallResourceResponse) error {
+				sent = append(sent, res)
+				return nil
+			}))
+			assert.NoError(t, err)
+			// All chunks were sent to the client
+			assert.Equal(t, chunks, sent)
+			// The truncated response was not cached
+			assert.False(t, updateCacheCalled)
+		})
 	})
 
 	t.Run("When RequestContext is nil", func(t *testing.T) {
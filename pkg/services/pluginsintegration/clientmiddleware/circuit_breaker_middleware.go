@@ -0,0 +1,251 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// circuitState is the state of the circuit breaker of a data source.
+type circuitState int
+
+const (
+	// circuitClosed lets all requests through.
+	circuitClosed circuitState = iota
+	// circuitHalfOpen lets a single request through to probe whether the data source recovered.
+	circuitHalfOpen
+	// circuitOpen rejects all requests.
+	circuitOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitHalfOpen:
+		return "half-open"
+	case circuitOpen:
+		return "open"
+	}
+	return ""
+}
+
+// requestOutcome is how a request counts towards the circuit breaker of a data source.
+type requestOutcome int
+
+const (
+	requestSucceeded requestOutcome = iota
+	requestFailed
+	// requestIgnored is the outcome of requests that say nothing about the health of the data source,
+	// such as requests cancelled by the client or rejected by the concurrency limits.
+	requestIgnored
+)
+
+// NewCircuitBreakerMiddleware creates a new backend.HandlerMiddleware that stops sending QueryData
+// and CallResource requests to a data source after a number of consecutive failed requests. While the
+// circuit breaker is open, requests fail with plugins.ErrPluginCircuitOpen without calling the plugin.
+// Once the open duration has passed a single request is let through, which closes the circuit breaker
+// if it succeeds and opens it again if it fails.
+func NewCircuitBreakerMiddleware(cfg setting.PluginLimitsSettings) backend.HandlerMiddleware {
+	breakers := newCircuitBreakers(cfg, time.Now)
+	return backend.HandlerMiddlewareFunc(func(next backend.Handler) backend.Handler {
+		return &CircuitBreakerMiddleware{
+			BaseHandler: backend.NewBaseHandler(next),
+			breakers:    breakers,
+		}
+	})
+}
+
+type CircuitBreakerMiddleware struct {
+	backend.BaseHandler
+
+	breakers *circuitBreakers
+}
+
+func (m *CircuitBreakerMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil || req.PluginContext.DataSourceInstanceSettings == nil {
+		return m.BaseHandler.QueryData(ctx, req)
+	}
+
+	p, err := m.breakers.allow(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := m.BaseHandler.QueryData(ctx, req)
+	m.breakers.done(ctx, p, queryDataOutcome(resp, err))
+	return resp, err
+}
+
+func (m *CircuitBreakerMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	if req == nil || req.PluginContext.DataSourceInstanceSettings == nil {
+		return m.BaseHandler.CallResource(ctx, req, sender)
+	}
+
+	p, err := m.breakers.allow(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	status := 0
+	err = m.BaseHandler.CallResource(ctx, req, backend.CallResourceResponseSenderFunc(func(res *backend.CallResourceResponse) error {
+		if res != nil && status == 0 {
+			status = res.Status
+		}
+		return sender.Send(res)
+	}))
+
+	outcome := errorOutcome(err)
+	if outcome == requestSucceeded && status >= http.StatusInternalServerError {
+		outcome = requestFailed
+	}
+	m.breakers.done(ctx, p, outcome)
+	return err
+}
+
+// errorOutcome returns the outcome of a request that returned err.
+func errorOutcome(err error) requestOutcome {
+	switch {
+	case err == nil:
+		return requestSucceeded
+	case errors.Is(err, context.Canceled), errors.Is(err, plugins.ErrPluginRequestLimitExceeded):
+		return requestIgnored
+	default:
+		return requestFailed
+	}
+}
+
+// queryDataOutcome returns the outcome of a QueryData request. A response fails the request only if all
+// of its queries failed, and none of them because the query itself was invalid.
+func queryDataOutcome(resp *backend.QueryDataResponse, err error) requestOutcome {
+	if err != nil || resp == nil || len(resp.Responses) == 0 {
+		return errorOutcome(err)
+	}
+	outcome := requestFailed
+	for _, dr := range resp.Responses {
+		if dr.Error == nil || (dr.Status >= http.StatusBadRequest && dr.Status < http.StatusInternalServerError) {
+			return requestSucceeded
+		}
+		if errors.Is(dr.Error, context.Canceled) {
+			outcome = requestIgnored
+		}
+	}
+	return outcome
+}
+
+// circuitBreakers holds the circuit breakers of all data sources.
+type circuitBreakers struct {
+	failureThreshold int
+	openDuration     time.Duration
+
+	mtx      sync.Mutex
+	breakers map[string]*circuitBreaker
+	now      func() time.Time
+}
+
+func newCircuitBreakers(cfg setting.PluginLimitsSettings, now func() time.Time) *circuitBreakers {
+	return &circuitBreakers{
+		failureThreshold: cfg.CircuitBreakerFailureThreshold,
+		openDuration:     cfg.CircuitBreakerOpenDuration,
+		breakers:         make(map[string]*circuitBreaker),
+		now:              now,
+	}
+}
+
+// permit is handed out by a circuit breaker to each request it lets through.
+type permit struct {
+	breaker *circuitBreaker
+	// probe is true for the request that probes a half-open circuit breaker.
+	probe bool
+}
+
+// allow returns a permit to send the request, or plugins.ErrPluginCircuitOpen if the circuit breaker of
+// the data source of the request does not let it through.
+func (c *circuitBreakers) allow(ctx context.Context, pCtx backend.PluginContext) (permit, error) {
+	key := dataSourceKey(pCtx)
+	c.mtx.Lock()
+	b, ok := c.breakers[key]
+	if !ok {
+		b = &circuitBreaker{}
+		c.breakers[key] = b
+	}
+	c.mtx.Unlock()
+
+	state, probe, allowed := b.allow(c.now(), c.openDuration)
+	reportCircuitState(ctx, state)
+	if !allowed {
+		return permit{}, plugins.ErrPluginCircuitOpen.Errorf("circuit breaker of data source %s is open", pCtx.DataSourceInstanceSettings.UID)
+	}
+	return permit{breaker: b, probe: probe}, nil
+}
+
+// done records the outcome of a request sent with the permit p.
+func (c *circuitBreakers) done(ctx context.Context, p permit, outcome requestOutcome) {
+	reportCircuitState(ctx, p.breaker.done(outcome, p.probe, c.now(), c.failureThreshold))
+}
+
+// circuitBreaker tracks the failed requests of a single data source.
+type circuitBreaker struct {
+	mtx      sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	// probing is true while the request that probes a half-open circuit breaker is in flight.
+	probing bool
+}
+
+// allow returns the state of the circuit breaker, whether the request probes a half-open circuit
+// breaker, and whether the request may be sent.
+func (b *circuitBreaker) allow(now time.Time, openDuration time.Duration) (circuitState, bool, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if now.Sub(b.openedAt) < openDuration {
+			return b.state, false, false
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		return b.state, true, true
+	case circuitHalfOpen:
+		if b.probing {
+			return b.state, false, false
+		}
+		b.probing = true
+		return b.state, true, true
+	default:
+		return b.state, false, true
+	}
+}
+
+// done records the outcome of a request and returns the new state of the circuit breaker.
+func (b *circuitBreaker) done(outcome requestOutcome, probe bool, now time.Time, failureThreshold int) circuitState {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+	switch outcome {
+	case requestSucceeded:
+		b.state = circuitClosed
+		b.failures = 0
+		b.probing = false
+	case requestFailed:
+		b.failures++
+		if b.state == circuitHalfOpen || b.failures >= failureThreshold {
+			b.state = circuitOpen
+			b.openedAt = now
+			b.probing = false
+		}
+	}
+	return b.state
+}
@@ -0,0 +1,164 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/handlertest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	pCtx := backend.PluginContext{
+		OrgID:                      1,
+		PluginID:                   pluginID,
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds1"},
+	}
+	cfg := setting.PluginLimitsSettings{
+		CircuitBreakerFailureThreshold: 2,
+		CircuitBreakerOpenDuration:     time.Minute,
+	}
+
+	setup := func(t *testing.T) (*handlertest.HandlerMiddlewareTest, *time.Time, *error) {
+		now := time.Now()
+		var queryErr error
+		breakers := newCircuitBreakers(cfg, func() time.Time { return now })
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(
+			backend.HandlerMiddlewareFunc(func(next backend.Handler) backend.Handler {
+				return &CircuitBreakerMiddleware{
+					BaseHandler: backend.NewBaseHandler(next),
+					breakers:    breakers,
+				}
+			}),
+		))
+		cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			return &backend.QueryDataResponse{}, queryErr
+		}
+		return cdt, &now, &queryErr
+	}
+
+	queryData := func(cdt *handlertest.HandlerMiddlewareTest) error {
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pCtx})
+		return err
+	}
+
+	t.Run("Should open after consecutive failures and reject requests", func(t *testing.T) {
+		cdt, _, queryErr := setup(t)
+		*queryErr = errors.New("connection refused")
+
+		require.Error(t, queryData(cdt))
+		require.NotErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+
+		*queryErr = nil
+		require.ErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+		err := cdt.MiddlewareHandler.CallResource(context.Background(), &backend.CallResourceRequest{PluginContext: pCtx}, nopCallResourceSender)
+		require.ErrorIs(t, err, plugins.ErrPluginCircuitOpen)
+
+		// Other data sources are not affected.
+		other := pCtx
+		other.DataSourceInstanceSettings = &backend.DataSourceInstanceSettings{UID: "ds2"}
+		_, err = cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: other})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should reset the failures after a successful request", func(t *testing.T) {
+		cdt, _, queryErr := setup(t)
+
+		*queryErr = errors.New("connection refused")
+		require.Error(t, queryData(cdt))
+		*queryErr = nil
+		require.NoError(t, queryData(cdt))
+		*queryErr = errors.New("connection refused")
+		require.NotErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+		require.NotErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+		require.ErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+	})
+
+	t.Run("Should let a probe through once the open duration passed", func(t *testing.T) {
+		cdt, now, queryErr := setup(t)
+		*queryErr = errors.New("connection refused")
+		require.Error(t, queryData(cdt))
+		require.Error(t, queryData(cdt))
+		require.ErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+
+		// A failed probe opens the circuit breaker again.
+		*now = now.Add(cfg.CircuitBreakerOpenDuration)
+		require.NotErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+		require.ErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+
+		// A successful probe closes it.
+		*now = now.Add(cfg.CircuitBreakerOpenDuration)
+		*queryErr = nil
+		require.NoError(t, queryData(cdt))
+		require.NoError(t, queryData(cdt))
+	})
+
+	t.Run("Should not count requests that say nothing about the data source", func(t *testing.T) {
+		cdt, _, queryErr := setup(t)
+
+		for _, err := range []error{context.Canceled, plugins.ErrPluginRequestLimitExceeded.Errorf("limit")} {
+			*queryErr = err
+			require.NotErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+			require.NotErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+		}
+		*queryErr = nil
+		require.NoError(t, queryData(cdt))
+	})
+
+	t.Run("Should count resource responses with server errors as failures", func(t *testing.T) {
+		cdt, _, _ := setup(t)
+		cdt.TestHandler.CallResourceFunc = func(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+			return sender.Send(&backend.CallResourceResponse{Status: http.StatusBadGateway})
+		}
+
+		for i := 0; i < cfg.CircuitBreakerFailureThreshold; i++ {
+			err := cdt.MiddlewareHandler.CallResource(context.Background(), &backend.CallResourceRequest{PluginContext: pCtx}, nopCallResourceSender)
+			require.NoError(t, err)
+		}
+		require.ErrorIs(t, queryData(cdt), plugins.ErrPluginCircuitOpen)
+	})
+}
+
+func TestQueryDataOutcome(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		responses backend.Responses
+		err       error
+		expected  requestOutcome
+	}{
+		{name: "no error", responses: backend.Responses{"A": {}}, expected: requestSucceeded},
+		{name: "request error", err: errors.New("error"), expected: requestFailed},
+		{name: "request cancelled", err: context.Canceled, expected: requestIgnored},
+		{
+			name:      "all queries failed",
+			responses: backend.Responses{"A": {Error: errors.New("error")}, "B": {Error: errors.New("error"), Status: backend.StatusBadGateway}},
+			expected:  requestFailed,
+		},
+		{
+			name:      "some queries failed",
+			responses: backend.Responses{"A": {Error: errors.New("error")}, "B": {}},
+			expected:  requestSucceeded,
+		},
+		{
+			name:      "invalid query",
+			responses: backend.Responses{"A": {Error: errors.New("error"), Status: backend.StatusBadRequest}},
+			expected:  requestSucceeded,
+		},
+		{
+			name:      "query cancelled",
+			responses: backend.Responses{"A": {Error: errors.New("error")}, "B": {Error: context.Canceled}},
+			expected:  requestIgnored,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, queryDataOutcome(&backend.QueryDataResponse{Responses: tc.responses}, tc.err))
+		})
+	}
+}
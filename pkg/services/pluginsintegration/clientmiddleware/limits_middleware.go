@@ -0,0 +1,192 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// errQueryTimeout is the cause of the context of a request that exceeded the query timeout.
+var errQueryTimeout = errors.New("query timeout exceeded")
+
+// NewLimitsMiddleware creates a new backend.HandlerMiddleware that limits the number of
+// concurrent QueryData and CallResource requests per data source and per organization,
+// and cancels requests that do not complete within the query timeout.
+func NewLimitsMiddleware(cfg setting.PluginLimitsSettings) backend.HandlerMiddleware {
+	dataSources := newSemaphores(cfg.MaxConcurrentRequestsPerDataSource)
+	orgs := newSemaphores(cfg.MaxConcurrentRequestsPerOrg)
+	return backend.HandlerMiddlewareFunc(func(next backend.Handler) backend.Handler {
+		return &LimitsMiddleware{
+			BaseHandler: backend.NewBaseHandler(next),
+			cfg:         cfg,
+			dataSources: dataSources,
+			orgs:        orgs,
+		}
+	})
+}
+
+type LimitsMiddleware struct {
+	backend.BaseHandler
+
+	cfg         setting.PluginLimitsSettings
+	dataSources *semaphores
+	orgs        *semaphores
+}
+
+func (m *LimitsMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil {
+		return m.BaseHandler.QueryData(ctx, req)
+	}
+
+	ctx, release, err := m.acquire(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resp, err := m.BaseHandler.QueryData(ctx, req)
+	if !timedOut(ctx) {
+		return resp, err
+	}
+	if err != nil {
+		return nil, m.timeoutError(req.PluginContext)
+	}
+	if resp != nil {
+		for refID, dr := range resp.Responses {
+			if dr.Error == nil {
+				continue
+			}
+			dr.Error = m.timeoutError(req.PluginContext)
+			dr.Status = backend.StatusTimeout
+			dr.ErrorSource = backend.ErrorSourceDownstream
+			resp.Responses[refID] = dr
+		}
+	}
+	return resp, nil
+}
+
+func (m *LimitsMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	if req == nil {
+		return m.BaseHandler.CallResource(ctx, req, sender)
+	}
+
+	ctx, release, err := m.acquire(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	err = m.BaseHandler.CallResource(ctx, req, sender)
+	if err != nil && timedOut(ctx) {
+		return m.timeoutError(req.PluginContext)
+	}
+	return err
+}
+
+// acquire takes a slot of the organization and of the data source of the request, waiting up to the
+// queue timeout for them to free up. It returns the context to send the request with, which is cancelled
+// once the query timeout is exceeded, and a function that frees the slots.
+func (m *LimitsMiddleware) acquire(ctx context.Context, pCtx backend.PluginContext) (context.Context, func(), error) {
+	waitCtx, cancelWait := context.WithTimeout(ctx, m.cfg.QueueTimeout)
+	defer cancelWait()
+
+	releaseOrg, ok := m.orgs.acquire(waitCtx, strconv.FormatInt(pCtx.OrgID, 10))
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, plugins.ErrPluginRequestLimitExceeded.Errorf("too many concurrent requests in organization %d", pCtx.OrgID)
+	}
+
+	releaseDataSource := func() {}
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil {
+		releaseDataSource, ok = m.dataSources.acquire(waitCtx, dataSourceKey(pCtx))
+		if !ok {
+			releaseOrg()
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, plugins.ErrPluginRequestLimitExceeded.Errorf("too many concurrent requests to data source %s", ds.UID)
+		}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if m.cfg.QueryTimeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(ctx, m.cfg.QueryTimeout, errQueryTimeout)
+	}
+
+	return ctx, func() {
+		cancel()
+		releaseDataSource()
+		releaseOrg()
+	}, nil
+}
+
+func (m *LimitsMiddleware) timeoutError(pCtx backend.PluginContext) error {
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil {
+		return plugins.ErrPluginRequestTimeout.Errorf("data source %s did not respond within %s", ds.UID, m.cfg.QueryTimeout)
+	}
+	return plugins.ErrPluginRequestTimeout.Errorf("plugin %s did not respond within %s", pCtx.PluginID, m.cfg.QueryTimeout)
+}
+
+// timedOut returns true if the context was cancelled because the query timeout was exceeded.
+func timedOut(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errQueryTimeout)
+}
+
+// dataSourceKey returns a key identifying the data source of a request across organizations.
+func dataSourceKey(pCtx backend.PluginContext) string {
+	return strconv.FormatInt(pCtx.OrgID, 10) + "/" + pCtx.DataSourceInstanceSettings.UID
+}
+
+// semaphores hands out a limited number of slots per key. A limit of 0 or less means no limit.
+type semaphores struct {
+	limit int
+
+	mtx   sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newSemaphores(limit int) *semaphores {
+	return &semaphores{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// acquire takes a slot of the key, waiting until one frees up or ctx is done. It returns a function
+// that frees the slot, and false if no slot could be taken.
+func (s *semaphores) acquire(ctx context.Context, key string) (func(), bool) {
+	if s.limit <= 0 {
+		return func() {}, true
+	}
+
+	s.mtx.Lock()
+	slots, ok := s.slots[key]
+	if !ok {
+		slots = make(chan struct{}, s.limit)
+		s.slots[key] = slots
+	}
+	s.mtx.Unlock()
+
+	release := func() { <-slots }
+	// Try without waiting first, so that a free slot is taken even if ctx is already done.
+	select {
+	case slots <- struct{}{}:
+		return release, true
+	default:
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return release, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
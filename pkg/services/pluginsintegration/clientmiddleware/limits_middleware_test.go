@@ -0,0 +1,169 @@
+package clientmiddleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/handlertest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLimitsMiddleware(t *testing.T) {
+	pluginContext := func(orgID int64, dsUID string) backend.PluginContext {
+		return backend.PluginContext{
+			OrgID:                      orgID,
+			PluginID:                   pluginID,
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: dsUID},
+		}
+	}
+
+	// blockingHandler makes QueryData block until release is closed, signalling started for each request.
+	blockingHandler := func(cdt *handlertest.HandlerMiddlewareTest) (started chan struct{}, release chan struct{}) {
+		started = make(chan struct{}, 10)
+		release = make(chan struct{})
+		cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			started <- struct{}{}
+			<-release
+			return &backend.QueryDataResponse{}, nil
+		}
+		return started, release
+	}
+
+	t.Run("Should reject requests over the data source limit", func(t *testing.T) {
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewLimitsMiddleware(setting.PluginLimitsSettings{
+			MaxConcurrentRequestsPerDataSource: 1,
+		})))
+		started, release := blockingHandler(cdt)
+
+		done := make(chan error)
+		go func() {
+			_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+			done <- err
+		}()
+		<-started
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+		require.ErrorIs(t, err, plugins.ErrPluginRequestLimitExceeded)
+
+		// Other data sources have their own limit.
+		go func() {
+			_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds2")})
+			done <- err
+		}()
+		<-started
+
+		close(release)
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+
+		// The slot is free again once the request completed.
+		_, err = cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should reject requests over the organization limit", func(t *testing.T) {
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewLimitsMiddleware(setting.PluginLimitsSettings{
+			MaxConcurrentRequestsPerOrg: 1,
+		})))
+		started, release := blockingHandler(cdt)
+
+		done := make(chan error)
+		go func() {
+			_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+			done <- err
+		}()
+		<-started
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds2")})
+		require.ErrorIs(t, err, plugins.ErrPluginRequestLimitExceeded)
+
+		err = cdt.MiddlewareHandler.CallResource(context.Background(), &backend.CallResourceRequest{PluginContext: pluginContext(1, "ds2")}, nopCallResourceSender)
+		require.ErrorIs(t, err, plugins.ErrPluginRequestLimitExceeded)
+
+		close(release)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("Should wait for a free slot up to the queue timeout", func(t *testing.T) {
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewLimitsMiddleware(setting.PluginLimitsSettings{
+			MaxConcurrentRequestsPerDataSource: 1,
+			QueueTimeout:                       time.Minute,
+		})))
+		started, release := blockingHandler(cdt)
+
+		done := make(chan error)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+				done <- err
+			}()
+		}
+		<-started
+		select {
+		case <-started:
+			t.Fatal("second request should wait for the first one")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("Should return timeout error when the query timeout is exceeded", func(t *testing.T) {
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewLimitsMiddleware(setting.PluginLimitsSettings{
+			QueryTimeout: 10 * time.Millisecond,
+		})))
+		cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		cdt.TestHandler.CallResourceFunc = func(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+		require.ErrorIs(t, err, plugins.ErrPluginRequestTimeout)
+
+		err = cdt.MiddlewareHandler.CallResource(context.Background(), &backend.CallResourceRequest{PluginContext: pluginContext(1, "ds1")}, nopCallResourceSender)
+		require.ErrorIs(t, err, plugins.ErrPluginRequestTimeout)
+	})
+
+	t.Run("Should replace query errors caused by the query timeout", func(t *testing.T) {
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewLimitsMiddleware(setting.PluginLimitsSettings{
+			QueryTimeout: 10 * time.Millisecond,
+		})))
+		cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			<-ctx.Done()
+			return &backend.QueryDataResponse{Responses: backend.Responses{
+				"A": {Error: ctx.Err()},
+			}}, nil
+		}
+
+		resp, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+		require.NoError(t, err)
+		require.ErrorIs(t, resp.Responses["A"].Error, plugins.ErrPluginRequestTimeout)
+		require.Equal(t, backend.StatusTimeout, resp.Responses["A"].Status)
+	})
+
+	t.Run("Should not replace errors of requests cancelled by the client", func(t *testing.T) {
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewLimitsMiddleware(setting.PluginLimitsSettings{
+			QueryTimeout: time.Minute,
+		})))
+		cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := cdt.MiddlewareHandler.QueryData(ctx, &backend.QueryDataRequest{PluginContext: pluginContext(1, "ds1")})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -2,6 +2,7 @@ package clientmiddleware
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -19,10 +20,15 @@ type pluginMetrics struct {
 	pluginRequestDuration        *prometheus.HistogramVec
 	pluginRequestSize            *prometheus.HistogramVec
 	pluginRequestDurationSeconds *prometheus.HistogramVec
+	pluginRequestsInFlight       *prometheus.GaugeVec
+	pluginRequestRejected        *prometheus.CounterVec
+	pluginCircuitBreakerState    *prometheus.GaugeVec
 }
 
 // MetricsMiddleware is a middleware that instruments plugin requests.
-// It tracks requests count, duration and size as prometheus metrics.
+// It tracks requests count, duration and size as prometheus metrics,
+// as well as the requests rejected by the LimitsMiddleware and the
+// CircuitBreakerMiddleware and the state of the circuit breakers.
 type MetricsMiddleware struct {
 	backend.BaseHandler
 	pluginMetrics
@@ -56,11 +62,29 @@ func newMetricsMiddleware(promRegisterer prometheus.Registerer, pluginRegistry r
 		Help:      "Plugin request duration in seconds",
 		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25},
 	}, append([]string{"source", "plugin_id", "endpoint", "status", "target"}, additionalLabels...))
+	pluginRequestsInFlight := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_requests_in_flight",
+		Help:      "The number of plugin requests in flight",
+	}, []string{"plugin_id", "endpoint", "target"})
+	pluginRequestRejected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "plugin_request_rejected_total",
+		Help:      "The total amount of plugin requests rejected by concurrency limits, timeouts or open circuit breakers",
+	}, []string{"plugin_id", "endpoint", "target", "reason"})
+	pluginCircuitBreakerState := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_circuit_breaker_state",
+		Help:      "The state of the circuit breaker of a data source: 0 is closed, 1 is half-open and 2 is open",
+	}, []string{"plugin_id", "datasource_uid"})
 	promRegisterer.MustRegister(
 		pluginRequestCounter,
 		pluginRequestDuration,
 		pluginRequestSize,
 		pluginRequestDurationSeconds,
+		pluginRequestsInFlight,
+		pluginRequestRejected,
+		pluginCircuitBreakerState,
 	)
 	return &MetricsMiddleware{
 		pluginMetrics: pluginMetrics{
@@ -68,6 +92,9 @@ func newMetricsMiddleware(promRegisterer prometheus.Registerer, pluginRegistry r
 			pluginRequestDuration:        pluginRequestDuration,
 			pluginRequestSize:            pluginRequestSize,
 			pluginRequestDurationSeconds: pluginRequestDurationSeconds,
+			pluginRequestsInFlight:       pluginRequestsInFlight,
+			pluginRequestRejected:        pluginRequestRejected,
+			pluginCircuitBreakerState:    pluginCircuitBreakerState,
 		},
 		pluginRegistry: pluginRegistry,
 	}
@@ -109,13 +136,25 @@ func (m *MetricsMiddleware) instrumentPluginRequest(ctx context.Context, pluginC
 		return err
 	}
 
+	endpoint := backend.EndpointFromContext(ctx)
+	inFlight := m.pluginRequestsInFlight.WithLabelValues(pluginCtx.PluginID, string(endpoint), target)
+	inFlight.Inc()
+	ctx, circuitReport := withCircuitStateReport(ctx)
+
 	start := time.Now()
 
 	status, err := fn(ctx)
 	elapsed := time.Since(start)
+	inFlight.Dec()
 
 	statusSource := backend.ErrorSourceFromContext(ctx)
-	endpoint := backend.EndpointFromContext(ctx)
+
+	if reason := rejectionReason(err); reason != "" {
+		m.pluginRequestRejected.WithLabelValues(pluginCtx.PluginID, string(endpoint), target, reason).Inc()
+	}
+	if circuitReport.reported && pluginCtx.DataSourceInstanceSettings != nil {
+		m.pluginCircuitBreakerState.WithLabelValues(pluginCtx.PluginID, pluginCtx.DataSourceInstanceSettings.UID).Set(float64(circuitReport.state))
+	}
 
 	pluginRequestDurationWithLabels := m.pluginRequestDuration.WithLabelValues(pluginCtx.PluginID, string(endpoint), target, string(statusSource))
 	pluginRequestCounterWithLabels := m.pluginRequestCounter.WithLabelValues(pluginCtx.PluginID, string(endpoint), status.String(), target, string(statusSource))
@@ -138,6 +177,45 @@ func (m *MetricsMiddleware) instrumentPluginRequest(ctx context.Context, pluginC
 	return err
 }
 
+// rejectionReason returns the value for the "reason" Prometheus label if err is returned
+// by the LimitsMiddleware or the CircuitBreakerMiddleware, or an empty string otherwise.
+func rejectionReason(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, plugins.ErrPluginRequestLimitExceeded):
+		return "concurrency_limit"
+	case errors.Is(err, plugins.ErrPluginRequestTimeout):
+		return "timeout"
+	case errors.Is(err, plugins.ErrPluginCircuitOpen):
+		return "circuit_open"
+	default:
+		return ""
+	}
+}
+
+type circuitStateReportKey struct{}
+
+// circuitStateReport is added to the context of a request by the MetricsMiddleware so that the
+// CircuitBreakerMiddleware, which comes after it, can report the state of the circuit breaker.
+type circuitStateReport struct {
+	reported bool
+	state    circuitState
+}
+
+func withCircuitStateReport(ctx context.Context) (context.Context, *circuitStateReport) {
+	report := &circuitStateReport{}
+	return context.WithValue(ctx, circuitStateReportKey{}, report), report
+}
+
+// reportCircuitState records the state of the circuit breaker for the request, if its context has a report.
+func reportCircuitState(ctx context.Context, state circuitState) {
+	if report, ok := ctx.Value(circuitStateReportKey{}).(*circuitStateReport); ok {
+		report.reported = true
+		report.state = state
+	}
+}
+
 func (m *MetricsMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	var requestSize float64
 	for _, v := range req.Queries {
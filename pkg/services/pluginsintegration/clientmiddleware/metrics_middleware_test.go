@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/handlertest"
@@ -17,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/instrumentationutils"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
@@ -247,6 +249,49 @@ func TestInstrumentationMiddlewareStatusSource(t *testing.T) {
 	})
 }
 
+func TestInstrumentationMiddlewareLimits(t *testing.T) {
+	pCtx := backend.PluginContext{
+		OrgID:                      1,
+		PluginID:                   pluginID,
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds1"},
+	}
+
+	promRegistry := prometheus.NewRegistry()
+	pluginsRegistry := fakes.NewFakePluginRegistry()
+	require.NoError(t, pluginsRegistry.Add(context.Background(), &plugins.Plugin{
+		JSONData: plugins.JSONData{ID: pluginID, Backend: true},
+	}))
+	metricsMw := newMetricsMiddleware(promRegistry, pluginsRegistry)
+	cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(
+		backend.HandlerMiddlewareFunc(func(next backend.Handler) backend.Handler {
+			metricsMw.BaseHandler = backend.NewBaseHandler(next)
+			return metricsMw
+		}),
+		NewCircuitBreakerMiddleware(setting.PluginLimitsSettings{
+			CircuitBreakerFailureThreshold: 1,
+			CircuitBreakerOpenDuration:     time.Hour,
+		}),
+	))
+	cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+		require.Equal(t, 1.0, testutil.ToFloat64(metricsMw.pluginRequestsInFlight.WithLabelValues(pluginID, string(backend.EndpointQueryData), string(backendplugin.TargetUnknown))))
+		return nil, errors.New("connection refused")
+	}
+
+	circuitState := metricsMw.pluginCircuitBreakerState.WithLabelValues(pluginID, "ds1")
+	rejected := metricsMw.pluginRequestRejected.WithLabelValues(pluginID, string(backend.EndpointQueryData), string(backendplugin.TargetUnknown), "circuit_open")
+
+	_, err := cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pCtx})
+	require.Error(t, err)
+	require.Equal(t, float64(circuitOpen), testutil.ToFloat64(circuitState))
+	require.Equal(t, 0.0, testutil.ToFloat64(rejected))
+	require.Equal(t, 0.0, testutil.ToFloat64(metricsMw.pluginRequestsInFlight.WithLabelValues(pluginID, string(backend.EndpointQueryData), string(backendplugin.TargetUnknown))))
+
+	_, err = cdt.MiddlewareHandler.QueryData(context.Background(), &backend.QueryDataRequest{PluginContext: pCtx})
+	require.ErrorIs(t, err, plugins.ErrPluginCircuitOpen)
+	require.Equal(t, float64(circuitOpen), testutil.ToFloat64(circuitState))
+	require.Equal(t, 1.0, testutil.ToFloat64(rejected))
+}
+
 // checkHistogram is a utility function that checks if a histogram with the given name and label values exists
 // and has been observed at least once.
 func checkHistogram(promRegistry *prometheus.Registry, expMetricName string, expLabels map[string]string) error {
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana-aws-sdk/pkg/awsds"
 	"github.com/grafana/grafana-azure-sdk-go/v2/azsettings"
 	"github.com/grafana/grafana/pkg/plugins/auth"
+	sqlite "github.com/grafana/grafana/pkg/tsdb/grafana-sqlite-datasource"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/proxy"
@@ -158,6 +159,10 @@ func (s *RequestConfigProvider) PluginRequestConfig(ctx context.Context, pluginI
 	m[backend.SQLMaxIdleConnsDefault] = strconv.Itoa(s.cfg.SQLDatasourceMaxIdleConnsDefault)
 	m[backend.SQLMaxConnLifetimeSecondsDefault] = strconv.Itoa(s.cfg.SQLDatasourceMaxConnLifetimeDefault)
 
+	if pluginID == sqlite.PluginID && len(s.cfg.SQLiteDatasourceAllowedPaths) > 0 {
+		m[sqlite.AllowedPathsKey] = strings.Join(s.cfg.SQLiteDatasourceAllowedPaths, ",")
+	}
+
 	if s.cfg.ResponseLimit > 0 {
 		m[backend.ResponseLimit] = strconv.FormatInt(s.cfg.ResponseLimit, 10)
 	}
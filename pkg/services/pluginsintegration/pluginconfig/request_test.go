@@ -225,6 +225,26 @@ func TestRequestConfigProvider_PluginRequestConfig_SQL(t *testing.T) {
 	})
 }
 
+func TestRequestConfigProvider_PluginRequestConfig_SQLite(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.SqlDatasourceSQLiteAllowedPaths = []string{"/var/lib/edge/*.db", "/data/metrics.db"}
+
+	pCfg, err := ProvidePluginInstanceConfig(cfg, setting.ProvideProvider(cfg), featuremgmt.WithFeatures())
+	require.NoError(t, err)
+
+	p := NewRequestConfigProvider(pCfg)
+
+	t.Run("Uses the allowed paths for the SQLite plugin", func(t *testing.T) {
+		require.Subset(t, p.PluginRequestConfig(context.Background(), "grafana-sqlite-datasource", nil), map[string]string{
+			"GF_SQL_SQLITE_ALLOWED_PATHS": "/var/lib/edge/*.db,/data/metrics.db",
+		})
+	})
+
+	t.Run("Doesn't use the allowed paths for other plugins", func(t *testing.T) {
+		require.NotContains(t, p.PluginRequestConfig(context.Background(), "mysql", nil), "GF_SQL_SQLITE_ALLOWED_PATHS")
+	})
+}
+
 func TestRequestConfigProvider_PluginRequestConfig_concurrentQueryCount(t *testing.T) {
 	t.Run("Uses the configured concurrent query count", func(t *testing.T) {
 		cfg := setting.NewCfg()
@@ -104,7 +104,6 @@ var WireSet = wire.NewSet(
 	registry.ProvideService,
 	wire.Bind(new(registry.Service), new(*registry.InMemory)),
 	repo.ProvideService,
-	wire.Bind(new(repo.Service), new(*repo.Manager)),
 	licensing.ProvideLicensing,
 	wire.Bind(new(plugins.Licensing), new(*licensing.Service)),
 	wire.Bind(new(sources.Registry), new(*sources.Service)),
@@ -188,9 +187,20 @@ func CreateMiddlewares(cfg *setting.Cfg, oAuthTokenService oauthtoken.OAuthToken
 		clientmiddleware.NewOAuthTokenMiddleware(oAuthTokenService),
 		clientmiddleware.NewCookiesMiddleware(skipCookiesNames),
 		clientmiddleware.NewCachingMiddlewareWithFeatureManager(cachingService, features),
-		clientmiddleware.NewForwardIDMiddleware(),
 	)
 
+	// The circuit breaker comes before the limits, so that requests that time out count as failures
+	// and requests rejected by an open circuit breaker don't take up a slot.
+	if cfg.PluginLimits.CircuitBreakerFailureThreshold > 0 {
+		middlewares = append(middlewares, clientmiddleware.NewCircuitBreakerMiddleware(cfg.PluginLimits))
+	}
+
+	if cfg.PluginLimits.LimitsEnabled() {
+		middlewares = append(middlewares, clientmiddleware.NewLimitsMiddleware(cfg.PluginLimits))
+	}
+
+	middlewares = append(middlewares, clientmiddleware.NewForwardIDMiddleware())
+
 	if cfg.SendUserHeader {
 		middlewares = append(middlewares, clientmiddleware.NewUserHeaderMiddleware())
 	}
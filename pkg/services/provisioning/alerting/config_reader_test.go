@@ -19,6 +19,7 @@ const (
 	testFileDasboardTypoSupport         = "./testdata/alert_rules/dasboard-typo-support"
 	testFileMultipleRules               = "./testdata/alert_rules/multiple-rules"
 	testFileMultipleFiles               = "./testdata/alert_rules/multiple-files"
+	testFilePrometheusRules             = "./testdata/alert_rules/prometheus-rules"
 	testFileCorrectProperties_cp        = "./testdata/contact_points/correct-properties"
 	testFileCorrectPropertiesWithOrg_cp = "./testdata/contact_points/correct-properties-with-org"
 	testFileEmptyUID                    = "./testdata/contact_points/empty-uid"
@@ -75,6 +76,21 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, ruleFiles[0].Groups, 2)
 	})
+	t.Run("the config reader should convert Prometheus rule groups", func(t *testing.T) {
+		ruleFiles, err := configReader.readConfig(ctx, testFilePrometheusRules)
+		require.NoError(t, err)
+		require.Len(t, ruleFiles[0].Groups, 1)
+		group := ruleFiles[0].Groups[0]
+		require.Equal(t, "node", group.Title)
+		require.Equal(t, "my_folder", group.FolderFullpath)
+		require.Equal(t, int64(2), group.OrgID)
+		require.Equal(t, int64(30), group.Interval)
+		require.Len(t, group.Rules, 2)
+		require.Equal(t, "HighLoad", group.Rules[0].Title)
+		require.Equal(t, int64(2), group.Rules[0].OrgID)
+		require.NotEmpty(t, group.Rules[0].UID)
+		require.NotNil(t, group.Rules[1].Record)
+	})
 	t.Run("the config reader should support .yaml,.yml and .json files", func(t *testing.T) {
 		ruleFiles, err := configReader.readConfig(ctx, testFileSupportedFiletypes)
 		require.NoError(t, err)
@@ -0,0 +1,76 @@
+package alerting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PrometheusRulesV1 provisions the groups of a Prometheus rule file as Grafana-managed alert rules.
+type PrometheusRulesV1 struct {
+	OrgID         values.Int64Value                 `json:"orgId" yaml:"orgId"`
+	Folder        values.StringValue                `json:"folder" yaml:"folder"`
+	DatasourceUID values.StringValue                `json:"datasourceUid" yaml:"datasourceUid"`
+	Groups        []definitions.PrometheusRuleGroup `json:"groups" yaml:"groups"`
+}
+
+func (promV1 *PrometheusRulesV1) mapToModel() ([]models.AlertRuleGroupWithFolderFullpath, error) {
+	orgID := promV1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	folder := promV1.Folder.Value()
+	if strings.TrimSpace(folder) == "" {
+		return nil, errors.New("prometheus rules have no folder set")
+	}
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:   promV1.DatasourceUID.Value(),
+		DefaultInterval: setting.DefaultRuleEvaluationInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]models.AlertRuleGroupWithFolderFullpath, 0, len(promV1.Groups))
+	for _, group := range promV1.Groups {
+		rules, err := converter.ConvertGroup(orgID, group)
+		if err != nil {
+			return nil, err
+		}
+		titles := make(map[string]int, len(rules))
+		for i := range rules {
+			rules[i].UID = prometheusRuleUID(orgID, folder, group.Name, rules[i].Title, titles[rules[i].Title])
+			titles[rules[i].Title]++
+		}
+		ruleGroup := models.AlertRuleGroupWithFolderFullpath{
+			AlertRuleGroup: &models.AlertRuleGroup{
+				Title: group.Name,
+				Rules: rules,
+			},
+			OrgID:          orgID,
+			FolderFullpath: folder,
+		}
+		if len(rules) > 0 {
+			ruleGroup.Interval = rules[0].IntervalSeconds
+		} else {
+			ruleGroup.Interval = int64(setting.DefaultRuleEvaluationInterval.Seconds())
+		}
+		groups = append(groups, ruleGroup)
+	}
+	return groups, nil
+}
+
+// prometheusRuleUID returns a stable UID for a rule of a Prometheus rule group, so that provisioning the
+// same file again updates the rules it created. Rules with the same title are told apart by their occurrence.
+func prometheusRuleUID(orgID int64, folder, group, title string, occurrence int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s\x00%s\x00%d", orgID, folder, group, title, occurrence)))
+	return "prom-" + hex.EncodeToString(sum[:])[:20]
+}
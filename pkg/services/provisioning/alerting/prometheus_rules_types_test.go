@@ -0,0 +1,69 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const prometheusRulesV1 = `
+folder: my_folder
+datasourceUid: prom-uid
+groups:
+  - name: node
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+      - alert: InstanceDown
+        expr: up{job="node"} == 0
+`
+
+func TestPrometheusRules(t *testing.T) {
+	parse := func(t *testing.T, raw string) PrometheusRulesV1 {
+		t.Helper()
+		var promV1 PrometheusRulesV1
+		require.NoError(t, yaml.Unmarshal([]byte(raw), &promV1))
+		return promV1
+	}
+
+	t.Run("valid rules should map to rule groups with stable UIDs", func(t *testing.T) {
+		promV1 := parse(t, prometheusRulesV1)
+		groups, err := promV1.mapToModel()
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, int64(1), groups[0].OrgID)
+		require.Equal(t, int64(60), groups[0].Interval)
+		rules := groups[0].Rules
+		require.Len(t, rules, 2)
+		require.NotEqual(t, rules[0].UID, rules[1].UID)
+		for _, r := range rules {
+			require.True(t, util.IsValidShortUID(r.UID))
+		}
+
+		again, err := promV1.mapToModel()
+		require.NoError(t, err)
+		require.Equal(t, rules[0].UID, again[0].Rules[0].UID)
+		require.Equal(t, rules[1].UID, again[0].Rules[1].UID)
+	})
+	t.Run("rules without a folder should error", func(t *testing.T) {
+		promV1 := parse(t, prometheusRulesV1)
+		require.NoError(t, yaml.Unmarshal([]byte(`""`), &promV1.Folder))
+		_, err := promV1.mapToModel()
+		require.Error(t, err)
+	})
+	t.Run("rules without a data source should error", func(t *testing.T) {
+		promV1 := parse(t, prometheusRulesV1)
+		require.NoError(t, yaml.Unmarshal([]byte(`""`), &promV1.DatasourceUID))
+		_, err := promV1.mapToModel()
+		require.Error(t, err)
+	})
+	t.Run("an invalid expression should error", func(t *testing.T) {
+		promV1 := parse(t, prometheusRulesV1)
+		promV1.Groups[0].Rules[0].Expr = "sum(up"
+		_, err := promV1.mapToModel()
+		require.Error(t, err)
+	})
+}
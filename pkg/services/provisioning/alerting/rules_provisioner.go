@@ -64,7 +64,7 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 					return err
 				}
 			}
-			err = prov.ruleService.UpdateRuleGroup(ctx, u, folderUID, group.Title, group.Interval)
+			err = prov.ruleService.UpdateRuleGroup(ctx, u, folderUID, group.Title, group.Interval, alert_models.ProvenanceFile)
 			if err != nil {
 				return err
 			}
@@ -62,21 +62,23 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 }
 
 type AlertRuleV1 struct {
-	UID                  values.StringValue      `json:"uid" yaml:"uid"`
-	Title                values.StringValue      `json:"title" yaml:"title"`
-	Condition            values.StringValue      `json:"condition" yaml:"condition"`
-	Data                 []QueryV1               `json:"data" yaml:"data"`
-	DasboardUID          values.StringValue      `json:"dasboardUid" yaml:"dasboardUid"` // TODO: Grandfathered typo support. TODO: This should be removed in V2.
-	DashboardUID         values.StringValue      `json:"dashboardUid" yaml:"dashboardUid"`
-	PanelID              values.Int64Value       `json:"panelId" yaml:"panelId"`
-	NoDataState          values.StringValue      `json:"noDataState" yaml:"noDataState"`
-	ExecErrState         values.StringValue      `json:"execErrState" yaml:"execErrState"`
-	For                  values.StringValue      `json:"for" yaml:"for"`
-	Annotations          values.StringMapValue   `json:"annotations" yaml:"annotations"`
-	Labels               values.StringMapValue   `json:"labels" yaml:"labels"`
-	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
-	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
-	Record               *RecordV1               `json:"record" yaml:"record"`
+	UID                         values.StringValue      `json:"uid" yaml:"uid"`
+	Title                       values.StringValue      `json:"title" yaml:"title"`
+	Condition                   values.StringValue      `json:"condition" yaml:"condition"`
+	Data                        []QueryV1               `json:"data" yaml:"data"`
+	DasboardUID                 values.StringValue      `json:"dasboardUid" yaml:"dasboardUid"` // TODO: Grandfathered typo support. TODO: This should be removed in V2.
+	DashboardUID                values.StringValue      `json:"dashboardUid" yaml:"dashboardUid"`
+	PanelID                     values.Int64Value       `json:"panelId" yaml:"panelId"`
+	NoDataState                 values.StringValue      `json:"noDataState" yaml:"noDataState"`
+	ExecErrState                values.StringValue      `json:"execErrState" yaml:"execErrState"`
+	For                         values.StringValue      `json:"for" yaml:"for"`
+	Annotations                 values.StringMapValue   `json:"annotations" yaml:"annotations"`
+	Labels                      values.StringMapValue   `json:"labels" yaml:"labels"`
+	IsPaused                    values.BoolValue        `json:"isPaused" yaml:"isPaused"`
+	NotificationSettings        *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
+	Record                      *RecordV1               `json:"record" yaml:"record"`
+	KeepFiringFor               values.StringValue      `json:"keepFiringFor" yaml:"keepFiringFor"`
+	MissingSeriesEvalsToResolve values.Int64Value       `json:"missingSeriesEvalsToResolve" yaml:"missingSeriesEvalsToResolve"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -108,6 +110,23 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	}
 	alertRule.For = time.Duration(duration)
 
+	keepFiringFor := model.Duration(0)
+	if rule.KeepFiringFor.Value() != "" {
+		var err error
+		keepFiringFor, err = model.ParseDuration(rule.KeepFiringFor.Value())
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse 'keepFiringFor' field: %w", alertRule.Title, err)
+		}
+	}
+	alertRule.KeepFiringFor = time.Duration(keepFiringFor)
+
+	if evals := rule.MissingSeriesEvalsToResolve.Value(); evals != 0 {
+		if evals < 0 {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: 'missingSeriesEvalsToResolve' must be at least 1", alertRule.Title)
+		}
+		alertRule.MissingSeriesEvalsToResolve = &evals
+	}
+
 	dasboardUID := rule.DasboardUID.Value()
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = withFallback(dashboardUID, dasboardUID) // Use correct spelling over supported typo.
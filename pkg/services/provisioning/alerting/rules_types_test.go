@@ -128,6 +128,34 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule without keep firing for and missing series evaluations should use the defaults", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ruleMapped.KeepFiringFor)
+		require.Nil(t, ruleMapped.MissingSeriesEvalsToResolve)
+	})
+	t.Run("a rule with keep firing for and missing series evaluations should map them", func(t *testing.T) {
+		rule := validRuleV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("5m"), &rule.KeepFiringFor))
+		require.NoError(t, yaml.Unmarshal([]byte("3"), &rule.MissingSeriesEvalsToResolve))
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+		require.Equal(t, util.Pointer[int64](3), ruleMapped.MissingSeriesEvalsToResolve)
+	})
+	t.Run("a rule with an invalid keep firing for duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("10x"), &rule.KeepFiringFor))
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with negative missing series evaluations should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("-1"), &rule.MissingSeriesEvalsToResolve))
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -41,6 +41,7 @@ type AlertingFileV1 struct {
 	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
 	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
 	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	PrometheusRules     []PrometheusRulesV1     `json:"prometheusRules" yaml:"prometheusRules"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -128,6 +129,13 @@ func (fileV1 *AlertingFileV1) mapRules(alertingFile *AlertingFile) error {
 		}
 		alertingFile.Groups = append(alertingFile.Groups, group)
 	}
+	for _, promV1 := range fileV1.PrometheusRules {
+		groups, err := promV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.Groups = append(alertingFile.Groups, groups...)
+	}
 	for _, ruleDeleteV1 := range fileV1.DeleteRules {
 		orgID := ruleDeleteV1.OrgID.Value()
 		if orgID < 1 {
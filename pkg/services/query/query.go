@@ -45,6 +45,7 @@ func ProvideService(
 	pluginRequestValidator validations.PluginRequestValidator,
 	pluginClient plugins.Client,
 	pCtxProvider *plugincontext.Provider,
+	savedQueryResolver SavedQueryResolver,
 ) *ServiceImpl {
 	g := &ServiceImpl{
 		cfg:                    cfg,
@@ -53,6 +54,7 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		pluginClient:           pluginClient,
 		pCtxProvider:           pCtxProvider,
+		savedQueryResolver:     savedQueryResolver,
 		log:                    log.New("query_data"),
 		concurrentQueryLimit:   cfg.SectionWithEnvOverrides("query").Key("concurrent_query_limit").MustInt(runtime.NumCPU()),
 	}
@@ -66,6 +68,11 @@ type Service interface {
 	QueryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error)
 }
 
+// SavedQueryResolver replaces the queries referencing a query of the query library with the saved query.
+type SavedQueryResolver interface {
+	ResolveSavedQueryReferences(ctx context.Context, user identity.Requester, queries []*simplejson.Json) error
+}
+
 // Gives us compile time error if the service does not adhere to the contract of the interface
 var _ Service = (*ServiceImpl)(nil)
 
@@ -76,6 +83,7 @@ type ServiceImpl struct {
 	pluginRequestValidator validations.PluginRequestValidator
 	pluginClient           plugins.Client
 	pCtxProvider           *plugincontext.Provider
+	savedQueryResolver     SavedQueryResolver
 	log                    log.Logger
 	concurrentQueryLimit   int
 }
@@ -88,6 +96,16 @@ func (s *ServiceImpl) Run(ctx context.Context) error {
 
 // QueryData processes queries and returns query responses. It handles queries to single or mixed datasources, as well as expressions.
 func (s *ServiceImpl) QueryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	// Replace the references to the query library with the saved queries
+	if hasSavedQueryReferences(reqDTO.Queries) {
+		if s.savedQueryResolver == nil {
+			return nil, ErrSavedQueryNotSupported
+		}
+		if err := s.savedQueryResolver.ResolveSavedQueryReferences(ctx, user, reqDTO.Queries); err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse the request into parsed queries grouped by datasource uid
 	parsedReq, err := s.parseMetricRequest(ctx, user, skipDSCache, reqDTO)
 	if err != nil {
@@ -106,6 +124,16 @@ func (s *ServiceImpl) QueryData(ctx context.Context, user identity.Requester, sk
 	return s.executeConcurrentQueries(ctx, user, skipDSCache, reqDTO, parsedReq.parsedQueries)
 }
 
+// hasSavedQueryReferences returns true if one of the queries references a query of the query library.
+func hasSavedQueryReferences(queries []*simplejson.Json) bool {
+	for _, query := range queries {
+		if _, ok := query.CheckGet("savedQuery"); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // splitResponse contains the results of a concurrent data source query - the response and any headers
 type splitResponse struct {
 	responses backend.Responses
@@ -451,6 +451,82 @@ func TestQueryDataMultipleSources(t *testing.T) {
 	})
 }
 
+func TestQueryDataSavedQueries(t *testing.T) {
+	t.Run("queries the saved query of the query library", func(t *testing.T) {
+		tc := setup(t)
+		tc.savedQueryResolver.resolve = func(queries []*simplejson.Json) error {
+			queries[0] = simplejson.NewFromAny(map[string]any{
+				"refId":      "A",
+				"datasource": map[string]any{"uid": "ds1", "type": "mysql"},
+				"queryType":  "saved",
+			})
+			return nil
+		}
+		mr := metricRequestWithQueries(t, `{
+			"refId": "A",
+			"savedQuery": {
+				"uid": "saved-query"
+			}
+		}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, mr)
+		require.NoError(t, err)
+		require.Equal(t, 1, tc.savedQueryResolver.calls)
+		require.NotNil(t, tc.pluginContext.req)
+		require.Len(t, tc.pluginContext.req.Queries, 1)
+		assert.Equal(t, "saved", tc.pluginContext.req.Queries[0].QueryType)
+		assert.Equal(t, "ds1", tc.pluginContext.req.PluginContext.DataSourceInstanceSettings.UID)
+	})
+
+	t.Run("returns the error of the query library without querying the datasource", func(t *testing.T) {
+		tc := setup(t)
+		expectedErr := errors.New("saved query not found")
+		tc.savedQueryResolver.resolve = func(queries []*simplejson.Json) error {
+			return expectedErr
+		}
+		mr := metricRequestWithQueries(t, `{
+			"refId": "A",
+			"savedQuery": {
+				"uid": "saved-query"
+			}
+		}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, mr)
+		require.ErrorIs(t, err, expectedErr)
+		assert.Nil(t, tc.pluginContext.req)
+	})
+
+	t.Run("returns an error for saved query references without a query library", func(t *testing.T) {
+		tc := setup(t)
+		tc.queryService.savedQueryResolver = nil
+		mr := metricRequestWithQueries(t, `{
+			"refId": "A",
+			"savedQuery": {
+				"uid": "saved-query"
+			}
+		}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, mr)
+		require.ErrorIs(t, err, ErrSavedQueryNotSupported)
+		assert.Nil(t, tc.pluginContext.req)
+	})
+
+	t.Run("does not use the query library without saved query references", func(t *testing.T) {
+		tc := setup(t)
+		mr := metricRequestWithQueries(t, `{
+			"refId": "A",
+			"datasource": {
+				"uid": "ds1",
+				"type": "mysql"
+			}
+		}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, mr)
+		require.NoError(t, err)
+		assert.Equal(t, 0, tc.savedQueryResolver.calls)
+	})
+}
+
 func setup(t *testing.T) *testContext {
 	dss := []*datasources.DataSource{
 		{UID: "gIEkMvIVz", Type: "postgres"},
@@ -484,11 +560,13 @@ func setup(t *testing.T) *testContext {
 	)
 	exprService := expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, pc, pCtxProvider,
 		featuremgmt.WithFeatures(), nil, tracing.InitializeTracerForTest())
-	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, pc, pCtxProvider) // provider belonging to this package
+	sr := &fakeSavedQueryResolver{}
+	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, pc, pCtxProvider, sr) // provider belonging to this package
 	return &testContext{
 		pluginContext:          pc,
 		secretStore:            ss,
 		pluginRequestValidator: rv,
+		savedQueryResolver:     sr,
 		queryService:           queryService,
 		signedInUser:           &user.SignedInUser{OrgID: 1, Login: "login", Name: "name", Email: "email", OrgRole: identity.RoleAdmin},
 	}
@@ -498,6 +576,7 @@ type testContext struct {
 	pluginContext          *fakePluginClient
 	secretStore            secretskvs.SecretsKVStore
 	pluginRequestValidator *fakePluginRequestValidator
+	savedQueryResolver     *fakeSavedQueryResolver
 	queryService           *ServiceImpl // implementation belonging to this package
 	signedInUser           *user.SignedInUser
 }
@@ -545,6 +624,19 @@ func (c *fakeDataSourceCache) GetDatasourceByUID(ctx context.Context, datasource
 	return nil, fmt.Errorf("not found")
 }
 
+type fakeSavedQueryResolver struct {
+	resolve func(queries []*simplejson.Json) error
+	calls   int
+}
+
+func (r *fakeSavedQueryResolver) ResolveSavedQueryReferences(ctx context.Context, user identity.Requester, queries []*simplejson.Json) error {
+	r.calls++
+	if r.resolve == nil {
+		return nil
+	}
+	return r.resolve(queries)
+}
+
 type fakePluginClient struct {
 	plugins.Client
 	req *backend.QueryDataRequest
@@ -0,0 +1,57 @@
+package queryhistory
+
+import (
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	ActionSavedQueriesRead   = "querylibrary:read"
+	ActionSavedQueriesCreate = "querylibrary:create"
+	ActionSavedQueriesWrite  = "querylibrary:write"
+	ActionSavedQueriesDelete = "querylibrary:delete"
+)
+
+var (
+	ScopeSavedQueriesProvider = ac.NewScopeProvider("querylibrary")
+	ScopeSavedQueriesAll      = ScopeSavedQueriesProvider.GetResourceAllScope()
+	ScopeSavedQueriesPrefix   = ac.ScopePrefix(ScopeSavedQueriesProvider.GetResourceScopeUID(""))
+)
+
+var (
+	savedQueriesReaderRole = ac.RoleDTO{
+		Name:        "fixed:querylibrary:reader",
+		DisplayName: "Query library reader",
+		Description: "Read and use the queries of the query library",
+		Group:       "Query library",
+		Permissions: []ac.Permission{
+			{Action: ActionSavedQueriesRead, Scope: ScopeSavedQueriesAll},
+		},
+	}
+
+	savedQueriesWriterRole = ac.RoleDTO{
+		Name:        "fixed:querylibrary:writer",
+		DisplayName: "Query library writer",
+		Description: "Create, update, delete, read and use the queries of the query library",
+		Group:       "Query library",
+		Permissions: []ac.Permission{
+			{Action: ActionSavedQueriesRead, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesCreate},
+			{Action: ActionSavedQueriesWrite, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesDelete, Scope: ScopeSavedQueriesAll},
+		},
+	}
+)
+
+func declareFixedRoles(service ac.Service) error {
+	reader := ac.RoleRegistration{
+		Role:   savedQueriesReaderRole,
+		Grants: []string{string(org.RoleViewer)},
+	}
+	writer := ac.RoleRegistration{
+		Role:   savedQueriesWriterRole,
+		Grants: []string{string(org.RoleEditor)},
+	}
+
+	return service.DeclareFixedRoles(reader, writer)
+}
@@ -0,0 +1,314 @@
+package queryhistory
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *QueryHistoryService) registerLibraryAPIEndpoints() {
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := ScopeSavedQueriesProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	s.RouteRegister.Group("/api/query-library", func(entities routing.RouteRegister) {
+		entities.Post("/", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesCreate)), routing.Wrap(s.createSavedQueryHandler))
+		entities.Get("/", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesRead)), routing.Wrap(s.searchSavedQueriesHandler))
+		entities.Get("/:uid", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesRead, uidScope)), routing.Wrap(s.getSavedQueryHandler))
+		entities.Put("/:uid", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesWrite, uidScope)), routing.Wrap(s.updateSavedQueryHandler))
+		entities.Delete("/:uid", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesDelete, uidScope)), routing.Wrap(s.deleteSavedQueryHandler))
+		entities.Get("/:uid/versions", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesRead, uidScope)), routing.Wrap(s.getSavedQueryVersionsHandler))
+		entities.Get("/:uid/versions/:version", middleware.ReqSignedIn, authorize(ac.EvalPermission(ActionSavedQueriesRead, uidScope)), routing.Wrap(s.getSavedQueryVersionHandler))
+	})
+}
+
+// swagger:route POST /query-library query_library createSavedQuery
+//
+// Add query to the query library.
+//
+// Adds a query to the query library of the org, or of a team of the user. The query can be promoted from an entry of the query history of the user.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *QueryHistoryService) createSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateSavedQueryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query, err := s.CreateSavedQuery(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add query to the query library", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route GET /query-library query_library searchSavedQueries
+//
+// Query library search.
+//
+// Returns a list of the queries of the query library shared with the user that match the search criteria.
+// Query library search supports pagination. Use the `limit` parameter to control the maximum number of queries returned; the default limit is 100.
+//
+// Responses:
+// 200: getSavedQuerySearchResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *QueryHistoryService) searchSavedQueriesHandler(c *contextmodel.ReqContext) response.Response {
+	query := SearchSavedQueriesQuery{
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		SearchString:   c.Query("searchString"),
+		Tags:           c.QueryStrings("tag"),
+		TeamID:         c.QueryInt64("teamId"),
+		Sort:           c.Query("sort"),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+	}
+
+	result, err := s.SearchSavedQueries(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to search the query library", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQuerySearchResponse{Result: result})
+}
+
+// swagger:route GET /query-library/{saved_query_uid} query_library getSavedQuery
+//
+// Get query of the query library.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Saved query not found", nil)
+	}
+
+	query, err := s.GetSavedQuery(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route PUT /query-library/{saved_query_uid} query_library updateSavedQuery
+//
+// Update query of the query library.
+//
+// Updates a query of the query library as specified by the UID and creates a new version of it. The update fails if the query was updated since the version it is based on.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *QueryHistoryService) updateSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Saved query not found", nil)
+	}
+
+	cmd := UpdateSavedQueryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query, err := s.UpdateSavedQuery(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route DELETE /query-library/{saved_query_uid} query_library deleteSavedQuery
+//
+// Delete query of the query library.
+//
+// Deletes a query of the query library with all its versions. This operation cannot be reverted.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) deleteSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Saved query not found", nil)
+	}
+
+	if err := s.DeleteSavedQuery(c.Req.Context(), c.SignedInUser, queryUID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete saved query", err)
+	}
+
+	return response.Success("Saved query deleted")
+}
+
+// swagger:route GET /query-library/{saved_query_uid}/versions query_library getSavedQueryVersions
+//
+// Get versions of a query of the query library.
+//
+// Returns the versions of a query of the query library, latest first.
+//
+// Responses:
+// 200: getSavedQueryVersionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getSavedQueryVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Saved query not found", nil)
+	}
+
+	versions, err := s.GetSavedQueryVersions(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get versions of saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryVersionsResponse{Result: versions})
+}
+
+// swagger:route GET /query-library/{saved_query_uid}/versions/{version} query_library getSavedQueryVersion
+//
+// Get version of a query of the query library.
+//
+// Responses:
+// 200: getSavedQueryVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getSavedQueryVersionHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Saved query not found", nil)
+	}
+
+	version, err := strconv.Atoi(web.Params(c.Req)[":version"])
+	if err != nil || version <= 0 {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	result, err := s.GetSavedQueryVersion(c.Req.Context(), c.SignedInUser, queryUID, version)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get version of saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryVersionResponse{Result: result})
+}
+
+// swagger:parameters getSavedQuery updateSavedQuery deleteSavedQuery getSavedQueryVersions getSavedQueryVersion
+type SavedQueryByUID struct {
+	// in:path
+	// required:true
+	UID string `json:"saved_query_uid"`
+}
+
+// swagger:parameters getSavedQueryVersion
+type SavedQueryVersionParams struct {
+	// in:path
+	// required:true
+	Version int `json:"version"`
+}
+
+// swagger:parameters searchSavedQueries
+type SearchSavedQueriesParams struct {
+	// List of data source UIDs to search for
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	DatasourceUid []string `json:"datasourceUid"`
+	// Text inside the title or the description that is searched for
+	// in:query
+	// required: false
+	SearchString string `json:"searchString"`
+	// List of tags the queries must have
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Tag []string `json:"tag"`
+	// Only return the queries shared with the team
+	// in:query
+	// required: false
+	TeamID int64 `json:"teamId"`
+	// Sort method
+	// in:query
+	// required: false
+	// default: alpha-asc
+	// Enum: alpha-asc,alpha-desc,time-desc
+	Sort string `json:"sort"`
+	// Use this parameter to access hits beyond limit. Numbering starts at 1. limit param acts as page size.
+	// in:query
+	// required: false
+	Page int `json:"page"`
+	// Limit the number of returned results
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters createSavedQuery
+type CreateSavedQueryParams struct {
+	// in:body
+	// required:true
+	Body CreateSavedQueryCommand `json:"body"`
+}
+
+// swagger:parameters updateSavedQuery
+type UpdateSavedQueryParams struct {
+	// in:body
+	// required:true
+	Body UpdateSavedQueryCommand `json:"body"`
+}
+
+// swagger:response getSavedQueryResponse
+type GetSavedQueryResponse struct {
+	// in: body
+	Body SavedQueryResponse `json:"body"`
+}
+
+// swagger:response getSavedQuerySearchResponse
+type GetSavedQuerySearchResponse struct {
+	// in: body
+	Body SavedQuerySearchResponse `json:"body"`
+}
+
+// swagger:response getSavedQueryVersionsResponse
+type GetSavedQueryVersionsResponse struct {
+	// in: body
+	Body SavedQueryVersionsResponse `json:"body"`
+}
+
+// swagger:response getSavedQueryVersionResponse
+type GetSavedQueryVersionResponse struct {
+	// in: body
+	Body SavedQueryVersionResponse `json:"body"`
+}
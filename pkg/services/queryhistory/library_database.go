@@ -0,0 +1,525 @@
+package queryhistory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	maxSavedQueryTitleLength = 190
+	maxSavedQueryTagLength   = 50
+)
+
+// createSavedQuery adds a query to the query library, optionally copying it from a query history entry of the user
+func (s QueryHistoryService) createSavedQuery(ctx context.Context, user *user.SignedInUser, cmd CreateSavedQueryCommand) (SavedQueryDTO, error) {
+	if !canAccessTeam(user, cmd.TeamID) {
+		return SavedQueryDTO{}, ErrSavedQueryAccessDenied.Errorf("user is not a member of team %d", cmd.TeamID)
+	}
+
+	var dto SavedQueryDTO
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		if cmd.QueryHistoryUID != "" {
+			var queryHistory QueryHistory
+			exists, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, cmd.QueryHistoryUID).Get(&queryHistory)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrSavedQueryInvalid.Errorf("%w: %s", ErrQueryNotFound, cmd.QueryHistoryUID)
+			}
+			cmd.DatasourceUID = queryHistory.DatasourceUID
+			cmd.Queries = queryHistory.Queries
+		}
+
+		tags, err := validateSavedQuery(cmd.Title, cmd.Tags, cmd.Queries)
+		if err != nil {
+			return err
+		}
+
+		now := s.now().Unix()
+		savedQuery := SavedQuery{
+			UID:           util.GenerateShortUID(),
+			OrgID:         user.OrgID,
+			TeamID:        cmd.TeamID,
+			Title:         strings.TrimSpace(cmd.Title),
+			Description:   cmd.Description,
+			DatasourceUID: cmd.DatasourceUID,
+			Queries:       cmd.Queries,
+			Version:       1,
+			CreatedBy:     user.UserID,
+			CreatedAt:     now,
+			UpdatedBy:     user.UserID,
+			UpdatedAt:     now,
+		}
+		if _, err := session.Insert(&savedQuery); err != nil {
+			return err
+		}
+		if err := saveSavedQueryVersion(session, savedQuery, tags, ""); err != nil {
+			return err
+		}
+		if err := saveSavedQueryTags(session, savedQuery, tags); err != nil {
+			return err
+		}
+
+		dto = savedQueryToDTO(savedQuery, tags)
+		return nil
+	})
+
+	return dto, err
+}
+
+// getSavedQuery returns a query of the query library shared with the user
+func (s QueryHistoryService) getSavedQuery(ctx context.Context, user identity.Requester, UID string) (SavedQueryDTO, error) {
+	var dto SavedQueryDTO
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		savedQuery, err := getSharedSavedQuery(session, user, UID)
+		if err != nil {
+			return err
+		}
+		tags, err := getSavedQueryTags(session, user.GetOrgID(), UID)
+		if err != nil {
+			return err
+		}
+		dto = savedQueryToDTO(*savedQuery, tags)
+		return nil
+	})
+
+	return dto, err
+}
+
+// searchSavedQueries searches for the queries of the query library the user can read
+func (s QueryHistoryService) searchSavedQueries(ctx context.Context, user *user.SignedInUser, query SearchSavedQueriesQuery) (SavedQuerySearchResult, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	filter, err := ac.Filter(user, "query_library.uid", ScopeSavedQueriesPrefix, ActionSavedQueriesRead)
+	if err != nil {
+		return SavedQuerySearchResult{}, err
+	}
+
+	var savedQueries []SavedQuery
+	var totalCount int
+	dtos := make([]SavedQueryDTO, 0)
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		savedQueriesBuilder := db.SQLBuilder{}
+		savedQueriesBuilder.Write(`SELECT query_library.* FROM query_library`)
+		writeSavedQueryFiltersSQL(query, user, filter, s.store, &savedQueriesBuilder)
+		switch query.Sort {
+		case "alpha-desc":
+			savedQueriesBuilder.Write(" ORDER BY query_library.title DESC ")
+		case "time-desc":
+			savedQueriesBuilder.Write(" ORDER BY query_library.updated_at DESC ")
+		default:
+			savedQueriesBuilder.Write(" ORDER BY query_library.title ASC ")
+		}
+		savedQueriesBuilder.Write(" LIMIT ? OFFSET ? ", query.Limit, query.Limit*(query.Page-1))
+
+		if err := session.SQL(savedQueriesBuilder.GetSQLString(), savedQueriesBuilder.GetParams()...).Find(&savedQueries); err != nil {
+			return err
+		}
+
+		countBuilder := db.SQLBuilder{}
+		countBuilder.Write(`SELECT COUNT(*) FROM query_library`)
+		writeSavedQueryFiltersSQL(query, user, filter, s.store, &countBuilder)
+		if _, err := session.SQL(countBuilder.GetSQLString(), countBuilder.GetParams()...).Get(&totalCount); err != nil {
+			return err
+		}
+
+		for _, savedQuery := range savedQueries {
+			tags, err := getSavedQueryTags(session, user.OrgID, savedQuery.UID)
+			if err != nil {
+				return err
+			}
+			dtos = append(dtos, savedQueryToDTO(savedQuery, tags))
+		}
+		return nil
+	})
+	if err != nil {
+		return SavedQuerySearchResult{}, err
+	}
+
+	return SavedQuerySearchResult{
+		TotalCount:   totalCount,
+		SavedQueries: dtos,
+		Page:         query.Page,
+		PerPage:      query.Limit,
+	}, nil
+}
+
+// updateSavedQuery updates a query of the query library and keeps the previous version
+func (s QueryHistoryService) updateSavedQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateSavedQueryCommand) (SavedQueryDTO, error) {
+	tags, err := validateSavedQuery(cmd.Title, cmd.Tags, cmd.Queries)
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	var dto SavedQueryDTO
+	err = s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		savedQuery, err := getSharedSavedQuery(session, user, UID)
+		if err != nil {
+			return err
+		}
+		if savedQuery.Version != cmd.Version {
+			return ErrSavedQueryVersionConflict.Errorf("expected version %d, got %d", savedQuery.Version, cmd.Version)
+		}
+
+		savedQuery.Title = strings.TrimSpace(cmd.Title)
+		savedQuery.Description = cmd.Description
+		savedQuery.DatasourceUID = cmd.DatasourceUID
+		savedQuery.Queries = cmd.Queries
+		savedQuery.Version++
+		savedQuery.UpdatedBy = user.UserID
+		savedQuery.UpdatedAt = s.now().Unix()
+
+		// Conditioning on the version prevents losing concurrent updates
+		affected, err := session.Where("id = ? AND version = ?", savedQuery.ID, cmd.Version).
+			Cols("title", "description", "datasource_uid", "queries", "version", "updated_by", "updated_at").
+			Update(savedQuery)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrSavedQueryVersionConflict.Errorf("saved query %s was updated concurrently", UID)
+		}
+
+		if err := saveSavedQueryVersion(session, *savedQuery, tags, cmd.Message); err != nil {
+			return err
+		}
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(&SavedQueryTag{}); err != nil {
+			return err
+		}
+		if err := saveSavedQueryTags(session, *savedQuery, tags); err != nil {
+			return err
+		}
+
+		dto = savedQueryToDTO(*savedQuery, tags)
+		return nil
+	})
+
+	return dto, err
+}
+
+// deleteSavedQuery deletes a query of the query library with its versions and tags
+func (s QueryHistoryService) deleteSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) error {
+	return s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		if _, err := getSharedSavedQuery(session, user, UID); err != nil {
+			return err
+		}
+
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(&SavedQueryTag{}); err != nil {
+			return err
+		}
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(&SavedQueryVersion{}); err != nil {
+			return err
+		}
+		_, err := session.Where("org_id = ? AND uid = ?", user.OrgID, UID).Delete(&SavedQuery{})
+		return err
+	})
+}
+
+// getSavedQueryVersions returns the versions of a query of the query library, latest first
+func (s QueryHistoryService) getSavedQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]SavedQueryVersionDTO, error) {
+	dtos := make([]SavedQueryVersionDTO, 0)
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		if _, err := getSharedSavedQuery(session, user, UID); err != nil {
+			return err
+		}
+
+		var versions []SavedQueryVersion
+		if err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Desc("version").Find(&versions); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			dtos = append(dtos, savedQueryVersionToDTO(version))
+		}
+		return nil
+	})
+
+	return dtos, err
+}
+
+// getSavedQueryVersion returns a version of a query of the query library
+func (s QueryHistoryService) getSavedQueryVersion(ctx context.Context, user identity.Requester, UID string, version int) (SavedQueryVersionDTO, error) {
+	var dto SavedQueryVersionDTO
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		if _, err := getSharedSavedQuery(session, user, UID); err != nil {
+			return err
+		}
+
+		var savedQueryVersion SavedQueryVersion
+		exists, err := session.Where("org_id = ? AND query_uid = ? AND version = ?", user.GetOrgID(), UID, version).Get(&savedQueryVersion)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrSavedQueryVersionNotFound.Errorf("saved query %s has no version %d", UID, version)
+		}
+		dto = savedQueryVersionToDTO(savedQueryVersion)
+		return nil
+	})
+
+	return dto, err
+}
+
+// getSharedSavedQuery returns a query of the query library if it is shared with the user
+func getSharedSavedQuery(session *db.Session, user identity.Requester, UID string) (*SavedQuery, error) {
+	var savedQuery SavedQuery
+	exists, err := session.Where("org_id = ? AND uid = ?", user.GetOrgID(), UID).Get(&savedQuery)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || !canAccessTeam(user, savedQuery.TeamID) {
+		return nil, ErrSavedQueryNotFound.Errorf("saved query %s not found", UID)
+	}
+	return &savedQuery, nil
+}
+
+// canAccessTeam returns true if the user can access the queries shared with the team. Org admins can
+// access the queries of all the teams.
+func canAccessTeam(user identity.Requester, teamID int64) bool {
+	return teamID == 0 || user.HasRole(org.RoleAdmin) || slices.Contains(user.GetTeams(), teamID)
+}
+
+func writeSavedQueryFiltersSQL(query SearchSavedQueriesQuery, user *user.SignedInUser, filter ac.SQLFilter, sqlStore db.DB, builder *db.SQLBuilder) {
+	params := []any{user.OrgID}
+	params = append(params, filter.Args...)
+	var sql bytes.Buffer
+	sql.WriteString(" WHERE query_library.org_id = ? AND " + filter.Where)
+
+	// Org admins can see the queries shared with all the teams
+	if !user.HasRole(org.RoleAdmin) {
+		sql.WriteString(" AND (query_library.team_id = 0")
+		if len(user.Teams) > 0 {
+			sql.WriteString(" OR query_library.team_id IN (?" + strings.Repeat(",?", len(user.Teams)-1) + ")")
+			for _, teamID := range user.Teams {
+				params = append(params, teamID)
+			}
+		}
+		sql.WriteString(")")
+	}
+
+	if query.TeamID != 0 {
+		sql.WriteString(" AND query_library.team_id = ?")
+		params = append(params, query.TeamID)
+	}
+
+	if query.SearchString != "" {
+		sql.WriteString(" AND (query_library.title " + sqlStore.GetDialect().LikeStr() + " ? OR query_library.description " + sqlStore.GetDialect().LikeStr() + " ?)")
+		params = append(params, "%"+query.SearchString+"%", "%"+query.SearchString+"%")
+	}
+
+	if len(query.DatasourceUIDs) > 0 {
+		sql.WriteString(" AND query_library.datasource_uid IN (?" + strings.Repeat(",?", len(query.DatasourceUIDs)-1) + ")")
+		for _, uid := range query.DatasourceUIDs {
+			params = append(params, uid)
+		}
+	}
+
+	// Queries must have all the tags
+	if len(query.Tags) > 0 {
+		sql.WriteString(" AND query_library.uid IN (SELECT t.query_uid FROM query_library_tag t WHERE t.org_id = ? AND t.term IN (?" + strings.Repeat(",?", len(query.Tags)-1) + ") GROUP BY t.query_uid HAVING COUNT(DISTINCT t.term) = ?)")
+		params = append(params, user.OrgID)
+		for _, tag := range query.Tags {
+			params = append(params, tag)
+		}
+		params = append(params, len(query.Tags))
+	}
+	builder.Write(sql.String(), params...)
+}
+
+// validateSavedQuery validates a saved query and returns its tags without duplicates
+func validateSavedQuery(title string, tags []string, queries *simplejson.Json) ([]string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, ErrSavedQueryInvalid.Errorf("title is required")
+	}
+	if len(title) > maxSavedQueryTitleLength {
+		return nil, ErrSavedQueryInvalid.Errorf("title must have at most %d characters", maxSavedQueryTitleLength)
+	}
+	if queries == nil || len(queries.MustArray()) == 0 {
+		return nil, ErrSavedQueryInvalid.Errorf("queries are required")
+	}
+
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+		if len(tag) > maxSavedQueryTagLength {
+			return nil, ErrSavedQueryInvalid.Errorf("tags must have at most %d characters", maxSavedQueryTagLength)
+		}
+		result = append(result, tag)
+	}
+	return result, nil
+}
+
+func saveSavedQueryVersion(session *db.Session, savedQuery SavedQuery, tags []string, message string) error {
+	_, err := session.Insert(&SavedQueryVersion{
+		OrgID:         savedQuery.OrgID,
+		QueryUID:      savedQuery.UID,
+		Version:       savedQuery.Version,
+		Title:         savedQuery.Title,
+		Description:   savedQuery.Description,
+		Tags:          simplejson.NewFromAny(tags),
+		DatasourceUID: savedQuery.DatasourceUID,
+		Queries:       savedQuery.Queries,
+		Message:       message,
+		CreatedBy:     savedQuery.UpdatedBy,
+		CreatedAt:     savedQuery.UpdatedAt,
+	})
+	return err
+}
+
+func saveSavedQueryTags(session *db.Session, savedQuery SavedQuery, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	savedQueryTags := make([]SavedQueryTag, 0, len(tags))
+	for _, tag := range tags {
+		savedQueryTags = append(savedQueryTags, SavedQueryTag{OrgID: savedQuery.OrgID, QueryUID: savedQuery.UID, Term: tag})
+	}
+	_, err := session.Insert(&savedQueryTags)
+	return err
+}
+
+func getSavedQueryTags(session *db.Session, orgID int64, UID string) ([]string, error) {
+	tags := make([]string, 0)
+	err := session.Table("query_library_tag").Where("org_id = ? AND query_uid = ?", orgID, UID).Asc("term").Cols("term").Find(&tags)
+	return tags, err
+}
+
+func savedQueryToDTO(savedQuery SavedQuery, tags []string) SavedQueryDTO {
+	return SavedQueryDTO{
+		UID:           savedQuery.UID,
+		TeamID:        savedQuery.TeamID,
+		Title:         savedQuery.Title,
+		Description:   savedQuery.Description,
+		Tags:          tags,
+		DatasourceUID: savedQuery.DatasourceUID,
+		Queries:       savedQuery.Queries,
+		Version:       savedQuery.Version,
+		CreatedBy:     savedQuery.CreatedBy,
+		CreatedAt:     savedQuery.CreatedAt,
+		UpdatedBy:     savedQuery.UpdatedBy,
+		UpdatedAt:     savedQuery.UpdatedAt,
+	}
+}
+
+func savedQueryVersionToDTO(version SavedQueryVersion) SavedQueryVersionDTO {
+	tags := make([]string, 0)
+	if version.Tags != nil {
+		tags = version.Tags.MustStringArray()
+	}
+	return SavedQueryVersionDTO{
+		Version:       version.Version,
+		Title:         version.Title,
+		Description:   version.Description,
+		Tags:          tags,
+		DatasourceUID: version.DatasourceUID,
+		Queries:       version.Queries,
+		Message:       version.Message,
+		CreatedBy:     version.CreatedBy,
+		CreatedAt:     version.CreatedAt,
+	}
+}
+
+// resolveSavedQueryReferences replaces the queries referencing a saved query with the saved query. The reference,
+// time and display options of the referencing query are kept.
+func (s QueryHistoryService) resolveSavedQueryReferences(ctx context.Context, user identity.Requester, queries []*simplejson.Json) error {
+	for i, query := range queries {
+		refJSON, ok := query.CheckGet("savedQuery")
+		if !ok {
+			continue
+		}
+
+		var ref SavedQueryRef
+		b, err := refJSON.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &ref); err != nil || ref.UID == "" {
+			return ErrSavedQueryInvalid.Errorf("invalid saved query reference in query %s", query.Get("refId").MustString())
+		}
+
+		hasAccess, err := s.accessControl.Evaluate(ctx, user, ac.EvalPermission(ActionSavedQueriesRead, ScopeSavedQueriesProvider.GetResourceScopeUID(ref.UID)))
+		if err != nil {
+			return err
+		}
+		if !hasAccess {
+			return ErrSavedQueryAccessDenied.Errorf("user cannot read saved query %s", ref.UID)
+		}
+
+		var datasourceUID string
+		var savedQueries *simplejson.Json
+		if ref.Version == 0 {
+			savedQuery, err := s.getSavedQuery(ctx, user, ref.UID)
+			if err != nil {
+				return err
+			}
+			datasourceUID, savedQueries = savedQuery.DatasourceUID, savedQuery.Queries
+		} else {
+			version, err := s.getSavedQueryVersion(ctx, user, ref.UID, ref.Version)
+			if err != nil {
+				return err
+			}
+			datasourceUID, savedQueries = version.DatasourceUID, version.Queries
+		}
+
+		resolved, err := findSavedQuery(savedQueries, ref.RefID)
+		if err != nil {
+			return err
+		}
+		if resolved == nil {
+			return ErrSavedQueryInvalid.Errorf("saved query %s has no query %q", ref.UID, ref.RefID)
+		}
+
+		for _, key := range []string{"refId", "intervalMs", "maxDataPoints", "hide"} {
+			if value, ok := query.CheckGet(key); ok {
+				resolved.Set(key, value.Interface())
+			} else {
+				resolved.Del(key)
+			}
+		}
+		if resolved.GetPath("datasource", "uid").MustString() == "" && datasourceUID != "" {
+			resolved.Set("datasource", map[string]any{"uid": datasourceUID})
+		}
+		queries[i] = resolved
+	}
+	return nil
+}
+
+// findSavedQuery returns a copy of the query with the refID, or of the first query when refID is empty
+func findSavedQuery(savedQueries *simplejson.Json, refID string) (*simplejson.Json, error) {
+	if savedQueries == nil {
+		return nil, nil
+	}
+	for _, item := range savedQueries.MustArray() {
+		query := simplejson.NewFromAny(item)
+		if refID != "" && query.Get("refId").MustString() != refID {
+			continue
+		}
+		b, err := query.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return simplejson.NewJson(b)
+	}
+	return nil, nil
+}
@@ -0,0 +1,168 @@
+package queryhistory
+
+import (
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+var (
+	ErrSavedQueryNotFound        = errutil.NotFound("querylibrary.not-found", errutil.WithPublicMessage("Saved query not found"))
+	ErrSavedQueryVersionNotFound = errutil.NotFound("querylibrary.version-not-found", errutil.WithPublicMessage("Saved query version not found"))
+	ErrSavedQueryInvalid         = errutil.BadRequest("querylibrary.invalid")
+	ErrSavedQueryVersionConflict = errutil.Conflict("querylibrary.version-conflict", errutil.WithPublicMessage("The saved query has been changed by someone else"))
+	ErrSavedQueryAccessDenied    = errutil.Forbidden("querylibrary.access-denied", errutil.WithPublicMessage("You are not allowed to access the saved query"))
+)
+
+// SavedQuery is the model for the queries shared in the query library of an org or a team
+type SavedQuery struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	UID   string `xorm:"uid"`
+	OrgID int64  `xorm:"org_id"`
+	// TeamID is the team the query is shared with, or 0 if it is shared with the whole org.
+	TeamID        int64 `xorm:"team_id"`
+	Title         string
+	Description   string
+	DatasourceUID string `xorm:"datasource_uid"`
+	Queries       *simplejson.Json
+	Version       int
+	CreatedBy     int64
+	CreatedAt     int64
+	UpdatedBy     int64
+	UpdatedAt     int64
+}
+
+func (SavedQuery) TableName() string { return "query_library" }
+
+// SavedQueryVersion is the model for the previous versions of the saved queries
+type SavedQueryVersion struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	QueryUID      string `xorm:"query_uid"`
+	Version       int
+	Title         string
+	Description   string
+	Tags          *simplejson.Json
+	DatasourceUID string `xorm:"datasource_uid"`
+	Queries       *simplejson.Json
+	Message       string
+	CreatedBy     int64
+	CreatedAt     int64
+}
+
+func (SavedQueryVersion) TableName() string { return "query_library_version" }
+
+// SavedQueryTag is the model for the tags of the saved queries
+type SavedQueryTag struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	QueryUID string `xorm:"query_uid"`
+	Term     string
+}
+
+func (SavedQueryTag) TableName() string { return "query_library_tag" }
+
+type SavedQueryDTO struct {
+	UID           string           `json:"uid"`
+	TeamID        int64            `json:"teamId,omitempty"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	Tags          []string         `json:"tags"`
+	DatasourceUID string           `json:"datasourceUid"`
+	Queries       *simplejson.Json `json:"queries"`
+	Version       int              `json:"version"`
+	CreatedBy     int64            `json:"createdBy"`
+	CreatedAt     int64            `json:"createdAt"`
+	UpdatedBy     int64            `json:"updatedBy"`
+	UpdatedAt     int64            `json:"updatedAt"`
+}
+
+type SavedQueryVersionDTO struct {
+	Version       int              `json:"version"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	Tags          []string         `json:"tags"`
+	DatasourceUID string           `json:"datasourceUid"`
+	Queries       *simplejson.Json `json:"queries"`
+	Message       string           `json:"message"`
+	CreatedBy     int64            `json:"createdBy"`
+	CreatedAt     int64            `json:"createdAt"`
+}
+
+// SavedQueryResponse is a response struct for SavedQueryDTO
+type SavedQueryResponse struct {
+	Result SavedQueryDTO `json:"result"`
+}
+
+type SavedQuerySearchResult struct {
+	TotalCount   int             `json:"totalCount"`
+	SavedQueries []SavedQueryDTO `json:"savedQueries"`
+	Page         int             `json:"page"`
+	PerPage      int             `json:"perPage"`
+}
+
+type SavedQuerySearchResponse struct {
+	Result SavedQuerySearchResult `json:"result"`
+}
+
+type SavedQueryVersionsResponse struct {
+	Result []SavedQueryVersionDTO `json:"result"`
+}
+
+type SavedQueryVersionResponse struct {
+	Result SavedQueryVersionDTO `json:"result"`
+}
+
+type SearchSavedQueriesQuery struct {
+	DatasourceUIDs []string `json:"datasourceUids"`
+	SearchString   string   `json:"searchString"`
+	Tags           []string `json:"tags"`
+	// TeamID limits the results to the queries shared with the team when not 0.
+	TeamID int64  `json:"teamId"`
+	Sort   string `json:"sort"`
+	Page   int    `json:"page"`
+	Limit  int    `json:"limit"`
+}
+
+// CreateSavedQueryCommand is the command for adding a query to the query library
+// swagger:model
+type CreateSavedQueryCommand struct {
+	// UID of the query history entry to promote. The data source and queries are copied from it when set.
+	// example: ByJHIIgVz
+	QueryHistoryUID string `json:"queryHistoryUid"`
+	// ID of the team the query is shared with. The query is shared with the whole org when 0.
+	TeamID int64 `json:"teamId"`
+	// required: true
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	// UID of the data source of the queries.
+	// example: PE1C5CBDA0504A6A3
+	DatasourceUID string `json:"datasourceUid"`
+	// The JSON model of queries.
+	Queries *simplejson.Json `json:"queries"`
+}
+
+// UpdateSavedQueryCommand is the command for updating a query of the query library. Each update creates a new version.
+// swagger:model
+type UpdateSavedQueryCommand struct {
+	// The version the update is based on. The update fails if the query has been updated since.
+	// required: true
+	Version int `json:"version"`
+	// required: true
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	Tags          []string         `json:"tags"`
+	DatasourceUID string           `json:"datasourceUid"`
+	Queries       *simplejson.Json `json:"queries"`
+	// Message describing the change.
+	Message string `json:"message"`
+}
+
+// SavedQueryRef is the reference to a saved query in the savedQuery field of a panel query
+type SavedQueryRef struct {
+	UID string `json:"uid"`
+	// Version of the saved query, or 0 for the latest version.
+	Version int `json:"version,omitempty"`
+	// RefID of the query to use when the saved query has several queries. The first query is used when empty.
+	RefID string `json:"refId,omitempty"`
+}
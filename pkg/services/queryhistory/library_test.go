@@ -0,0 +1,279 @@
+package queryhistory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func testLibraryScenario(t *testing.T, desc string, fn func(t *testing.T, sc scenarioContext)) {
+	t.Helper()
+
+	testScenarioWithQueryInQueryHistory(t, desc, func(t *testing.T, sc scenarioContext) {
+		setSavedQueryPermissions(sc.reqContext.SignedInUser, ScopeSavedQueriesAll)
+		fn(t, sc)
+	})
+}
+
+func setSavedQueryPermissions(usr *user.SignedInUser, scopes ...string) {
+	usr.Permissions = map[int64]map[string][]string{
+		testOrgID: {
+			ActionSavedQueriesRead: scopes,
+		},
+	}
+}
+
+func createSavedQuery(t *testing.T, sc scenarioContext, cmd CreateSavedQueryCommand) SavedQueryDTO {
+	t.Helper()
+
+	if cmd.Queries == nil && cmd.QueryHistoryUID == "" {
+		cmd.DatasourceUID = testDsUID1
+		cmd.Queries = simplejson.NewFromAny([]any{
+			map[string]any{"refId": "A", "expr": "up"},
+			map[string]any{"refId": "B", "expr": "down"},
+		})
+	}
+	query, err := sc.service.CreateSavedQuery(context.Background(), sc.reqContext.SignedInUser, cmd)
+	require.NoError(t, err)
+	return query
+}
+
+func TestIntegrationCreateSavedQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testLibraryScenario(t, "When users promote a query of their query history it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(CreateSavedQueryCommand{
+				QueryHistoryUID: sc.initialResult.Result.UID,
+				Title:           " Availability ",
+				Tags:            []string{"slo", " slo", "prod", ""},
+			})
+
+			resp := sc.service.createSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result SavedQueryResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, "Availability", result.Result.Title)
+			require.Equal(t, []string{"slo", "prod"}, result.Result.Tags)
+			require.Equal(t, testDsUID1, result.Result.DatasourceUID)
+			require.Equal(t, "test", result.Result.Queries.GetIndex(0).Get("expr").MustString())
+			require.Equal(t, 1, result.Result.Version)
+
+			query, err := sc.service.GetSavedQuery(context.Background(), sc.reqContext.SignedInUser, result.Result.UID)
+			require.NoError(t, err)
+			require.Equal(t, []string{"prod", "slo"}, query.Tags)
+		})
+
+	testLibraryScenario(t, "When users promote an unknown query of query history it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			_, err := sc.service.CreateSavedQuery(context.Background(), sc.reqContext.SignedInUser, CreateSavedQueryCommand{
+				QueryHistoryUID: "unknown",
+				Title:           "Availability",
+			})
+			require.ErrorIs(t, err, ErrSavedQueryInvalid)
+		})
+
+	testLibraryScenario(t, "When users add a query without title it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(CreateSavedQueryCommand{QueryHistoryUID: sc.initialResult.Result.UID})
+
+			resp := sc.service.createSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	testLibraryScenario(t, "When users share a query with a team they are not a member of it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			_, err := sc.service.CreateSavedQuery(context.Background(), sc.reqContext.SignedInUser, CreateSavedQueryCommand{
+				QueryHistoryUID: sc.initialResult.Result.UID,
+				Title:           "Availability",
+				TeamID:          2,
+			})
+			require.ErrorIs(t, err, ErrSavedQueryAccessDenied)
+		})
+}
+
+func TestIntegrationSearchSavedQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testLibraryScenario(t, "When users search the query library it should only return the queries shared with them",
+		func(t *testing.T, sc scenarioContext) {
+			usr := sc.reqContext.SignedInUser
+			usr.Teams = []int64{1}
+			availability := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability", Tags: []string{"slo", "prod"}})
+			latency := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Latency", Description: "p99 latency", Tags: []string{"slo"}, TeamID: 1})
+			usr.Teams = []int64{2}
+			createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Errors", TeamID: 2})
+			usr.Teams = []int64{1}
+
+			result, err := sc.service.SearchSavedQueries(context.Background(), usr, SearchSavedQueriesQuery{})
+			require.NoError(t, err)
+			require.Equal(t, 2, result.TotalCount)
+			require.Equal(t, availability.UID, result.SavedQueries[0].UID)
+			require.Equal(t, latency.UID, result.SavedQueries[1].UID)
+
+			result, err = sc.service.SearchSavedQueries(context.Background(), usr, SearchSavedQueriesQuery{Tags: []string{"slo", "prod"}})
+			require.NoError(t, err)
+			require.Equal(t, 1, result.TotalCount)
+			require.Equal(t, availability.UID, result.SavedQueries[0].UID)
+
+			result, err = sc.service.SearchSavedQueries(context.Background(), usr, SearchSavedQueriesQuery{SearchString: "p99"})
+			require.NoError(t, err)
+			require.Equal(t, 1, result.TotalCount)
+			require.Equal(t, latency.UID, result.SavedQueries[0].UID)
+
+			result, err = sc.service.SearchSavedQueries(context.Background(), usr, SearchSavedQueriesQuery{TeamID: 1})
+			require.NoError(t, err)
+			require.Equal(t, 1, result.TotalCount)
+			require.Equal(t, latency.UID, result.SavedQueries[0].UID)
+
+			result, err = sc.service.SearchSavedQueries(context.Background(), usr, SearchSavedQueriesQuery{Limit: 1, Page: 2})
+			require.NoError(t, err)
+			require.Equal(t, 2, result.TotalCount)
+			require.Len(t, result.SavedQueries, 1)
+			require.Equal(t, latency.UID, result.SavedQueries[0].UID)
+		})
+
+	testLibraryScenario(t, "When users search the query library it should only return the queries they can read",
+		func(t *testing.T, sc scenarioContext) {
+			availability := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability"})
+			createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Latency"})
+			setSavedQueryPermissions(sc.reqContext.SignedInUser, ScopeSavedQueriesProvider.GetResourceScopeUID(availability.UID))
+
+			result, err := sc.service.SearchSavedQueries(context.Background(), sc.reqContext.SignedInUser, SearchSavedQueriesQuery{})
+			require.NoError(t, err)
+			require.Equal(t, 1, result.TotalCount)
+			require.Equal(t, availability.UID, result.SavedQueries[0].UID)
+		})
+}
+
+func TestIntegrationUpdateSavedQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testLibraryScenario(t, "When users update a query of the query library it should create a new version",
+		func(t *testing.T, sc scenarioContext) {
+			query := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability", Tags: []string{"slo"}})
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": query.UID})
+			sc.reqContext.Req.Body = mockRequestBody(UpdateSavedQueryCommand{
+				Version:       1,
+				Title:         "Availability per host",
+				Tags:          []string{"prod"},
+				DatasourceUID: testDsUID2,
+				Queries:       simplejson.NewFromAny([]any{map[string]any{"refId": "A", "expr": "up by (host)"}}),
+				Message:       "Group by host",
+			})
+			resp := sc.service.updateSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result SavedQueryResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, 2, result.Result.Version)
+			require.Equal(t, []string{"prod"}, result.Result.Tags)
+
+			versions, err := sc.service.GetSavedQueryVersions(context.Background(), sc.reqContext.SignedInUser, query.UID)
+			require.NoError(t, err)
+			require.Len(t, versions, 2)
+			require.Equal(t, 2, versions[0].Version)
+			require.Equal(t, "Group by host", versions[0].Message)
+
+			version, err := sc.service.GetSavedQueryVersion(context.Background(), sc.reqContext.SignedInUser, query.UID, 1)
+			require.NoError(t, err)
+			require.Equal(t, "Availability", version.Title)
+			require.Equal(t, []string{"slo"}, version.Tags)
+			require.Equal(t, testDsUID1, version.DatasourceUID)
+
+			_, err = sc.service.GetSavedQueryVersion(context.Background(), sc.reqContext.SignedInUser, query.UID, 3)
+			require.ErrorIs(t, err, ErrSavedQueryVersionNotFound)
+		})
+
+	testLibraryScenario(t, "When users update an outdated version of a query it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			query := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability"})
+			cmd := UpdateSavedQueryCommand{Version: 1, Title: "Availability", Queries: query.Queries}
+			_, err := sc.service.UpdateSavedQuery(context.Background(), sc.reqContext.SignedInUser, query.UID, cmd)
+			require.NoError(t, err)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": query.UID})
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.updateSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 409, resp.Status())
+		})
+}
+
+func TestIntegrationDeleteSavedQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testLibraryScenario(t, "When users delete a query of the query library it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			query := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability", Tags: []string{"slo"}})
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": query.UID})
+			resp := sc.service.deleteSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			resp = sc.service.getSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+
+			result, err := sc.service.SearchSavedQueries(context.Background(), sc.reqContext.SignedInUser, SearchSavedQueriesQuery{Tags: []string{"slo"}})
+			require.NoError(t, err)
+			require.Equal(t, 0, result.TotalCount)
+		})
+}
+
+func TestIntegrationResolveSavedQueryReferences(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testLibraryScenario(t, "When queries reference a saved query they should be replaced with it",
+		func(t *testing.T, sc scenarioContext) {
+			query := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability"})
+			_, err := sc.service.UpdateSavedQuery(context.Background(), sc.reqContext.SignedInUser, query.UID, UpdateSavedQueryCommand{
+				Version: 1,
+				Title:   "Availability",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"refId": "A", "expr": "up2"}}),
+			})
+			require.NoError(t, err)
+
+			queries := []*simplejson.Json{
+				simplejson.NewFromAny(map[string]any{"refId": "X", "intervalMs": 1000, "savedQuery": map[string]any{"uid": query.UID}}),
+				simplejson.NewFromAny(map[string]any{"refId": "Y", "savedQuery": map[string]any{"uid": query.UID, "version": 1, "refId": "B"}}),
+				simplejson.NewFromAny(map[string]any{"refId": "Z", "expr": "inline"}),
+			}
+			err = sc.service.ResolveSavedQueryReferences(context.Background(), sc.reqContext.SignedInUser, queries)
+			require.NoError(t, err)
+
+			require.Equal(t, "X", queries[0].Get("refId").MustString())
+			require.Equal(t, "up2", queries[0].Get("expr").MustString())
+			require.Equal(t, int64(1000), queries[0].Get("intervalMs").MustInt64())
+			require.Equal(t, testDsUID1, queries[0].GetPath("datasource", "uid").MustString())
+			require.Equal(t, "Y", queries[1].Get("refId").MustString())
+			require.Equal(t, "down", queries[1].Get("expr").MustString())
+			require.Equal(t, "inline", queries[2].Get("expr").MustString())
+
+			// The saved query is left unchanged.
+			saved, err := sc.service.GetSavedQuery(context.Background(), sc.reqContext.SignedInUser, query.UID)
+			require.NoError(t, err)
+			require.Equal(t, "A", saved.Queries.GetIndex(0).Get("refId").MustString())
+		})
+
+	testLibraryScenario(t, "When users cannot read the referenced saved query it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			query := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Availability"})
+			setSavedQueryPermissions(sc.reqContext.SignedInUser, ScopeSavedQueriesProvider.GetResourceScopeUID("other"))
+
+			queries := []*simplejson.Json{simplejson.NewFromAny(map[string]any{"refId": "A", "savedQuery": map[string]any{"uid": query.UID}})}
+			err := sc.service.ResolveSavedQueryReferences(context.Background(), sc.reqContext.SignedInUser, queries)
+			require.ErrorIs(t, err, ErrSavedQueryAccessDenied)
+		})
+}
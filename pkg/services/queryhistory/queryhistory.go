@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -12,7 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl) *QueryHistoryService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl, accesscontrolService ac.Service) (*QueryHistoryService, error) {
 	s := &QueryHistoryService{
 		store:         sqlStore,
 		Cfg:           cfg,
@@ -27,7 +29,13 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.Rout
 		s.registerAPIEndpoints()
 	}
 
-	return s
+	// The query library is shared by the users of an org, so it is available even when query history is disabled
+	if err := declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+	s.registerLibraryAPIEndpoints()
+
+	return s, nil
 }
 
 type Service interface {
@@ -39,6 +47,15 @@ type Service interface {
 	UnstarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
 	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
 	EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error)
+	CreateSavedQuery(ctx context.Context, user *user.SignedInUser, cmd CreateSavedQueryCommand) (SavedQueryDTO, error)
+	SearchSavedQueries(ctx context.Context, user *user.SignedInUser, query SearchSavedQueriesQuery) (SavedQuerySearchResult, error)
+	GetSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error)
+	UpdateSavedQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateSavedQueryCommand) (SavedQueryDTO, error)
+	DeleteSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) error
+	GetSavedQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]SavedQueryVersionDTO, error)
+	GetSavedQueryVersion(ctx context.Context, user *user.SignedInUser, UID string, version int) (SavedQueryVersionDTO, error)
+	// ResolveSavedQueryReferences replaces the data queries referencing a saved query with the saved query.
+	ResolveSavedQueryReferences(ctx context.Context, user identity.Requester, queries []*simplejson.Json) error
 }
 
 type QueryHistoryService struct {
@@ -81,3 +98,35 @@ func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Contex
 func (s QueryHistoryService) EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error) {
 	return s.enforceQueryHistoryRowLimit(ctx, limit, starredQueries)
 }
+
+func (s QueryHistoryService) CreateSavedQuery(ctx context.Context, user *user.SignedInUser, cmd CreateSavedQueryCommand) (SavedQueryDTO, error) {
+	return s.createSavedQuery(ctx, user, cmd)
+}
+
+func (s QueryHistoryService) SearchSavedQueries(ctx context.Context, user *user.SignedInUser, query SearchSavedQueriesQuery) (SavedQuerySearchResult, error) {
+	return s.searchSavedQueries(ctx, user, query)
+}
+
+func (s QueryHistoryService) GetSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error) {
+	return s.getSavedQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) UpdateSavedQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateSavedQueryCommand) (SavedQueryDTO, error) {
+	return s.updateSavedQuery(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) DeleteSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) error {
+	return s.deleteSavedQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) GetSavedQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]SavedQueryVersionDTO, error) {
+	return s.getSavedQueryVersions(ctx, user, UID)
+}
+
+func (s QueryHistoryService) GetSavedQueryVersion(ctx context.Context, user *user.SignedInUser, UID string, version int) (SavedQueryVersionDTO, error) {
+	return s.getSavedQueryVersion(ctx, user, UID, version)
+}
+
+func (s QueryHistoryService) ResolveSavedQueryReferences(ctx context.Context, user identity.Requester, queries []*simplejson.Json) error {
+	return s.resolveSavedQueryReferences(ctx, user, queries)
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"sort"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
 	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/services/star"
-	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"go.opentelemetry.io/otel"
 )
@@ -35,7 +35,7 @@ type Query struct {
 	Title         string
 	Tags          []string
 	OrgId         int64
-	SignedInUser  *user.SignedInUser
+	SignedInUser  identity.Requester
 	Limit         int64
 	Page          int64
 	IsStarred     bool
@@ -67,8 +67,9 @@ func (s *SearchService) SearchHandler(ctx context.Context, query *Query) (model.
 	ctx, span := tracer.Start(ctx, "search.SearchHandler")
 	defer span.End()
 
+	userID, _ := identity.UserIdentifier(query.SignedInUser.GetID())
 	starredQuery := star.GetUserStarsQuery{
-		UserID: query.SignedInUser.UserID,
+		UserID: userID,
 	}
 	staredDashIDs, err := s.starService.GetByUser(ctx, &starredQuery)
 	if err != nil {
@@ -477,7 +477,14 @@ func (s *SecretsService) RotateDataKeys(ctx context.Context) error {
 }
 
 func (s *SecretsService) ReEncryptDataKeys(ctx context.Context) error {
-	s.log.Info("Data keys re-encryption triggered")
+	return s.ReEncryptDataKeysWithProvider(ctx, s.currentProviderID)
+}
+
+// ReEncryptDataKeysWithProvider re-encrypts all the data keys with the given provider,
+// which must be one of the available providers. It is used to move the data keys onto
+// a new provider before making it the current one.
+func (s *SecretsService) ReEncryptDataKeysWithProvider(ctx context.Context, providerID secrets.ProviderID) error {
+	s.log.Info("Data keys re-encryption triggered", "provider", providerID)
 
 	if s.features.IsEnabled(ctx, featuremgmt.FlagDisableEnvelopeEncryption) {
 		s.log.Info("Envelope encryption is not enabled but trying to init providers anyway...")
@@ -488,7 +495,12 @@ func (s *SecretsService) ReEncryptDataKeys(ctx context.Context) error {
 		}
 	}
 
-	if err := s.store.ReEncryptDataKeys(ctx, s.providers, s.currentProviderID); err != nil {
+	providerID = kmsproviders.NormalizeProviderID(providerID)
+	if _, ok := s.providers[providerID]; !ok {
+		return fmt.Errorf("missing configuration for encryption provider %s", providerID)
+	}
+
+	if err := s.store.ReEncryptDataKeys(ctx, s.providers, providerID); err != nil {
 		s.log.Error("Data keys re-encryption failed", "error", err)
 		return err
 	}
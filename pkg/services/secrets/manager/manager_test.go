@@ -368,6 +368,18 @@ func TestSecretsService_ReEncryptDataKeys(t *testing.T) {
 		assert.Empty(t, svc.dataKeyCache.byId)
 		assert.Empty(t, svc.dataKeyCache.byLabel)
 	})
+
+	t.Run("re-encryption with a missing provider should fail", func(t *testing.T) {
+		prevDataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+
+		err = svc.ReEncryptDataKeysWithProvider(ctx, "keyring.missing")
+		require.ErrorContains(t, err, "missing configuration for encryption provider keyring.missing")
+
+		dataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, prevDataKeys, dataKeys)
+	})
 }
 
 func TestSecretsService_Decrypt(t *testing.T) {
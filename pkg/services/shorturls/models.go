@@ -7,11 +7,14 @@ import (
 )
 
 var (
-	ErrShortURLBadRequest   = errutil.BadRequest("shorturl.bad-request")
-	ErrShortURLNotFound     = errutil.NotFound("shorturl.not-found")
-	ErrShortURLAbsolutePath = errutil.ValidationFailed("shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
-	ErrShortURLInvalidPath  = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
-	ErrShortURLInternal     = errutil.Internal("shorturl.internal")
+	ErrShortURLBadRequest    = errutil.BadRequest("shorturl.bad-request")
+	ErrShortURLNotFound      = errutil.NotFound("shorturl.not-found")
+	ErrShortURLAbsolutePath  = errutil.ValidationFailed("shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
+	ErrShortURLInvalidPath   = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
+	ErrShortURLInvalidSlug   = errutil.ValidationFailed("shorturl.invalid-slug", errutil.WithPublicMessage("Invalid short URL slug"))
+	ErrShortURLInvalidExpiry = errutil.ValidationFailed("shorturl.invalid-expiry", errutil.WithPublicMessage("Short URL expiry should be in the future"))
+	ErrShortURLSlugTaken     = errutil.Conflict("shorturl.slug-taken", errutil.WithPublicMessage("Short URL slug is already in use"))
+	ErrShortURLInternal      = errutil.Internal("shorturl.internal")
 )
 
 type ShortUrl struct {
@@ -22,6 +25,32 @@ type ShortUrl struct {
 	CreatedBy  int64
 	CreatedAt  int64
 	LastSeenAt int64
+	// ExpiresAt is the time the short URL stops redirecting, or 0 if it never expires.
+	ExpiresAt int64
+	HitCount  int64
+}
+
+// IsExpired returns true if the short URL has an expiry before the given time.
+func (s *ShortUrl) IsExpired(now time.Time) bool {
+	return s.ExpiresAt > 0 && s.ExpiresAt <= now.Unix()
+}
+
+type CreateShortURLCommand struct {
+	Path string
+	// Slug is the human-readable uid of the short URL. A random uid is generated when empty.
+	Slug string
+	// ExpiresAt is the time the short URL stops redirecting, or 0 if it never expires.
+	ExpiresAt int64
+}
+
+type SearchShortURLsQuery struct {
+	OrgID int64
+	// Query matches the uids and paths of the short URLs.
+	Query string
+	// CreatedBy limits the results to the short URLs created by the user when not 0.
+	CreatedBy int64
+	Limit     int
+	Page      int
 }
 
 type DeleteShortUrlCommand struct {
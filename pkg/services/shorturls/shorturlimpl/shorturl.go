@@ -9,11 +9,15 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
 	"github.com/teris-io/shortid"
 )
 
 var getTime = time.Now
 
+// maxSlugLength is the length of the short_url.uid column.
+const maxSlugLength = 40
+
 type ShortURLService struct {
 	SQLStore store
 }
@@ -34,8 +38,8 @@ func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shortur
 	return s.SQLStore.Update(ctx, shortURL)
 }
 
-func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, relPath string) (*shorturls.ShortUrl, error) {
-	relPath = strings.TrimSpace(relPath)
+func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
+	relPath := strings.TrimSpace(cmd.Path)
 
 	if path.IsAbs(relPath) {
 		return nil, shorturls.ErrShortURLAbsolutePath.Errorf("expected relative path: %s", relPath)
@@ -44,27 +48,59 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedIn
 		return nil, shorturls.ErrShortURLInvalidPath.Errorf("path cannot contain '../': %s", relPath)
 	}
 
-	uid, err := shortid.Generate()
-	if err != nil {
-		return nil, shorturls.ErrShortURLInternal.Errorf("failed to generate uid: %w", err)
+	now := getTime()
+	if cmd.ExpiresAt != 0 && cmd.ExpiresAt <= now.Unix() {
+		return nil, shorturls.ErrShortURLInvalidExpiry.Errorf("expiry is not in the future: %d", cmd.ExpiresAt)
+	}
+
+	uid := strings.TrimSpace(cmd.Slug)
+	if uid != "" {
+		if len(uid) > maxSlugLength || !util.IsValidShortUID(uid) {
+			return nil, shorturls.ErrShortURLInvalidSlug.Errorf("slug must have at most %d letters, numbers, '-' or '_': %s", maxSlugLength, uid)
+		}
+		_, err := s.SQLStore.Get(ctx, user, uid)
+		if err == nil {
+			return nil, shorturls.ErrShortURLSlugTaken.Errorf("slug already in use: %s", uid)
+		}
+		if !shorturls.ErrShortURLNotFound.Is(err) {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to check slug: %w", err)
+		}
+	} else {
+		var err error
+		uid, err = shortid.Generate()
+		if err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to generate uid: %w", err)
+		}
 	}
 
-	now := time.Now().Unix()
 	shortURL := shorturls.ShortUrl{
 		OrgId:     user.OrgID,
 		Uid:       uid,
 		Path:      relPath,
 		CreatedBy: user.UserID,
-		CreatedAt: now,
+		CreatedAt: now.Unix(),
+		ExpiresAt: cmd.ExpiresAt,
 	}
 
 	if err := s.SQLStore.Insert(ctx, &shortURL); err != nil {
+		// The slug can be taken between the check above and the insert.
+		if shorturls.ErrShortURLSlugTaken.Is(err) {
+			return nil, err
+		}
 		return nil, shorturls.ErrShortURLInternal.Errorf("failed to insert shorturl: %w", err)
 	}
 
 	return &shortURL, nil
 }
 
+func (s ShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error) {
+	return s.SQLStore.Search(ctx, query)
+}
+
+func (s ShortURLService) DeleteShortURL(ctx context.Context, user *user.SignedInUser, uid string) error {
+	return s.SQLStore.DeleteByUID(ctx, user, uid)
+}
+
 func (s ShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.SQLStore.Delete(ctx, cmd)
 }
@@ -26,7 +26,7 @@ func TestShortURLService(t *testing.T) {
 
 		service := ShortURLService{SQLStore: &sqlStore{db: store}}
 
-		newShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+		newShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: refPath})
 		require.NoError(t, err)
 		require.NotNil(t, newShortURL)
 		require.NotEmpty(t, newShortURL.Uid)
@@ -53,10 +53,18 @@ func TestShortURLService(t *testing.T) {
 			updatedShortURL, err := service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
 			require.NoError(t, err)
 			require.Equal(t, expectedTime.Unix(), updatedShortURL.LastSeenAt)
+			require.Equal(t, int64(1), updatedShortURL.HitCount)
+
+			err = service.UpdateLastSeenAt(context.Background(), existingShortURL)
+			require.NoError(t, err)
+
+			updatedShortURL, err = service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
+			require.NoError(t, err)
+			require.Equal(t, int64(2), updatedShortURL.HitCount)
 		})
 
 		t.Run("and stale short urls can be deleted", func(t *testing.T) {
-			staleShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+			staleShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: refPath})
 			require.NoError(t, err)
 			require.NotNil(t, staleShortURL)
 			require.NotEmpty(t, staleShortURL.Uid)
@@ -81,6 +89,76 @@ func TestShortURLService(t *testing.T) {
 		})
 	})
 
+	t.Run("User can create short URLs with a slug", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		shortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/abc/incident", Slug: "incident-42"})
+		require.NoError(t, err)
+		require.Equal(t, "incident-42", shortURL.Uid)
+
+		_, err = service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/def/other", Slug: "incident-42"})
+		require.True(t, shorturls.ErrShortURLSlugTaken.Is(err))
+
+		_, err = service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/def/other", Slug: "incident 42"})
+		require.True(t, shorturls.ErrShortURLInvalidSlug.Is(err))
+
+		t.Run("and get a conflict if the slug is taken after it is checked", func(t *testing.T) {
+			racing := ShortURLService{SQLStore: slugNotFoundStore{store: &sqlStore{db: store}}}
+			_, err := racing.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/def/other", Slug: "incident-42"})
+			require.True(t, shorturls.ErrShortURLSlugTaken.Is(err))
+		})
+
+		t.Run("and search and delete them", func(t *testing.T) {
+			found, err := service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{OrgID: user.OrgID, Query: "incident"})
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			require.Equal(t, "incident-42", found[0].Uid)
+
+			found, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{OrgID: user.OrgID, Query: "incident", CreatedBy: user.UserID + 1})
+			require.NoError(t, err)
+			require.Empty(t, found)
+
+			require.NoError(t, service.DeleteShortURL(context.Background(), user, "incident-42"))
+			_, err = service.GetShortURLByUID(context.Background(), user, "incident-42")
+			require.True(t, shorturls.ErrShortURLNotFound.Is(err))
+
+			err = service.DeleteShortURL(context.Background(), user, "incident-42")
+			require.True(t, shorturls.ErrShortURLNotFound.Is(err))
+		})
+	})
+
+	t.Run("User can create expiring short URLs", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		_, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/abc/incident", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+		require.True(t, shorturls.ErrShortURLInvalidExpiry.Is(err))
+
+		expiresAt := time.Now().Add(time.Hour)
+		shortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/abc/incident", ExpiresAt: expiresAt.Unix()})
+		require.NoError(t, err)
+		require.False(t, shortURL.IsExpired(time.Now()))
+		require.True(t, shortURL.IsExpired(expiresAt))
+
+		t.Run("and unvisited short URLs are kept until they expire", func(t *testing.T) {
+			cmd := shorturls.DeleteShortUrlCommand{OlderThan: time.Unix(shortURL.CreatedAt, 0)}
+			require.NoError(t, service.DeleteStaleShortURLs(context.Background(), &cmd))
+			_, err := service.GetShortURLByUID(context.Background(), user, shortURL.Uid)
+			require.NoError(t, err)
+
+			origGetTime := getTime
+			t.Cleanup(func() {
+				getTime = origGetTime
+			})
+			getTime = func() time.Time {
+				return expiresAt
+			}
+
+			require.NoError(t, service.DeleteStaleShortURLs(context.Background(), &cmd))
+			_, err = service.GetShortURLByUID(context.Background(), user, shortURL.Uid)
+			require.True(t, shorturls.ErrShortURLNotFound.Is(err))
+		})
+	})
+
 	t.Run("User cannot look up nonexistent short URLs", func(t *testing.T) {
 		service := ShortURLService{SQLStore: &sqlStore{db: store}}
 
@@ -90,3 +168,12 @@ func TestShortURLService(t *testing.T) {
 		require.Nil(t, shortURL)
 	})
 }
+
+// slugNotFoundStore doesn't find any short URL, as if they were created after being looked up.
+type slugNotFoundStore struct {
+	store
+}
+
+func (s slugNotFoundStore) Get(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error) {
+	return nil, shorturls.ErrShortURLNotFound.Errorf("short URL not found")
+}
@@ -12,9 +12,16 @@ type store interface {
 	Get(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error)
 	Update(ctx context.Context, shortURL *shorturls.ShortUrl) error
 	Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error
+	Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error)
+	DeleteByUID(ctx context.Context, user *user.SignedInUser, uid string) error
 	Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error
 }
 
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
 type sqlStore struct {
 	db db.DB
 }
@@ -42,10 +49,12 @@ func (s sqlStore) Get(ctx context.Context, user *user.SignedInUser, uid string)
 func (s sqlStore) Update(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	shortURL.LastSeenAt = getTime().Unix()
 	return s.db.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
-		_, err := dbSession.ID(shortURL.Id).Update(shortURL)
+		// Increment the hit count in the database so that concurrent visits are all counted
+		_, err := dbSession.Exec("UPDATE short_url SET last_seen_at = ?, hit_count = hit_count + 1 WHERE id = ?", shortURL.LastSeenAt, shortURL.Id)
 		if err != nil {
 			return err
 		}
+		shortURL.HitCount++
 		return nil
 	})
 }
@@ -53,15 +62,62 @@ func (s sqlStore) Update(ctx context.Context, shortURL *shorturls.ShortUrl) erro
 func (s sqlStore) Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	return s.db.WithDbSession(ctx, func(session *db.Session) error {
 		_, err := session.Insert(shortURL)
+		if err != nil && s.db.GetDialect().IsUniqueConstraintViolation(err) {
+			return shorturls.ErrShortURLSlugTaken.Errorf("slug already in use: %s", shortURL.Uid)
+		}
 		return err
 	})
 }
 
+func (s sqlStore) Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	shortURLs := make([]*shorturls.ShortUrl, 0)
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sess := dbSession.Where("org_id=?", query.OrgID)
+		if query.Query != "" {
+			like := s.db.GetDialect().LikeStr()
+			sess.And("(uid "+like+" ? OR path "+like+" ?)", "%"+query.Query+"%", "%"+query.Query+"%")
+		}
+		if query.CreatedBy != 0 {
+			sess.And("created_by=?", query.CreatedBy)
+		}
+		return sess.Desc("created_at").Limit(limit, (page-1)*limit).Find(&shortURLs)
+	})
+	return shortURLs, err
+}
+
+func (s sqlStore) DeleteByUID(ctx context.Context, user *user.SignedInUser, uid string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		result, err := session.Exec("DELETE FROM short_url WHERE org_id = ? AND uid = ?", user.OrgID, uid)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return shorturls.ErrShortURLNotFound.Errorf("short URL not found")
+		}
+		return nil
+	})
+}
+
 func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
-		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)"
+		// Short URLs with an expiry are kept until they expire, even if they were never visited
+		var rawSql = "DELETE FROM short_url WHERE (expires_at = 0 AND created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)) OR (expires_at > 0 AND expires_at <= ?)"
 
-		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix()); err != nil {
+		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix(), getTime().Unix()); err != nil {
 			return err
 		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
 			return err
@@ -133,11 +133,21 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 
 	ualert.AddRuleMetadata(mg)
 
+	ualert.AddRuleKeepFiringForColumns(mg)
+
+	ualert.AddRuleUpdatedByColumns(mg)
+
+	ualert.AddDeletedAlertRuleTable(mg)
+
+	ualert.AddSilenceScheduleTable(mg)
+
 	accesscontrol.AddOrphanedMigrations(mg)
 
 	accesscontrol.AddActionSetPermissionsMigrator(mg)
 
 	externalsession.AddMigration(mg)
+
+	addQueryLibraryMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
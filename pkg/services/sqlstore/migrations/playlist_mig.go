@@ -41,6 +41,10 @@ func addPlaylistMigrations(mg *Migrator) {
 	mg.AddMigration("Add playlist column updated_at", NewAddColumnMigration(playlistV2(), &Column{
 		Name: "updated_at", Type: DB_BigInt, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("Add playlist_item column options", NewAddColumnMigration(playlistItemV2, &Column{
+		Name: "options", Type: DB_Text, Nullable: true,
+	}))
 }
 
 func addPlaylistUIDMigration(mg *Migrator) {
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addQueryLibraryMigrations(mg *Migrator) {
+	queryLibraryV1 := Table{
+		Name: "query_library",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "queries", Type: DB_Text, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "team_id"}},
+		},
+	}
+
+	mg.AddMigration("create query_library table v1", NewAddTableMigration(queryLibraryV1))
+	mg.AddMigration("add unique index query_library.org_id-uid", NewAddIndexMigration(queryLibraryV1, queryLibraryV1.Indices[0]))
+	mg.AddMigration("add index query_library.org_id-team_id", NewAddIndexMigration(queryLibraryV1, queryLibraryV1.Indices[1]))
+
+	queryLibraryVersionV1 := Table{
+		Name: "query_library_version",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "query_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "tags", Type: DB_Text, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "queries", Type: DB_Text, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "query_uid", "version"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create query_library_version table v1", NewAddTableMigration(queryLibraryVersionV1))
+	mg.AddMigration("add unique index query_library_version.org_id-query_uid-version", NewAddIndexMigration(queryLibraryVersionV1, queryLibraryVersionV1.Indices[0]))
+
+	queryLibraryTagV1 := Table{
+		Name: "query_library_tag",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "query_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "term", Type: DB_NVarchar, Length: 50, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "query_uid"}},
+			{Cols: []string{"org_id", "term"}},
+		},
+	}
+
+	mg.AddMigration("create query_library_tag table v1", NewAddTableMigration(queryLibraryTagV1))
+	mg.AddMigration("add index query_library_tag.org_id-query_uid", NewAddIndexMigration(queryLibraryTagV1, queryLibraryTagV1.Indices[0]))
+	mg.AddMigration("add index query_library_tag.org_id-term", NewAddIndexMigration(queryLibraryTagV1, queryLibraryTagV1.Indices[1]))
+}
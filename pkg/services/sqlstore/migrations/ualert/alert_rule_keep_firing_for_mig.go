@@ -0,0 +1,23 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleKeepFiringForColumns adds columns to alert_rule to configure how firing series resolve.
+func AddRuleKeepFiringForColumns(mg *migrator.Migrator) {
+	keepFiringFor := &migrator.Column{
+		Name:     "keep_firing_for",
+		Type:     migrator.DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}
+	missingSeriesEvals := &migrator.Column{
+		Name:     "missing_series_evals_to_resolve",
+		Type:     migrator.DB_SmallInt,
+		Nullable: true,
+	}
+
+	mg.AddMigration("add keep_firing_for column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, keepFiringFor))
+	mg.AddMigration("add keep_firing_for column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, keepFiringFor))
+	mg.AddMigration("add missing_series_evals_to_resolve column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, missingSeriesEvals))
+	mg.AddMigration("add missing_series_evals_to_resolve column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, missingSeriesEvals))
+}
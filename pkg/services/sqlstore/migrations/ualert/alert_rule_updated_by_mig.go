@@ -0,0 +1,16 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleUpdatedByColumns adds columns to alert_rule and alert_rule_version to keep who changed the rule.
+func AddRuleUpdatedByColumns(mg *migrator.Migrator) {
+	updatedBy := &migrator.Column{
+		Name:     "updated_by",
+		Type:     migrator.DB_NVarchar,
+		Length:   40,
+		Nullable: true,
+	}
+
+	mg.AddMigration("add updated_by column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, updatedBy))
+	mg.AddMigration("add updated_by column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, updatedBy))
+}
@@ -0,0 +1,28 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddDeletedAlertRuleTable creates the table that keeps the deleted alert rules until they are restored or purged.
+func AddDeletedAlertRuleTable(mg *migrator.Migrator) {
+	deletedRuleTable := migrator.Table{
+		Name: "alert_rule_deleted",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "deleted", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "rule", Type: migrator.DB_MediumText, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid"}},
+			{Cols: []string{"deleted"}},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_deleted table", migrator.NewAddTableMigration(deletedRuleTable))
+	mg.AddMigration("add index on org_id and rule_uid to alert_rule_deleted table", migrator.NewAddIndexMigration(deletedRuleTable, deletedRuleTable.Indices[0]))
+	mg.AddMigration("add index on deleted to alert_rule_deleted table", migrator.NewAddIndexMigration(deletedRuleTable, deletedRuleTable.Indices[1]))
+}
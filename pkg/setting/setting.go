@@ -190,6 +190,7 @@ type Cfg struct {
 	PluginSettings                   PluginSettings
 	PluginsAllowUnsigned             []string
 	PluginCatalogURL                 string
+	PluginRepositoryIndex            string
 	PluginCatalogHiddenPlugins       []string
 	PluginAdminEnabled               bool
 	PluginAdminExternalManageEnabled bool
@@ -205,6 +206,9 @@ type Cfg struct {
 	PluginsCDNURLTemplate    string
 	PluginLogBackendRequests bool
 
+	// Concurrency limits, timeouts and circuit breaking of data source requests
+	PluginLimits PluginLimitsSettings
+
 	// Panels
 	DisableSanitizeHtml bool
 
@@ -344,6 +348,8 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	// SqlDatasourceSQLiteAllowedPaths are the patterns of the database files the SQLite data sources can open
+	SqlDatasourceSQLiteAllowedPaths []string
 
 	// Snapshots
 	SnapshotEnabled      bool
@@ -397,6 +403,12 @@ type Cfg struct {
 
 	Quota QuotaSettings
 
+	// HTTP API rate limiting
+	RateLimiting RateLimitingSettings
+
+	// Query and resource caching
+	QueryCaching QueryCachingSettings
+
 	// User settings
 	AllowUserSignUp            bool
 	AllowUserOrgCreate         bool
@@ -1219,6 +1231,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	if err := cfg.readPluginSettings(iniFile); err != nil {
 		return err
 	}
+	cfg.readPluginLimitsSettings()
 
 	// nolint:staticcheck
 	if err := cfg.readFeatureToggles(iniFile); err != nil {
@@ -1250,6 +1263,8 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	}
 
 	cfg.readQuotaSettings()
+	cfg.readRateLimitingSettings()
+	cfg.readQueryCachingSettings()
 
 	cfg.readExpressionsSettings()
 	if err := cfg.readGrafanaEnvironmentMetrics(); err != nil {
@@ -2007,6 +2022,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqlDatasourceSQLiteAllowedPaths = util.SplitString(sqlDatasources.Key("sqlite_allowed_paths").String())
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
@@ -0,0 +1,40 @@
+package setting
+
+import (
+	"time"
+)
+
+type PluginLimitsSettings struct {
+	// MaxConcurrentRequestsPerDataSource limits the concurrent query and resource requests to a single data source. 0 means no limit
+	MaxConcurrentRequestsPerDataSource int
+	// MaxConcurrentRequestsPerOrg limits the concurrent query and resource requests of an organization. 0 means no limit
+	MaxConcurrentRequestsPerOrg int
+	// QueueTimeout is how long a request waits for a free slot before it is rejected
+	QueueTimeout time.Duration
+	// QueryTimeout is the time a data source has to answer a query or resource request. 0 means no timeout
+	QueryTimeout time.Duration
+	// CircuitBreakerFailureThreshold is the number of consecutive failed requests that opens the circuit breaker of
+	// a data source. 0 disables the circuit breaker
+	CircuitBreakerFailureThreshold int
+	// CircuitBreakerOpenDuration is how long requests are rejected before a single request is let through to probe
+	// the data source
+	CircuitBreakerOpenDuration time.Duration
+}
+
+// LimitsEnabled returns true if a concurrency limit or a query timeout is set.
+func (s PluginLimitsSettings) LimitsEnabled() bool {
+	return s.MaxConcurrentRequestsPerDataSource > 0 || s.MaxConcurrentRequestsPerOrg > 0 || s.QueryTimeout > 0
+}
+
+func (cfg *Cfg) readPluginLimitsSettings() {
+	section := cfg.Raw.Section("plugin_limits")
+
+	cfg.PluginLimits = PluginLimitsSettings{
+		MaxConcurrentRequestsPerDataSource: section.Key("max_concurrent_requests_per_datasource").MustInt(0),
+		MaxConcurrentRequestsPerOrg:        section.Key("max_concurrent_requests_per_org").MustInt(0),
+		QueueTimeout:                       section.Key("queue_timeout").MustDuration(5 * time.Second),
+		QueryTimeout:                       section.Key("query_timeout").MustDuration(0),
+		CircuitBreakerFailureThreshold:     section.Key("circuit_breaker_failure_threshold").MustInt(0),
+		CircuitBreakerOpenDuration:         section.Key("circuit_breaker_open_duration").MustDuration(30 * time.Second),
+	}
+}
@@ -0,0 +1,26 @@
+package setting
+
+import (
+	"time"
+)
+
+type QueryCachingSettings struct {
+	Enabled bool
+	// TTL is how long query results are cached when neither the query nor the data source sets a TTL
+	TTL time.Duration
+	// ResourcesTTL is how long resource responses are cached
+	ResourcesTTL time.Duration
+	// MaxValueSize is the largest encoded response, in bytes, stored in the cache. 0 means no limit
+	MaxValueSize int64
+}
+
+func (cfg *Cfg) readQueryCachingSettings() {
+	section := cfg.Raw.Section("caching")
+
+	cfg.QueryCaching = QueryCachingSettings{
+		Enabled:      section.Key("enabled").MustBool(false),
+		TTL:          section.Key("ttl").MustDuration(5 * time.Minute),
+		ResourcesTTL: section.Key("resources_ttl").MustDuration(5 * time.Minute),
+		MaxValueSize: section.Key("max_value_mb").MustInt64(1) * 1024 * 1024,
+	}
+}
@@ -0,0 +1,85 @@
+package setting
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const rateLimitingSectionPrefix = "rate_limiting."
+
+type RateLimitingSettings struct {
+	// Enabled turns on the rate limiting of HTTP API requests
+	Enabled bool
+	// Limit is the limit of the API requests that are not part of a route group
+	Limit RateLimit
+	// Groups are the route groups with their own limits. A request belongs to the group with the longest matching path prefix
+	Groups []RateLimitGroup
+	// ExemptServerAdmins makes the requests of server admins bypass the rate limits
+	ExemptServerAdmins bool
+}
+
+// RateLimit is a token bucket limit.
+type RateLimit struct {
+	// RequestsPerSecond is the rate at which the bucket is refilled. 0 means no limit
+	RequestsPerSecond float64
+	// Burst is the size of the bucket, i.e. the maximum number of requests that can be sent at once
+	Burst int
+}
+
+// RateLimitGroup is a group of routes, identified by their path prefixes, which is limited separately.
+type RateLimitGroup struct {
+	Name         string
+	PathPrefixes []string
+	RateLimit
+}
+
+// read rate limiting configs from ini file. The route groups look like:
+// [rate_limiting.<group>]
+// path_prefixes = /api/ds/query /api/tsdb/query
+// requests_per_second = 5
+// burst = 20
+func (cfg *Cfg) readRateLimitingSettings() {
+	section := cfg.Raw.Section("rate_limiting")
+
+	settings := RateLimitingSettings{
+		Enabled:            section.Key("enabled").MustBool(false),
+		Limit:              readRateLimit(section.Key("requests_per_second").MustFloat64(10), section.Key("burst").MustInt(0)),
+		ExemptServerAdmins: section.Key("exempt_server_admins").MustBool(false),
+	}
+
+	for _, s := range cfg.Raw.Sections() {
+		name, ok := strings.CutPrefix(s.Name(), rateLimitingSectionPrefix)
+		if !ok || name == "" {
+			continue
+		}
+
+		prefixes := util.SplitString(s.Key("path_prefixes").String())
+		if len(prefixes) == 0 {
+			cfg.Logger.Warn("Ignoring rate limiting route group without path prefixes", "group", name)
+			continue
+		}
+
+		settings.Groups = append(settings.Groups, RateLimitGroup{
+			Name:         name,
+			PathPrefixes: prefixes,
+			RateLimit: readRateLimit(
+				s.Key("requests_per_second").MustFloat64(settings.Limit.RequestsPerSecond),
+				s.Key("burst").MustInt(0),
+			),
+		})
+	}
+
+	cfg.RateLimiting = settings
+}
+
+// readRateLimit returns a limit, with a burst defaulting to the requests per second.
+func readRateLimit(requestsPerSecond float64, burst int) RateLimit {
+	if burst <= 0 {
+		burst = int(requestsPerSecond)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return RateLimit{RequestsPerSecond: requestsPerSecond, Burst: burst}
+}
@@ -0,0 +1,39 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestRateLimitingSettings(t *testing.T) {
+	raw, err := ini.Load([]byte(`
+		[rate_limiting]
+		enabled = true
+		requests_per_second = 20
+		burst = 100
+
+		[rate_limiting.query]
+		path_prefixes = /api/ds/query, /api/tsdb/query
+		requests_per_second = 5
+
+		[rate_limiting.search]
+		path_prefixes = /api/search
+		burst = 10
+
+		[rate_limiting.invalid]
+		requests_per_second = 1`))
+	require.NoError(t, err)
+
+	cfg := NewCfg()
+	cfg.Raw = raw
+	cfg.readRateLimitingSettings()
+
+	require.True(t, cfg.RateLimiting.Enabled)
+	require.Equal(t, RateLimit{RequestsPerSecond: 20, Burst: 100}, cfg.RateLimiting.Limit)
+	require.Equal(t, []RateLimitGroup{
+		{Name: "query", PathPrefixes: []string{"/api/ds/query", "/api/tsdb/query"}, RateLimit: RateLimit{RequestsPerSecond: 5, Burst: 5}},
+		{Name: "search", PathPrefixes: []string{"/api/search"}, RateLimit: RateLimit{RequestsPerSecond: 20, Burst: 10}},
+	}, cfg.RateLimiting.Groups)
+}
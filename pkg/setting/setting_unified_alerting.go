@@ -108,6 +108,7 @@ type UnifiedAlertingSettings struct {
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	SkipClustering                bool
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	NotificationHistory           UnifiedAlertingNotificationHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	RecordingRules                RecordingRuleSettings
 
@@ -126,6 +127,10 @@ type UnifiedAlertingSettings struct {
 	// should be stored in the database for each alert_rule in an organization including the current one.
 	// 0 value means no limit
 	RuleVersionRecordLimit int
+
+	// DeletedRuleRetention defines how long deleted alert rules can be restored before they are purged.
+	// 0 value means that alert rules are deleted permanently.
+	DeletedRuleRetention time.Duration
 }
 
 type RecordingRuleSettings struct {
@@ -176,6 +181,14 @@ type UnifiedAlertingStateHistorySettings struct {
 	ExternalLabels        map[string]string
 }
 
+// UnifiedAlertingNotificationHistorySettings configures the history of the notifications sent by the contact points.
+// The history is written to the backends configured for the state history.
+type UnifiedAlertingNotificationHistorySettings struct {
+	Enabled bool
+	// Retention is how far back the notification history can be queried.
+	Retention time.Duration
+}
+
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
 // It hides the implementation details of the Enabled and simplifies its usage.
 func (u *UnifiedAlertingSettings) IsEnabled() bool {
@@ -425,6 +438,19 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	notificationHistory := iniFile.Section("unified_alerting.notification_history")
+	uaCfgNotificationHistory := UnifiedAlertingNotificationHistorySettings{
+		Enabled: notificationHistory.Key("enabled").MustBool(false),
+	}
+	uaCfgNotificationHistory.Retention, err = gtime.ParseDuration(valueAsString(notificationHistory, "retention", (7 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgNotificationHistory.Retention <= 0 {
+		return fmt.Errorf("setting 'retention' in section 'unified_alerting.notification_history' is invalid, only a positive duration is allowed")
+	}
+	uaCfg.NotificationHistory = uaCfgNotificationHistory
+
 	rr := iniFile.Section("recording_rules")
 	uaCfgRecordingRules := RecordingRuleSettings{
 		Enabled:           rr.Key("enabled").MustBool(false),
@@ -465,6 +491,14 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		return fmt.Errorf("setting 'rule_version_record_limit' is invalid, only 0 or a positive integer are allowed")
 	}
 
+	uaCfg.DeletedRuleRetention, err = gtime.ParseDuration(valueAsString(ua, "deleted_rule_retention", (30 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.DeletedRuleRetention < 0 {
+		return fmt.Errorf("setting 'deleted_rule_retention' is invalid, only 0 or a positive duration are allowed")
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
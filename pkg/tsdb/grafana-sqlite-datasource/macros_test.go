@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newSQLiteMacroEngine()
+	query := &backend.DataQuery{}
+
+	t.Run("Given a time range between 2018-04-12 00:00 and 2018-04-12 00:05", func(t *testing.T) {
+		from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+		to := from.Add(5 * time.Minute)
+		timeRange := backend.TimeRange{From: from, To: to}
+
+		t.Run("interpolate __time function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, "select unixepoch(time_column) AS time", sql)
+		})
+
+		t.Run("interpolate __timeGroup function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY unixepoch(time_column) / 300 * 300", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
+		t.Run("interpolate __timeGroup function with spaces around arguments", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column , '5m')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY unixepoch(time_column) / 300 * 300", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with fill", func(t *testing.T) {
+			fillQuery := &backend.DataQuery{JSON: []byte("{}")}
+			sql, err := engine.Interpolate(fillQuery, timeRange, "GROUP BY $__timeGroup(time_column,'5m', NULL)")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY unixepoch(time_column) / 300 * 300", sql)
+			require.JSONEq(t, `{"fill":true,"fillInterval":300,"fillMode":"null"}`, string(fillQuery.JSON))
+		})
+
+		t.Run("interpolate __timeFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("WHERE unixepoch(time_column) BETWEEN %d AND %d", from.Unix(), to.Unix()), sql)
+		})
+
+		t.Run("interpolate __timeFrom function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom()")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select datetime(%d, 'unixepoch')", from.Unix()), sql)
+		})
+
+		t.Run("interpolate __timeTo function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeTo()")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select datetime(%d, 'unixepoch')", to.Unix()), sql)
+		})
+
+		t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select time >= %d AND time <= %d", from.Unix(), to.Unix()), sql)
+		})
+
+		t.Run("interpolate __unixEpochNanoFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochNanoFilter(time)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select time >= %d AND time <= %d", from.UnixNano(), to.UnixNano()), sql)
+		})
+
+		t.Run("interpolate __unixEpochGroup function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroup(time_column,'5m')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroupAlias(time_column,'5m')")
+			require.Nil(t, err)
+
+			require.Equal(t, "SELECT CAST(time_column / 300 AS INTEGER) * 300", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
+		t.Run("should return an error for unknown macros", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "select $__unknown(time)")
+			require.EqualError(t, err, "unknown macro __unknown")
+		})
+	})
+}
@@ -0,0 +1,247 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana/pkg/tsdb/grafana-sqlite-datasource/sqleng"
+)
+
+const (
+	// PluginID is the identifier of the SQLite data source plugin.
+	PluginID = "grafana-sqlite-datasource"
+	// AllowedPathsKey is the key of the plugin configuration holding the comma separated patterns
+	// of the database files the data sources are allowed to open.
+	AllowedPathsKey = "GF_SQL_SQLITE_ALLOWED_PATHS"
+
+	driverName = "grafana-sqlite-datasource"
+)
+
+var errPathNotAllowed = errors.New("database file is not allowed")
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			// Attaching databases would give access to files outside of the allowed paths.
+			conn.SetLimit(sqlite3.SQLITE_LIMIT_ATTACHED, 0)
+			return nil
+		},
+	})
+}
+
+func NewInstanceSettings(logger log.Logger) datasource.InstanceFactoryFunc {
+	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		cfg := backend.GrafanaConfigFromContext(ctx)
+		sqlCfg, err := cfg.SQL()
+		if err != nil {
+			return nil, err
+		}
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    sqlCfg.DefaultMaxOpenConns,
+			MaxIdleConns:    sqlCfg.DefaultMaxIdleConns,
+			ConnMaxLifetime: sqlCfg.DefaultMaxConnLifetimeSeconds,
+		}
+
+		err = json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+
+		path, err := allowedPath(database, splitAllowedPaths(cfg.Get(AllowedPathsKey)))
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData:                jsonData,
+			Database:                path,
+			ID:                      settings.ID,
+			Updated:                 settings.Updated,
+			UID:                     settings.UID,
+			DecryptedSecureJSONData: settings.DecryptedSecureJSONData,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "CHAR", "VARCHAR", "NCHAR", "NVARCHAR", "CLOB"},
+			RowLimit:          sqlCfg.RowLimit,
+		}
+
+		userFacingDefaultError, err := cfg.UserFacingDefaultError()
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := sql.Open(driverName, connectionString(path))
+		if err != nil {
+			return nil, err
+		}
+
+		db.SetMaxOpenConns(config.DSInfo.JsonData.MaxOpenConns)
+		db.SetMaxIdleConns(config.DSInfo.JsonData.MaxIdleConns)
+		db.SetConnMaxLifetime(time.Duration(config.DSInfo.JsonData.ConnMaxLifetime) * time.Second)
+
+		return sqleng.NewQueryDataHandler(userFacingDefaultError, db, config, &sqliteQueryResultTransformer{}, newSQLiteMacroEngine(), logger)
+	}
+}
+
+// splitAllowedPaths returns the patterns of the allowed database files.
+func splitAllowedPaths(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// allowedPath returns the path of the database file with its symbolic links resolved if both the path and the
+// resolved path match one of the patterns. Patterns use the syntax of filepath.Match, and no path is allowed
+// without patterns.
+func allowedPath(path string, patterns []string) (string, error) {
+	if path == "" {
+		return "", errors.New("missing database file path")
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("database file path %q must be absolute", path)
+	}
+
+	path = filepath.Clean(path)
+	if !matchesAny(path, patterns) {
+		return "", fmt.Errorf("%w: %s does not match the allowed paths of the sql_datasources configuration", errPathNotAllowed, path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open database file: %w", err)
+	}
+	if !matchesAny(resolved, patterns) {
+		return "", fmt.Errorf("%w: %s links to %s, which does not match the allowed paths of the sql_datasources configuration", errPathNotAllowed, path, resolved)
+	}
+
+	return resolved, nil
+}
+
+func matchesAny(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(pattern, path); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionString returns the URI opening the database file in read-only mode. The query_only pragma
+// additionally rejects the statements that would change the database.
+func connectionString(path string) string {
+	u := url.URL{
+		Scheme:   "file",
+		Path:     filepath.ToSlash(path),
+		RawQuery: "mode=ro&_query_only=true",
+	}
+	return u.String()
+}
+
+type sqliteQueryResultTransformer struct{}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(_ log.Logger, err error) error {
+	return err
+}
+
+var (
+	sqliteIntegerTypes = []string{"INTEGER", "INT", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT8"}
+	sqliteRealTypes    = []string{"REAL", "DOUBLE", "DOUBLE PRECISION", "FLOAT", "NUMERIC", "DECIMAL"}
+	sqliteTimeTypes    = []string{"DATETIME", "TIMESTAMP", "DATE"}
+
+	// sqliteTimeFormats are the formats of the values of the time columns. The values the driver parses
+	// are scanned in RFC3339, while the others are scanned as they are stored.
+	sqliteTimeFormats = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04",
+		"2006-01-02T15:04",
+		"2006-01-02",
+	}
+)
+
+// GetConverterList returns the converters of the columns declared with the common integer, real and time types.
+// SQLite accepts any declared type, so the columns with other types are read as strings.
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	var converters []sqlutil.StringConverter
+	for _, typeName := range sqliteIntegerTypes {
+		converters = append(converters, newStringConverter(typeName, data.FieldTypeNullableInt64, func(in string) (any, error) {
+			v, err := strconv.ParseInt(in, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return &v, nil
+		}))
+	}
+	for _, typeName := range sqliteRealTypes {
+		converters = append(converters, newStringConverter(typeName, data.FieldTypeNullableFloat64, func(in string) (any, error) {
+			v, err := strconv.ParseFloat(in, 64)
+			if err != nil {
+				return nil, err
+			}
+			return &v, nil
+		}))
+	}
+	for _, typeName := range sqliteTimeTypes {
+		converters = append(converters, newStringConverter(typeName, data.FieldTypeNullableTime, func(in string) (any, error) {
+			var err error
+			for _, format := range sqliteTimeFormats {
+				var v time.Time
+				if v, err = time.Parse(format, in); err == nil {
+					return &v, nil
+				}
+			}
+			return nil, err
+		}))
+	}
+	return converters
+}
+
+func newStringConverter(typeName string, fieldType data.FieldType, parse func(string) (any, error)) sqlutil.StringConverter {
+	return sqlutil.StringConverter{
+		Name:           "handle " + typeName,
+		InputScanKind:  reflect.Struct,
+		InputTypeName:  typeName,
+		ConversionFunc: func(in *string) (*string, error) { return in, nil },
+		Replacer: &sqlutil.StringFieldReplacer{
+			OutputFieldType: fieldType,
+			ReplaceFunc: func(in *string) (any, error) {
+				if in == nil {
+					return nil, nil
+				}
+				return parse(*in)
+			},
+		},
+	}
+}
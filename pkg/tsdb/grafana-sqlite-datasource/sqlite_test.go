@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/grafana-sqlite-datasource/sqleng"
+)
+
+func TestAllowedPath(t *testing.T) {
+	dir := t.TempDir()
+	allowedDir := filepath.Join(dir, "allowed")
+	require.NoError(t, os.Mkdir(allowedDir, 0o750))
+	dbPath := filepath.Join(allowedDir, "metrics.db")
+	require.NoError(t, os.WriteFile(dbPath, nil, 0o600))
+	otherPath := filepath.Join(dir, "other.db")
+	require.NoError(t, os.WriteFile(otherPath, nil, 0o600))
+	linkPath := filepath.Join(allowedDir, "link.db")
+	require.NoError(t, os.Symlink(otherPath, linkPath))
+
+	patterns := []string{filepath.Join(allowedDir, "*.db")}
+
+	t.Run("should allow the paths matching a pattern", func(t *testing.T) {
+		path, err := allowedPath(filepath.Join(allowedDir, "..", "allowed", "metrics.db"), patterns)
+		require.NoError(t, err)
+		resolved, err := filepath.EvalSymlinks(dbPath)
+		require.NoError(t, err)
+		require.Equal(t, resolved, path)
+	})
+
+	t.Run("should not allow the paths not matching any pattern", func(t *testing.T) {
+		_, err := allowedPath(otherPath, patterns)
+		require.ErrorIs(t, err, errPathNotAllowed)
+
+		_, err = allowedPath(filepath.Join(allowedDir, "..", "other.db"), patterns)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should not allow the links to paths not matching any pattern", func(t *testing.T) {
+		_, err := allowedPath(linkPath, patterns)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should not allow any path without patterns", func(t *testing.T) {
+		_, err := allowedPath(dbPath, splitAllowedPaths(""))
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should not allow relative paths", func(t *testing.T) {
+		_, err := allowedPath("metrics.db", []string{"*"})
+		require.Error(t, err)
+	})
+}
+
+func TestSplitAllowedPaths(t *testing.T) {
+	require.Equal(t, []string{"/var/lib/edge/*.db", "/data/metrics.db"}, splitAllowedPaths(" /var/lib/edge/*.db, ,/data/metrics.db"))
+	require.Nil(t, splitAllowedPaths(""))
+}
+
+func TestSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.db")
+
+	rw, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = rw.Exec(`CREATE TABLE metrics (time DATETIME, host TEXT, value REAL);
+		INSERT INTO metrics VALUES
+			('2018-03-15 13:00:00', 'server1', 1),
+			('2018-03-15 13:01:00', 'server1', 3),
+			('2018-03-15 13:05:00', 'server1', 5),
+			('2018-03-15 14:00:00', 'server1', 100);`)
+	require.NoError(t, err)
+	require.NoError(t, rw.Close())
+
+	db, err := sql.Open(driverName, connectionString(path))
+	require.NoError(t, err)
+
+	logger := backend.NewLoggerWith("logger", "sqlite.test")
+	handler, err := sqleng.NewQueryDataHandler("", db, sqleng.DataPluginConfiguration{
+		TimeColumnNames:   []string{"time", "time_sec"},
+		MetricColumnTypes: []string{"TEXT"},
+		RowLimit:          1000000,
+	}, &sqliteQueryResultTransformer{}, newSQLiteMacroEngine(), logger)
+	require.NoError(t, err)
+	t.Cleanup(handler.Dispose)
+
+	from := time.Date(2018, 3, 15, 13, 0, 0, 0, time.UTC)
+	timeRange := backend.TimeRange{From: from, To: from.Add(30 * time.Minute)}
+
+	query := func(t *testing.T, rawSQL string, format string) backend.DataResponse {
+		t.Helper()
+		resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				JSON:      []byte(`{"rawSql": "` + rawSQL + `", "format": "` + format + `"}`),
+				RefID:     "A",
+				TimeRange: timeRange,
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("should report the database as healthy", func(t *testing.T) {
+		res, err := handler.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("should group time series with the time macros", func(t *testing.T) {
+		res := query(t, "SELECT $__timeGroupAlias(time, '5m'), avg(value) AS value FROM metrics WHERE $__timeFilter(time) GROUP BY 1 ORDER BY 1", "time_series")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, from.Unix(), frame.Fields[0].At(0).(*time.Time).Unix())
+		require.Equal(t, from.Add(5*time.Minute).Unix(), frame.Fields[0].At(1).(*time.Time).Unix())
+		require.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+		require.Equal(t, 5.0, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("should read declared time and text columns in tables", func(t *testing.T) {
+		res := query(t, "SELECT time, host, value FROM metrics WHERE $__timeFilter(time) ORDER BY time", "table")
+		require.NoError(t, res.Error)
+
+		frame := res.Frames[0]
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, from.Unix(), frame.Fields[0].At(0).(*time.Time).Unix())
+		require.Equal(t, "server1", *frame.Fields[1].At(0).(*string))
+		require.Equal(t, 1.0, *frame.Fields[2].At(0).(*float64))
+	})
+
+	t.Run("should not change the database", func(t *testing.T) {
+		res := query(t, "INSERT INTO metrics VALUES ('2018-03-15 13:10:00', 'server1', 1)", "table")
+		require.Error(t, res.Error)
+
+		_, err := db.Exec("DELETE FROM metrics")
+		require.Error(t, err)
+	})
+
+	t.Run("should not attach other databases", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "other.db")
+		_, err := db.Exec("ATTACH DATABASE '" + other + "' AS other")
+		require.Error(t, err)
+	})
+}
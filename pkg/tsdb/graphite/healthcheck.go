@@ -0,0 +1,67 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	healthCheckRefID  = "__healthcheck__"
+	healthCheckTarget = "constantLine(100)"
+)
+
+// CheckHealth renders a constant line for the last hour, which only succeeds when Grafana can
+// reach Graphite and Graphite can evaluate functions.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	if _, err := s.getDSInfo(ctx, req.PluginContext); err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusUnknown,
+			Message: "Failed to get data source info",
+		}, err
+	}
+
+	model, err := json.Marshal(map[string]string{TargetModelField: healthCheckTarget})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: req.PluginContext,
+		Queries: []backend.DataQuery{{
+			RefID:     healthCheckRefID,
+			TimeRange: backend.TimeRange{From: now.Add(-time.Hour), To: now},
+			JSON:      model,
+		}},
+	})
+	if err != nil {
+		return healthCheckError(ctx, fmt.Errorf("error received while querying graphite: %w", err)), nil
+	}
+
+	res := resp.Responses[healthCheckRefID]
+	if res.Error != nil {
+		return healthCheckError(ctx, fmt.Errorf("error from graphite: %w", res.Error)), nil
+	}
+	if len(res.Frames) == 0 {
+		return healthCheckError(ctx, fmt.Errorf("graphite returned no data for %s", healthCheckTarget)), nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
+
+func healthCheckError(ctx context.Context, err error) *backend.CheckHealthResult {
+	logger.FromContext(ctx).Error("Graphite health check failed", "error", err)
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusError,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,77 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/render", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			assert.Contains(t, r.Form.Get("target"), healthCheckTarget)
+			_, _ = w.Write([]byte(`[{"target": "constantLine(100) __healthcheck__", "datapoints": [[100, 1], [100, 2]]}]`))
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		assert.Equal(t, "Data source is working", res.Message)
+	})
+
+	t.Run("graphite returns an error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Contains(t, res.Message, "500 Internal Server Error")
+	})
+
+	t.Run("graphite returns no data", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+	})
+}
+
+type testInstanceManager struct {
+	dsInfo datasourceInfo
+}
+
+func (m testInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.dsInfo, nil
+}
+
+func (m testInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+func newTestService(srv *httptest.Server) *Service {
+	return &Service{
+		im: testInstanceManager{dsInfo: datasourceInfo{
+			HTTPClient: srv.Client(),
+			URL:        srv.URL,
+		}},
+		tracer: tracing.InitializeTracerForTest(),
+	}
+}
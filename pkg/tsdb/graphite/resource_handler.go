@@ -0,0 +1,145 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// resourceMethods lists the Graphite endpoints that can be called through CallResource
+// and the HTTP methods allowed for each of them.
+var resourceMethods = map[string][]string{
+	"metrics/find":           {http.MethodGet, http.MethodPost},
+	"tags/autoComplete/tags": {http.MethodGet},
+	"functions":              {http.MethodGet},
+}
+
+// Graphite 1.1.7 returns `"default": Infinity` in /functions, which is not valid JSON.
+// See https://github.com/graphite-project/graphite-web/issues/2609
+var functionsInfinityRegex = regexp.MustCompile(`"default": ?Infinity`)
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath := strings.Trim(req.Path, "/")
+	methods, ok := resourceMethods[resourcePath]
+	if !ok {
+		logger.Error("Invalid resource path", "path", req.Path)
+		return fmt.Errorf("invalid resource URL: %s", req.Path)
+	}
+	if !isAllowedMethod(req.Method, methods) {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusMethodNotAllowed,
+			Headers: map[string][]string{
+				"Allow": {strings.Join(methods, ", ")},
+			},
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	graphiteReq, err := s.createResourceRequest(ctx, dsInfo, req, resourcePath)
+	if err != nil {
+		logger.Error("Failed to create resource request", "error", err, "path", req.Path)
+		return err
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("path", resourcePath),
+		attribute.Int64("datasource_id", dsInfo.Id),
+		attribute.Int64("org_id", req.PluginContext.OrgID),
+	)
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if res != nil {
+		span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error("Failed resource call to graphite", "error", err, "path", resourcePath)
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read resource response", "error", err, "path", resourcePath)
+		return err
+	}
+
+	if resourcePath == "functions" && res.StatusCode/100 == 2 {
+		body = functionsInfinityRegex.ReplaceAll(body, []byte(`"default": 1e9999`))
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+// createResourceRequest creates the request to Graphite, keeping the query string of the
+// original request and, for POST requests, the form encoded body.
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, req *backend.CallResourceRequest, resourcePath string) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse data source URL: %s, error: %w", dsInfo.URL, err)
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource URL: %s, error: %w", req.URL, err)
+	}
+	u.RawQuery = reqURL.RawQuery
+
+	var body io.Reader
+	if req.Method == http.MethodPost {
+		body = bytes.NewReader(req.Body)
+	}
+
+	graphiteReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if req.Method == http.MethodPost {
+		graphiteReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return graphiteReq, nil
+}
+
+func isAllowedMethod(method string, allowed []string) bool {
+	for _, m := range allowed {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
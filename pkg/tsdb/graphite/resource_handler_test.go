@@ -0,0 +1,100 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var lastRequest *http.Request
+	var lastBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lastRequest, lastBody = r, string(body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/functions":
+			_, _ = w.Write([]byte(`{"movingAverage": {"params": [{"name": "windowSize", "default": Infinity}]}}`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	service := newTestService(srv)
+
+	call := func(t *testing.T, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		var res *backend.CallResourceResponse
+		err := service.CallResource(context.Background(), req, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+			res = r
+			return nil
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		return res
+	}
+
+	t.Run("metrics/find forwards the form body and query string", func(t *testing.T) {
+		res := call(t, &backend.CallResourceRequest{
+			Method: http.MethodPost,
+			Path:   "metrics/find",
+			URL:    "metrics/find?from=-1h&until=now",
+			Body:   []byte("query=servers.*"),
+		})
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, []string{"application/json"}, res.Headers["Content-Type"])
+		assert.Equal(t, "/metrics/find", lastRequest.URL.Path)
+		assert.Equal(t, "-1h", lastRequest.URL.Query().Get("from"))
+		assert.Equal(t, "application/x-www-form-urlencoded", lastRequest.Header.Get("Content-Type"))
+		assert.Equal(t, "query=servers.*", lastBody)
+	})
+
+	t.Run("tags/autoComplete/tags keeps repeated parameters", func(t *testing.T) {
+		res := call(t, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "tags/autoComplete/tags",
+			URL:    "tags/autoComplete/tags?expr=a%3Db&expr=c%3Dd&tagPrefix=ho",
+		})
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, "/tags/autoComplete/tags", lastRequest.URL.Path)
+		assert.Equal(t, []string{"a=b", "c=d"}, lastRequest.URL.Query()["expr"])
+		assert.Equal(t, "ho", lastRequest.URL.Query().Get("tagPrefix"))
+	})
+
+	t.Run("functions fixes Infinity defaults", func(t *testing.T) {
+		res := call(t, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "functions",
+			URL:    "functions",
+		})
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, `{"movingAverage": {"params": [{"name": "windowSize", "default": 1e9999}]}}`, string(res.Body))
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		res := call(t, &backend.CallResourceRequest{
+			Method: http.MethodDelete,
+			Path:   "functions",
+			URL:    "functions",
+		})
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Status)
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "render",
+			URL:    "render",
+		}, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+			return nil
+		}))
+		require.ErrorContains(t, err, "invalid resource URL")
+	})
+}
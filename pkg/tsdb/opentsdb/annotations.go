@@ -0,0 +1,116 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// annotationQuery is the model saved by the OpenTSDB annotation editor.
+type annotationQuery struct {
+	FromAnnotations bool   `json:"fromAnnotations"`
+	Target          string `json:"target"`
+	IsGlobal        bool   `json:"isGlobal"`
+}
+
+// splitAnnotationQueries separates annotation queries from metric queries.
+func splitAnnotationQueries(queries []backend.DataQuery) ([]backend.DataQuery, []backend.DataQuery) {
+	var metricQueries, annotationQueries []backend.DataQuery
+	for _, query := range queries {
+		model := annotationQuery{}
+		if err := json.Unmarshal(query.JSON, &model); err == nil && model.FromAnnotations {
+			annotationQueries = append(annotationQueries, query)
+			continue
+		}
+		metricQueries = append(metricQueries, query)
+	}
+	return metricQueries, annotationQueries
+}
+
+// queryAnnotations returns the annotations of the target metric, or the global annotations when
+// isGlobal is set, as a frame with time, timeEnd and text fields.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	model := annotationQuery{}
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse annotation query: %v", err))
+	}
+	if model.Target == "" {
+		return backend.DataResponse{Frames: data.Frames{newAnnotationFrame(query.RefID, nil)}}
+	}
+
+	tsdbQuery := OpenTsdbQuery{
+		Start:             query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		End:               query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries:           []map[string]any{{"aggregator": "sum", "metric": model.Target}},
+		GlobalAnnotations: true,
+	}
+
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadGateway, err.Error())
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Annotation request failed", "status", res.Status, "body", string(body))
+		return backend.ErrDataResponse(backend.Status(res.StatusCode), fmt.Sprintf("request failed, status: %s", res.Status))
+	}
+
+	var responseData []OpenTsdbResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb annotation response", "error", err, "status", res.Status, "body", string(body))
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+
+	var annotations []OpenTsdbAnnotation
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if model.IsGlobal {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+	return backend.DataResponse{Frames: data.Frames{newAnnotationFrame(query.RefID, annotations)}}
+}
+
+func newAnnotationFrame(refID string, annotations []OpenTsdbAnnotation) *data.Frame {
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, a := range annotations {
+		times = append(times, time.Unix(a.StartTime, 0).UTC())
+		var end *time.Time
+		if a.EndTime > 0 {
+			t := time.Unix(a.EndTime, 0).UTC()
+			end = &t
+		}
+		timeEnds = append(timeEnds, end)
+		texts = append(texts, a.Description)
+	}
+
+	frame := data.NewFrame(refID,
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+	frame.RefID = refID
+	return frame
+}
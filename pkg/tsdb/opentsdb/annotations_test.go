@@ -0,0 +1,84 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAnnotations(t *testing.T) {
+	var lastQuery OpenTsdbQuery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &lastQuery))
+		_, _ = w.Write([]byte(`[{
+			"metric": "deploys",
+			"tags": {},
+			"dps": [],
+			"annotations": [{"description": "deploy v1", "startTime": 1700000000, "endTime": 1700000060}],
+			"globalAnnotations": [{"description": "maintenance", "startTime": 1700000100}]
+		}]`))
+	}))
+	t.Cleanup(srv.Close)
+	service := newTestService(srv)
+
+	timeRange := backend.TimeRange{From: time.Unix(1699990000, 0), To: time.Unix(1700010000, 0)}
+
+	t.Run("annotations of the target metric", func(t *testing.T) {
+		resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "Anno",
+				TimeRange: timeRange,
+				JSON:      []byte(`{"fromAnnotations": true, "target": "deploys"}`),
+			}},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, lastQuery.GlobalAnnotations)
+		assert.Equal(t, "deploys", lastQuery.Queries[0]["metric"])
+
+		res := resp.Responses["Anno"]
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+		frame := res.Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), frame.Fields[0].At(0))
+		assert.Equal(t, time.Unix(1700000060, 0).UTC(), *frame.Fields[1].At(0).(*time.Time))
+		assert.Equal(t, "deploy v1", frame.Fields[2].At(0))
+	})
+
+	t.Run("global annotations", func(t *testing.T) {
+		resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "Anno",
+				TimeRange: timeRange,
+				JSON:      []byte(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`),
+			}},
+		})
+		require.NoError(t, err)
+
+		frame := resp.Responses["Anno"].Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, "maintenance", frame.Fields[2].At(0))
+		assert.Nil(t, frame.Fields[1].At(0))
+	})
+}
+
+func TestSplitAnnotationQueries(t *testing.T) {
+	metrics, annotations := splitAnnotationQueries([]backend.DataQuery{
+		{RefID: "A", JSON: []byte(`{"metric": "cpu"}`)},
+		{RefID: "B", JSON: []byte(`{"fromAnnotations": true, "target": "deploys"}`)},
+	})
+	require.Len(t, metrics, 1)
+	require.Len(t, annotations, 1)
+	assert.Equal(t, "A", metrics[0].RefID)
+	assert.Equal(t, "B", annotations[0].RefID)
+}
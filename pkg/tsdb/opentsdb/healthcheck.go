@@ -0,0 +1,90 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CheckHealth requests the OpenTSDB version to check that Grafana can reach the server.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusUnknown,
+			Message: "Failed to get data source info",
+		}, err
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		logger.Error("Failed to parse data source URL", "error", err, "url", dsInfo.URL)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusUnknown,
+			Message: "Failed to parse data source URL",
+		}, err
+	}
+	u.Path = path.Join(u.Path, "api/version")
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		logger.Error("Failed to create request", "error", err, "url", u.String())
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusUnknown,
+			Message: "Failed to create request",
+		}, err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		logger.Error("Failed to do health check request", "error", err, "url", u.String())
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Failed to connect to OpenTSDB: %v", err),
+		}, nil
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("OpenTSDB returned an error, status: %s", res.Status),
+		}, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read health check response", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusUnknown,
+			Message: "Failed to read response",
+		}, err
+	}
+
+	var version OpenTsdbVersion
+	if err := json.Unmarshal(body, &version); err != nil || version.Version == "" {
+		logger.Info("Unexpected health check response", "error", err, "body", string(body))
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: "The response from /api/version does not look like OpenTSDB",
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: fmt.Sprintf("Data source is working, OpenTSDB version %s", version.Version),
+	}, nil
+}
@@ -63,18 +63,45 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 }
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	var tsdbQuery OpenTsdbQuery
-
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	metricQueries, annotationQueries := splitAnnotationQueries(req.Queries)
+	if len(annotationQueries) == 0 {
+		return s.queryMetrics(ctx, logger, req.PluginContext, metricQueries)
+	}
+
+	resp := backend.NewQueryDataResponse()
+	if len(metricQueries) > 0 {
+		metricsResp, err := s.queryMetrics(ctx, logger, req.PluginContext, metricQueries)
+		if err != nil {
+			return metricsResp, err
+		}
+		for refID, r := range metricsResp.Responses {
+			resp.Responses[refID] = r
+		}
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	for _, query := range annotationQueries {
+		resp.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query)
+	}
+	return resp, nil
+}
+
+func (s *Service) queryMetrics(ctx context.Context, logger log.Logger, pluginCtx backend.PluginContext, queries []backend.DataQuery) (*backend.QueryDataResponse, error) {
+	var tsdbQuery OpenTsdbQuery
+
+	q := queries[0]
 
 	myRefID := q.RefID
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range queries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -84,7 +111,7 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	dsInfo, err := s.getDSInfo(ctx, pluginCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,90 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// resourcePaths lists the OpenTSDB endpoints that can be called through CallResource.
+var resourcePaths = map[string]bool{
+	"api/suggest":        true,
+	"api/search/lookup":  true,
+	"api/aggregators":    true,
+	"api/config/filters": true,
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath := strings.Trim(req.Path, "/")
+	if !resourcePaths[resourcePath] {
+		logger.Error("Invalid resource path", "path", req.Path)
+		return fmt.Errorf("invalid resource URL: %s", req.Path)
+	}
+	if req.Method != http.MethodGet {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusMethodNotAllowed,
+			Headers: map[string][]string{
+				"Allow": {http.MethodGet},
+			},
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse data source URL: %s, error: %w", dsInfo.URL, err)
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse resource URL: %s, error: %w", req.URL, err)
+	}
+	u.RawQuery = reqURL.RawQuery
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		logger.Error("Failed to create request", "error", err, "url", u.String())
+		return err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		logger.Error("Failed resource call to opentsdb", "error", err, "path", resourcePath)
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read resource response", "error", err, "path", resourcePath)
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
@@ -0,0 +1,70 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var lastRequest *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastRequest = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["cpu.idle","cpu.user"]`))
+	}))
+	t.Cleanup(srv.Close)
+	service := newTestService(srv)
+
+	send := func(t *testing.T, req *backend.CallResourceRequest) (*backend.CallResourceResponse, error) {
+		t.Helper()
+		var res *backend.CallResourceResponse
+		err := service.CallResource(context.Background(), req, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+			res = r
+			return nil
+		}))
+		return res, err
+	}
+
+	for _, p := range []string{"api/suggest?type=metrics&q=cpu&max=10", "api/search/lookup?m=cpu&limit=10", "api/aggregators", "api/config/filters"} {
+		t.Run(p, func(t *testing.T) {
+			u, err := http.NewRequest(http.MethodGet, "/"+p, nil)
+			require.NoError(t, err)
+
+			res, err := send(t, &backend.CallResourceRequest{
+				Method: http.MethodGet,
+				Path:   u.URL.Path,
+				URL:    p,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.Status)
+			assert.Equal(t, `["cpu.idle","cpu.user"]`, string(res.Body))
+			assert.Equal(t, u.URL.Path, lastRequest.URL.Path)
+			assert.Equal(t, u.URL.RawQuery, lastRequest.URL.RawQuery)
+		})
+	}
+
+	t.Run("only GET is allowed", func(t *testing.T) {
+		res, err := send(t, &backend.CallResourceRequest{
+			Method: http.MethodPost,
+			Path:   "api/suggest",
+			URL:    "api/suggest",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Status)
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		_, err := send(t, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "api/query",
+			URL:    "api/query",
+		})
+		require.ErrorContains(t, err, "invalid resource URL")
+	})
+}
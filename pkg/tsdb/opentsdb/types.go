@@ -1,13 +1,29 @@
 package opentsdb
 
 type OpenTsdbQuery struct {
-	Start   int64            `json:"start"`
-	End     int64            `json:"end"`
-	Queries []map[string]any `json:"queries"`
+	Start             int64            `json:"start"`
+	End               int64            `json:"end"`
+	Queries           []map[string]any `json:"queries"`
+	GlobalAnnotations bool             `json:"globalAnnotations,omitempty"`
 }
 
 type OpenTsdbResponse struct {
-	Metric     string            `json:"metric"`
-	Tags       map[string]string `json:"tags"`
-	DataPoints [][]float64       `json:"dps"`
+	Metric            string               `json:"metric"`
+	Tags              map[string]string    `json:"tags"`
+	DataPoints        [][]float64          `json:"dps"`
+	Annotations       []OpenTsdbAnnotation `json:"annotations,omitempty"`
+	GlobalAnnotations []OpenTsdbAnnotation `json:"globalAnnotations,omitempty"`
+}
+
+type OpenTsdbAnnotation struct {
+	TSUID       string            `json:"tsuid,omitempty"`
+	Description string            `json:"description"`
+	Notes       string            `json:"notes,omitempty"`
+	Custom      map[string]string `json:"custom,omitempty"`
+	StartTime   int64             `json:"startTime"`
+	EndTime     int64             `json:"endTime,omitempty"`
+}
+
+type OpenTsdbVersion struct {
+	Version string `json:"version"`
 }
@@ -0,0 +1,124 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	healthCheckTimeout = 30 * time.Second
+	// healthCheckSearchRange is the time range of the search query checking the gRPC endpoint.
+	healthCheckSearchRange = 15 * time.Minute
+)
+
+// CheckHealth checks that Tempo is reachable over HTTP, and over gRPC if the search queries are streamed.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		ctxLogger.Error("Failed to get datasource information", "error", err, "function", logEntrypoint())
+		return healthCheckError(fmt.Sprintf("Failed to get data source information: %s", err)), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := s.checkHTTPHealth(ctx, dsInfo); err != nil {
+		ctxLogger.Warn("Tempo HTTP health check failed", "error", err, "function", logEntrypoint())
+		return healthCheckError(fmt.Sprintf("Unable to connect with Tempo: %s", err)), nil
+	}
+
+	if dsInfo.StreamingSearchEnabled {
+		if err := s.checkGrpcHealth(ctx, dsInfo); err != nil {
+			ctxLogger.Warn("Tempo gRPC health check failed", "error", err, "function", logEntrypoint())
+			return healthCheckError(fmt.Sprintf("Test for streaming failed, consider disabling streaming: %s", err)), nil
+		}
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Successfully connected to Tempo data source.",
+	}, nil
+}
+
+// checkHTTPHealth requests the echo endpoint, falling back to the build info endpoint
+// for the Tempo deployments that don't serve it.
+func (s *Service) checkHTTPHealth(ctx context.Context, dsInfo *Datasource) error {
+	status, body, err := s.healthCheckRequest(ctx, dsInfo, "/api/echo")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		status, body, err = s.healthCheckRequest(ctx, dsInfo, "/api/status/buildinfo")
+		if err != nil {
+			return err
+		}
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", status, body)
+	}
+	return nil
+}
+
+func (s *Service) healthCheckRequest(ctx context.Context, dsInfo *Datasource, path string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dsInfo.URL+path, nil)
+	if err != nil {
+		return 0, "", err
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// checkGrpcHealth runs a search query for a single trace over gRPC and waits for its first response.
+func (s *Service) checkGrpcHealth(ctx context.Context, dsInfo *Datasource) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "User-Agent", backend.UserAgentFromContext(ctx).String())
+
+	now := time.Now()
+	stream, err := dsInfo.StreamingClient.Search(ctx, &tempopb.SearchRequest{
+		Query: "{}",
+		Limit: 1,
+		Start: uint32(now.Add(-healthCheckSearchRange).Unix()),
+		End:   uint32(now.Unix()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := stream.Recv(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func healthCheckError(message string) *backend.CheckHealthResult {
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusError,
+		Message: message,
+	}
+}
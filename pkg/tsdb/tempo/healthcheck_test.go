@@ -0,0 +1,124 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestCheckHealth(t *testing.T) {
+	newServer := func(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("should succeed if the echo endpoint responds", func(t *testing.T) {
+		srv := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/echo", r.URL.Path)
+			_, _ = w.Write([]byte("echo"))
+		})
+		service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		assert.Equal(t, "Successfully connected to Tempo data source.", res.Message)
+	})
+
+	t.Run("should fall back to the build info endpoint", func(t *testing.T) {
+		var paths []string
+		srv := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			if r.URL.Path == "/api/echo" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"version":"2.6.0"}`))
+		})
+		service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		assert.Equal(t, []string{"/api/echo", "/api/status/buildinfo"}, paths)
+	})
+
+	t.Run("should report the HTTP errors", func(t *testing.T) {
+		srv := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("invalid credentials"))
+		})
+		service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Equal(t, "Unable to connect with Tempo: unexpected status 401: invalid credentials", res.Message)
+	})
+
+	t.Run("should check the gRPC endpoint if streaming is enabled", func(t *testing.T) {
+		srv := newServer(t, func(w http.ResponseWriter, r *http.Request) {})
+		client := &mockStreamingClient{stream: &mockStreamer{}}
+		service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL, StreamingClient: client, StreamingSearchEnabled: true})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		require.NotNil(t, client.req)
+		assert.Equal(t, "{}", client.req.Query)
+		assert.Equal(t, uint32(1), client.req.Limit)
+	})
+
+	t.Run("should report the gRPC errors", func(t *testing.T) {
+		srv := newServer(t, func(w http.ResponseWriter, r *http.Request) {})
+		client := &mockStreamingClient{stream: &mockStreamer{err: errors.New("connection refused")}}
+		service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL, StreamingClient: client, StreamingSearchEnabled: true})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Equal(t, "Test for streaming failed, consider disabling streaming: connection refused", res.Message)
+	})
+
+	t.Run("should not check the gRPC endpoint if streaming is disabled", func(t *testing.T) {
+		srv := newServer(t, func(w http.ResponseWriter, r *http.Request) {})
+		client := &mockStreamingClient{stream: &mockStreamer{err: errors.New("connection refused")}}
+		service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL, StreamingClient: client})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		assert.Nil(t, client.req)
+	})
+}
+
+func newTestService(ds *Datasource) *Service {
+	return &Service{
+		logger: backend.NewLoggerWith("logger", "tsdb.tempo.test"),
+		im: datasource.NewInstanceManager(func(context.Context, backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return ds, nil
+		}),
+	}
+}
+
+type mockStreamingClient struct {
+	tempopb.StreamingQuerierClient
+	stream *mockStreamer
+	req    *tempopb.SearchRequest
+}
+
+func (c *mockStreamingClient) Search(_ context.Context, in *tempopb.SearchRequest, _ ...grpc.CallOption) (tempopb.StreamingQuerier_SearchClient, error) {
+	c.req = in
+	return c.stream, nil
+}
@@ -0,0 +1,96 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// resourcePathRegex matches the paths of the tag and tag value lookups, for both the v1 and v2 search APIs.
+// The tag of the tag value lookups is captured, as it is unescaped in the request path.
+var resourcePathRegex = regexp.MustCompile(`^api/(v2/)?search/(?:tags|tag/([^/]+)/values)$`)
+
+// CallResource forwards the tag and tag value lookups to Tempo, so that they use the data source HTTP client
+// and its middlewares, such as the secure socks proxy.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	if req.Method != http.MethodGet || !resourcePathRegex.MatchString(req.Path) {
+		ctxLogger.Error("Invalid resource path", "method", req.Method, "path", req.Path, "function", logEntrypoint())
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf(`{"message":"invalid resource path %q"}`, req.Path)),
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		ctxLogger.Error("Failed to get datasource information", "error", err, "function", logEntrypoint())
+		return err
+	}
+
+	tempoURL, err := createResourceURL(dsInfo.URL, req)
+	if err != nil {
+		ctxLogger.Error("Failed to create resource URL", "error", err, "function", logEntrypoint())
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, tempoURL, nil)
+	if err != nil {
+		ctxLogger.Error("Failed to create request", "error", err, "function", logEntrypoint())
+		return err
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		ctxLogger.Error("Failed to send request to Tempo", "error", err, "path", req.Path, "function", logEntrypoint())
+		return fmt.Errorf("failed get to tempo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Error("Failed to read response body", "error", err, "function", logEntrypoint())
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  resp.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+// createResourceURL returns the Tempo URL of the resource path, keeping the query parameters of the request.
+// The tag of the tag value lookups is escaped again, so that tags with reserved characters reach Tempo unchanged.
+func createResourceURL(dsURL string, req *backend.CallResourceRequest) (string, error) {
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse resource URL %q: %w", req.URL, err)
+	}
+
+	path := req.Path
+	if match := resourcePathRegex.FindStringSubmatch(req.Path); match != nil && match[2] != "" {
+		path = "api/" + match[1] + "search/tag/" + url.PathEscape(match[2]) + "/values"
+	}
+
+	tempoURL := dsURL + "/" + path
+	if reqURL.RawQuery != "" {
+		tempoURL += "?" + reqURL.RawQuery
+	}
+	return tempoURL, nil
+}
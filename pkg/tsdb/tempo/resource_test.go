@@ -0,0 +1,88 @@
+package tempo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tagNames":["service.name"]}`))
+	}))
+	t.Cleanup(srv.Close)
+	service := newTestService(&Datasource{HTTPClient: srv.Client(), URL: srv.URL})
+
+	callResource := func(t *testing.T, method, path, rawQuery string) *backend.CallResourceResponse {
+		t.Helper()
+		u := path
+		if rawQuery != "" {
+			u += "?" + rawQuery
+		}
+		var res *backend.CallResourceResponse
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: method,
+			Path:   path,
+			URL:    u,
+		}, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+			res = r
+			return nil
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		return res
+	}
+
+	t.Run("should forward the tag lookups", func(t *testing.T) {
+		for _, path := range []string{"api/search/tags", "api/v2/search/tags"} {
+			requested = ""
+			res := callResource(t, http.MethodGet, path, "scope=span")
+			assert.Equal(t, http.StatusOK, res.Status)
+			assert.Equal(t, `{"tagNames":["service.name"]}`, string(res.Body))
+			assert.Equal(t, []string{"application/json"}, res.Headers["Content-Type"])
+			assert.Equal(t, "/"+path+"?scope=span", requested)
+		}
+	})
+
+	t.Run("should forward the tag value lookups", func(t *testing.T) {
+		for _, path := range []string{"api/search/tag/service.name/values", "api/v2/search/tag/resource.service.name/values"} {
+			requested = ""
+			res := callResource(t, http.MethodGet, path, "q=%7Bspan.http.method%3D%22GET%22%7D")
+			assert.Equal(t, http.StatusOK, res.Status)
+			assert.Equal(t, "/"+path+"?q=%7Bspan.http.method%3D%22GET%22%7D", requested)
+		}
+	})
+
+	t.Run("should escape the tag of the tag value lookups", func(t *testing.T) {
+		requested = ""
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "api/v2/search/tag/span.my tag?#%/values",
+			URL:    "api/v2/search/tag/span.my%20tag%3F%23%25/values?scope=span",
+		}, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+			assert.Equal(t, http.StatusOK, r.Status)
+			return nil
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v2/search/tag/span.my%20tag%3F%23%25/values?scope=span", requested)
+	})
+
+	t.Run("should reject other paths and methods", func(t *testing.T) {
+		requested = ""
+		for _, path := range []string{"api/traces/abc", "api/search", "api/v2/search/tag/a/b/values", "api/search/tags/../../traces"} {
+			res := callResource(t, http.MethodGet, path, "")
+			assert.Equal(t, http.StatusNotFound, res.Status, path)
+		}
+		res := callResource(t, http.MethodPost, "api/search/tags", "")
+		assert.Equal(t, http.StatusNotFound, res.Status)
+		assert.Empty(t, requested)
+	})
+}
@@ -14,8 +14,10 @@ type Datasource struct {
 }
 
 var (
-	_ backend.QueryDataHandler = (*Datasource)(nil)
-	_ backend.StreamHandler    = (*Datasource)(nil)
+	_ backend.QueryDataHandler    = (*Datasource)(nil)
+	_ backend.StreamHandler       = (*Datasource)(nil)
+	_ backend.CheckHealthHandler  = (*Datasource)(nil)
+	_ backend.CallResourceHandler = (*Datasource)(nil)
 )
 
 func NewDatasource(c context.Context, b backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
@@ -39,3 +41,11 @@ func (d *Datasource) PublishStream(ctx context.Context, req *backend.PublishStre
 func (d *Datasource) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
 	return d.Service.RunStream(ctx, req, sender)
 }
+
+func (d *Datasource) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return d.Service.CheckHealth(ctx, req)
+}
+
+func (d *Datasource) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return d.Service.CallResource(ctx, req, sender)
+}
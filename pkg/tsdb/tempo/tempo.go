@@ -2,6 +2,7 @@ package tempo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime"
@@ -49,6 +50,14 @@ type Datasource struct {
 	HTTPClient      *http.Client
 	StreamingClient tempopb.StreamingQuerierClient
 	URL             string
+	// StreamingSearchEnabled is true if the search queries are streamed over gRPC.
+	StreamingSearchEnabled bool
+}
+
+type jsonData struct {
+	StreamingEnabled struct {
+		Search bool `json:"search"`
+	} `json:"streamingEnabled"`
 }
 
 func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -66,6 +75,14 @@ func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.Ins
 			return nil, err
 		}
 
+		var jd jsonData
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jd); err != nil {
+				ctxLogger.Error("Failed to parse settings", "error", err, "function", logEntrypoint())
+				return nil, fmt.Errorf("failed to parse settings: %w", err)
+			}
+		}
+
 		streamingClient, err := newGrpcClient(ctx, settings, opts)
 		if err != nil {
 			ctxLogger.Error("Failed to get gRPC client", "error", err, "function", logEntrypoint())
@@ -73,9 +90,10 @@ func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.Ins
 		}
 
 		model := &Datasource{
-			HTTPClient:      client,
-			StreamingClient: streamingClient,
-			URL:             settings.URL,
+			HTTPClient:             client,
+			StreamingClient:        streamingClient,
+			URL:                    settings.URL,
+			StreamingSearchEnabled: jd.StreamingEnabled.Search,
 		}
 		return model, nil
 	}
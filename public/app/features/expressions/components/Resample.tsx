@@ -20,6 +20,10 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
     onChange({ ...query, window: event.target.value });
   };
 
+  const onMaxGapChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, maxGap: event.target.value });
+  };
+
   const onRefIdChange = (value: SelectableValue<string>) => {
     onChange({ ...query, expression: value.value });
   };
@@ -49,6 +53,11 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
         <InlineField label="Upsample">
           <Select options={upsamplingTypes} value={upsampler} onChange={onSelectUpsampler} width={25} />
         </InlineField>
+        {(query.upsampler === 'linear' || query.upsampler === 'nearest') && (
+          <InlineField label="Max gap" tooltip="Leave gaps longer than this empty, for example 5m">
+            <Input onChange={onMaxGapChange} value={query.maxGap} placeholder="none" width={15} />
+          </InlineField>
+        )}
       </InlineFieldRow>
     </>
   );
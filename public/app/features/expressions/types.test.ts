@@ -0,0 +1,28 @@
+import { reducerTypes } from './types';
+
+// The reducers implemented by GetReduceFunc in pkg/expr/mathexp/reduce.go
+const backendReducers = [
+  'sum',
+  'mean',
+  'min',
+  'max',
+  'count',
+  'last',
+  'median',
+  'p90',
+  'p95',
+  'p99',
+  'stddev',
+  'first',
+  'diff',
+  'range',
+  'count_non_null',
+];
+
+describe('reducerTypes', () => {
+  it('should only offer reducers implemented by the backend', () => {
+    for (const reducer of reducerTypes) {
+      expect(backendReducers).toContain(reducer.value);
+    }
+  });
+});
@@ -75,6 +75,15 @@ export const expressionTypes: Array<SelectableValue<ExpressionQueryType>> = [
   return true;
 });
 
+/**
+ * Reducers of the reduce expression whose IDs are not, or differ from, a ReducerID.
+ * MATCHES the ReducerID constants in pkg/expr/mathexp/reduce.go
+ */
+export enum ExpressionReducerID {
+  stdDev = 'stddev',
+  countNonNull = 'count_non_null',
+}
+
 export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.min, label: 'Min', description: 'Get the minimum value' },
   { value: ReducerID.max, label: 'Max', description: 'Get the maximum value' },
@@ -83,6 +92,14 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: ReducerID.diff, label: 'Difference', description: 'Difference between the last and first value' },
+  { value: ReducerID.range, label: 'Range', description: 'Difference between the maximum and minimum value' },
+  { value: ExpressionReducerID.stdDev, label: 'StdDev', description: 'Get the standard deviation' },
+  { value: ReducerID.p90, label: '90th %', description: 'Get the 90th percentile value' },
+  { value: ReducerID.p95, label: '95th %', description: 'Get the 95th percentile value' },
+  { value: ReducerID.p99, label: '99th %', description: 'Get the 99th percentile value' },
+  { value: ExpressionReducerID.countNonNull, label: 'Count non-null', description: 'Get the number of values that are not null' },
 ];
 
 export enum ReducerMode {
@@ -121,6 +138,8 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'interpolate between the previous and next known values' },
+  { value: 'nearest', label: 'nearest', description: 'fill with the known value closest in time' },
 ];
 
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
@@ -141,6 +160,7 @@ export interface ExpressionQuery extends DataQuery {
   window?: string;
   downsampler?: string;
   upsampler?: string;
+  maxGap?: string;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
 }
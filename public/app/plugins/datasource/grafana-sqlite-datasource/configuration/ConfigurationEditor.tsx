@@ -0,0 +1,59 @@
+import { DataSourcePluginOptionsEditorProps, onUpdateDatasourceJsonDataOption } from '@grafana/data';
+import { ConfigSection, DataSourceDescription } from '@grafana/experimental';
+import { ConnectionLimits, Divider } from '@grafana/sql';
+import { Field, Input } from '@grafana/ui';
+
+import { SQLiteOptions } from '../types';
+
+export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options, onOptionsChange } = props;
+  const jsonData = options.jsonData;
+
+  const WIDTH_LONG = 40;
+
+  return (
+    <>
+      <DataSourceDescription
+        dataSourceName="SQLite"
+        docsLink="https://grafana.com/docs/grafana/latest/datasources/sqlite/"
+        hasRequiredFields={true}
+      />
+
+      <Divider />
+
+      <ConfigSection title="Database file">
+        <Field
+          label="Path"
+          description="Absolute path of the database file on the Grafana server. The file is opened read-only, and must match one of the paths allowed by the sqlite_allowed_paths option of the server configuration."
+          required
+        >
+          <Input
+            width={WIDTH_LONG}
+            name="path"
+            value={jsonData.database || ''}
+            placeholder="/var/lib/grafana/sqlite/metrics.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'database')}
+          />
+        </Field>
+      </ConfigSection>
+
+      <Divider />
+
+      <ConfigSection title="Additional settings" isCollapsible>
+        <Field
+          label="Min time interval"
+          description="A lower limit for the auto group by time interval. Recommended to be set to write frequency, for example 1m if your data is written every minute."
+        >
+          <Input
+            width={WIDTH_LONG}
+            placeholder="1m"
+            value={jsonData.timeInterval || ''}
+            onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+          />
+        </Field>
+
+        <ConnectionLimits options={options} onOptionsChange={onOptionsChange} />
+      </ConfigSection>
+    </>
+  );
+};
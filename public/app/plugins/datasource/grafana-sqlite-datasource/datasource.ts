@@ -0,0 +1,72 @@
+import { DataSourceInstanceSettings } from '@grafana/data';
+import { LanguageDefinition } from '@grafana/experimental';
+import { DB, SqlDatasource, SQLQuery, SQLSelectableValue, formatSQL } from '@grafana/sql';
+
+import { getFieldConfig, quoteIdentifierIfNecessary, quoteLiteral, toRawSql, unquoteIdentifier } from './sqlUtil';
+import { SQLiteOptions } from './types';
+
+export class SQLiteDatasource extends SqlDatasource {
+  sqlLanguageDefinition: LanguageDefinition | undefined;
+
+  constructor(instanceSettings: DataSourceInstanceSettings<SQLiteOptions>) {
+    super(instanceSettings);
+  }
+
+  getQueryModel() {
+    return { quoteLiteral };
+  }
+
+  getSqlLanguageDefinition(): LanguageDefinition {
+    if (this.sqlLanguageDefinition !== undefined) {
+      return this.sqlLanguageDefinition;
+    }
+
+    this.sqlLanguageDefinition = {
+      id: 'sql',
+      formatter: formatSQL,
+    };
+    return this.sqlLanguageDefinition;
+  }
+
+  async fetchTables(): Promise<string[]> {
+    const tables = await this.runSql<string[]>(
+      "SELECT name FROM sqlite_schema WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name",
+      { refId: 'tables' }
+    );
+    return tables.map((t) => quoteIdentifierIfNecessary(t[0]));
+  }
+
+  async fetchFields(query: Partial<SQLQuery>): Promise<SQLSelectableValue[]> {
+    if (!query.table) {
+      return [];
+    }
+    const columns = await this.runSql<string[]>(
+      `SELECT name, type FROM pragma_table_info(${quoteLiteral(unquoteIdentifier(query.table))}) ORDER BY cid`,
+      { refId: 'fields' }
+    );
+    return columns.map((c) => ({
+      label: c[0],
+      value: quoteIdentifierIfNecessary(c[0]),
+      type: c[1],
+      ...getFieldConfig(c[1]),
+    }));
+  }
+
+  getDB(): DB {
+    if (this.db !== undefined) {
+      return this.db;
+    }
+
+    return {
+      init: () => Promise.resolve(true),
+      datasets: () => Promise.resolve([]),
+      tables: () => this.fetchTables(),
+      fields: (query: SQLQuery) => this.fetchFields(query),
+      validateQuery: (query: SQLQuery) => Promise.resolve({ query, error: '', isError: false, isValid: true }),
+      dsID: () => this.id,
+      toRawSql,
+      functions: () => ['TOTAL', 'GROUP_CONCAT'],
+      getEditorLanguageDefinition: () => this.getSqlLanguageDefinition(),
+    };
+  }
+}
@@ -0,0 +1,12 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { SQLQuery, SqlQueryEditor } from '@grafana/sql';
+
+import { CheatSheet } from './CheatSheet';
+import { ConfigurationEditor } from './configuration/ConfigurationEditor';
+import { SQLiteDatasource } from './datasource';
+import { SQLiteOptions } from './types';
+
+export const plugin = new DataSourcePlugin<SQLiteDatasource, SQLQuery, SQLiteOptions>(SQLiteDatasource)
+  .setQueryEditor(SqlQueryEditor)
+  .setQueryEditorHelp(CheatSheet)
+  .setConfigEditor(ConfigurationEditor);
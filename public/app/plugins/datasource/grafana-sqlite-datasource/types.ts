@@ -0,0 +1,6 @@
+import { SQLOptions } from '@grafana/sql';
+
+/**
+ * The path of the database file is stored in the database option.
+ */
+export interface SQLiteOptions extends SQLOptions {}
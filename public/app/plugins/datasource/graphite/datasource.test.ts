@@ -39,6 +39,7 @@ describe('graphiteDatasource', () => {
 
     const instanceSettings = {
       url: '/api/datasources/proxy/1',
+      uid: 'graphite',
       name: 'graphiteProd',
       jsonData: {
         rollupIndicatorEnabled: true,
@@ -292,15 +293,16 @@ describe('graphiteDatasource', () => {
   });
 
   describe('when fetching Graphite function descriptions', () => {
-    // `"default": Infinity` (invalid JSON) in params passed by Graphite API in 1.1.7
-    const INVALID_JSON =
-      '{"testFunction":{"name":"function","description":"description","module":"graphite.render.functions","group":"Transform","params":[{"name":"param","type":"intOrInf","required":true,"default":Infinity}]}}';
+    // The backend replaces `"default": Infinity` (invalid JSON) returned by Graphite API in 1.1.7 with 1e9999
+    const FIXED_JSON =
+      '{"testFunction":{"name":"function","description":"description","module":"graphite.render.functions","group":"Transform","params":[{"name":"param","type":"intOrInf","required":true,"default":1e9999}]}}';
 
-    it('should parse the response with an invalid JSON', async () => {
+    it('should fetch the functions from the backend', async () => {
       fetchMock.mockImplementation(() => {
-        return of(createFetchResponse(INVALID_JSON));
+        return of(createFetchResponse(JSON.parse(FIXED_JSON)));
       });
       const funcDefs = await ctx.ds.getFuncDefs();
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/graphite/resources/functions');
       expect(funcDefs).toEqual({
         testFunction: {
           category: 'Transform',
@@ -322,6 +324,19 @@ describe('graphiteDatasource', () => {
     });
   });
 
+  describe('when testing the data source', () => {
+    it('should run the health check of the backend', async () => {
+      fetchMock.mockImplementation(() => {
+        return of(createFetchResponse({ status: 'OK', message: 'Data source is working' }));
+      });
+
+      const result = await ctx.ds.testDatasource();
+
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/graphite/health');
+      expect(result).toEqual({ status: 'success', message: 'Data source is working' });
+    });
+  });
+
   describe('building graphite params', () => {
     it('should return empty array if no targets', () => {
       const results = ctx.ds.buildGraphiteParams({
@@ -428,32 +443,26 @@ describe('graphiteDatasource', () => {
       });
     });
 
-    it('should generate tags query', () => {
-      ctx.ds.metricFindQuery('tags()').then((data) => {
-        results = data;
-      });
+    it('should generate tags query', async () => {
+      results = await ctx.ds.metricFindQuery('tags()');
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
     });
 
-    it('should generate tags query with a filter expression', () => {
-      ctx.ds.metricFindQuery('tags(server=backend_01)').then((data) => {
-        results = data;
-      });
+    it('should generate tags query with a filter expression', async () => {
+      results = await ctx.ds.metricFindQuery('tags(server=backend_01)');
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual(['server=backend_01']);
       expect(results).not.toBe(null);
     });
 
-    it('should generate tags query for an expression with whitespace after', () => {
-      ctx.ds.metricFindQuery('tags(server=backend_01 )').then((data) => {
-        results = data;
-      });
+    it('should generate tags query for an expression with whitespace after', async () => {
+      results = await ctx.ds.metricFindQuery('tags(server=backend_01 )');
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual(['server=backend_01']);
       expect(results).not.toBe(null);
     });
@@ -502,7 +511,7 @@ describe('graphiteDatasource', () => {
       expect(results).not.toBe(null);
     });
 
-    it('/metrics/find should be POST', () => {
+    it('/metrics/find should be POST', async () => {
       ctx.templateSrv.init([
         {
           type: 'query',
@@ -510,33 +519,27 @@ describe('graphiteDatasource', () => {
           current: { value: ['bar'] },
         },
       ]);
-      ctx.ds.metricFindQuery('[[foo]]').then((data) => {
-        results = data;
-      });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      results = await ctx.ds.metricFindQuery('[[foo]]');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/metrics/find');
       expect(requestOptions.method).toEqual('POST');
       expect(requestOptions.headers).toHaveProperty('Content-Type', 'application/x-www-form-urlencoded');
       expect(requestOptions.data).toMatch(`query=bar`);
       expect(requestOptions).toHaveProperty('params');
     });
 
-    it('should interpolate $__searchFilter with searchFilter', () => {
-      ctx.ds.metricFindQuery('app.$__searchFilter', { searchFilter: 'backend' }).then((data) => {
-        results = data;
-      });
+    it('should interpolate $__searchFilter with searchFilter', async () => {
+      results = await ctx.ds.metricFindQuery('app.$__searchFilter', { searchFilter: 'backend' });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/metrics/find');
       expect(requestOptions.params).toEqual({});
       expect(requestOptions.data).toEqual('query=app.backend*');
       expect(results).not.toBe(null);
     });
 
-    it('should interpolate $__searchFilter with default when searchFilter is missing', () => {
-      ctx.ds.metricFindQuery('app.$__searchFilter', {}).then((data) => {
-        results = data;
-      });
+    it('should interpolate $__searchFilter with default when searchFilter is missing', async () => {
+      results = await ctx.ds.metricFindQuery('app.$__searchFilter', {});
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/metrics/find');
       expect(requestOptions.params).toEqual({});
       expect(requestOptions.data).toEqual('query=app.*');
       expect(results).not.toBe(null);
@@ -554,10 +557,8 @@ describe('graphiteDatasource', () => {
 
     it('should fetch from /metrics/find endpoint when queryType is default or query is string', async () => {
       const stringQuery = 'query';
-      ctx.ds.metricFindQuery(stringQuery).then((data) => {
-        results = data;
-      });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      results = await ctx.ds.metricFindQuery(stringQuery);
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/metrics/find');
       expect(results).not.toBe(null);
 
       const objectQuery = {
@@ -567,7 +568,7 @@ describe('graphiteDatasource', () => {
         datasource: ctx.ds,
       };
       const data = await ctx.ds.metricFindQuery(objectQuery);
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite/resources/metrics/find');
       expect(data).toBeTruthy();
     });
 
@@ -9,7 +9,6 @@ import {
   DataFrame,
   DataQueryRequest,
   DataQueryResponse,
-  DataSourceApi,
   DataSourceWithQueryExportSupport,
   dateMath,
   dateTime,
@@ -21,7 +20,7 @@ import {
   toDataFrame,
   getSearchFilterScopedVar,
 } from '@grafana/data';
-import { BackendSrvRequest, getBackendSrv } from '@grafana/runtime';
+import { BackendSrvRequest, DataSourceWithBackend, getBackendSrv } from '@grafana/runtime';
 import { isVersionGtOrEq, SemVersion } from 'app/core/utils/version';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 import { getRollupNotice, getRuntimeConsolidationNotice } from 'app/plugins/datasource/graphite/meta';
@@ -65,7 +64,7 @@ function convertGlobToRegEx(text: string): string {
 }
 
 export class GraphiteDatasource
-  extends DataSourceApi<GraphiteQuery, GraphiteOptions, GraphiteQueryImportConfiguration>
+  extends DataSourceWithBackend<GraphiteQuery, GraphiteOptions>
   implements DataSourceWithQueryExportSupport<GraphiteQuery>
 {
   basicAuth: string;
@@ -634,30 +633,23 @@ export class GraphiteDatasource
       params.until = range.until;
     }
 
-    const httpOptions: BackendSrvRequest = {
-      method: 'POST',
-      url: '/metrics/find',
+    return this.postResource<Array<{ text: string; expandable?: number }>>('metrics/find', `query=${query}`, {
       params,
-      data: `query=${query}`,
       headers: {
         'Content-Type': 'application/x-www-form-urlencoded',
       },
       // for cancellations
       requestId: requestId,
-    };
-
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          return _map(results.data, (metric) => {
-            return {
-              text: metric.text,
-              expandable: metric.expandable ? true : false,
-            };
-          });
-        })
-      )
-    );
+    })
+      .then((results) => {
+        return _map(results, (metric) => {
+          return {
+            text: metric.text,
+            expandable: metric.expandable ? true : false,
+          };
+        });
+      })
+      .catch((err) => Promise.reject(reduceError(err)));
   }
 
   /**
@@ -783,15 +775,16 @@ export class GraphiteDatasource
       params.until = this.translateTime(options.range.to, true, options.timezone);
     }
 
-    const httpOptions: BackendSrvRequest = {
-      method: 'GET',
-      url: '/tags/autoComplete/tags',
-      params,
+    return this.getResource<string[]>('tags/autoComplete/tags', params, {
       // for cancellations
       requestId: options.requestId,
-    };
-
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    })
+      .then((results) => {
+        return _map(results, (value) => {
+          return { text: value };
+        });
+      })
+      .catch((err) => Promise.reject(reduceError(err)));
   }
 
   getTagValuesAutoComplete(expressions: string[], tag: string, valuePrefix?: string, optionalOptions?: any) {
@@ -870,56 +863,18 @@ export class GraphiteDatasource
       return this.funcDefsPromise;
     }
 
-    const httpOptions = {
-      method: 'GET',
-      url: '/functions',
-      // add responseType because if this is not defined,
-      // backend_srv defaults to json
-      responseType: 'text' as const,
-    };
-
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          // Fix for a Graphite bug: https://github.com/graphite-project/graphite-web/issues/2609
-          // There is a fix for it https://github.com/graphite-project/graphite-web/pull/2612 but
-          // it was merged to master in July 2020 but it has never been released (the last Graphite
-          // release was 1.1.7 - March 2020). The bug was introduced in Graphite 1.1.7, in versions
-          // 1.1.0 - 1.1.6 /functions endpoint returns a valid JSON
-          const fixedData = JSON.parse(results.data.replace(/"default": ?Infinity/g, '"default": 1e9999'));
-          this.funcDefs = gfunc.parseFuncDefs(fixedData);
-          return this.funcDefs;
-        }),
-        catchError((error) => {
-          console.error('Fetching graphite functions error', error);
-          this.funcDefs = gfunc.getFuncDefs(this.graphiteVersion);
-          return of(this.funcDefs);
-        })
-      )
-    );
-  }
-
-  testDatasource() {
-    const query: DataQueryRequest<GraphiteQuery> = {
-      app: 'graphite',
-      interval: '10ms',
-      intervalMs: 10,
-      requestId: 'reqId',
-      scopedVars: {},
-      startTime: 0,
-      timezone: 'browser',
-      panelId: 3,
-      rangeRaw: { from: 'now-1h', to: 'now' },
-      range: {
-        from: dateTime('now-1h'),
-        to: dateTime('now'),
-        raw: { from: 'now-1h', to: 'now' },
-      },
-      targets: [{ refId: 'A', target: 'constantLine(100)' }],
-      maxDataPoints: 300,
-    };
-
-    return lastValueFrom(this.query(query)).then(() => ({ status: 'success', message: 'Data source is working' }));
+    // The backend fixes the invalid JSON returned by Graphite 1.1.7 for this endpoint, see
+    // https://github.com/graphite-project/graphite-web/issues/2609
+    return this.getResource('functions')
+      .then((results) => {
+        this.funcDefs = gfunc.parseFuncDefs(results);
+        return this.funcDefs;
+      })
+      .catch((error) => {
+        console.error('Fetching graphite functions error', error);
+        this.funcDefs = gfunc.getFuncDefs(this.graphiteVersion);
+        return this.funcDefs;
+      });
   }
 
   doGraphiteRequest(
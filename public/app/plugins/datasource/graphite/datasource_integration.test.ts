@@ -20,6 +20,7 @@ describe('graphiteDatasource integration with backendSrv and fetch', () => {
 
     const instanceSettings = {
       url: '/api/datasources/proxy/1',
+      uid: 'graphite',
       name: 'graphiteProd',
       jsonData: {
         rollupIndicatorEnabled: true,
@@ -30,34 +31,6 @@ describe('graphiteDatasource integration with backendSrv and fetch', () => {
   });
 
   describe('returns a list of functions', () => {
-    it('should return a list of functions with invalid JSON', async () => {
-      const INVALID_JSON =
-        '{"testFunction":{"name":"function","description":"description","module":"graphite.render.functions","group":"Transform","params":[{"name":"param","type":"intOrInf","required":true,"default":Infinity}]}}';
-
-      mockBackendSrv(INVALID_JSON);
-
-      const funcDefs = await ctx.ds.getFuncDefs();
-
-      expect(funcDefs).toEqual({
-        testFunction: {
-          category: 'Transform',
-          defaultParams: ['inf'],
-          description: 'description',
-          fake: true,
-          name: 'function',
-          params: [
-            {
-              multiple: false,
-              name: 'param',
-              optional: false,
-              options: undefined,
-              type: 'int_or_infinity',
-            },
-          ],
-        },
-      });
-    });
-
     it('should return a list of functions with valid JSON', async () => {
       const VALID_JSON =
         '{"testFunction":{"name":"function","description":"description","module":"graphite.render.functions","group":"Transform","params":[{"name":"param","type":"intOrInf","required":true,"default":1e9999}]}}';
@@ -118,9 +91,7 @@ function mockBackendSrv(data: string) {
       url: 'http://localhost:3000/api/some-mock',
       headers: new Headers({
         method: 'GET',
-        url: '/functions',
-        // to work around Graphite returning invalid JSON
-        responseType: 'text',
+        url: '/api/datasources/uid/graphite/resources/functions',
       }),
     };
     return of(mockedResponse);
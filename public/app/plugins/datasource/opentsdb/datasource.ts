@@ -13,27 +13,18 @@ import {
   map as _map,
   toPairs,
 } from 'lodash';
-import { lastValueFrom, merge, Observable, of } from 'rxjs';
+import { from, lastValueFrom, Observable, of } from 'rxjs';
 import { catchError, map } from 'rxjs/operators';
 
-import {
-  AnnotationEvent,
-  DataQueryRequest,
-  DataQueryResponse,
-  DataSourceApi,
-  dateMath,
-  DateTime,
-  ScopedVars,
-  toDataFrame,
-} from '@grafana/data';
-import { FetchResponse, getBackendSrv } from '@grafana/runtime';
+import { DataQueryRequest, DataQueryResponse, dateMath, DateTime, ScopedVars } from '@grafana/data';
+import { DataSourceWithBackend, FetchResponse, getBackendSrv } from '@grafana/runtime';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import { AnnotationEditor } from './components/AnnotationEditor';
 import { prepareAnnotation } from './migrations';
-import { OpenTsdbFilter, OpenTsdbOptions, OpenTsdbQuery } from './types';
+import { OpenTsdbFilter, OpenTsdbLookupResponse, OpenTsdbOptions, OpenTsdbQuery } from './types';
 
-export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenTsdbOptions> {
+export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuery, OpenTsdbOptions> {
   type: 'opentsdb';
   url: string;
   name: string;
@@ -73,29 +64,12 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
 
   // Called once per panel (graph)
   query(options: DataQueryRequest<OpenTsdbQuery>): Observable<DataQueryResponse> {
-    // migrate annotations
+    // annotations are queried by the backend, which returns them as frames
     if (options.targets.some((target: OpenTsdbQuery) => target.fromAnnotations)) {
-      const streams: Array<Observable<DataQueryResponse>> = [];
-
-      for (const annotation of options.targets) {
-        if (annotation.target) {
-          streams.push(
-            new Observable((subscriber) => {
-              this.annotationEvent(options, annotation)
-                .then((events) => subscriber.next({ data: [toDataFrame(events)] }))
-                .catch((ex) => {
-                  // grafana fetch throws the error so for annotation consistency among datasources
-                  // we return an empty array which displays as 'no events found'
-                  // in the annnotation editor
-                  return subscriber.next({ data: [toDataFrame([])] });
-                })
-                .finally(() => subscriber.complete());
-            })
-          );
-        }
-      }
-
-      return merge(...streams);
+      return super.query({
+        ...options,
+        targets: options.targets.filter((target) => target.fromAnnotations && target.target),
+      });
     }
 
     const start = this.convertToTSDBTime(options.range.raw.from, false, options.timezone);
@@ -160,42 +134,6 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     );
   }
 
-  annotationEvent(options: DataQueryRequest, annotation: OpenTsdbQuery): Promise<AnnotationEvent[]> {
-    const start = this.convertToTSDBTime(options.range.raw.from, false, options.timezone);
-    const end = this.convertToTSDBTime(options.range.raw.to, true, options.timezone);
-    const qs = [];
-    const eventList: AnnotationEvent[] = [];
-
-    qs.push({ aggregator: 'sum', metric: annotation.target });
-
-    const queries = compact(qs);
-
-    return lastValueFrom(
-      this.performTimeSeriesQuery(queries, start, end).pipe(
-        map((results) => {
-          if (results.data[0]) {
-            let annotationObject = results.data[0].annotations;
-            if (annotation.isGlobal) {
-              annotationObject = results.data[0].globalAnnotations;
-            }
-            if (annotationObject) {
-              each(annotationObject, (ann) => {
-                const event = {
-                  text: ann.description,
-                  time: Math.floor(ann.startTime) * 1000,
-                  annotation: annotation,
-                };
-
-                eventList.push(event);
-              });
-            }
-          }
-          return eventList;
-        })
-      )
-    );
-  }
-
   targetContainsTemplate(target: any) {
     if (target.filters && target.filters.length > 0) {
       for (let i = 0; i < target.filters.length; i++) {
@@ -261,11 +199,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   _performSuggestQuery(query: string, type: string) {
-    return this._get('/api/suggest', { type, q: query, max: this.lookupLimit }).pipe(
-      map((result) => {
-        return result.data;
-      })
-    );
+    return this._getResource<string[]>('api/suggest', { type, q: query, max: this.lookupLimit });
   }
 
   _performMetricKeyValueLookup(metric: string, keys: string) {
@@ -285,9 +219,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
 
     const m = metric + '{' + keysQuery + '}';
 
-    return this._get('/api/search/lookup', { m: m, limit: this.lookupLimit }).pipe(
-      map((result) => {
-        result = result.data.results;
+    return this._getResource<OpenTsdbLookupResponse>('api/search/lookup', { m: m, limit: this.lookupLimit }).pipe(
+      map((response) => {
+        const result = response.results;
         const tagvs: any[] = [];
         each(result, (r) => {
           if (tagvs.indexOf(r.tags[key]) === -1) {
@@ -304,9 +238,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       return of([]);
     }
 
-    return this._get('/api/search/lookup', { m: metric, limit: 1000 }).pipe(
-      map((result) => {
-        result = result.data.results;
+    return this._getResource<OpenTsdbLookupResponse>('api/search/lookup', { m: metric, limit: 1000 }).pipe(
+      map((response) => {
+        const result = response.results;
         const tagks: any[] = [];
         each(result, (r) => {
           each(r.tags, (tagv, tagk) => {
@@ -320,19 +254,11 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     );
   }
 
-  _get(
-    relativeUrl: string,
+  _getResource<T>(
+    path: string,
     params?: { type?: string; q?: string; max?: number; m?: string; limit?: number }
-  ): Observable<FetchResponse> {
-    const options = {
-      method: 'GET',
-      url: this.url + relativeUrl,
-      params: params,
-    };
-
-    this._addCredentialOptions(options);
-
-    return getBackendSrv().fetch(options);
+  ): Observable<T> {
+    return from(this.getResource<T>(path, params));
   }
 
   _addCredentialOptions(options: Record<string, unknown>) {
@@ -398,26 +324,16 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     return Promise.resolve([]);
   }
 
-  testDatasource() {
-    return lastValueFrom(
-      this._performSuggestQuery('cpu', 'metrics').pipe(
-        map(() => {
-          return { status: 'success', message: 'Data source is working' };
-        })
-      )
-    );
-  }
-
   getAggregators() {
     if (this.aggregatorsPromise) {
       return this.aggregatorsPromise;
     }
 
     this.aggregatorsPromise = lastValueFrom(
-      this._get('/api/aggregators').pipe(
+      this._getResource<string[]>('api/aggregators').pipe(
         map((result) => {
-          if (result.data && isArray(result.data)) {
-            return result.data.sort();
+          if (result && isArray(result)) {
+            return result.sort();
           }
           return [];
         })
@@ -432,10 +348,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     }
 
     this.filterTypesPromise = lastValueFrom(
-      this._get('/api/config/filters').pipe(
+      this._getResource<Record<string, unknown>>('api/config/filters').pipe(
         map((result) => {
-          if (result.data) {
-            return Object.keys(result.data).sort();
+          if (result) {
+            return Object.keys(result).sort();
           }
           return [];
         })
@@ -1,4 +1,4 @@
-import { of } from 'rxjs';
+import { lastValueFrom, of } from 'rxjs';
 
 import { DataQueryRequest, dateTime } from '@grafana/data';
 import { backendSrv } from 'app/core/services/backend_srv'; // will use the version in __mocks__
@@ -29,7 +29,7 @@ describe('opentsdb', () => {
     const fetchMock = jest.spyOn(backendSrv, 'fetch');
     fetchMock.mockImplementation(() => of(createFetchResponse(data)));
 
-    const instanceSettings = { url: '', jsonData: { tsdbVersion: 1 } };
+    const instanceSettings = { url: '', uid: 'opentsdb', jsonData: { tsdbVersion: 1 } };
     const replace = jest.fn((value) => value);
     const templateSrv = {
       replace,
@@ -47,7 +47,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('metrics(pew)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('metrics');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('pew');
       expect(results).not.toBe(null);
@@ -59,7 +59,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_names(cpu)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu');
       expect(results).not.toBe(null);
     });
@@ -70,7 +70,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*}');
       expect(results).not.toBe(null);
     });
@@ -81,7 +81,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env}');
       expect(results).not.toBe(null);
     });
@@ -92,7 +92,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env, region=$region)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env,region=$region}');
       expect(results).not.toBe(null);
     });
@@ -103,7 +103,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('suggest_tagk(foo)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagk');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('foo');
       expect(results).not.toBe(null);
@@ -115,13 +115,49 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('suggest_tagv(bar)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagv');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('bar');
       expect(results).not.toBe(null);
     });
   });
 
+  describe('When using the backend', () => {
+    it('should get the aggregators and filter types from resources', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { literal_or: {}, wildcard: {} } });
+
+      expect(await ds.getFilterTypes()).toEqual(['literal_or', 'wildcard']);
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/config/filters');
+
+      fetchMock.mockImplementation(() => of(createFetchResponse(['sum', 'avg'])));
+      expect(await ds.getAggregators()).toEqual(['avg', 'sum']);
+      expect(fetchMock.mock.calls[1][0].url).toBe('/api/datasources/uid/opentsdb/resources/api/aggregators');
+    });
+
+    it('should run the health check when testing the data source', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { status: 'OK', message: 'Data source is working' } });
+
+      const result = await ds.testDatasource();
+
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb/health');
+      expect(result).toEqual({ status: 'success', message: 'Data source is working' });
+    });
+
+    it('should query annotations through the backend', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { results: {} } });
+      const request = {
+        targets: [{ refId: 'Anno', fromAnnotations: true, target: 'deploys', isGlobal: false }],
+        range: { from: dateTime(0), to: dateTime(1000), raw: { from: 'now-6h', to: 'now' } },
+        scopedVars: {},
+      } as unknown as DataQueryRequest<OpenTsdbQuery>;
+
+      await lastValueFrom(ds.query(request));
+
+      expect(fetchMock.mock.calls[0][0].url).toContain('/api/ds/query');
+      expect(fetchMock.mock.calls[0][0].data.queries[0]).toMatchObject({ target: 'deploys', fromAnnotations: true });
+    });
+  });
+
   describe('When interpolating variables', () => {
     it('should return an empty array if no queries are provided', () => {
       const { ds } = getTestcontext();
@@ -52,3 +52,7 @@ export type OpenTsdbFilter = {
   filter: string;
   groupBy: boolean;
 };
+
+export type OpenTsdbLookupResponse = {
+  results: Array<{ tags: Record<string, string> }>;
+};
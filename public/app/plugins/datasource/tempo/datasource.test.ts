@@ -20,6 +20,7 @@ import {
   BackendDataSourceResponse,
   config,
   FetchResponse,
+  HealthStatus,
   setBackendSrv,
   setDataSourceSrv,
   TemplateSrv,
@@ -363,16 +364,17 @@ describe('Tempo data source', () => {
   });
 
   describe('test the testDatasource function', () => {
-    it('should return a success msg if response.ok is true', async () => {
-      mockObservable = () => of({ ok: true });
-      const handleStreamingQuery = jest
-        .spyOn(TempoDatasource.prototype, 'handleStreamingQuery')
-        .mockImplementation(() => of({ data: [] }));
+    it('should return the result of the backend health check', async () => {
+      const callHealthCheck = jest.spyOn(TempoDatasource.prototype, 'callHealthCheck').mockResolvedValue({
+        status: HealthStatus.OK,
+        message: 'Successfully connected to Tempo data source.',
+      });
 
       const ds = new TempoDatasource(defaultSettings);
       const response = await ds.testDatasource();
       expect(response.status).toBe('success');
-      expect(handleStreamingQuery).toHaveBeenCalled();
+      expect(response.message).toBe('Successfully connected to Tempo data source.');
+      expect(callHealthCheck).toHaveBeenCalled();
     });
   });
 
@@ -383,6 +385,18 @@ describe('Tempo data source', () => {
       const response = await ds.metadataRequest('/api/search/tags');
       expect(response).toBe('456');
     });
+
+    it('should request the backend resource handlers', async () => {
+      const fetch = jest.fn(() => of({ data: {} }));
+      mockObservable = fetch;
+      const ds = new TempoDatasource(defaultSettings);
+      await ds.metadataRequest('/api/v2/search/tag/span.http.method/values', { q: '{}' });
+      expect(fetch).toHaveBeenCalledWith({
+        url: `/api/datasources/uid/${ds.uid}/resources/api/v2/search/tag/span.http.method/values?q=%7B%7D`,
+        method: 'GET',
+        hideFromInspector: true,
+      });
+    });
   });
 
   it('should include time shift when querying for traceID', () => {